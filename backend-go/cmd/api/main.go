@@ -120,16 +120,10 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
-	"github.com/swaggo/http-swagger"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/axfinn/todoIng/backend-go/internal/api"
-	"github.com/axfinn/todoIng/backend-go/internal/captcha"
-	"github.com/axfinn/todoIng/backend-go/internal/email"
+	"github.com/axfinn/todoIng/backend-go/internal/app"
 	"github.com/axfinn/todoIng/backend-go/internal/observability"
-	"go.mongodb.org/mongo-driver/bson"
-	"golang.org/x/crypto/bcrypt"
 
 	_ "github.com/axfinn/todoIng/backend-go/docs" // 导入生成的文档
 )
@@ -138,13 +132,15 @@ import (
 func init() {
 	// 引用所有handler依赖类型，确保swag扫描时能发现它们
 	_ = api.TaskDeps{}
+	_ = api.LabelDeps{}
+	_ = api.ProjectDeps{}
+	_ = api.WorkflowDeps{}
 	_ = api.ReportDeps{}
+	_ = api.TrashDeps{}
 	_ = api.CaptchaDeps{}
 	_ = api.AuthDeps{}
 }
 
-var client *mongo.Client
-
 func main() {
 	_ = godotenv.Load()
 
@@ -163,61 +159,19 @@ func main() {
 		defer func() { _ = shutdown(context.Background()) }()
 	}
 
-	mongoURI := os.Getenv("MONGO_URI")
-	if mongoURI == "" {
-		observability.LogError("MONGO_URI environment variable not set")
-		log.Fatal("MONGO_URI not set")
-	}
-	observability.LogInfo("Connecting to MongoDB at %s", mongoURI)
-
-	var err error
-	clientOpts := options.Client().ApplyURI(mongoURI)
-	// 暂时移除 mongo tracing 监控
-	client, err = mongo.Connect(ctx, clientOpts)
+	a, err := app.New(ctx)
 	if err != nil {
-		observability.LogError("Failed to connect to MongoDB: %v", err)
+		observability.LogError("Failed to initialize storage: %v", err)
 		log.Fatal(err)
 	}
-	if err = client.Ping(ctx, nil); err != nil {
-		observability.LogError("Failed to ping MongoDB: %v", err)
+
+	handler, err := a.HTTPHandler(context.Background())
+	if err != nil {
+		observability.LogError("Failed to initialize router: %v", err)
 		log.Fatal(err)
 	}
-	observability.LogInfo("MongoDB connected successfully")
-
-	db := client.Database("todoing")
-	emailStore := email.NewStore(10*time.Minute, 3)
-	captchaStore := captcha.NewStore(5 * time.Minute)
-	observability.LogInfo("Email store and captcha store initialized")
-
-	r := api.NewRouter()
-	// 暂时直接使用普通的 router，不使用 otelhttp
-	handler := r
 	observability.LogInfo("Router initialized")
 
-	// Swagger 文档路由
-	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
-
-	// 静态文件服务 - API 文档
-	docsHandler := http.StripPrefix("/docs/", http.FileServer(http.Dir("docs/")))
-	r.PathPrefix("/docs/").Handler(docsHandler)
-
-	// 完整 API 文档路由
-	r.HandleFunc("/api-docs", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		http.ServeFile(w, r, "docs/api_complete.json")
-	}).Methods(http.MethodGet)
-
-	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
-	}).Methods(http.MethodGet)
-
-	api.SetupAuthRoutes(r, &api.AuthDeps{DB: db, EmailCodes: emailStore})
-	api.SetupCaptchaRoutes(r, &api.CaptchaDeps{Store: captchaStore})
-	api.SetupTaskRoutes(r, &api.TaskDeps{DB: db})
-	api.SetupReportRoutes(r, &api.ReportDeps{DB: db})
-	observability.LogInfo("All API routes configured")
-
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "5001"
@@ -228,33 +182,15 @@ func main() {
 	// create default user if not exists
 	go func() {
 		time.Sleep(500 * time.Millisecond)
-		observability.LogInfo("Starting default user creation check...")
-		username := os.Getenv("DEFAULT_USERNAME")
-		password := os.Getenv("DEFAULT_PASSWORD")
-		emailAddr := os.Getenv("DEFAULT_EMAIL")
-		if username == "" || password == "" || emailAddr == "" {
-			observability.LogWarn("Default user environment variables not set, skipping default user creation")
-			return
-		}
-		ctxDef, cancelDef := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancelDef()
-		usersCol := db.Collection("users")
-		err := usersCol.FindOne(ctxDef, bson.M{"$or": []bson.M{{"username": username}, {"email": emailAddr}}}).Err()
-		if err == mongo.ErrNoDocuments {
-			hash, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
-			_, errIns := usersCol.InsertOne(ctxDef, bson.M{"username": username, "email": emailAddr, "password": string(hash), "createdAt": time.Now()})
-			if errIns != nil {
-				observability.LogError("Failed to create default user: %v", errIns)
-			} else {
-				observability.LogInfo("Default user created successfully: %s (%s)", username, emailAddr)
-			}
-		} else if err == nil {
-			observability.LogInfo("Default user already exists: %s", username)
-		} else {
-			observability.LogError("Error checking for default user: %v", err)
-		}
+		a.EnsureDefaultUser(context.Background())
 	}()
 
+	// 定时清理回收站中超过保留期限的记录
+	stopPurge := a.StartTrashPurge()
+
+	// 后台为尚未索引的任务与报表补建全文索引
+	stopBackfill := a.StartSearchBackfill()
+
 	go func() {
 		observability.LogInfo("Server starting on port %s", port)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -279,10 +215,12 @@ func main() {
 		observability.LogInfo("HTTP server shutdown successfully")
 	}
 
-	if err := client.Disconnect(ctxShut); err != nil {
-		observability.LogError("MongoDB disconnect error: %v", err)
+	stopPurge()
+	stopBackfill()
+	if err := a.Close(ctxShut); err != nil {
+		observability.LogError("Database close error: %v", err)
 	} else {
-		observability.LogInfo("MongoDB disconnected successfully")
+		observability.LogInfo("Database connection closed")
 	}
 
 	observability.LogInfo("Application shutdown complete")
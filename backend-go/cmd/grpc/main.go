@@ -11,13 +11,8 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
 
-	"github.com/axfinn/todoIng/backend-go/internal/services"
-	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"github.com/axfinn/todoIng/backend-go/internal/app"
 )
 
 // @title TodoIng gRPC API
@@ -29,36 +24,24 @@ import (
 func main() {
 	_ = godotenv.Load()
 
-	// 连接数据库
+	// 初始化存储
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	mongoURI := os.Getenv("MONGO_URI")
-	if mongoURI == "" {
-		log.Fatal("MONGO_URI not set")
-	}
-
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	a, err := app.New(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
+	log.Println("Storage initialized for gRPC server")
 
-	if err = client.Ping(ctx, nil); err != nil {
-		log.Fatal(err)
-	}
-	log.Println("MongoDB connected for gRPC server")
-
-	db := client.Database("todoing")
-
-	// 创建 gRPC 服务器
-	grpcServer := grpc.NewServer()
+	// 创建 gRPC 服务器并注册服务
+	grpcServer := a.GRPCServer()
 
-	// 注册服务
-	authService := services.NewAuthService(db)
-	pb.RegisterAuthServiceServer(grpcServer, authService)
+	// 定时清理回收站中超过保留期限的记录
+	stopPurge := a.StartTrashPurge()
 
-	// 启用反射（用于 grpcurl 等工具）
-	reflection.Register(grpcServer)
+	// 后台为尚未索引的任务与报表补建全文索引
+	stopBackfill := a.StartSearchBackfill()
 
 	// 监听端口
 	port := os.Getenv("GRPC_PORT")
@@ -89,8 +72,10 @@ func main() {
 
 	ctxShut, cancelShut := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancelShut()
-	if err := client.Disconnect(ctxShut); err != nil {
-		log.Printf("MongoDB disconnect error: %v", err)
+	stopPurge()
+	stopBackfill()
+	if err := a.Close(ctxShut); err != nil {
+		log.Printf("Database close error: %v", err)
 	}
 
 	fmt.Println("gRPC server exited")
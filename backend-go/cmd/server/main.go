@@ -0,0 +1,108 @@
+// 单进程入口：在同一端口上同时提供 HTTP (/api、/api/v2、文档) 与 gRPC 服务，
+// 共享一个数据库连接、一套验证码存储和一次优雅关闭流程。
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/axfinn/todoIng/backend-go/internal/app"
+	"github.com/axfinn/todoIng/backend-go/internal/observability"
+
+	_ "github.com/axfinn/todoIng/backend-go/docs" // 导入生成的文档
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	observability.InitLogger()
+	observability.LogInfo("Application starting up...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	shutdown, errTrace := observability.InitTracer(context.Background(), "todoing-server", os.Getenv("ENVIRONMENT"), "1.0")
+	if errTrace != nil {
+		log.Printf("tracing init error: %v", errTrace)
+	} else {
+		defer func() { _ = shutdown(context.Background()) }()
+	}
+
+	a, err := app.New(ctx)
+	if err != nil {
+		observability.LogError("Failed to initialize storage: %v", err)
+		log.Fatal(err)
+	}
+
+	httpHandler, err := a.HTTPHandler(context.Background())
+	if err != nil {
+		observability.LogError("Failed to initialize router: %v", err)
+		log.Fatal(err)
+	}
+	grpcServer := a.GRPCServer()
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "5001"
+	}
+	server, err := app.NewServer(":"+port, grpcServer, httpHandler)
+	if err != nil {
+		observability.LogError("Failed to initialize server: %v", err)
+		log.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		a.EnsureDefaultUser(context.Background())
+	}()
+
+	// 定时清理回收站中超过保留期限的记录
+	stopPurge := a.StartTrashPurge()
+
+	// 后台为尚未索引的任务与报表补建全文索引
+	stopBackfill := a.StartSearchBackfill()
+
+	go func() {
+		observability.LogInfo("HTTP and gRPC server starting on port %s", port)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			observability.LogError("Server error: %s", err)
+			log.Fatalf("listen: %s", err)
+		}
+	}()
+
+	// graceful shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	observability.LogInfo("Server is ready and listening for requests")
+	<-quit
+	observability.LogInfo("Shutdown signal received, starting graceful shutdown...")
+
+	ctxShut, cancelShut := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShut()
+
+	// 先拒绝新的 gRPC 调用并等待进行中的调用完成，再关闭 HTTP 服务器；超时后强制关闭仍未结束的流
+	if err := server.Shutdown(ctxShut); err != nil {
+		observability.LogError("Server shutdown error: %v", err)
+	} else {
+		observability.LogInfo("HTTP and gRPC server shutdown successfully")
+	}
+
+	stopPurge()
+	stopBackfill()
+	if err := a.Close(ctxShut); err != nil {
+		observability.LogError("Database close error: %v", err)
+	} else {
+		observability.LogInfo("Database connection closed")
+	}
+
+	observability.LogInfo("Application shutdown complete")
+	fmt.Println("Server exiting")
+}
@@ -0,0 +1,220 @@
+// Package account 用户注册、登录与邮箱验证码：密码哈希与校验、唯一性检查、验证码校验与令牌签发。
+// /api 处理器与 gRPC 服务共用这些逻辑，只负责把请求转换为参数并把错误转换为各自的响应
+package account
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/axfinn/todoIng/backend-go/internal/auth"
+	"github.com/axfinn/todoIng/backend-go/internal/email"
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// TokenTTL 签发的令牌有效期
+const TokenTTL = time.Hour
+
+var (
+	// ErrRegistrationDisabled 已关闭注册（DISABLE_REGISTRATION=true）
+	ErrRegistrationDisabled = errors.New("registration is disabled")
+	// ErrFields 用户名、邮箱为空或密码少于 6 位
+	ErrFields = errors.New("username, email and a password of at least 6 characters are required")
+	// ErrEmailRequired 邮箱为空
+	ErrEmailRequired = errors.New("email required")
+	// ErrExists 邮箱或用户名已被占用
+	ErrExists = errors.New("user already exists")
+	// ErrNoUser 邮箱或令牌对应的用户不存在
+	ErrNoUser = errors.New("user does not exist")
+	// ErrCredentials 密码错误，或密码登录的用户不存在
+	ErrCredentials = errors.New("invalid credentials")
+	// ErrEmailCode 邮箱验证码缺失、错误或过期，具体原因只记日志
+	ErrEmailCode = errors.New("invalid verification code")
+	// ErrEmailDisabled 未启用邮箱验证码（ENABLE_EMAIL_VERIFICATION=true）
+	ErrEmailDisabled = errors.New("email verification disabled")
+	// ErrToken 令牌无效或已过期
+	ErrToken = errors.New("invalid token")
+)
+
+// EmailVerification 是否启用邮箱验证码：启用后注册须提供验证码，并可用验证码登录
+func EmailVerification() bool {
+	return os.Getenv("ENABLE_EMAIL_VERIFICATION") == "true"
+}
+
+// Service 用户注册、登录与邮箱验证码
+type Service struct {
+	Users      repository.UserRepository
+	EmailCodes *email.Store
+}
+
+// Session 注册或登录成功后签发的令牌与用户，User 不含密码哈希
+type Session struct {
+	Token string
+	User  *models.User
+}
+
+// Registration 注册参数；启用邮箱验证码时须提供 EmailCodeID 与 EmailCode
+type Registration struct {
+	Username    string
+	Email       string
+	Password    string
+	EmailCode   string
+	EmailCodeID string
+}
+
+// Register 校验参数与邮箱验证码，邮箱与用户名均未被占用时创建用户并签发令牌；
+// 校验失败时返回本包的错误，其余为存储错误
+func (s *Service) Register(ctx context.Context, in Registration) (*Session, error) {
+	if os.Getenv("DISABLE_REGISTRATION") == "true" {
+		return nil, ErrRegistrationDisabled
+	}
+	if in.Username == "" || in.Email == "" || len(in.Password) < 6 {
+		return nil, ErrFields
+	}
+	addr := strings.ToLower(in.Email)
+	if EmailVerification() {
+		if err := s.verifyCode(in.EmailCodeID, addr, in.EmailCode); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.Users.FindByEmailOrUsername(ctx, addr, in.Username); err == nil {
+		return nil, ErrExists
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(in.Password), 10)
+	if err != nil {
+		return nil, err
+	}
+	user := &models.User{Username: in.Username, Email: addr, Password: string(hash), CreatedAt: time.Now()}
+	if err := s.Users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return session(user)
+}
+
+// Login 以邮箱与密码登录；用户不存在与密码错误同样返回 ErrCredentials
+func (s *Service) Login(ctx context.Context, emailAddr, password string) (*Session, error) {
+	user, err := s.findByEmail(ctx, emailAddr)
+	if errors.Is(err, ErrNoUser) {
+		observability.LogWarn("Login failed - user not found for email: %s", strings.ToLower(emailAddr))
+		return nil, ErrCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		observability.LogWarn("Password verification failed for user: %s", user.Email)
+		return nil, ErrCredentials
+	}
+	return session(user)
+}
+
+// LoginWithCode 以邮箱验证码登录，未启用邮箱验证码时返回 ErrEmailDisabled
+func (s *Service) LoginWithCode(ctx context.Context, emailAddr, codeID, code string) (*Session, error) {
+	if !EmailVerification() {
+		return nil, ErrEmailDisabled
+	}
+	user, err := s.findByEmail(ctx, emailAddr)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyCode(codeID, user.Email, code); err != nil {
+		return nil, err
+	}
+	return session(user)
+}
+
+// SendRegisterCode 向尚未注册的邮箱发送注册验证码，返回验证码 ID
+func (s *Service) SendRegisterCode(ctx context.Context, emailAddr string) (string, error) {
+	if !EmailVerification() {
+		return "", ErrEmailDisabled
+	}
+	_, err := s.findByEmail(ctx, emailAddr)
+	switch {
+	case err == nil:
+		return "", ErrExists
+	case !errors.Is(err, ErrNoUser):
+		return "", err
+	}
+	return s.sendCode(emailAddr), nil
+}
+
+// SendLoginCode 向已注册的邮箱发送登录验证码，返回验证码 ID
+func (s *Service) SendLoginCode(ctx context.Context, emailAddr string) (string, error) {
+	if !EmailVerification() {
+		return "", ErrEmailDisabled
+	}
+	if _, err := s.findByEmail(ctx, emailAddr); err != nil {
+		return "", err
+	}
+	return s.sendCode(emailAddr), nil
+}
+
+// Authenticate 校验令牌并返回其用户，User 不含密码哈希
+func (s *Service) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	claims, err := auth.Parse(token)
+	if err != nil {
+		return nil, ErrToken
+	}
+	user, err := s.Users.GetByID(ctx, claims.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNoUser
+	}
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// findByEmail 按小写邮箱查找用户，邮箱为空时返回 ErrEmailRequired，不存在时返回 ErrNoUser
+func (s *Service) findByEmail(ctx context.Context, emailAddr string) (*models.User, error) {
+	if emailAddr == "" {
+		return nil, ErrEmailRequired
+	}
+	user, err := s.Users.GetByEmail(ctx, strings.ToLower(emailAddr))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNoUser
+	}
+	return user, err
+}
+
+// verifyCode 校验邮箱验证码，失败原因只记日志，统一返回 ErrEmailCode
+func (s *Service) verifyCode(id, emailAddr, code string) error {
+	if id == "" || code == "" {
+		return ErrEmailCode
+	}
+	if err := s.EmailCodes.Verify(id, emailAddr, code); err != nil {
+		observability.LogWarn("Email code verification failed for %s: %v", emailAddr, err)
+		return ErrEmailCode
+	}
+	return nil
+}
+
+// sendCode 为小写邮箱生成验证码并发送到原始邮箱地址，返回验证码 ID
+func (s *Service) sendCode(emailAddr string) string {
+	id, code := s.EmailCodes.Generate(strings.ToLower(emailAddr), 6)
+	if err := email.Send(emailAddr, code); err != nil {
+		observability.LogWarn("Failed to send verification code to %s: %v", emailAddr, err)
+	}
+	return id
+}
+
+// session 为用户签发令牌
+func session(user *models.User) (*Session, error) {
+	token, err := auth.Generate(user.ID, TokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	observability.LogInfo("Login successful for user: %s (ID: %s)", user.Email, user.ID)
+	u := *user
+	u.Password = ""
+	return &Session{Token: token, User: &u}, nil
+}
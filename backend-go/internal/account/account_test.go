@@ -0,0 +1,95 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/email"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test_secret")
+	ctx := context.Background()
+	s := &Service{Users: memory.NewUserRepository(), EmailCodes: email.NewStore(time.Minute, 3)}
+
+	session, err := s.Register(ctx, Registration{Username: "alice", Email: "Alice@Example.com", Password: "password123"})
+	if err != nil || session.Token == "" || session.User.Email != "alice@example.com" || session.User.Password != "" {
+		t.Fatalf("Register failed: %+v (%v)", session, err)
+	}
+	tests := []struct {
+		in   Registration
+		want error
+	}{
+		{Registration{Username: "bob", Email: "bob@example.com", Password: "123"}, ErrFields},
+		{Registration{Username: "bob", Email: "ALICE@example.com", Password: "password123"}, ErrExists},
+		{Registration{Username: "alice", Email: "bob@example.com", Password: "password123"}, ErrExists},
+	}
+	for _, tt := range tests {
+		if _, err := s.Register(ctx, tt.in); !errors.Is(err, tt.want) {
+			t.Errorf("%+v: expected %v, got %v", tt.in, tt.want, err)
+		}
+	}
+
+	if got, err := s.Login(ctx, "ALICE@example.com", "password123"); err != nil || got.User.ID != session.User.ID {
+		t.Errorf("Login failed: %+v (%v)", got, err)
+	}
+	for _, addr := range []string{"alice@example.com", "nobody@example.com"} {
+		if _, err := s.Login(ctx, addr, "wrong"); !errors.Is(err, ErrCredentials) {
+			t.Errorf("%s: expected ErrCredentials, got %v", addr, err)
+		}
+	}
+	user, err := s.Authenticate(ctx, session.Token)
+	if err != nil || user.ID != session.User.ID || user.Password != "" {
+		t.Errorf("Authenticate failed: %+v (%v)", user, err)
+	}
+	if _, err := s.Authenticate(ctx, "invalid"); !errors.Is(err, ErrToken) {
+		t.Errorf("Expected ErrToken, got %v", err)
+	}
+
+	t.Setenv("DISABLE_REGISTRATION", "true")
+	if _, err := s.Register(ctx, Registration{Username: "carol", Email: "carol@example.com", Password: "password123"}); !errors.Is(err, ErrRegistrationDisabled) {
+		t.Errorf("Expected ErrRegistrationDisabled, got %v", err)
+	}
+}
+
+func TestEmailCodes(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test_secret")
+	ctx := context.Background()
+	codes := email.NewStore(time.Minute, 3)
+	s := &Service{Users: memory.NewUserRepository(), EmailCodes: codes}
+
+	if _, err := s.SendLoginCode(ctx, "alice@example.com"); !errors.Is(err, ErrEmailDisabled) {
+		t.Errorf("Expected ErrEmailDisabled, got %v", err)
+	}
+	t.Setenv("ENABLE_EMAIL_VERIFICATION", "true")
+
+	// 注册须提供邮箱验证码，验证码按小写邮箱校验
+	if _, err := s.Register(ctx, Registration{Username: "alice", Email: "alice@example.com", Password: "password123"}); !errors.Is(err, ErrEmailCode) {
+		t.Errorf("Expected ErrEmailCode, got %v", err)
+	}
+	id, code := codes.Generate("alice@example.com", 6)
+	if _, err := s.Register(ctx, Registration{Username: "alice", Email: "Alice@example.com", Password: "password123", EmailCodeID: id, EmailCode: code}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if _, err := s.SendRegisterCode(ctx, "alice@example.com"); !errors.Is(err, ErrExists) {
+		t.Errorf("Expected ErrExists, got %v", err)
+	}
+	if _, err := s.SendLoginCode(ctx, "nobody@example.com"); !errors.Is(err, ErrNoUser) {
+		t.Errorf("Expected ErrNoUser, got %v", err)
+	}
+
+	id, err := s.SendLoginCode(ctx, "alice@example.com")
+	if err != nil || id == "" {
+		t.Fatalf("SendLoginCode failed: %q (%v)", id, err)
+	}
+	if _, err := s.LoginWithCode(ctx, "alice@example.com", id, "wrong"); !errors.Is(err, ErrEmailCode) {
+		t.Errorf("Expected ErrEmailCode, got %v", err)
+	}
+	id, code = codes.Generate("alice@example.com", 6)
+	if session, err := s.LoginWithCode(ctx, "ALICE@example.com", id, code); err != nil || session.Token == "" {
+		t.Errorf("LoginWithCode failed: %+v (%v)", session, err)
+	}
+}
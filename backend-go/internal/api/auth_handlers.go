@@ -3,28 +3,30 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
+	"github.com/axfinn/todoIng/backend-go/internal/account"
 	"github.com/axfinn/todoIng/backend-go/internal/auth"
 	"github.com/axfinn/todoIng/backend-go/internal/captcha"
 	"github.com/axfinn/todoIng/backend-go/internal/email"
-	"github.com/axfinn/todoIng/backend-go/internal/models"
 	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
 	"github.com/gorilla/mux"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthDeps struct {
-	DB         *mongo.Database
+	Users      repository.UserRepository
 	EmailCodes *email.Store
 }
 
+// accounts 以处理器的依赖构造注册与登录服务
+func (d *AuthDeps) accounts() *account.Service {
+	return &account.Service{Users: d.Users, EmailCodes: d.EmailCodes}
+}
+
 // RegisterRequest 用户注册请求结构
 type registerRequest struct {
 	Username    string `json:"username" example:"johndoe" validate:"required"`
@@ -51,87 +53,95 @@ type UserResponse struct {
 	UpdatedAt time.Time `json:"updatedAt" example:"2023-12-01T10:00:00Z"`
 }
 
-// LoginResponse 登录响应结构
+// LoginResponse 注册与登录的响应结构
 type LoginResponse struct {
 	Token string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 	User  UserResponse `json:"user"`
 }
 
+// loginResponse 将签发的令牌与用户转换为响应，与 gRPC 的 LoginResponse 字段一致
+func loginResponse(s *account.Session) LoginResponse {
+	return LoginResponse{
+		Token: s.Token,
+		User: UserResponse{
+			ID:        s.User.ID,
+			Username:  s.User.Username,
+			Email:     s.User.Email,
+			CreatedAt: s.User.CreatedAt,
+			UpdatedAt: s.User.CreatedAt, // 模型中没有 UpdatedAt
+		},
+	}
+}
+
+// accountError 将 account 包的错误转换为响应，其余视为存储错误
+func accountError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, account.ErrRegistrationDisabled):
+		JSON(w, http.StatusForbidden, map[string]string{"msg": "Registration is disabled"})
+	case errors.Is(err, account.ErrFields):
+		JSON(w, 400, map[string]string{"msg": "Invalid fields"})
+	case errors.Is(err, account.ErrEmailRequired):
+		JSON(w, 400, map[string]string{"msg": "Email required"})
+	case errors.Is(err, account.ErrEmailCode):
+		JSON(w, 400, map[string]string{"msg": "Invalid verification code"})
+	case errors.Is(err, account.ErrEmailDisabled):
+		JSON(w, 400, map[string]string{"msg": "Email verification disabled"})
+	case errors.Is(err, account.ErrExists):
+		JSON(w, 409, map[string]string{"msg": "User already exists"})
+	case errors.Is(err, account.ErrNoUser):
+		JSON(w, 401, map[string]string{"msg": "User does not exist"})
+	case errors.Is(err, account.ErrCredentials):
+		JSON(w, 401, map[string]string{"msg": "Invalid credentials"})
+	default:
+		observability.LogError("Auth store error: %v", err)
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+	}
+}
+
 // Register 用户注册
 // @Summary 用户注册
-// @Description 注册新用户账户
+// @Description 注册新用户账户，成功后返回令牌与用户信息
 // @Tags 认证
 // @Accept json
 // @Produce json
 // @Param request body registerRequest true "注册信息"
-// @Success 201 {object} map[string]interface{} "注册成功"
-// @Failure 400 {object} map[string]string "请求参数错误"
+// @Success 201 {object} LoginResponse "注册成功"
+// @Failure 400 {object} map[string]string "请求参数错误或邮箱验证码无效"
+// @Failure 403 {object} map[string]string "注册已关闭"
 // @Failure 409 {object} map[string]string "用户已存在"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /api/auth/register [post]
-
 func (d *AuthDeps) Register(w http.ResponseWriter, r *http.Request) {
-	if os.Getenv("DISABLE_REGISTRATION") == "true" {
-		JSON(w, http.StatusForbidden, map[string]string{"msg": "Registration is disabled"})
-		return
-	}
 	var req registerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		JSON(w, 400, map[string]string{"msg": "Invalid body"})
 		return
 	}
-	if req.Username == "" || req.Email == "" || len(req.Password) < 6 {
-		JSON(w, 400, map[string]string{"msg": "Invalid fields"})
-		return
-	}
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	users := d.DB.Collection("users")
-	// email verify
-	if os.Getenv("ENABLE_EMAIL_VERIFICATION") == "true" {
-		if err := d.EmailCodes.Verify(req.EmailCodeId, strings.ToLower(req.Email), req.EmailCode); err != nil {
-			JSON(w, 400, map[string]string{"msg": err.Error()})
-			return
-		}
-	}
-	// uniqueness
-	if err := users.FindOne(ctx, bson.M{"$or": []bson.M{{"email": req.Email}, {"username": req.Username}}}).Err(); err == nil {
-		JSON(w, 409, map[string]string{"msg": "User already exists"})
-		return
-	}
-	pwHash, _ := bcrypt.GenerateFromPassword([]byte(req.Password), 10)
-	user := models.User{Username: req.Username, Email: strings.ToLower(req.Email), Password: string(pwHash), CreatedAt: time.Now()}
-	res, err := users.InsertOne(ctx, user)
+	session, err := d.accounts().Register(ctx, account.Registration{
+		Username:    req.Username,
+		Email:       req.Email,
+		Password:    req.Password,
+		EmailCode:   req.EmailCode,
+		EmailCodeID: req.EmailCodeId,
+	})
 	if err != nil {
-		JSON(w, 500, map[string]string{"msg": "DB error"})
+		accountError(w, err)
 		return
 	}
-	id := res.InsertedID.(primitive.ObjectID).Hex()
-	token, _ := auth.Generate(id, time.Hour)
-
-	userResponse := UserResponse{
-		ID:        id,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.CreatedAt, // 使用CreatedAt作为初始UpdatedAt
-	}
-
-	JSON(w, 201, map[string]interface{}{
-		"token": token,
-		"user":  userResponse,
-	})
+	JSON(w, 201, loginResponse(session))
 }
 
 // Login 用户登录
 // @Summary 用户登录
-// @Description 用户通过邮箱和密码登录
+// @Description 用户通过邮箱和密码登录；启用邮箱验证码时提供 emailCode 与 emailCodeId 则以验证码登录
 // @Tags 认证
 // @Accept json
 // @Produce json
 // @Param request body loginRequest true "登录信息"
 // @Success 200 {object} LoginResponse "登录成功"
-// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 400 {object} map[string]string "请求参数错误或邮箱验证码无效"
 // @Failure 401 {object} map[string]string "认证失败"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /api/auth/login [post]
@@ -144,54 +154,20 @@ func (d *AuthDeps) Login(w http.ResponseWriter, r *http.Request) {
 		JSON(w, 400, map[string]string{"msg": "Invalid body"})
 		return
 	}
-	if req.Email == "" {
-		observability.LogWarn("Login attempt without email from IP: %s", r.RemoteAddr)
-		JSON(w, 400, map[string]string{"msg": "Email required"})
-		return
-	}
-
-	normalizedEmail := strings.ToLower(req.Email)
-	observability.CtxLog(r.Context(), "Login attempt for email: %s", normalizedEmail)
-
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	users := d.DB.Collection("users")
-	var user models.User
-	err := users.FindOne(ctx, bson.M{"email": normalizedEmail}).Decode(&user)
+	var session *account.Session
+	var err error
+	if req.EmailCode != "" && req.EmailCodeId != "" && account.EmailVerification() {
+		session, err = d.accounts().LoginWithCode(ctx, req.Email, req.EmailCodeId, req.EmailCode)
+	} else {
+		session, err = d.accounts().Login(ctx, req.Email, req.Password)
+	}
 	if err != nil {
-		observability.LogWarn("Login failed - user not found for email: %s", normalizedEmail)
-		// 如果是邮箱验证码登录但用户不存在，返回用户不存在的错误
-		if req.EmailCode != "" && req.EmailCodeId != "" && os.Getenv("ENABLE_EMAIL_VERIFICATION") == "true" {
-			JSON(w, 401, map[string]string{"msg": "User does not exist"})
-			return
-		}
-		JSON(w, 401, map[string]string{"msg": "Invalid credentials"})
+		accountError(w, err)
 		return
 	}
-	// email code login
-	if req.EmailCode != "" && req.EmailCodeId != "" && os.Getenv("ENABLE_EMAIL_VERIFICATION") == "true" {
-		observability.CtxLog(r.Context(), "Attempting email code verification for user: %s", normalizedEmail)
-		// 使用小写邮箱地址进行验证，确保与存储时一致
-		if err := d.EmailCodes.Verify(req.EmailCodeId, normalizedEmail, req.EmailCode); err != nil {
-			observability.LogWarn("Email code verification failed for user: %s, error: %v", normalizedEmail, err)
-			// 统一验证码相关的错误信息，避免暴露具体的验证码错误
-			JSON(w, 400, map[string]string{"msg": "Invalid verification code"})
-			return
-		}
-		observability.LogInfo("Email code verification successful for user: %s", normalizedEmail)
-	} else {
-		observability.CtxLog(r.Context(), "Attempting password verification for user: %s", normalizedEmail)
-		if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
-			observability.LogWarn("Password verification failed for user: %s", normalizedEmail)
-			JSON(w, 401, map[string]string{"msg": "Invalid credentials"})
-			return
-		}
-		observability.LogInfo("Password verification successful for user: %s", normalizedEmail)
-	}
-
-	token, _ := auth.Generate(user.ID, time.Hour)
-	observability.LogInfo("Login successful for user: %s (ID: %s)", normalizedEmail, user.ID)
-	JSON(w, 200, map[string]string{"token": token})
+	JSON(w, 200, loginResponse(session))
 }
 
 func (d *AuthDeps) Me(w http.ResponseWriter, r *http.Request) {
@@ -202,10 +178,7 @@ func (d *AuthDeps) Me(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	users := d.DB.Collection("users")
-	var user models.User
-	objID, _ := primitive.ObjectIDFromHex(uid)
-	err := users.FindOne(ctx, bson.M{"_id": objID}).Decode(&user)
+	user, err := d.Users.GetByID(ctx, uid)
 	if err != nil {
 		JSON(w, 500, map[string]string{"msg": "Not found"})
 		return
@@ -214,71 +187,34 @@ func (d *AuthDeps) Me(w http.ResponseWriter, r *http.Request) {
 	JSON(w, 200, user)
 }
 
-func (d *AuthDeps) SendRegisterEmailCode(w http.ResponseWriter, r *http.Request) {
-	if os.Getenv("ENABLE_EMAIL_VERIFICATION") != "true" {
-		JSON(w, 400, map[string]string{"msg": "Email verification disabled"})
-		return
-	}
-	var body struct {
-		Email string `json:"email"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		JSON(w, 400, map[string]string{"msg": "Invalid body"})
-		return
-	}
-	if body.Email == "" {
-		JSON(w, 400, map[string]string{"msg": "Email required"})
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
-	users := d.DB.Collection("users")
-	normalizedEmail := strings.ToLower(body.Email)
-
-	// 注册时检查用户是否已存在
-	if err := users.FindOne(ctx, bson.M{"email": normalizedEmail}).Err(); err == nil {
-		JSON(w, 400, map[string]string{"msg": "User already exists"})
-		return
-	}
+// emailRequest 发送邮箱验证码的请求
+type emailRequest struct {
+	Email string `json:"email"`
+}
 
-	id, code := d.EmailCodes.Generate(normalizedEmail, 6)
-	_ = email.Send(body.Email, code) // 发送邮件使用原始邮箱格式
-	JSON(w, 200, map[string]string{"id": id, "msg": "Verification code sent"})
+func (d *AuthDeps) SendRegisterEmailCode(w http.ResponseWriter, r *http.Request) {
+	d.sendEmailCode(w, r, d.accounts().SendRegisterCode, "Verification code sent")
 }
 
 func (d *AuthDeps) SendLoginEmailCode(w http.ResponseWriter, r *http.Request) {
-	if os.Getenv("ENABLE_EMAIL_VERIFICATION") != "true" {
-		JSON(w, 400, map[string]string{"msg": "Email verification disabled"})
-		return
-	}
-	var body struct {
-		Email string `json:"email"`
-	}
+	d.sendEmailCode(w, r, d.accounts().SendLoginCode, "Login verification code sent")
+}
+
+// sendEmailCode 解析邮箱并以 send 发送验证码，成功时返回验证码 ID 与 msg
+func (d *AuthDeps) sendEmailCode(w http.ResponseWriter, r *http.Request, send func(context.Context, string) (string, error), msg string) {
+	var body emailRequest
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		JSON(w, 400, map[string]string{"msg": "Invalid body"})
 		return
 	}
-	if body.Email == "" {
-		JSON(w, 400, map[string]string{"msg": "Email required"})
-		return
-	}
-
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	users := d.DB.Collection("users")
-	normalizedEmail := strings.ToLower(body.Email)
-
-	// 登录时检查用户是否存在
-	var user models.User
-	if err := users.FindOne(ctx, bson.M{"email": normalizedEmail}).Decode(&user); err != nil {
-		JSON(w, 400, map[string]string{"msg": "User does not exist"})
+	id, err := send(ctx, body.Email)
+	if err != nil {
+		accountError(w, err)
 		return
 	}
-
-	id, code := d.EmailCodes.Generate(normalizedEmail, 6)
-	_ = email.Send(body.Email, code) // 发送邮件使用原始邮箱格式
-	JSON(w, 200, map[string]string{"id": id, "msg": "Login verification code sent"})
+	JSON(w, 200, map[string]string{"id": id, "msg": msg})
 }
 
 func SetupAuthRoutes(r *mux.Router, deps *AuthDeps) {
@@ -292,17 +228,17 @@ func SetupAuthRoutes(r *mux.Router, deps *AuthDeps) {
 
 // 包装函数，用于兼容测试代码
 
-func RegisterHandler(db *mongo.Database, emailStore *email.Store, captchaStore *captcha.Store) http.HandlerFunc {
-	deps := &AuthDeps{DB: db, EmailCodes: emailStore}
+func RegisterHandler(users repository.UserRepository, emailStore *email.Store, captchaStore *captcha.Store) http.HandlerFunc {
+	deps := &AuthDeps{Users: users, EmailCodes: emailStore}
 	return deps.Register
 }
 
-func LoginHandler(db *mongo.Database) http.HandlerFunc {
-	deps := &AuthDeps{DB: db}
+func LoginHandler(users repository.UserRepository) http.HandlerFunc {
+	deps := &AuthDeps{Users: users}
 	return deps.Login
 }
 
-func VerifyTokenHandler(db *mongo.Database) http.HandlerFunc {
+func VerifyTokenHandler(users repository.UserRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
@@ -323,12 +259,12 @@ func VerifyTokenHandler(db *mongo.Database) http.HandlerFunc {
 	}
 }
 
-func EmailCodeLoginHandler(db *mongo.Database, emailStore *email.Store) http.HandlerFunc {
-	deps := &AuthDeps{DB: db, EmailCodes: emailStore}
+func EmailCodeLoginHandler(users repository.UserRepository, emailStore *email.Store) http.HandlerFunc {
+	deps := &AuthDeps{Users: users, EmailCodes: emailStore}
 	return deps.Login // 邮箱验证码登录使用相同的登录逻辑
 }
 
-func SendLoginEmailCodeHandler(db *mongo.Database, emailStore *email.Store) http.HandlerFunc {
-	deps := &AuthDeps{DB: db, EmailCodes: emailStore}
+func SendLoginEmailCodeHandler(users repository.UserRepository, emailStore *email.Store) http.HandlerFunc {
+	deps := &AuthDeps{Users: users, EmailCodes: emailStore}
 	return deps.SendLoginEmailCode
 }
@@ -10,27 +10,19 @@ import (
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/axfinn/todoIng/backend-go/internal/auth"
 	"github.com/axfinn/todoIng/backend-go/internal/captcha"
 	"github.com/axfinn/todoIng/backend-go/internal/email"
-	"github.com/axfinn/todoIng/backend-go/test/testutil"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
 )
 
 func TestRegister(t *testing.T) {
-	// 设置测试数据库
-	testDB := testutil.SetupTestDB(t)
-	defer testDB.TeardownTestDB(t)
-
 	// 设置测试依赖
 	emailStore := email.NewStore(10*time.Minute, 3)
 	captchaStore := captcha.NewStore(5 * time.Minute)
 
-	// 创建路由
-	r := mux.NewRouter()
-	setupAuthRoutes(r, testDB.DB, emailStore, captchaStore)
-
 	tests := []struct {
 		name           string
 		requestBody    map[string]interface{}
@@ -84,7 +76,11 @@ func TestRegister(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// 对于重复邮箱测试，先清理数据库
+			// 每个用例使用独立的内存存储，避免测试间干扰
+			r := mux.NewRouter()
+			setupAuthRoutes(r, memory.NewUserRepository(), emailStore, captchaStore)
+
+			// 对于重复邮箱测试，先注册一个相同邮箱的用户
 			if tt.name == "重复邮箱" {
 				// 先注册一个用户
 				firstUser := map[string]interface{}{
@@ -125,22 +121,16 @@ func TestRegister(t *testing.T) {
 					t.Errorf("Expected field '%s' not found in response", tt.expectedField)
 				}
 			}
-
-			// 清理数据库以避免测试间干扰
-			testDB.CleanCollection(t, "users")
 		})
 	}
 }
 
 func TestLogin(t *testing.T) {
-	testDB := testutil.SetupTestDB(t)
-	defer testDB.TeardownTestDB(t)
-
 	emailStore := email.NewStore(10*time.Minute, 3)
 	captchaStore := captcha.NewStore(5 * time.Minute)
 
 	r := mux.NewRouter()
-	setupAuthRoutes(r, testDB.DB, emailStore, captchaStore)
+	setupAuthRoutes(r, memory.NewUserRepository(), emailStore, captchaStore)
 
 	// 先注册一个用户
 	registerData := map[string]interface{}{
@@ -209,8 +199,11 @@ func TestLogin(t *testing.T) {
 					t.Fatalf("Failed to unmarshal response: %v", err)
 				}
 
-				if _, exists := response["token"]; !exists {
-					t.Error("Expected 'token' field not found in response")
+				// 与注册及 gRPC 登录相同，返回令牌与用户
+				for _, field := range []string{"token", "user"} {
+					if _, exists := response[field]; !exists {
+						t.Errorf("Expected '%s' field not found in response", field)
+					}
 				}
 			}
 		})
@@ -218,9 +211,6 @@ func TestLogin(t *testing.T) {
 }
 
 func TestVerifyToken(t *testing.T) {
-	testDB := testutil.SetupTestDB(t)
-	defer testDB.TeardownTestDB(t)
-
 	// 创建一个有效的JWT令牌
 	userID := primitive.NewObjectID()
 	token, err := auth.GenerateJWT(userID.Hex())
@@ -232,7 +222,7 @@ func TestVerifyToken(t *testing.T) {
 	captchaStore := captcha.NewStore(5 * time.Minute)
 
 	r := mux.NewRouter()
-	setupAuthRoutes(r, testDB.DB, emailStore, captchaStore)
+	setupAuthRoutes(r, memory.NewUserRepository(), emailStore, captchaStore)
 
 	tests := []struct {
 		name           string
@@ -275,12 +265,12 @@ func TestVerifyToken(t *testing.T) {
 }
 
 // setupAuthRoutes 设置认证路由用于测试
-func setupAuthRoutes(r *mux.Router, db *mongo.Database, emailStore *email.Store, captchaStore *captcha.Store) {
+func setupAuthRoutes(r *mux.Router, users repository.UserRepository, emailStore *email.Store, captchaStore *captcha.Store) {
 	authRouter := r.PathPrefix("/auth").Subrouter()
 
-	authRouter.HandleFunc("/register", RegisterHandler(db, emailStore, captchaStore)).Methods("POST")
-	authRouter.HandleFunc("/login", LoginHandler(db)).Methods("POST")
-	authRouter.HandleFunc("/verify", VerifyTokenHandler(db)).Methods("GET")
-	authRouter.HandleFunc("/login/email-code", EmailCodeLoginHandler(db, emailStore)).Methods("POST")
-	authRouter.HandleFunc("/send-login-email-code", SendLoginEmailCodeHandler(db, emailStore)).Methods("POST")
+	authRouter.HandleFunc("/register", RegisterHandler(users, emailStore, captchaStore)).Methods("POST")
+	authRouter.HandleFunc("/login", LoginHandler(users)).Methods("POST")
+	authRouter.HandleFunc("/verify", VerifyTokenHandler(users)).Methods("GET")
+	authRouter.HandleFunc("/login/email-code", EmailCodeLoginHandler(users, emailStore)).Methods("POST")
+	authRouter.HandleFunc("/send-login-email-code", SendLoginEmailCodeHandler(users, emailStore)).Methods("POST")
 }
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/taskbulk"
+	"github.com/axfinn/todoIng/backend-go/internal/taskcomment"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type bulkRequest struct {
+	IDs    []string    `json:"ids"`    // 与 filter 二选一
+	Filter *bulkFilter `json:"filter"` // 与 ids 二选一，条件与 GET /api/tasks 的同名参数一致
+	Op     string      `json:"op"`     // setStatus、setPriority、setAssignee、setDeadline、delete 或 comment
+	Value  *string     `json:"value"`  // 状态、优先级、负责人、截止日期或评论内容；负责人与截止日期为 null 或空串表示清空
+	Force  bool        `json:"force"`  // 完成任务时忽略未完成的阻塞任务
+	Atomic bool        `json:"atomic"` // 任一任务失败时不修改任何任务
+}
+
+type bulkFilter struct {
+	Q        string   `json:"q"`
+	Status   string   `json:"status"`
+	Priority string   `json:"priority"`
+	Assignee string   `json:"assignee"`
+	Parent   string   `json:"parent"`
+	Project  string   `json:"project"`
+	Label    []string `json:"label"`
+}
+
+// values 转换为任务列表的查询参数
+func (f *bulkFilter) values() url.Values {
+	q := url.Values{}
+	for k, v := range map[string]string{"status": f.Status, "priority": f.Priority, "assignee": f.Assignee, "parent": f.Parent, "project": f.Project} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	if len(f.Label) > 0 {
+		q["label"] = f.Label
+	}
+	return q
+}
+
+// BulkTasks 批量操作任务
+// @Summary 批量操作任务
+// @Description 对 ids 列出或 filter 匹配的任务（至多 500 个）执行同一操作，所有修改以一次批量写入保存。
+// @Description 不存在或不属于当前用户的任务标记为 skipped 并跳过；校验失败的任务标记为 failed，其余任务照常修改。
+// @Description atomic=true 时只要有任务失败就不修改任何任务，返回 409，可修改的任务标记为 aborted。
+// @Description setStatus 按工作流校验状态、流转与 WIP 上限，同一批进入同一状态的任务合并计入上限；delete 将任务及其子任务移入回收站
+// @Description 写入在一个事务中完成（内存、SQL、副本集或分片集群上的 MongoDB），任务在读取后被其他请求修改时返回 409 且未做任何修改；
+// @Description 单机 MongoDB 不支持事务，写入中途失败时返回 409 与 partial=true，已写入的任务为 updated，中止处的任务为 failed，之后的任务为 aborted
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param body body bulkRequest true "批量操作"
+// @Success 200 {object} map[string]interface{} "逐个任务的结果"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 409 {object} map[string]interface{} "atomic 模式下有任务失败而未做任何修改，任务在读取后被其他请求修改，或 partial=true 时只写入了部分任务"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/tasks/bulk [post]
+func (d *TaskDeps) BulkTasks(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req bulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if (req.IDs == nil) == (req.Filter == nil) {
+		JSON(w, 400, map[string]string{"msg": "Provide either ids or filter"})
+		return
+	}
+	if len(req.IDs) > taskbulk.MaxTasks {
+		JSON(w, 400, map[string]string{"msg": "Too many tasks"})
+		return
+	}
+	op, ok := bulkOperation(w, &req)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	flows, ok := loadWorkflows(ctx, w, d.Workflows, uid)
+	if !ok {
+		return
+	}
+	ids := req.IDs
+	if req.Filter != nil {
+		if ids, ok = d.bulkTargets(ctx, w, uid, flows, req.Filter); !ok {
+			return
+		}
+	}
+	results, applied, err := taskbulk.Apply(ctx, d.Tasks, flows, uid, ids, op, time.Now())
+	var partial *repository.PartialError
+	switch {
+	case errors.As(err, &partial):
+	case errors.Is(err, repository.ErrVersion):
+		JSON(w, 409, map[string]string{"msg": "Tasks were modified, please retry"})
+		return
+	case err != nil:
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+
+	counts := map[string]int{}
+	out := make([]bson.M, 0, len(results))
+	for _, res := range results {
+		item := bson.M{"_id": res.ID}
+		switch {
+		case errors.Is(res.Err, repository.ErrNotFound):
+			item["status"] = "skipped"
+			item["msg"] = "Task not found"
+		case errors.Is(res.Err, taskbulk.ErrAborted):
+			item["status"] = "aborted"
+		case res.Err != nil:
+			item["status"] = "failed"
+			item["msg"] = bulkItemError(res.Err)
+		case !applied:
+			item["status"] = "aborted"
+		default:
+			item["status"] = "updated"
+			if res.Next != nil {
+				item["next"] = taskResponse(res.Next)
+			}
+		}
+		counts[item["status"].(string)]++
+		out = append(out, item)
+	}
+	resp := map[string]interface{}{
+		"applied": applied,
+		"updated": counts["updated"],
+		"skipped": counts["skipped"],
+		"failed":  counts["failed"],
+		"results": out,
+	}
+	switch {
+	case partial != nil:
+		resp["partial"] = true
+		resp["msg"] = "Bulk operation partially applied, please retry the remaining tasks"
+		JSON(w, 409, resp)
+		return
+	case !applied:
+		resp["msg"] = "Bulk operation aborted"
+		JSON(w, 409, resp)
+		return
+	}
+	JSON(w, 200, resp)
+}
+
+// bulkOperation 校验操作类型与取值，出错时写入 400 响应
+func bulkOperation(w http.ResponseWriter, req *bulkRequest) (taskbulk.Operation, bool) {
+	op := taskbulk.Operation{Op: taskbulk.Op(req.Op), Force: req.Force, Atomic: req.Atomic}
+	if !op.Op.Valid() {
+		JSON(w, 400, map[string]string{"msg": "Invalid op"})
+		return op, false
+	}
+	value := ""
+	if req.Value != nil {
+		value = *req.Value
+	}
+	switch op.Op {
+	case taskbulk.OpSetStatus, taskbulk.OpSetPriority, taskbulk.OpComment:
+		if strings.TrimSpace(value) == "" {
+			JSON(w, 400, map[string]string{"msg": "Value is required"})
+			return op, false
+		}
+		op.Status, op.Priority, op.Comment = value, value, value
+	case taskbulk.OpSetAssignee:
+		if value != "" {
+			op.Assignee = &value
+		}
+	case taskbulk.OpSetDeadline:
+		var ok bool
+		if op.Deadline, ok = queryTime(value, false); !ok {
+			JSON(w, 400, map[string]string{"msg": "Invalid deadline"})
+			return op, false
+		}
+	}
+	return op, true
+}
+
+// bulkTargets 返回 filter 匹配的任务 ID，超过上限或条件无效时写入 400 响应
+func (d *TaskDeps) bulkTargets(ctx context.Context, w http.ResponseWriter, uid string, flows *workflow.Set, f *bulkFilter) ([]string, bool) {
+	filter, msg := taskFilterFromQuery(f.values(), flows)
+	if msg != "" {
+		JSON(w, 400, map[string]string{"msg": msg})
+		return nil, false
+	}
+	cond, ok := parseTaskQuery(w, f.Q)
+	if !ok {
+		return nil, false
+	}
+	switch {
+	case filter.Query == nil:
+		filter.Query = cond
+	case cond != nil:
+		filter.Query = query.And{filter.Query, cond}
+	}
+	filter.UserID = uid
+	filter.Limit = taskbulk.MaxTasks + 1
+	tasks, err := d.Tasks.List(ctx, filter)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return nil, false
+	}
+	if len(tasks) > taskbulk.MaxTasks {
+		JSON(w, 400, map[string]string{"msg": "Too many tasks"})
+		return nil, false
+	}
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids, true
+}
+
+// bulkItemError 单个任务失败的原因，与单个更新接口的错误信息一致
+func bulkItemError(err error) string {
+	switch {
+	case errors.Is(err, workflow.ErrStatus):
+		return "Invalid status"
+	case errors.Is(err, workflow.ErrPriority):
+		return "Invalid priority"
+	case errors.Is(err, workflow.ErrTransition):
+		return "Status transition not allowed"
+	case errors.Is(err, workflow.ErrWIPLimit):
+		return "WIP limit reached"
+	case errors.Is(err, taskbulk.ErrBlocked):
+		return "Task is blocked"
+	case errors.Is(err, taskbulk.ErrRecurrence):
+		return "Invalid recurrence"
+	case errors.Is(err, taskcomment.ErrEmpty):
+		return "Comment text is required"
+	case errors.Is(err, repository.ErrVersion):
+		return "Task was modified"
+	}
+	return err.Error()
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestBulkTasks(t *testing.T) {
+	r := mux.NewRouter()
+	tasks := memory.NewTaskRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks})
+	SetupTrashRoutes(r, &TrashDeps{Tasks: tasks, Reports: memory.NewReportRepository()})
+
+	create := func(user string, body map[string]interface{}) string {
+		return decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", user, body), 200)["_id"].(string)
+	}
+	a := create("u1", map[string]interface{}{"title": "a"})
+	b := create("u1", map[string]interface{}{"title": "b", "blockedBy": []string{a}})
+	c := create("u1", map[string]interface{}{"title": "c"})
+	foreign := create("u2", map[string]interface{}{"title": "别人的"})
+
+	bulk := func(body map[string]interface{}, code int) map[string]interface{} {
+		t.Helper()
+		return decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks/bulk", "u1", body), code)
+	}
+	status := func(id string) string {
+		return decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+id, "u1", nil), 200)["status"].(string)
+	}
+
+	// 仍被阻塞时 atomic 整批失败，不修改任何任务
+	got := bulk(map[string]interface{}{"ids": []string{b, c}, "op": "setStatus", "value": "Done", "atomic": true}, 409)
+	results := got["results"].([]interface{})
+	if got["failed"] != 1.0 || results[0].(map[string]interface{})["msg"] != "Task is blocked" || results[1].(map[string]interface{})["status"] != "aborted" {
+		t.Errorf("Unexpected aborted result %v", got)
+	}
+	if status(c) != "To Do" {
+		t.Error("Expected aborted batch to change nothing")
+	}
+
+	// 同一批中一起完成的阻塞任务不再阻塞其他任务，与顺序无关；其他用户与不存在的任务被跳过
+	got = bulk(map[string]interface{}{"ids": []string{b, a, foreign, "missing"}, "op": "setStatus", "value": "Done"}, 200)
+	if got["updated"] != 2.0 || got["skipped"] != 2.0 || got["applied"] != true {
+		t.Errorf("Unexpected bulk result %v", got)
+	}
+	if status(a) != "Done" || status(b) != "Done" {
+		t.Error("Expected both tasks to be done")
+	}
+	if w := doJSON(t, r, http.MethodGet, "/api/tasks/"+foreign, "u2", nil); decodeMap(t, w, 200)["status"] != "To Do" {
+		t.Error("Other user's task must not change")
+	}
+
+	// 按条件选择任务
+	got = bulk(map[string]interface{}{"filter": map[string]interface{}{"q": "title:a"}, "op": "comment", "value": "批量备注"}, 200)
+	if got["updated"] != 1.0 {
+		t.Errorf("Expected one filtered task, got %v", got)
+	}
+	if comments := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+a, "u1", nil), 200)["comments"].([]interface{}); len(comments) != 1 {
+		t.Errorf("Expected bulk comment, got %v", comments)
+	}
+	bulk(map[string]interface{}{"filter": map[string]interface{}{"status": "Done"}, "op": "setDeadline", "value": "2024-05-01"}, 200)
+	if got := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+b, "u1", nil), 200); got["deadline"] != "2024-05-01T00:00:00Z" {
+		t.Errorf("Expected deadline to be set, got %v", got["deadline"])
+	}
+
+	// delete 将任务及其子任务移入回收站
+	decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks/"+a+"/subtasks", "u1", map[string]string{"title": "子任务"}), 200)
+	bulk(map[string]interface{}{"ids": []string{a}, "op": "delete"}, 200)
+	if trash := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/trash", "u1", nil), 200); len(trash["tasks"].([]interface{})) != 2 {
+		t.Errorf("Expected task and subtask in trash, got %v", trash["tasks"])
+	}
+
+	for name, body := range map[string]map[string]interface{}{
+		"no target":     {"op": "delete"},
+		"both targets":  {"ids": []string{b}, "filter": map[string]interface{}{}, "op": "delete"},
+		"unknown op":    {"ids": []string{b}, "op": "archive"},
+		"missing value": {"ids": []string{b}, "op": "setStatus"},
+		"bad deadline":  {"ids": []string{b}, "op": "setDeadline", "value": "tomorrow"},
+		"bad query":     {"filter": map[string]interface{}{"q": "status:("}, "op": "delete"},
+	} {
+		if w := doJSON(t, r, http.MethodPost, "/api/tasks/bulk", "u1", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, w.Code)
+		}
+	}
+}
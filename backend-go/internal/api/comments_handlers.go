@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/taskcomment"
+)
+
+type commentRequest struct {
+	Text     *string `json:"text"`
+	ParentID *string `json:"parentId"` // 仅创建时有效，为所回复的评论 ID
+}
+
+// commentError 将评论操作的错误转换为 HTTP 响应
+func commentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, taskcomment.ErrEmpty):
+		JSON(w, 400, map[string]string{"msg": "Text is required"})
+	case errors.Is(err, taskcomment.ErrParent):
+		JSON(w, 400, map[string]string{"msg": "Parent comment not found"})
+	case errors.Is(err, taskcomment.ErrNotFound):
+		JSON(w, 404, map[string]string{"msg": "Comment not found"})
+	case errors.Is(err, taskcomment.ErrForbidden):
+		JSON(w, 403, map[string]string{"msg": "Only the author can change this comment"})
+	default:
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+	}
+}
+
+// ListComments 获取任务评论
+// @Summary 获取任务评论
+// @Description 按发表时间正序返回全部评论；parentId 为所回复的评论，客户端据此组织讨论串
+// @Tags 任务管理
+// @Produce json
+// @Param id path string true "任务ID"
+// @Success 200 {object} []models.Comment "评论列表"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Router /api/tasks/{id}/comments [get]
+func (d *TaskDeps) ListComments(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, err := d.Tasks.Get(ctx, uid, muxVar(r, "id"))
+	if err != nil {
+		d.taskError(w, err)
+		return
+	}
+	if task.Comments == nil {
+		task.Comments = []models.Comment{}
+	}
+	JSON(w, 200, task.Comments)
+}
+
+// AddComment 发表评论
+// @Summary 发表任务评论
+// @Description 以当前用户身份发表评论；parentId 非空时回复同一任务中的该评论
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param comment body commentRequest true "评论，text 必填"
+// @Param If-Match header string false "GET 返回的 ETag"
+// @Success 200 {object} models.Comment "新评论"
+// @Failure 400 {object} map[string]string "内容为空或所回复的评论不存在"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
+// @Router /api/tasks/{id}/comments [post]
+func (d *TaskDeps) AddComment(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req commentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if req.Text == nil {
+		JSON(w, 400, map[string]string{"msg": "Text is required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "id"))
+	if !ok {
+		return
+	}
+	now := time.Now()
+	comment, err := taskcomment.Add(task, uid, *req.Text, req.ParentID, now)
+	if err != nil {
+		commentError(w, err)
+		return
+	}
+	task.UpdatedAt = now
+	if err := d.Tasks.Update(ctx, task); err != nil {
+		d.writeError(ctx, w, uid, task.ID, err)
+		return
+	}
+	d.setETag(ctx, w, uid, task)
+	JSON(w, 200, comment)
+}
+
+// UpdateComment 编辑评论
+// @Summary 编辑任务评论
+// @Description 只有作者可以编辑；保留发表时间，updatedAt 记录最后一次编辑的时间
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param commentId path string true "评论ID"
+// @Param comment body commentRequest true "评论，text 必填"
+// @Param If-Match header string false "GET 返回的 ETag"
+// @Success 200 {object} models.Comment "修改后的评论"
+// @Failure 400 {object} map[string]string "内容为空"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 403 {object} map[string]string "不是评论作者"
+// @Failure 404 {object} map[string]string "任务或评论不存在"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
+// @Router /api/tasks/{id}/comments/{commentId} [patch]
+func (d *TaskDeps) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req commentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if req.Text == nil {
+		JSON(w, 400, map[string]string{"msg": "Text is required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "id"))
+	if !ok {
+		return
+	}
+	now := time.Now()
+	comment, err := taskcomment.Edit(task, muxVar(r, "commentId"), uid, *req.Text, now)
+	if err != nil {
+		commentError(w, err)
+		return
+	}
+	task.UpdatedAt = now
+	if err := d.Tasks.Update(ctx, task); err != nil {
+		d.writeError(ctx, w, uid, task.ID, err)
+		return
+	}
+	d.setETag(ctx, w, uid, task)
+	JSON(w, 200, comment)
+}
+
+// DeleteComment 删除评论
+// @Summary 删除任务评论
+// @Description 只有作者可以删除；评论下的回复一并删除，commentsRemoved 为删除的评论数
+// @Tags 任务管理
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param commentId path string true "评论ID"
+// @Param If-Match header string false "GET 返回的 ETag"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 403 {object} map[string]string "不是评论作者"
+// @Failure 404 {object} map[string]string "任务或评论不存在"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
+// @Router /api/tasks/{id}/comments/{commentId} [delete]
+func (d *TaskDeps) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "id"))
+	if !ok {
+		return
+	}
+	removed, err := taskcomment.Remove(task, muxVar(r, "commentId"), uid)
+	if err != nil {
+		commentError(w, err)
+		return
+	}
+	task.UpdatedAt = time.Now()
+	if err := d.Tasks.Update(ctx, task); err != nil {
+		d.writeError(ctx, w, uid, task.ID, err)
+		return
+	}
+	d.setETag(ctx, w, uid, task)
+	JSON(w, 200, map[string]interface{}{"msg": "Comment removed", "commentsRemoved": removed})
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestComments(t *testing.T) {
+	r := mux.NewRouter()
+	tasks := memory.NewTaskRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks})
+
+	task := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{
+		"title":    "写周报",
+		"comments": []map[string]string{{"text": "先列提纲"}},
+	}), 200)
+	id := task["_id"].(string)
+	base := "/api/tasks/" + id + "/comments"
+
+	listComments := func() []models.Comment {
+		t.Helper()
+		w := doJSON(t, r, http.MethodGet, base, "u1", nil)
+		var out []models.Comment
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || w.Code != 200 {
+			t.Fatalf("List comments: %d %s", w.Code, w.Body.String())
+		}
+		return out
+	}
+	first := listComments()
+	if len(first) != 1 || first[0].ID == "" || first[0].CreatedBy != "u1" {
+		t.Fatalf("Unexpected initial comments %+v", first)
+	}
+	rootID := first[0].ID
+
+	reply := decodeMap(t, doJSON(t, r, http.MethodPost, base, "u1", map[string]string{"text": "提纲已发", "parentId": rootID}), 200)
+	if reply["parentId"] != rootID || reply["updatedAt"] != nil {
+		t.Errorf("Unexpected reply %v", reply)
+	}
+	if w := doJSON(t, r, http.MethodPost, base, "u1", map[string]string{"text": "x", "parentId": "missing"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Reply to unknown comment: expected 400, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodPost, base, "u1", map[string]string{"text": " "}); w.Code != http.StatusBadRequest {
+		t.Errorf("Blank comment: expected 400, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodPost, base, "u2", map[string]string{"text": "x"}); w.Code != http.StatusNotFound {
+		t.Errorf("Other user's task: expected 404, got %d", w.Code)
+	}
+
+	// 编辑保留发表时间与作者
+	edited := decodeMap(t, doJSON(t, r, http.MethodPatch, base+"/"+rootID, "u1", map[string]string{"text": "提纲定稿"}), 200)
+	if edited["text"] != "提纲定稿" || edited["updatedAt"] == nil || edited["createdAt"] != first[0].CreatedAt.Format(time.RFC3339Nano) {
+		t.Errorf("Unexpected edited comment %v", edited)
+	}
+	if w := doJSON(t, r, http.MethodPatch, base+"/missing", "u1", map[string]string{"text": "x"}); w.Code != http.StatusNotFound {
+		t.Errorf("Edit unknown comment: expected 404, got %d", w.Code)
+	}
+
+	// 更新任务时的 comments 追加新评论，不改写已有评论
+	decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]interface{}{
+		"comments": []map[string]string{{"text": "补充数据"}},
+	}), 200)
+	all := listComments()
+	if len(all) != 3 || all[0].ID != rootID || all[0].Text != "提纲定稿" || all[2].Text != "补充数据" {
+		t.Errorf("Expected comments to be appended, got %+v", all)
+	}
+
+	// 只有作者可以修改或删除评论
+	stored, _ := tasks.Get(context.Background(), "u1", id)
+	stored.Comments = append(stored.Comments, models.Comment{ID: "guest", Text: "导入的评论", CreatedBy: "u2", CreatedAt: time.Now()})
+	if err := tasks.Update(context.Background(), stored); err != nil {
+		t.Fatal(err)
+	}
+	if w := doJSON(t, r, http.MethodPatch, base+"/guest", "u1", map[string]string{"text": "x"}); w.Code != http.StatusForbidden {
+		t.Errorf("Edit by non-author: expected 403, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodDelete, base+"/guest", "u1", nil); w.Code != http.StatusForbidden {
+		t.Errorf("Delete by non-author: expected 403, got %d", w.Code)
+	}
+
+	// 删除评论时连同回复一起删除
+	removed := decodeMap(t, doJSON(t, r, http.MethodDelete, base+"/"+rootID, "u1", nil), 200)
+	if removed["commentsRemoved"] != float64(2) {
+		t.Errorf("Expected 2 comments removed, got %v", removed)
+	}
+	if left := listComments(); len(left) != 2 || left[0].Text != "补充数据" {
+		t.Errorf("Unexpected remaining comments %+v", left)
+	}
+}
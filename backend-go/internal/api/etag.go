@@ -0,0 +1,45 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// etag 由记录版本与 GET 返回的完整响应体生成强 ETag。响应体中包含来自其他记录的内容
+// （子任务树与完成度、报表关联的任务），这些内容变化而记录版本不变时 ETag 同样改变
+func etag(version int64, representation interface{}) string {
+	data, _ := json.Marshal(representation)
+	sum := sha256.Sum256(data)
+	return `"` + strconv.FormatInt(version, 10) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// hasIfMatch 判断请求是否携带 If-Match
+func hasIfMatch(r *http.Request) bool {
+	return len(r.Header.Values("If-Match")) > 0
+}
+
+// ifMatch 判断 If-Match 是否与记录的当前 ETag 相符；未携带该头时总是允许。
+// 支持逗号分隔的多个 ETag 与 *，弱 ETag 不参与比较
+func ifMatch(r *http.Request, current string) bool {
+	if !hasIfMatch(r) {
+		return true
+	}
+	for _, v := range r.Header.Values("If-Match") {
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.TrimSpace(tag); tag == "*" || tag == current {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// preconditionFailed 写入 412 响应，current 与 GET 的响应体相同，tag 为其 ETag，客户端据此合并后重试
+func preconditionFailed(w http.ResponseWriter, msg, tag string, current interface{}) {
+	w.Header().Set("ETag", tag)
+	JSON(w, 412, map[string]interface{}{"msg": msg, "current": current})
+}
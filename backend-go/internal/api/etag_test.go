@@ -0,0 +1,179 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+// withIfMatch 为经过 h 的请求加上 If-Match 头
+func withIfMatch(h http.Handler, tag string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("If-Match", tag)
+		h.ServeHTTP(w, r)
+	})
+}
+
+func TestConditionalWrites(t *testing.T) {
+	r := mux.NewRouter()
+	tasks := memory.NewTaskRepository()
+	reports := memory.NewReportRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks})
+	SetupReportRoutes(r, &ReportDeps{Reports: reports, Tasks: tasks})
+
+	id := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "写周报"}), 200)["_id"].(string)
+	get := func(path string) (map[string]interface{}, string) {
+		w := doJSON(t, r, http.MethodGet, path, "u1", nil)
+		return decodeMap(t, w, 200), w.Header().Get("ETag")
+	}
+	task, tag := get("/api/tasks/" + id)
+	if !strings.HasPrefix(tag, `"1-`) || task["version"] != 1.0 {
+		t.Fatalf("Expected strong ETag for version 1, got %q", tag)
+	}
+
+	// 新增子任务后任务版本不变，但响应体（子任务与完成度）变化，ETag 随之改变
+	decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "收集数据", "parentId": id}), 200)
+	if _, got := get("/api/tasks/" + id); got == tag {
+		t.Fatalf("Expected ETag to change with subtasks, got %q", got)
+	}
+	if w := doJSON(t, withIfMatch(r, tag), http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"title": "写月报"}); w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("Update after subtask change: expected 412, got %d", w.Code)
+	}
+	_, tag = get("/api/tasks/" + id)
+
+	// 第一个标签页保存成功，响应头给出新的 ETag
+	w := doJSON(t, withIfMatch(r, tag), http.MethodPut, "/api/tasks/"+id, "u1", map[string]string{"title": "写月报"})
+	updated := w.Header().Get("ETag")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Update with current ETag: %d %s", w.Code, w.Body.String())
+	}
+	if _, got := get("/api/tasks/" + id); got != updated {
+		t.Errorf("Expected ETag %q after update, GET returned %q", updated, got)
+	}
+
+	// 第二个标签页基于旧版本保存，返回 412 与 GET 形状相同的当前内容，不覆盖
+	w = doJSON(t, withIfMatch(r, tag), http.MethodPut, "/api/tasks/"+id, "u1", map[string]string{"title": "写日报"})
+	stale := decodeMap(t, w, http.StatusPreconditionFailed)
+	current, _ := stale["current"].(map[string]interface{})
+	want, _ := get("/api/tasks/" + id)
+	if w.Header().Get("ETag") != updated || !reflect.DeepEqual(current, want) {
+		t.Errorf("Expected GET representation with 412, got %v (ETag %q)", stale, w.Header().Get("ETag"))
+	}
+	if subtasks, _ := current["subtasks"].([]interface{}); len(subtasks) != 1 || current["progress"] == nil {
+		t.Errorf("Expected subtasks and progress in 412 body, got %v", current)
+	}
+	if w = doJSON(t, withIfMatch(r, tag), http.MethodDelete, "/api/tasks/"+id, "u1", nil); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Delete with stale ETag: expected 412, got %d", w.Code)
+	}
+	decodeMap(t, doJSON(t, withIfMatch(r, `"9-0", `+updated), http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"priority": "High"}), 200)
+	decodeMap(t, doJSON(t, withIfMatch(r, "*"), http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"priority": "Low"}), 200)
+	_, tag = get("/api/tasks/" + id)
+	if w = doJSON(t, withIfMatch(r, "W/"+tag), http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"priority": "High"}); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Weak ETag: expected 412, got %d", w.Code)
+	}
+	// 不携带 If-Match 时照常修改
+	decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"priority": "High"}), 200)
+	_, tag = get("/api/tasks/" + id)
+	decodeMap(t, doJSON(t, withIfMatch(r, tag), http.MethodDelete, "/api/tasks/"+id, "u1", nil), 200)
+
+	other := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "整理文档"}), 200)["_id"].(string)
+	rep := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/reports/generate", "u1", map[string]string{
+		"type": "weekly", "period": "W1", "startDate": "2000-01-01", "endDate": "2999-12-31"}), 200)["_id"].(string)
+	_, tag = get("/api/reports/" + rep)
+	if !strings.HasPrefix(tag, `"1-`) {
+		t.Errorf("Expected strong report ETag for version 1, got %q", tag)
+	}
+	// 关联的任务变化时报表的 ETag 同样改变
+	decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+other, "u1", map[string]string{"status": "Done"}), 200)
+	if _, got := get("/api/reports/" + rep); got == tag {
+		t.Errorf("Expected report ETag to change with its tasks, got %q", got)
+	}
+	decodeMap(t, doJSON(t, r, http.MethodPost, "/api/reports/"+rep+"/polish", "u1", nil), 200)
+	w = doJSON(t, withIfMatch(r, tag), http.MethodDelete, "/api/reports/"+rep, "u1", nil)
+	want, tag = get("/api/reports/" + rep)
+	if current, _ := decodeMap(t, w, http.StatusPreconditionFailed)["current"].(map[string]interface{}); !reflect.DeepEqual(current, want) || current["version"] != 2.0 {
+		t.Errorf("Expected GET representation of report with 412, got %v", current)
+	}
+	decodeMap(t, doJSON(t, withIfMatch(r, tag), http.MethodDelete, "/api/reports/"+rep, "u1", nil), 200)
+}
+
+func TestIfMatch(t *testing.T) {
+	for _, tc := range []struct {
+		header []string
+		want   bool
+	}{
+		{nil, true},
+		{[]string{`"3"`}, true},
+		{[]string{`"2"`}, false},
+		{[]string{`"1", "3"`}, true},
+		{[]string{`"1"`, `"3"`}, true},
+		{[]string{"*"}, true},
+		{[]string{`W/"3"`}, false},
+		{[]string{"3"}, false},
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/", nil)
+		for _, h := range tc.header {
+			req.Header.Add("If-Match", h)
+		}
+		if got := ifMatch(req, `"3"`); got != tc.want {
+			t.Errorf("ifMatch(%q) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestConditionalSubresourceWrites(t *testing.T) {
+	r := mux.NewRouter()
+	tasks := memory.NewTaskRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks})
+	SetupReportRoutes(r, &ReportDeps{Reports: memory.NewReportRepository(), Tasks: tasks})
+	get := func(path string) (map[string]interface{}, string) {
+		w := doJSON(t, r, http.MethodGet, path, "u1", nil)
+		return decodeMap(t, w, 200), w.Header().Get("ETag")
+	}
+
+	id := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "发布"}), 200)["_id"].(string)
+	sub := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "打包"}), 200)["_id"].(string)
+	_, stale := get("/api/tasks/" + id)
+
+	// 评论、检查项与子任务的写入同样按 If-Match 校验，成功时返回新的 ETag
+	w := doJSON(t, withIfMatch(r, stale), http.MethodPost, "/api/tasks/"+id+"/comments", "u1", map[string]string{"text": "先合并"})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == stale {
+		t.Fatalf("Comment with current ETag: %d, ETag %q", w.Code, w.Header().Get("ETag"))
+	}
+	writes := []struct {
+		method, path string
+		tag          string
+		body         interface{}
+	}{
+		{http.MethodPost, "/api/tasks/" + id + "/comments", stale, map[string]string{"text": "再发布"}},
+		{http.MethodPost, "/api/tasks/" + id + "/checklist", stale, map[string]string{"text": "写变更日志"}},
+		{http.MethodPut, "/api/tasks/" + id + "/subtasks/" + sub, `"9-0"`, nil},
+		{http.MethodDelete, "/api/tasks/" + id + "/subtasks/" + sub, `"9-0"`, nil},
+	}
+	for _, tc := range writes {
+		w := doJSON(t, withIfMatch(r, tc.tag), tc.method, tc.path, "u1", tc.body)
+		current, _ := decodeMap(t, w, http.StatusPreconditionFailed)["current"].(map[string]interface{})
+		if current == nil || current["_id"] == nil {
+			t.Errorf("%s %s: expected current representation, got %s", tc.method, tc.path, w.Body.String())
+		}
+	}
+	if task, _ := get("/api/tasks/" + id); len(task["comments"].([]interface{})) != 1 || len(task["subtasks"].([]interface{})) != 0 {
+		t.Errorf("Expected stale writes to be rejected, got %v", task)
+	}
+
+	rep := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/reports/generate", "u1", map[string]string{
+		"type": "weekly", "period": "W1", "startDate": "2000-01-01", "endDate": "2999-12-31"}), 200)["_id"].(string)
+	_, tag := get("/api/reports/" + rep)
+	decodeMap(t, doJSON(t, withIfMatch(r, tag), http.MethodPost, "/api/reports/"+rep+"/polish", "u1", nil), 200)
+	w = doJSON(t, withIfMatch(r, tag), http.MethodPost, "/api/reports/"+rep+"/polish", "u1", nil)
+	want, _ := get("/api/reports/" + rep)
+	if current, _ := decodeMap(t, w, http.StatusPreconditionFailed)["current"].(map[string]interface{}); !reflect.DeepEqual(current, want) {
+		t.Errorf("Expected GET representation of report with 412, got %v", current)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/depgraph"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GraphTasks 获取任务依赖图与关键路径
+// @Summary 获取任务依赖图
+// @Description 返回参与依赖关系的任务（nodes）与依赖边（edges，from 阻塞 to），以及 target 的关键路径。
+// @Description earliestFinish 为考虑未完成阻塞任务的截止日期后最早可完成的时间，late 表示它晚于任务自身的截止日期。
+// @Description 未指定 target 时取最拖后的未完成任务；criticalPath 从最上游的阻塞任务排到 target
+// @Tags 任务管理
+// @Produce json
+// @Param target query string false "目标任务ID"
+// @Success 200 {object} map[string]interface{} "依赖图"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "目标任务不存在"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/tasks/graph [get]
+func (d *TaskDeps) GraphTasks(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	flows, ok := loadWorkflows(ctx, w, d.Workflows, uid)
+	if !ok {
+		return
+	}
+	g, err := depgraph.Load(ctx, d.Tasks, flows, uid)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	plan, err := g.CriticalPath(r.URL.Query().Get("target"))
+	if errors.Is(err, repository.ErrNotFound) {
+		JSON(w, 404, map[string]string{"msg": "Task not found"})
+		return
+	}
+	nodes := make([]bson.M, 0, len(plan.Nodes))
+	for _, n := range plan.Nodes {
+		resp := taskResponse(n.Task)
+		resp["earliestFinish"] = n.EarliestFinish
+		resp["late"] = n.Late
+		resp["critical"] = n.Critical
+		nodes = append(nodes, resp)
+	}
+	edges := plan.Edges
+	if edges == nil {
+		edges = []depgraph.Edge{}
+	}
+	JSON(w, 200, map[string]interface{}{
+		"nodes":        nodes,
+		"edges":        edges,
+		"target":       plan.Target,
+		"criticalPath": plan.CriticalPath,
+	})
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestDependenciesAndGraph(t *testing.T) {
+	r := mux.NewRouter()
+	tasks := memory.NewTaskRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks})
+	SetupTrashRoutes(r, &TrashDeps{Tasks: tasks, Reports: memory.NewReportRepository()})
+
+	create := func(body map[string]interface{}) string {
+		return decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", body), 200)["_id"].(string)
+	}
+	design := create(map[string]interface{}{"title": "设计", "deadline": "2024-03-10"})
+	api := create(map[string]interface{}{"title": "接口", "deadline": "2024-03-05", "blockedBy": []string{design}})
+	docs := create(map[string]interface{}{"title": "文档", "deadline": "2024-03-01"})
+	release := create(map[string]interface{}{"title": "发布", "deadline": "2024-03-08", "blockedBy": []string{api, docs, api}})
+	if w := doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{"title": "x", "blockedBy": []string{"missing"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Missing blocker: expected 400, got %d", w.Code)
+	}
+
+	// 成环时返回路径
+	cycle := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+design, "u1", map[string]interface{}{"blockedBy": []string{release}}), 409)
+	if path := cycle["path"].([]interface{}); len(path) != 4 || path[0].(map[string]interface{})["title"] != "设计" || path[3].(map[string]interface{})["_id"] != design {
+		t.Errorf("Unexpected cycle path %v", cycle["path"])
+	}
+
+	// 关键路径：设计 → 接口 → 发布，发布最早 3-10 完成，晚于其截止日期
+	graph := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/graph", "u1", nil), 200)
+	critical := graph["criticalPath"].([]interface{})
+	if graph["target"] != release || len(critical) != 3 || critical[0] != design || critical[2] != release {
+		t.Errorf("Unexpected critical path %v (target %v)", critical, graph["target"])
+	}
+	if nodes, edges := graph["nodes"].([]interface{}), graph["edges"].([]interface{}); len(nodes) != 4 || len(edges) != 3 {
+		t.Errorf("Expected 4 nodes and 3 edges, got %d/%d", len(nodes), len(edges))
+	}
+	for _, n := range graph["nodes"].([]interface{}) {
+		node := n.(map[string]interface{})
+		if node["_id"] == release && (node["late"] != true || node["critical"] != true) {
+			t.Errorf("Expected release to be late and critical, got %v", node)
+		}
+	}
+	if w := doJSON(t, r, http.MethodGet, "/api/tasks/graph?target=missing", "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Missing target: expected 404, got %d", w.Code)
+	}
+
+	// 被阻塞的任务不能直接完成
+	blocked := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+release, "u1", map[string]string{"status": "Done"}), 409)
+	if len(blocked["blockedBy"].([]interface{})) != 2 {
+		t.Errorf("Expected 2 open blockers, got %v", blocked["blockedBy"])
+	}
+	decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+release+"?force=true", "u1", map[string]string{"status": "Done"}), 200)
+
+	// 移入回收站后保留依赖以便恢复，永久删除后从阻塞列表中移除
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/tasks/"+docs, "u1", nil), 200)
+	if got := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+release, "u1", nil), 200); len(got["blockedBy"].([]interface{})) != 2 {
+		t.Errorf("Expected trashed docs to stay linked, got %v", got["blockedBy"])
+	}
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/trash/tasks/"+docs, "u1", nil), 200)
+	if got := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+release, "u1", nil), 200); len(got["blockedBy"].([]interface{})) != 1 {
+		t.Errorf("Expected docs to be unlinked, got %v", got["blockedBy"])
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// ListHistory 获取任务历史
+// @Summary 获取任务历史
+// @Description 按时间正序返回任务的创建、修改、评论与删除记录，每条记录包含操作者与各字段的新旧值；任务删除后仍可查询
+// @Tags 任务管理
+// @Produce json
+// @Param id path string true "任务ID"
+// @Success 200 {object} []models.TaskEvent "历史记录"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Router /api/tasks/{id}/history [get]
+func (d *TaskDeps) ListHistory(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	id := muxVar(r, "id")
+	events := []models.TaskEvent{}
+	if d.History != nil {
+		var err error
+		events, err = d.History.List(ctx, repository.HistoryFilter{UserID: uid, TaskIDs: []string{id}})
+		if err != nil {
+			JSON(w, 500, map[string]string{"msg": "DB error"})
+			return
+		}
+	}
+	// 没有历史时区分任务不存在与历史功能启用前创建的任务
+	if len(events) == 0 {
+		if _, err := d.Tasks.Get(ctx, uid, id); err != nil {
+			d.taskError(w, err)
+			return
+		}
+	}
+	JSON(w, 200, events)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+	"github.com/axfinn/todoIng/backend-go/internal/taskhistory"
+)
+
+func TestHistory(t *testing.T) {
+	r := mux.NewRouter()
+	history := memory.NewHistoryRepository()
+	tasks := taskhistory.NewTaskRepository(memory.NewTaskRepository(), history)
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks, History: history})
+	SetupReportRoutes(r, &ReportDeps{Reports: memory.NewReportRepository(), Tasks: tasks, History: history})
+
+	task := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "写周报", "status": "To Do"}), 200)
+	id := task["_id"].(string)
+	decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"status": "Done"}), 200)
+	decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks/"+id+"/comments", "u1", map[string]string{"text": "已发"}), 200)
+
+	listHistory := func() []models.TaskEvent {
+		t.Helper()
+		w := doJSON(t, r, http.MethodGet, "/api/tasks/"+id+"/history", "u1", nil)
+		var out []models.TaskEvent
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || w.Code != 200 {
+			t.Fatalf("List history: %d %s", w.Code, w.Body.String())
+		}
+		return out
+	}
+	events := listHistory()
+	if len(events) != 3 || events[0].Action != models.ActionCreated || events[1].Action != models.ActionUpdated ||
+		events[2].Action != models.ActionCommented || events[1].Actor != "u1" {
+		t.Fatalf("Unexpected history %+v", events)
+	}
+	if c := events[1].Changes; len(c) != 1 || c[0].Field != "status" || *c[0].Old != "To Do" || *c[0].New != "Done" {
+		t.Errorf("Unexpected status change %+v", c)
+	}
+
+	// 报表时间线展示周期内的状态变化
+	rep := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/reports/generate", "u1", map[string]string{
+		"type": "weekly", "period": "W1", "startDate": "2000-01-01", "endDate": "2999-12-31"}), 200)
+	if content := rep["content"].(string); !strings.Contains(content, "状态 To Do → Done") || !strings.Contains(content, "新增 1 条评论") {
+		t.Errorf("Expected status transition in report timeline, got %q", content)
+	}
+
+	if w := doJSON(t, r, http.MethodGet, "/api/tasks/"+id+"/history", "u2", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Other user's task: expected 404, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodGet, "/api/tasks/missing/history", "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Unknown task: expected 404, got %d", w.Code)
+	}
+
+	// 任务删除后历史仍可查询
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/tasks/"+id, "u1", nil), 200)
+	if events := listHistory(); len(events) != 4 || events[3].Action != models.ActionDeleted {
+		t.Errorf("Expected deletion recorded, got %+v", events)
+	}
+}
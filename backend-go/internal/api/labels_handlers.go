@@ -0,0 +1,325 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/tasklabel"
+)
+
+// LabelDeps 标签管理；Tasks 用于重命名、合并与删除标签时同步改写任务
+type LabelDeps struct {
+	Labels repository.LabelRepository
+	Tasks  repository.TaskRepository
+}
+
+type labelRequest struct {
+	Name        *string `json:"name"`
+	Color       *string `json:"color"` // #RRGGBB，空字符串表示不设颜色
+	Description *string `json:"description"`
+}
+
+// labelResponse 与任务、报表一致使用 _id 字段
+func labelResponse(l *models.Label) bson.M {
+	return bson.M{
+		"_id":         l.ID,
+		"name":        l.Name,
+		"color":       l.Color,
+		"description": l.Description,
+		"createdAt":   l.CreatedAt,
+		"updatedAt":   l.UpdatedAt,
+	}
+}
+
+// labelError 将存储错误转换为 HTTP 响应
+func labelError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		JSON(w, 404, map[string]string{"msg": "Label not found"})
+	case errors.Is(err, repository.ErrConflict):
+		JSON(w, 409, map[string]string{"msg": "Label name already exists"})
+	case errors.Is(err, tasklabel.ErrInvalid):
+		JSON(w, 400, map[string]string{"msg": "Invalid label", "error": "name must be 1-50 characters and color must be #RRGGBB"})
+	default:
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+	}
+}
+
+// ListLabels 获取标签列表
+// @Summary 获取用户的标签
+// @Description 按名称升序返回当前用户的全部标签
+// @Tags 标签管理
+// @Produce json
+// @Success 200 {object} []map[string]interface{} "标签列表"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/labels [get]
+func (d *LabelDeps) ListLabels(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	labels, err := d.Labels.List(ctx, uid)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	out := make([]bson.M, 0, len(labels))
+	for i := range labels {
+		out = append(out, labelResponse(&labels[i]))
+	}
+	JSON(w, 200, out)
+}
+
+// CreateLabel 创建标签
+// @Summary 创建标签
+// @Description 名称在当前用户内不区分大小写唯一，最多 50 个字符；颜色为 #RRGGBB，可为空
+// @Tags 标签管理
+// @Accept json
+// @Produce json
+// @Param label body labelRequest true "标签信息"
+// @Success 200 {object} map[string]interface{} "创建成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 409 {object} map[string]string "标签重名"
+// @Router /api/labels [post]
+func (d *LabelDeps) CreateLabel(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req labelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if req.Name == nil {
+		JSON(w, 400, map[string]string{"msg": "Name is required"})
+		return
+	}
+	now := time.Now()
+	label := &models.Label{UserID: uid, Name: *req.Name, CreatedAt: now, UpdatedAt: now}
+	if req.Color != nil {
+		label.Color = *req.Color
+	}
+	if req.Description != nil {
+		label.Description = *req.Description
+	}
+	if err := tasklabel.Validate(label); err != nil {
+		labelError(w, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if err := d.Labels.Create(ctx, label); err != nil {
+		labelError(w, err)
+		return
+	}
+	JSON(w, 200, labelResponse(label))
+}
+
+// GetLabel 获取标签详情
+// @Summary 获取标签详情
+// @Tags 标签管理
+// @Produce json
+// @Param id path string true "标签ID"
+// @Success 200 {object} map[string]interface{} "标签详情"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "标签不存在"
+// @Router /api/labels/{id} [get]
+func (d *LabelDeps) GetLabel(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	label, err := d.Labels.Get(ctx, uid, mux.Vars(r)["id"])
+	if err != nil {
+		labelError(w, err)
+		return
+	}
+	JSON(w, 200, labelResponse(label))
+}
+
+// UpdateLabel 修改标签
+// @Summary 修改标签
+// @Description 未提供的字段保持不变；修改名称时同步改写所有带有该标签的任务，tasksUpdated 为被改写的任务数
+// @Tags 标签管理
+// @Accept json
+// @Produce json
+// @Param id path string true "标签ID"
+// @Param label body labelRequest true "标签信息"
+// @Success 200 {object} map[string]interface{} "修改后的标签"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "标签不存在"
+// @Failure 409 {object} map[string]string "标签重名"
+// @Router /api/labels/{id} [put]
+func (d *LabelDeps) UpdateLabel(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req labelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if req.Name == nil && req.Color == nil && req.Description == nil {
+		JSON(w, 400, map[string]string{"msg": "No fields to update"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	label, err := d.Labels.Get(ctx, uid, mux.Vars(r)["id"])
+	if err != nil {
+		labelError(w, err)
+		return
+	}
+	oldName := label.Name
+	if req.Name != nil {
+		label.Name = *req.Name
+	}
+	if req.Color != nil {
+		label.Color = *req.Color
+	}
+	if req.Description != nil {
+		label.Description = *req.Description
+	}
+	if err := tasklabel.Validate(label); err != nil {
+		labelError(w, err)
+		return
+	}
+	label.UpdatedAt = time.Now()
+	if err := d.Labels.Update(ctx, label); err != nil {
+		labelError(w, err)
+		return
+	}
+	updated := 0
+	if label.Name != oldName {
+		if updated, err = tasklabel.Rewrite(ctx, d.Tasks, uid, []string{oldName}, label.Name, label.UpdatedAt); err != nil {
+			JSON(w, 500, map[string]string{"msg": "DB error"})
+			return
+		}
+	}
+	resp := labelResponse(label)
+	resp["tasksUpdated"] = updated
+	JSON(w, 200, resp)
+}
+
+// DeleteLabel 删除标签
+// @Summary 删除标签
+// @Description 删除标签并从所有任务中移除，tasksUpdated 为被改写的任务数
+// @Tags 标签管理
+// @Produce json
+// @Param id path string true "标签ID"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "标签不存在"
+// @Router /api/labels/{id} [delete]
+func (d *LabelDeps) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	label, err := d.Labels.Get(ctx, uid, mux.Vars(r)["id"])
+	if err != nil {
+		labelError(w, err)
+		return
+	}
+	if err := d.Labels.Delete(ctx, uid, label.ID); err != nil {
+		labelError(w, err)
+		return
+	}
+	updated, err := tasklabel.Rewrite(ctx, d.Tasks, uid, []string{label.Name}, "", time.Now())
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	JSON(w, 200, map[string]interface{}{"msg": "Label removed", "tasksUpdated": updated})
+}
+
+// MergeLabel 合并标签
+// @Summary 合并标签
+// @Description 将该标签合并到 into 指定的标签：带有该标签的任务改为带有目标标签，随后删除该标签
+// @Tags 标签管理
+// @Accept json
+// @Produce json
+// @Param id path string true "被合并的标签ID"
+// @Param body body object true "目标标签，如 {\"into\": \"<标签ID>\"}"
+// @Success 200 {object} map[string]interface{} "目标标签及被改写的任务数"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "标签不存在"
+// @Router /api/labels/{id}/merge [post]
+func (d *LabelDeps) MergeLabel(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req struct {
+		Into string `json:"into"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Into == "" {
+		JSON(w, 400, map[string]string{"msg": "Target label is required"})
+		return
+	}
+	id := mux.Vars(r)["id"]
+	if req.Into == id {
+		JSON(w, 400, map[string]string{"msg": "Cannot merge a label into itself"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	source, err := d.Labels.Get(ctx, uid, id)
+	if err != nil {
+		labelError(w, err)
+		return
+	}
+	target, err := d.Labels.Get(ctx, uid, req.Into)
+	if err != nil {
+		labelError(w, err)
+		return
+	}
+	updated, err := tasklabel.Rewrite(ctx, d.Tasks, uid, []string{source.Name}, target.Name, time.Now())
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	if err := d.Labels.Delete(ctx, uid, source.ID); err != nil {
+		labelError(w, err)
+		return
+	}
+	resp := labelResponse(target)
+	resp["tasksUpdated"] = updated
+	JSON(w, 200, resp)
+}
+
+func SetupLabelRoutes(r *mux.Router, deps *LabelDeps) {
+	s := r.PathPrefix("/api/labels").Subrouter()
+	s.Handle("", Auth(http.HandlerFunc(deps.ListLabels))).Methods(http.MethodGet)
+	s.Handle("", Auth(http.HandlerFunc(deps.CreateLabel))).Methods(http.MethodPost)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.GetLabel))).Methods(http.MethodGet)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.UpdateLabel))).Methods(http.MethodPut)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.DeleteLabel))).Methods(http.MethodDelete)
+	s.Handle("/{id}/merge", Auth(http.HandlerFunc(deps.MergeLabel))).Methods(http.MethodPost)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestLabels(t *testing.T) {
+	r := mux.NewRouter()
+	tasks, labels := memory.NewTaskRepository(), memory.NewLabelRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks, Labels: labels})
+	SetupLabelRoutes(r, &LabelDeps{Labels: labels, Tasks: tasks})
+
+	newLabel := func(name string) string {
+		return decodeMap(t, doJSON(t, r, http.MethodPost, "/api/labels", "u1", map[string]string{"name": name, "color": "#00aa00"}), 200)["_id"].(string)
+	}
+	bug := newLabel(" Bug ")
+	defect := newLabel("defect")
+	work := newLabel("工作")
+	if w := doJSON(t, r, http.MethodPost, "/api/labels", "u1", map[string]string{"name": "BUG"}); w.Code != http.StatusConflict {
+		t.Errorf("Duplicate name: expected 409, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodPost, "/api/labels", "u1", map[string]string{"name": "x", "color": "green"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Invalid color: expected 400, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodGet, "/api/labels/"+bug, "u2", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Other user's label: expected 404, got %d", w.Code)
+	}
+
+	// 任务按名称引用标签，不区分大小写并规范为标签名称
+	task := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{
+		"title": "修复登录", "labels": []string{"bug", "DEFECT", "Bug"}}), 200)
+	id := task["_id"].(string)
+	if got := task["labels"].([]interface{}); len(got) != 2 || got[0] != "Bug" || got[1] != "defect" {
+		t.Errorf("Expected labels [Bug defect], got %v", got)
+	}
+	other := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{
+		"title": "写文档", "labels": []string{"工作"}}), 200)["_id"].(string)
+	if w := doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]interface{}{"labels": []string{"missing"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Unknown label: expected 400, got %d", w.Code)
+	}
+
+	listTasks := func(query string) []interface{} {
+		t.Helper()
+		w := doJSON(t, r, http.MethodGet, "/api/tasks?"+query, "u1", nil)
+		var out []interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || w.Code != 200 {
+			t.Fatalf("%s: %d %s", query, w.Code, w.Body.String())
+		}
+		return out
+	}
+	if got := listTasks("label=defect&label=工作"); len(got) != 2 {
+		t.Errorf("Expected 2 tasks labeled defect or 工作, got %d", len(got))
+	}
+	if got := listTasks("q=tag:none"); len(got) != 0 {
+		t.Errorf("Expected no unlabeled tasks, got %d", len(got))
+	}
+
+	// 重命名与合并同步改写任务
+	renamed := decodeMap(t, doJSON(t, r, http.MethodPut, "/api/labels/"+bug, "u1", map[string]string{"name": "缺陷"}), 200)
+	if renamed["name"] != "缺陷" || renamed["tasksUpdated"] != float64(1) {
+		t.Errorf("Unexpected rename response %v", renamed)
+	}
+	if w := doJSON(t, r, http.MethodPut, "/api/labels/"+bug, "u1", map[string]string{"name": "DEFECT"}); w.Code != http.StatusConflict {
+		t.Errorf("Rename onto existing label: expected 409, got %d", w.Code)
+	}
+	merged := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/labels/"+defect+"/merge", "u1", map[string]string{"into": bug}), 200)
+	if merged["name"] != "缺陷" || merged["tasksUpdated"] != float64(1) {
+		t.Errorf("Unexpected merge response %v", merged)
+	}
+	got := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+id, "u1", nil), 200)
+	if labels := got["labels"].([]interface{}); len(labels) != 1 || labels[0] != "缺陷" {
+		t.Errorf("Expected labels [缺陷] after rename and merge, got %v", labels)
+	}
+	if w := doJSON(t, r, http.MethodGet, "/api/labels/"+defect, "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Merged label: expected 404, got %d", w.Code)
+	}
+
+	// 删除标签后从任务中移除
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/labels/"+work, "u1", nil), 200)
+	if got := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+other, "u1", nil), 200); len(got["labels"].([]interface{})) != 0 {
+		t.Errorf("Expected no labels after delete, got %v", got["labels"])
+	}
+
+	w := doJSON(t, r, http.MethodGet, "/api/labels", "u1", nil)
+	var list []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 1 || list[0]["name"] != "缺陷" {
+		t.Errorf("Expected only 缺陷 left, got %s", w.Body.String())
+	}
+}
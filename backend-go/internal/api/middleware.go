@@ -9,6 +9,7 @@ import (
 
 	"github.com/axfinn/todoIng/backend-go/internal/auth"
 	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/taskhistory"
 	"github.com/gorilla/mux"
 )
 
@@ -70,7 +71,8 @@ func Auth(next http.Handler) http.Handler {
 			http.Error(w, "Token invalid", http.StatusUnauthorized)
 			return
 		}
-		ctx := context.WithValue(r.Context(), userKey, claims.UserID)
+		// 同时记为任务历史的操作者
+		ctx := taskhistory.WithActor(context.WithValue(r.Context(), userKey, claims.UserID), claims.UserID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
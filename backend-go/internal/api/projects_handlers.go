@@ -0,0 +1,450 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/taskproject"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+// ProjectDeps 项目管理；Tasks 用于统计、移动任务以及删除项目时移出任务，
+// Workflows 用于按状态类别统计以及删除项目时删除项目的工作流
+type ProjectDeps struct {
+	Projects  repository.ProjectRepository
+	Tasks     repository.TaskRepository
+	Workflows repository.WorkflowRepository
+}
+
+type projectRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Archived    *bool   `json:"archived"`
+	Position    *int    `json:"position"` // 创建时省略表示排在最后
+}
+
+// projectResponse 与任务、报表一致使用 _id 字段，附带项目内任务的统计
+func projectResponse(p *models.Project, stats models.Statistics) bson.M {
+	return bson.M{
+		"_id":         p.ID,
+		"name":        p.Name,
+		"description": p.Description,
+		"archived":    p.Archived,
+		"position":    p.Position,
+		"statistics":  stats,
+		"createdAt":   p.CreatedAt,
+		"updatedAt":   p.UpdatedAt,
+	}
+}
+
+// projectError 将存储错误转换为 HTTP 响应
+func projectError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		JSON(w, 404, map[string]string{"msg": "Project not found"})
+	case errors.Is(err, taskproject.ErrArchived):
+		JSON(w, 409, map[string]string{"msg": "Project is archived"})
+	case errors.Is(err, taskproject.ErrInvalid):
+		JSON(w, 400, map[string]string{"msg": "Invalid project", "error": "name must be 1-100 characters"})
+	default:
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+	}
+}
+
+// stats 按用户的工作流统计各项目的任务
+func (d *ProjectDeps) stats(ctx context.Context, uid string, ids []string, now time.Time) (map[string]models.Statistics, error) {
+	flows, err := workflow.Load(ctx, d.Workflows, uid)
+	if err != nil {
+		return nil, err
+	}
+	return taskproject.Stats(ctx, d.Tasks, flows, uid, ids, now)
+}
+
+// respondProject 统计项目内的任务并写入响应
+func (d *ProjectDeps) respondProject(ctx context.Context, w http.ResponseWriter, p *models.Project) {
+	stats, err := d.stats(ctx, p.UserID, []string{p.ID}, time.Now())
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	JSON(w, 200, projectResponse(p, stats[p.ID]))
+}
+
+// ListProjects 获取项目列表
+// @Summary 获取用户的项目
+// @Description 按位置升序返回当前用户的项目，每个项目附带任务统计；archived 为 true/false 时只返回已归档/未归档的项目
+// @Tags 项目管理
+// @Produce json
+// @Param archived query bool false "按归档状态过滤"
+// @Success 200 {object} []map[string]interface{} "项目列表"
+// @Failure 400 {object} map[string]string "查询参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/projects [get]
+func (d *ProjectDeps) ListProjects(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	archived := r.URL.Query().Get("archived")
+	if archived != "" && archived != "true" && archived != "false" {
+		JSON(w, 400, map[string]string{"msg": "Invalid archived"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	projects, err := d.Projects.List(ctx, uid)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	ids := make([]string, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+	stats, err := d.stats(ctx, uid, ids, time.Now())
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	out := make([]bson.M, 0, len(projects))
+	for i := range projects {
+		p := &projects[i]
+		if archived != "" && p.Archived != (archived == "true") {
+			continue
+		}
+		out = append(out, projectResponse(p, stats[p.ID]))
+	}
+	JSON(w, 200, out)
+}
+
+// CreateProject 创建项目
+// @Summary 创建项目
+// @Description 名称最多 100 个字符；未指定 position 时排在现有项目之后
+// @Tags 项目管理
+// @Accept json
+// @Produce json
+// @Param project body projectRequest true "项目信息"
+// @Success 200 {object} map[string]interface{} "创建成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/projects [post]
+func (d *ProjectDeps) CreateProject(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req projectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if req.Name == nil {
+		JSON(w, 400, map[string]string{"msg": "Name is required"})
+		return
+	}
+	now := time.Now()
+	project := &models.Project{UserID: uid, Name: *req.Name, CreatedAt: now, UpdatedAt: now}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	if req.Archived != nil {
+		project.Archived = *req.Archived
+	}
+	if err := taskproject.Validate(project); err != nil {
+		projectError(w, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if req.Position != nil {
+		project.Position = *req.Position
+	} else {
+		existing, err := d.Projects.List(ctx, uid)
+		if err != nil {
+			JSON(w, 500, map[string]string{"msg": "DB error"})
+			return
+		}
+		project.Position = taskproject.NextPosition(existing)
+	}
+	if err := d.Projects.Create(ctx, project); err != nil {
+		projectError(w, err)
+		return
+	}
+	JSON(w, 200, projectResponse(project, models.Statistics{}))
+}
+
+// GetProject 获取项目详情
+// @Summary 获取项目详情
+// @Description 返回项目及其任务统计，统计口径与报表一致
+// @Tags 项目管理
+// @Produce json
+// @Param id path string true "项目ID"
+// @Success 200 {object} map[string]interface{} "项目详情"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "项目不存在"
+// @Router /api/projects/{id} [get]
+func (d *ProjectDeps) GetProject(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	project, err := d.Projects.Get(ctx, uid, mux.Vars(r)["id"])
+	if err != nil {
+		projectError(w, err)
+		return
+	}
+	d.respondProject(ctx, w, project)
+}
+
+// UpdateProject 修改项目
+// @Summary 修改项目
+// @Description 未提供的字段保持不变；archived=true 归档项目，归档后的项目保留任务但不能再加入任务
+// @Tags 项目管理
+// @Accept json
+// @Produce json
+// @Param id path string true "项目ID"
+// @Param project body projectRequest true "项目信息"
+// @Success 200 {object} map[string]interface{} "修改后的项目"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "项目不存在"
+// @Router /api/projects/{id} [put]
+func (d *ProjectDeps) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req projectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if req.Name == nil && req.Description == nil && req.Archived == nil && req.Position == nil {
+		JSON(w, 400, map[string]string{"msg": "No fields to update"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	project, err := d.Projects.Get(ctx, uid, mux.Vars(r)["id"])
+	if err != nil {
+		projectError(w, err)
+		return
+	}
+	if req.Name != nil {
+		project.Name = *req.Name
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	if req.Archived != nil {
+		project.Archived = *req.Archived
+	}
+	if req.Position != nil {
+		project.Position = *req.Position
+	}
+	if err := taskproject.Validate(project); err != nil {
+		projectError(w, err)
+		return
+	}
+	project.UpdatedAt = time.Now()
+	if err := d.Projects.Update(ctx, project); err != nil {
+		projectError(w, err)
+		return
+	}
+	d.respondProject(ctx, w, project)
+}
+
+// DeleteProject 删除项目
+// @Summary 删除项目
+// @Description 删除项目及其工作流，项目内的任务保留并移出项目，tasksUpdated 为被移出的任务数
+// @Tags 项目管理
+// @Produce json
+// @Param id path string true "项目ID"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "项目不存在"
+// @Router /api/projects/{id} [delete]
+func (d *ProjectDeps) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	id := mux.Vars(r)["id"]
+	if err := d.Projects.Delete(ctx, uid, id); err != nil {
+		projectError(w, err)
+		return
+	}
+	updated, err := taskproject.Detach(ctx, d.Tasks, uid, id, time.Now())
+	if err == nil {
+		err = d.deleteWorkflow(ctx, uid, id)
+	}
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	JSON(w, 200, map[string]interface{}{"msg": "Project removed", "tasksUpdated": updated})
+}
+
+// deleteWorkflow 删除项目的工作流，项目没有工作流时不做处理
+func (d *ProjectDeps) deleteWorkflow(ctx context.Context, uid, projectID string) error {
+	if d.Workflows == nil {
+		return nil
+	}
+	list, err := d.Workflows.List(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, wf := range list {
+		if wf.ProjectID != nil && *wf.ProjectID == projectID {
+			return d.Workflows.Delete(ctx, uid, wf.ID)
+		}
+	}
+	return nil
+}
+
+// ReorderProjects 调整项目顺序
+// @Summary 调整项目顺序
+// @Description 按 ids 的顺序重新编排项目位置，未列出的项目保持原有顺序排在之后；返回调整后的项目列表
+// @Tags 项目管理
+// @Accept json
+// @Produce json
+// @Param body body object true "项目ID顺序，如 {\"ids\": [\"<项目ID>\"]}"
+// @Success 200 {object} []map[string]interface{} "调整后的项目列表"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "项目不存在"
+// @Router /api/projects/reorder [post]
+func (d *ProjectDeps) ReorderProjects(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req struct {
+		IDs []string `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.IDs) == 0 {
+		JSON(w, 400, map[string]string{"msg": "Project ids are required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	projects, err := d.Projects.List(ctx, uid)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	changed, err := taskproject.Reorder(projects, req.IDs)
+	if err != nil {
+		projectError(w, err)
+		return
+	}
+	now := time.Now()
+	for _, p := range changed {
+		p.UpdatedAt = now
+		if err := d.Projects.Update(ctx, p); err != nil {
+			projectError(w, err)
+			return
+		}
+	}
+	ids := make([]string, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+	stats, err := d.stats(ctx, uid, ids, now)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	out := make([]bson.M, len(projects))
+	for i := range projects {
+		p := &projects[i]
+		out[p.Position] = projectResponse(p, stats[p.ID])
+	}
+	JSON(w, 200, out)
+}
+
+// MoveTasks 将任务移入项目
+// @Summary 将任务移入项目
+// @Description 将 taskIds 中的任务连同其全部子任务移入该项目，原先可属于其他项目或不属于任何项目；
+// @Description 不能移入已归档的项目。tasksUpdated 为实际改变项目的任务数
+// @Tags 项目管理
+// @Accept json
+// @Produce json
+// @Param id path string true "项目ID"
+// @Param body body object true "任务ID列表，如 {\"taskIds\": [\"<任务ID>\"]}"
+// @Success 200 {object} map[string]interface{} "项目及被移动的任务数"
+// @Failure 400 {object} map[string]string "请求参数错误或任务不存在"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "项目不存在"
+// @Failure 409 {object} map[string]string "项目已归档"
+// @Router /api/projects/{id}/tasks [post]
+func (d *ProjectDeps) MoveTasks(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req struct {
+		TaskIDs []string `json:"taskIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.TaskIDs) == 0 {
+		JSON(w, 400, map[string]string{"msg": "Task ids are required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	project, err := taskproject.Open(ctx, d.Projects, uid, mux.Vars(r)["id"])
+	if err != nil {
+		projectError(w, err)
+		return
+	}
+	now := time.Now()
+	updated, err := taskproject.Move(ctx, d.Tasks, uid, req.TaskIDs, &project.ID, now)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		JSON(w, 400, map[string]string{"msg": "Task not found"})
+		return
+	case err != nil:
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	stats, err := d.stats(ctx, uid, []string{project.ID}, now)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	resp := projectResponse(project, stats[project.ID])
+	resp["tasksUpdated"] = updated
+	JSON(w, 200, resp)
+}
+
+func SetupProjectRoutes(r *mux.Router, deps *ProjectDeps) {
+	s := r.PathPrefix("/api/projects").Subrouter()
+	s.Handle("", Auth(http.HandlerFunc(deps.ListProjects))).Methods(http.MethodGet)
+	s.Handle("", Auth(http.HandlerFunc(deps.CreateProject))).Methods(http.MethodPost)
+	s.Handle("/reorder", Auth(http.HandlerFunc(deps.ReorderProjects))).Methods(http.MethodPost)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.GetProject))).Methods(http.MethodGet)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.UpdateProject))).Methods(http.MethodPut)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.DeleteProject))).Methods(http.MethodDelete)
+	s.Handle("/{id}/tasks", Auth(http.HandlerFunc(deps.MoveTasks))).Methods(http.MethodPost)
+}
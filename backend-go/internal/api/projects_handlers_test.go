@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestProjects(t *testing.T) {
+	r := mux.NewRouter()
+	tasks, projects := memory.NewTaskRepository(), memory.NewProjectRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks, Projects: projects})
+	SetupProjectRoutes(r, &ProjectDeps{Projects: projects, Tasks: tasks})
+	SetupReportRoutes(r, &ReportDeps{Reports: memory.NewReportRepository(), Tasks: tasks, Projects: projects})
+
+	newProject := func(name string) map[string]interface{} {
+		return decodeMap(t, doJSON(t, r, http.MethodPost, "/api/projects", "u1", map[string]string{"name": name}), 200)
+	}
+	work := newProject(" 工作 ")
+	home := newProject("家务")
+	if work["name"] != "工作" || work["position"] != float64(0) || home["position"] != float64(1) {
+		t.Errorf("Unexpected projects %v %v", work, home)
+	}
+	workID, homeID := work["_id"].(string), home["_id"].(string)
+	if w := doJSON(t, r, http.MethodPost, "/api/projects", "u1", map[string]string{"name": "  "}); w.Code != http.StatusBadRequest {
+		t.Errorf("Blank name: expected 400, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodGet, "/api/projects/"+workID, "u2", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Other user's project: expected 404, got %d", w.Code)
+	}
+
+	// 子任务默认归入父任务所在的项目
+	parent := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{"title": "发布", "projectId": workID}), 200)
+	parentID := parent["_id"].(string)
+	child := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{"title": "打包", "parentId": parentID, "status": "Done"}), 200)
+	if child["projectId"] != workID {
+		t.Errorf("Expected subtask to inherit project, got %v", child["projectId"])
+	}
+	loose := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{"title": "买菜"}), 200)["_id"].(string)
+	if w := doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{"title": "x", "projectId": "missing"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Unknown project: expected 400, got %d", w.Code)
+	}
+
+	listTasks := func(query string) []interface{} {
+		t.Helper()
+		w := doJSON(t, r, http.MethodGet, "/api/tasks?"+query, "u1", nil)
+		var out []interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || w.Code != 200 {
+			t.Fatalf("%s: %d %s", query, w.Code, w.Body.String())
+		}
+		return out
+	}
+	if got := listTasks("project=" + workID); len(got) != 2 {
+		t.Errorf("Expected 2 tasks in 工作, got %d", len(got))
+	}
+	if got := listTasks("project=none&q=status:todo"); len(got) != 1 {
+		t.Errorf("Expected 1 open task without project, got %d", len(got))
+	}
+
+	got := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/projects/"+workID, "u1", nil), 200)
+	if stats := got["statistics"].(map[string]interface{}); stats["totalTasks"] != float64(2) || stats["completedTasks"] != float64(1) {
+		t.Errorf("Unexpected statistics %v", stats)
+	}
+
+	// 移动任务时子任务一并移动
+	moved := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/projects/"+homeID+"/tasks", "u1", map[string]interface{}{"taskIds": []string{parentID, loose}}), 200)
+	if moved["tasksUpdated"] != float64(3) || moved["statistics"].(map[string]interface{})["totalTasks"] != float64(3) {
+		t.Errorf("Unexpected move response %v", moved)
+	}
+	if w := doJSON(t, r, http.MethodPost, "/api/projects/"+homeID+"/tasks", "u1", map[string]interface{}{"taskIds": []string{"missing"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Unknown task: expected 400, got %d", w.Code)
+	}
+
+	// 归档的项目不能再加入任务
+	decodeMap(t, doJSON(t, r, http.MethodPut, "/api/projects/"+workID, "u1", map[string]bool{"archived": true}), 200)
+	if w := doJSON(t, r, http.MethodPatch, "/api/tasks/"+loose, "u1", map[string]string{"projectId": workID}); w.Code != http.StatusConflict {
+		t.Errorf("Archived project: expected 409, got %d", w.Code)
+	}
+	w := doJSON(t, r, http.MethodGet, "/api/projects?archived=false", "u1", nil)
+	var list []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 1 || list[0]["_id"] != homeID {
+		t.Errorf("Expected only 家务 unarchived, got %s", w.Body.String())
+	}
+
+	// 报表可限定在项目内，标题附带项目名称
+	rep := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/reports/generate", "u1", map[string]string{
+		"type": "weekly", "period": "W1", "startDate": "2000-01-01", "endDate": "2999-12-31", "projectId": homeID}), 200)
+	if rep["projectId"] != homeID || len(rep["tasks"].([]interface{})) != 3 || !strings.HasSuffix(rep["title"].(string), " - 家务") {
+		t.Errorf("Unexpected project report %v", rep)
+	}
+	if w := doJSON(t, r, http.MethodPost, "/api/reports/generate", "u1", map[string]string{
+		"type": "weekly", "period": "W1", "startDate": "2000-01-01", "endDate": "2999-12-31", "projectId": "missing"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Report for unknown project: expected 400, got %d", w.Code)
+	}
+
+	w = doJSON(t, r, http.MethodPost, "/api/projects/reorder", "u1", map[string][]string{"ids": {homeID}})
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 2 || list[0]["_id"] != homeID || list[1]["position"] != float64(1) {
+		t.Errorf("Expected 家务 first after reorder, got %s", w.Body.String())
+	}
+
+	// 删除项目后任务保留并移出项目
+	deleted := decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/projects/"+homeID, "u1", nil), 200)
+	if deleted["tasksUpdated"] != float64(3) {
+		t.Errorf("Expected 3 tasks detached, got %v", deleted)
+	}
+	if got := listTasks("project=none"); len(got) != 3 {
+		t.Errorf("Expected 3 tasks without project after delete, got %d", len(got))
+	}
+}
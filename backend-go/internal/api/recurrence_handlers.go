@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/recurrence"
+)
+
+// maxOccurrences 预览重复实例的数量上限
+const maxOccurrences = 100
+
+// ListOccurrences 预览重复任务之后的实例
+// @Summary 预览重复任务
+// @Description 按任务的重复规则列出当前实例之后的实例日期，不会创建任务；不重复的任务返回空列表
+// @Tags 任务管理
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param count query int false "实例数量，默认 5，最多 100"
+// @Success 200 {object} map[string]interface{} "重复规则与实例列表"
+// @Failure 400 {object} map[string]string "参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Router /api/tasks/{id}/occurrences [get]
+func (d *TaskDeps) ListOccurrences(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	count := 5
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxOccurrences {
+			JSON(w, 400, map[string]string{"msg": "Invalid count"})
+			return
+		}
+		count = n
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, err := d.Tasks.Get(ctx, uid, muxVar(r, "id"))
+	if err != nil {
+		d.taskError(w, err)
+		return
+	}
+	occurrences, err := recurrence.Upcoming(task, count)
+	if err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid recurrence", "error": err.Error()})
+		return
+	}
+	JSON(w, 200, map[string]interface{}{"recurrence": task.Recurrence, "occurrences": occurrences})
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestRecurringTasks(t *testing.T) {
+	r := mux.NewRouter()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: memory.NewTaskRepository()})
+
+	if w := doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{
+		"title": "倒垃圾", "recurrence": map[string]string{"rule": "FREQ=WEEKLY"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Recurrence without dates: expected 400, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{
+		"title": "倒垃圾", "deadline": "2024-01-01", "recurrence": map[string]string{"rule": "FREQ=YEARLY"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Unsupported FREQ: expected 400, got %d", w.Code)
+	}
+
+	task := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{
+		"title": "倒垃圾", "scheduledDate": "2024-01-01", "deadline": "2024-01-02",
+		"recurrence": map[string]string{"rule": "freq=weekly;byday=mo,th;count=3"}}), 200)
+	id := task["_id"].(string)
+	if rec := task["recurrence"].(map[string]interface{}); rec["rule"] != "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=3" {
+		t.Errorf("Expected normalized rule, got %v", rec)
+	}
+
+	preview := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+id+"/occurrences?count=10", "u1", nil), 200)
+	occ := preview["occurrences"].([]interface{})
+	if len(occ) != 2 || occ[0].(map[string]interface{})["scheduledDate"] != "2024-01-04T00:00:00Z" ||
+		occ[1].(map[string]interface{})["deadline"] != "2024-01-09T00:00:00Z" {
+		t.Errorf("Unexpected occurrences %v", occ)
+	}
+	if w := doJSON(t, r, http.MethodGet, "/api/tasks/"+id+"/occurrences?count=0", "u1", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Invalid count: expected 400, got %d", w.Code)
+	}
+
+	// 完成后生成下一次实例，规则转移到新实例
+	done := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"status": "Done"}), 200)
+	next := done["next"].(map[string]interface{})
+	if done["recurrence"] != nil || next["status"] != "To Do" || next["scheduledDate"] != "2024-01-04T00:00:00Z" ||
+		next["deadline"] != "2024-01-05T00:00:00Z" || next["recurrence"].(map[string]interface{})["rule"] != "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=2" {
+		t.Errorf("Unexpected completion %v", done)
+	}
+	// 再次保存已完成的任务不会重复生成
+	if again := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"status": "Done"}), 200); again["next"] != nil {
+		t.Errorf("Expected no new instance, got %v", again["next"])
+	}
+
+	nextID := next["_id"].(string)
+	last := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+nextID, "u1", map[string]string{"status": "Done"}), 200)["next"].(map[string]interface{})
+	if end := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+last["_id"].(string), "u1", map[string]string{"status": "Done"}), 200); end["next"] != nil {
+		t.Errorf("Expected series to end after COUNT, got %v", end["next"])
+	}
+
+	// 取消重复
+	other := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{
+		"title": "浇花", "deadline": "2024-01-01", "recurrence": map[string]string{"rule": "FREQ=DAILY", "tzid": "Asia/Shanghai"}}), 200)["_id"].(string)
+	if w := doJSON(t, r, http.MethodPatch, "/api/tasks/"+other, "u1", map[string]string{"deadline": ""}); w.Code != http.StatusBadRequest {
+		t.Errorf("Removing the only date of a recurring task: expected 400, got %d", w.Code)
+	}
+	cleared := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+other, "u1", map[string]interface{}{"recurrence": map[string]string{"rule": ""}}), 200)
+	if cleared["recurrence"] != nil {
+		t.Errorf("Expected recurrence to be cleared, got %v", cleared["recurrence"])
+	}
+}
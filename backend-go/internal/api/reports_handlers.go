@@ -5,17 +5,26 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"sort"
 	"strings"
 	"time"
 
+	"github.com/axfinn/todoIng/backend-go/internal/datetime"
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/report"
+	"github.com/axfinn/todoIng/backend-go/internal/reportgen"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/trash"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-type ReportDeps struct{ DB *mongo.Database }
+type ReportDeps struct {
+	Reports   repository.ReportRepository
+	Tasks     repository.TaskRepository
+	Projects  repository.ProjectRepository
+	Workflows repository.WorkflowRepository
+	History   repository.HistoryRepository
+}
 
 // ListReports 获取报表列表
 // @Summary 获取用户的所有报表
@@ -23,6 +32,7 @@ type ReportDeps struct{ DB *mongo.Database }
 // @Tags 报表管理
 // @Accept json
 // @Produce json
+// @Param projectId query string false "只返回限定在该项目的报表"
 // @Success 200 {object} []map[string]interface{} "报表列表"
 // @Failure 401 {object} map[string]string "未授权"
 // @Failure 500 {object} map[string]string "服务器内部错误"
@@ -35,33 +45,21 @@ func (d *ReportDeps) ListReports(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
-	cur, err := d.DB.Collection("reports").Find(ctx, bson.M{"userId": uid})
+	reports, err := d.Reports.List(ctx, repository.ReportFilter{UserID: uid, ProjectID: r.URL.Query().Get("projectId")})
 	if err != nil {
 		JSON(w, 500, map[string]string{"msg": "DB error"})
 		return
 	}
-	defer cur.Close(ctx)
-	var reports []bson.M
-	for cur.Next(ctx) {
-		var m bson.M
-		if cur.Decode(&m) == nil {
-			if id, ok := m["_id"].(primitive.ObjectID); ok {
-				m["_id"] = id.Hex()
-			}
-			reports = append(reports, m)
-		}
+	out := make([]bson.M, 0, len(reports))
+	for i := range reports {
+		out = append(out, reportResponse(&reports[i]))
 	}
-	sort.SliceStable(reports, func(i, j int) bool {
-		ti, _ := reports[i]["createdAt"].(time.Time)
-		tj, _ := reports[j]["createdAt"].(time.Time)
-		return ti.After(tj)
-	})
-	JSON(w, 200, reports)
+	JSON(w, 200, out)
 }
 
 // GetReport 获取报表详情
 // @Summary 获取单个报表详情
-// @Description 根据报表ID获取报表的详细信息，包含关联的任务数据
+// @Description 根据报表ID获取报表的详细信息，包含关联的任务数据；ETag 由报表版本与整个响应体生成，关联任务变化时同样改变，可在删除时通过 If-Match 传回
 // @Tags 报表管理
 // @Accept json
 // @Produce json
@@ -77,41 +75,98 @@ func (d *ReportDeps) GetReport(w http.ResponseWriter, r *http.Request) {
 		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
 		return
 	}
-	id := mux.Vars(r)["id"]
-	objID, err := primitive.ObjectIDFromHex(id)
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	rep, err := d.Reports.Get(ctx, uid, mux.Vars(r)["id"])
 	if err != nil {
-		JSON(w, 404, map[string]string{"msg": "Report not found"})
+		reportError(w, err)
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-	defer cancel()
-	var rep bson.M
-	if err := d.DB.Collection("reports").FindOne(ctx, bson.M{"_id": objID, "userId": uid}).Decode(&rep); err != nil {
-		JSON(w, 404, map[string]string{"msg": "Report not found"})
+	resp, tag, err := d.representation(ctx, uid, rep)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
 		return
 	}
-	rep["_id"] = id
+	w.Header().Set("ETag", tag)
+	JSON(w, 200, resp)
+}
 
-	// 兼容 Node.js 版本：populate 任务详情
-	if taskIDs, ok := rep["tasks"].([]interface{}); ok && len(taskIDs) > 0 {
-		var tasks []bson.M
-		for _, taskID := range taskIDs {
-			if taskIDStr, ok := taskID.(string); ok {
-				if taskObjID, err := primitive.ObjectIDFromHex(taskIDStr); err == nil {
-					var task bson.M
-					if err := d.DB.Collection("tasks").FindOne(ctx, bson.M{"_id": taskObjID}).Decode(&task); err == nil {
-						if id, ok := task["_id"].(primitive.ObjectID); ok {
-							task["_id"] = id.Hex()
-						}
-						tasks = append(tasks, task)
-					}
-				}
-			}
+// representation 返回 GET /api/reports/{id} 的响应体及其 ETag，412 响应复用同一形状
+func (d *ReportDeps) representation(ctx context.Context, uid string, rep *models.Report) (bson.M, string, error) {
+	resp := reportResponse(rep)
+
+	// 兼容 Node.js 版本：populate 任务详情，按报表中保存的顺序返回；
+	// 回收站中的任务带有 deletedAt，永久删除的任务会被跳过
+	if len(rep.Tasks) > 0 {
+		tasks, err := d.generator().Populate(ctx, uid, rep)
+		if err != nil {
+			return nil, "", err
+		}
+		populated := make([]bson.M, 0, len(tasks))
+		for i := range tasks {
+			populated = append(populated, taskResponse(&tasks[i]))
 		}
-		rep["tasks"] = tasks
+		resp["tasks"] = populated
 	}
+	return resp, etag(rep.Version, resp), nil
+}
 
-	JSON(w, 200, rep)
+// generator 返回生成报表的 reportgen 服务，与 gRPC 报表服务共用
+func (d *ReportDeps) generator() *reportgen.Service {
+	return &reportgen.Service{Reports: d.Reports, Tasks: d.Tasks, Projects: d.Projects, Workflows: d.Workflows, History: d.History}
+}
+
+// reportError 将存储错误转换为 HTTP 响应
+func reportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		JSON(w, 404, map[string]string{"msg": "Report not found"})
+	case errors.Is(err, repository.ErrVersion):
+		JSON(w, 412, map[string]string{"msg": "Report was modified"})
+	default:
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+	}
+}
+
+// writableReport 读取要修改的报表并校验 If-Match，不存在或与当前 ETag 不符时写入响应
+func (d *ReportDeps) writableReport(ctx context.Context, w http.ResponseWriter, r *http.Request, uid string) (*models.Report, bool) {
+	rep, err := d.Reports.Get(ctx, uid, mux.Vars(r)["id"])
+	if err != nil {
+		reportError(w, err)
+		return nil, false
+	}
+	if !hasIfMatch(r) {
+		return rep, true
+	}
+	resp, tag, err := d.representation(ctx, uid, rep)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return nil, false
+	}
+	if !ifMatch(r, tag) {
+		preconditionFailed(w, "Report was modified", tag, resp)
+		return nil, false
+	}
+	return rep, true
+}
+
+// writeError 转换写入报表时的错误；读取后报表已被其他请求修改时返回 412 及其当前内容
+func (d *ReportDeps) writeError(ctx context.Context, w http.ResponseWriter, uid, id string, err error) {
+	if !errors.Is(err, repository.ErrVersion) {
+		reportError(w, err)
+		return
+	}
+	rep, err := d.Reports.Get(ctx, uid, id)
+	if err != nil {
+		reportError(w, err)
+		return
+	}
+	resp, tag, err := d.representation(ctx, uid, rep)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	preconditionFailed(w, "Report was modified", tag, resp)
 }
 
 type generateReportRequest struct {
@@ -119,6 +174,8 @@ type generateReportRequest struct {
 	Period    string `json:"period"`
 	StartDate string `json:"startDate"`
 	EndDate   string `json:"endDate"`
+	Query     string `json:"query"`     // 可选，查询语言表达式，仅统计匹配的任务
+	ProjectID string `json:"projectId"` // 可选，仅统计该项目内的任务
 }
 
 // POST /api/reports/generate
@@ -137,199 +194,117 @@ func (d *ReportDeps) GenerateReport(w http.ResponseWriter, r *http.Request) {
 		JSON(w, 400, map[string]string{"msg": "Please provide type, period, startDate, and endDate"})
 		return
 	}
-	if req.Type != "daily" && req.Type != "weekly" && req.Type != "monthly" {
+	if !report.ValidType(req.Type) {
 		JSON(w, 400, map[string]string{"msg": "Invalid type"})
 		return
 	}
 
-	// 支持多种日期格式，兼容前端
-	start, err1 := parseFlexibleDate(req.StartDate)
-	end, err2 := parseFlexibleDate(req.EndDate)
-	if err1 != nil || err2 != nil {
-		JSON(w, 400, map[string]string{"msg": "Invalid date format"})
+	start, err := datetime.Parse(req.StartDate)
+	if err != nil {
+		bodyError(w, err)
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
-	defer cancel()
-	tasksCur, err := d.DB.Collection("tasks").Find(ctx, bson.M{"createdBy": uid, "createdAt": bson.M{"$gte": start, "$lte": end}})
+	end, err := datetime.Parse(req.EndDate)
 	if err != nil {
-		JSON(w, 500, map[string]string{"msg": "DB error"})
+		bodyError(w, err)
 		return
 	}
-	defer tasksCur.Close(ctx)
-	var tasks []bson.M
-	for tasksCur.Next(ctx) {
-		var t bson.M
-		if tasksCur.Decode(&t) == nil {
-			if id, ok := t["_id"].(primitive.ObjectID); ok {
-				t["_id"] = id.Hex()
-			}
-			tasks = append(tasks, t)
-		}
-	}
-	total := len(tasks)
-	completed := 0
-	inProgress := 0
-	overdue := 0
-	now := time.Now()
-	for _, t := range tasks {
-		status, _ := t["status"].(string)
-		if status == "Done" {
-			completed++
-		} else if status == "In Progress" {
-			inProgress++
-		}
-		if ddl, ok := t["deadline"].(time.Time); ok {
-			if ddl.Before(now) && status != "Done" {
-				overdue++
-			}
-		}
+	cond, ok := parseTaskQuery(w, req.Query)
+	if !ok {
+		return
 	}
-	completionRate := 0
-	if total > 0 {
-		completionRate = int(float64(completed) / float64(total) * 100)
-	}
-	titles := map[string]string{"daily": "日报 - " + req.Period, "weekly": "周报 - " + req.Period, "monthly": "月报 - " + req.Period}
-	var sb strings.Builder
-	sb.WriteString("# " + titles[req.Type] + "\n\n")
-	sb.WriteString("报告周期: " + start.Format("2006/01/02") + " - " + end.Format("2006/01/02") + "\n\n")
-	sb.WriteString("## 统计信息\n")
-	sb.WriteString("- 总任务数: " + itoa(total) + "\n")
-	sb.WriteString("- 已完成任务: " + itoa(completed) + "\n")
-	sb.WriteString("- 进行中任务: " + itoa(inProgress) + "\n")
-	sb.WriteString("- 过期任务: " + itoa(overdue) + "\n")
-	sb.WriteString("- 完成率: " + itoa(completionRate) + "%\n\n")
-	sb.WriteString("## 任务详情\n")
-	if len(tasks) == 0 {
-		sb.WriteString("此周期内未找到任务。\n")
-	} else {
-		for _, t := range tasks {
-			title, _ := t["title"].(string)
-			status, _ := t["status"].(string)
-			priority, _ := t["priority"].(string)
-			createdAt, _ := t["createdAt"].(time.Time)
-			updatedAt, _ := t["updatedAt"].(time.Time)
-			sb.WriteString("### 任务: " + title + "\n")
-			sb.WriteString("- **任务状态**: " + status + "\n")
-			sb.WriteString("- **任务优先级**: " + priority + "\n")
-			if !createdAt.IsZero() {
-				sb.WriteString("- **创建时间**: " + createdAt.Format("2006-01-02 15:04:05") + "\n")
-			}
-			if !updatedAt.IsZero() {
-				sb.WriteString("- **更新时间**: " + updatedAt.Format("2006-01-02 15:04:05") + "\n")
-			}
-			if ddl, ok := t["deadline"].(time.Time); ok && !ddl.IsZero() {
-				sb.WriteString("- **截止日期**: " + ddl.Format("2006-01-02 15:04:05") + "\n")
-			}
-			if sch, ok := t["scheduledDate"].(time.Time); ok && !sch.IsZero() {
-				sb.WriteString("- **计划日期**: " + sch.Format("2006-01-02 15:04:05") + "\n")
-			}
-			desc, _ := t["description"].(string)
-			if desc == "" {
-				desc = "无"
-			}
-			sb.WriteString("- **任务描述**: " + desc + "\n\n")
-			sb.WriteString("#### 任务活动时间线\n")
-			sb.WriteString("- " + createdAt.Format("2006-01-02 15:04:05") + ": 任务已创建\n")
-			if !updatedAt.IsZero() && updatedAt.After(createdAt.Add(5*time.Second)) {
-				sb.WriteString("- " + updatedAt.Format("2006-01-02 15:04:05") + ": 任务已更新\n")
-			}
-			sb.WriteString("\n---\n\n")
-		}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	rep, _, err := d.generator().Generate(ctx, uid, reportgen.Input{
+		Type:      req.Type,
+		Period:    req.Period,
+		Start:     start,
+		End:       end,
+		Query:     cond,
+		ProjectID: req.ProjectID,
+	})
+	if errors.Is(err, reportgen.ErrProject) {
+		JSON(w, 400, map[string]string{"msg": "Project not found"})
+		return
 	}
-	content := sb.String()
-	reportDoc := bson.M{
-		"userId":          uid,
-		"type":            req.Type,
-		"period":          req.Period,
-		"title":           titles[req.Type],
-		"content":         content,
-		"polishedContent": nil,
-		"tasks":           extractTaskIDs(tasks),
-		"statistics": bson.M{
-			"totalTasks":      total,
-			"completedTasks":  completed,
-			"inProgressTasks": inProgress,
-			"overdueTasks":    overdue,
-			"completionRate":  completionRate,
-		},
-		"createdAt": time.Now(),
-		"updatedAt": time.Now(),
-	}
-	res, err := d.DB.Collection("reports").InsertOne(ctx, reportDoc)
 	if err != nil {
 		JSON(w, 500, map[string]string{"msg": "DB error"})
 		return
 	}
-	reportDoc["_id"] = res.InsertedID.(primitive.ObjectID).Hex()
-	JSON(w, 200, reportDoc)
+	JSON(w, 200, reportResponse(rep))
 }
 
-func extractTaskIDs(tasks []bson.M) []string {
-	ids := make([]string, 0, len(tasks))
-	for _, t := range tasks {
-		if id, ok := t["_id"].(string); ok {
-			ids = append(ids, id)
-		}
+// reportResponse 保持与 Node.js 版本一致的 _id 字段
+func reportResponse(rep *models.Report) bson.M {
+	labelStats := rep.LabelStatistics
+	if labelStats == nil {
+		labelStats = []models.LabelStatistics{}
+	}
+	return bson.M{
+		"_id":             rep.ID,
+		"userId":          rep.UserID,
+		"type":            rep.Type,
+		"period":          rep.Period,
+		"title":           rep.Title,
+		"content":         rep.Content,
+		"polishedContent": rep.PolishedContent,
+		"tasks":           rep.Tasks,
+		"statistics":      rep.Statistics,
+		"labelStatistics": labelStats,
+		"projectId":       rep.ProjectID,
+		"startDate":       rep.StartDate,
+		"endDate":         rep.EndDate,
+		"createdAt":       rep.CreatedAt,
+		"updatedAt":       rep.UpdatedAt,
+		"deletedAt":       rep.DeletedAt,
+		"version":         rep.Version,
 	}
-	return ids
 }
 
-// POST /api/reports/{id}/polish
+// POST /api/reports/{id}/polish，If-Match 与 GET 返回的 ETag 不符时返回 412，current 与 GET 的响应体相同
 func (d *ReportDeps) PolishReport(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == "" {
 		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
 		return
 	}
-	id := mux.Vars(r)["id"]
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		JSON(w, 404, map[string]string{"msg": "Report not found"})
-		return
-	}
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
-	var rep bson.M
-	if err := d.DB.Collection("reports").FindOne(ctx, bson.M{"_id": objID, "userId": uid}).Decode(&rep); err != nil {
-		JSON(w, 404, map[string]string{"msg": "Report not found"})
+	rep, ok := d.writableReport(ctx, w, r, uid)
+	if !ok {
 		return
 	}
-	original := rep["content"].(string)
-	polished := original
-	if pc, ok := rep["polishedContent"].(string); ok && pc != "" {
-		polished = pc
-	} else {
-		polished = original + "\n\n[Polished Placeholder]"
+	polished := rep.Content + "\n\n[Polished Placeholder]"
+	if rep.PolishedContent != nil && *rep.PolishedContent != "" {
+		polished = *rep.PolishedContent
 	}
-	_, err = d.DB.Collection("reports").UpdateByID(ctx, objID, bson.M{"$set": bson.M{"polishedContent": polished, "updatedAt": time.Now()}})
-	if err != nil {
-		JSON(w, 500, map[string]string{"msg": "DB error"})
+	rep.PolishedContent = &polished
+	rep.UpdatedAt = time.Now()
+	if err := d.Reports.Update(ctx, rep); err != nil {
+		d.writeError(ctx, w, uid, rep.ID, err)
 		return
 	}
-	rep["polishedContent"] = polished
-	rep["_id"] = id
-	JSON(w, 200, rep)
+	if _, tag, err := d.representation(ctx, uid, rep); err == nil {
+		w.Header().Set("ETag", tag)
+	}
+	JSON(w, 200, reportResponse(rep))
 }
 
-// DELETE /api/reports/{id}
+// DELETE /api/reports/{id}，报表移入回收站；If-Match 与 GET 返回的 ETag 不符时返回 412，current 与 GET 的响应体相同
 func (d *ReportDeps) DeleteReport(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == "" {
 		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
 		return
 	}
-	id := mux.Vars(r)["id"]
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		JSON(w, 404, map[string]string{"msg": "Report not found"})
-		return
-	}
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	res, err := d.DB.Collection("reports").DeleteOne(ctx, bson.M{"_id": objID, "userId": uid})
-	if err != nil || res.DeletedCount == 0 {
-		JSON(w, 404, map[string]string{"msg": "Report not found"})
+	rep, ok := d.writableReport(ctx, w, r, uid)
+	if !ok {
+		return
+	}
+	if err := trash.TrashReport(ctx, d.Reports, rep, time.Now()); err != nil {
+		d.writeError(ctx, w, uid, rep.ID, err)
 		return
 	}
 	JSON(w, 200, map[string]string{"msg": "Report removed"})
@@ -343,35 +318,18 @@ func (d *ReportDeps) ExportReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	vars := mux.Vars(r)
-	id := vars["id"]
 	format := strings.ToLower(vars["format"])
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		JSON(w, 404, map[string]string{"msg": "Report not found"})
-		return
-	}
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	var rep bson.M
-	if err := d.DB.Collection("reports").FindOne(ctx, bson.M{"_id": objID, "userId": uid}).Decode(&rep); err != nil {
-		JSON(w, 404, map[string]string{"msg": "Report not found"})
+	rep, err := d.Reports.Get(ctx, uid, vars["id"])
+	if err != nil {
+		reportError(w, err)
 		return
 	}
-	content := ""
-	if pc, ok := rep["polishedContent"].(string); ok && pc != "" {
-		content = pc
-	} else {
-		content = rep["content"].(string)
-	}
-	fileExt := "txt"
-	contentType := "text/plain"
-	if format == "md" || format == "markdown" {
-		fileExt = "md"
-		contentType = "text/markdown"
-	}
+	data, filename, contentType := report.Export(rep, format)
 	w.Header().Set("Content-Type", contentType)
-	w.Header().Set("Content-Disposition", "attachment; filename=report-"+rep["period"].(string)+"."+fileExt)
-	_, _ = w.Write([]byte(content))
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	_, _ = w.Write(data)
 }
 
 func SetupReportRoutes(r *mux.Router, deps *ReportDeps) {
@@ -383,35 +341,3 @@ func SetupReportRoutes(r *mux.Router, deps *ReportDeps) {
 	s.Handle("/{id}/polish", Auth(http.HandlerFunc(deps.PolishReport))).Methods(http.MethodPost)
 	s.Handle("/{id}/export/{format}", Auth(http.HandlerFunc(deps.ExportReport))).Methods(http.MethodGet)
 }
-
-// small helpers without importing strconv to keep file self-contained
-func itoa(i int) string {
-	if i == 0 {
-		return "0"
-	}
-	b := make([]byte, 0, 6)
-	for i > 0 {
-		b = append([]byte{byte('0' + i%10)}, b...)
-		i /= 10
-	}
-	return string(b)
-}
-
-// 灵活的日期解析，兼容前端多种格式
-func parseFlexibleDate(dateStr string) (time.Time, error) {
-	formats := []string{
-		time.RFC3339,
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05",
-		"2006-01-02 15:04:05",
-		"2006-01-02",
-	}
-
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t, nil
-		}
-	}
-
-	return time.Time{}, errors.New("unsupported date format")
-}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/axfinn/todoIng/backend-go/internal/search"
+	"github.com/gorilla/mux"
+)
+
+type SearchDeps struct{ Service *search.Service }
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// Search 全文检索
+// @Summary 全文检索任务与报表
+// @Description 在当前用户的任务标题、描述、评论以及报表标题、内容、润色内容中检索，
+// @Description 返回包含全部查询词的结果，按相关度排序；中文按字切分，无需空格分词。
+// @Description highlights 为各命中字段经 HTML 转义的摘要，命中部分以 <mark> 包裹。
+// @Tags 检索
+// @Accept json
+// @Produce json
+// @Param q query string true "查询内容"
+// @Param type query string false "只检索某类结果" Enums(task, report)
+// @Param limit query int false "返回数量，默认 20，最大 100"
+// @Success 200 {object} map[string]interface{} "检索结果"
+// @Failure 400 {object} map[string]string "查询参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/search [get]
+func (d *SearchDeps) Search(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	q := r.URL.Query()
+	text := strings.TrimSpace(q.Get("q"))
+	if text == "" {
+		JSON(w, 400, map[string]string{"msg": "Query is required"})
+		return
+	}
+	kind := search.Kind(q.Get("type"))
+	if kind != "" && kind != search.KindTask && kind != search.KindReport {
+		JSON(w, 400, map[string]string{"msg": "Invalid type"})
+		return
+	}
+	limit := defaultSearchLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			JSON(w, 400, map[string]string{"msg": "Invalid limit"})
+			return
+		}
+		limit = min(n, maxSearchLimit)
+	}
+
+	hits, total, err := d.Service.Search(r.Context(), search.Query{UserID: uid, Text: text, Kind: kind, Limit: limit})
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	JSON(w, 200, map[string]interface{}{"query": text, "total": total, "results": hits})
+}
+
+func SetupSearchRoutes(r *mux.Router, deps *SearchDeps) {
+	r.Handle("/api/search", Auth(http.HandlerFunc(deps.Search))).Methods(http.MethodGet)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+	"github.com/axfinn/todoIng/backend-go/internal/search"
+)
+
+func TestSearchHandler(t *testing.T) {
+	store, tasks := memory.NewSearchRepository(), memory.NewTaskRepository()
+	r := mux.NewRouter()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: search.NewTaskRepository(tasks, store)})
+	SetupSearchRoutes(r, &SearchDeps{Service: &search.Service{Store: store, Tasks: tasks, Reports: memory.NewReportRepository()}})
+
+	for _, title := range []string{"整理项目文档", "买菜"} {
+		if w := doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": title}); w.Code != http.StatusOK {
+			t.Fatalf("Create: expected 200, got %d", w.Code)
+		}
+	}
+
+	w := doJSON(t, r, http.MethodGet, "/api/search?q=%E6%96%87%E6%A1%A3", "u1", nil) // 文档
+	if w.Code != http.StatusOK {
+		t.Fatalf("Search: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Total   int          `json:"total"`
+		Results []search.Hit `json:"results"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Total != 1 || len(resp.Results) != 1 || resp.Results[0].Highlights["title"] != "整理项目<mark>文档</mark>" {
+		t.Errorf("Unexpected search response %s", w.Body.String())
+	}
+
+	// 其他用户检索不到
+	w = doJSON(t, r, http.MethodGet, "/api/search?q=%E6%96%87%E6%A1%A3", "u2", nil)
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Total != 0 {
+		t.Errorf("Expected no results for other user, got %s", w.Body.String())
+	}
+
+	for _, q := range []string{"", "q=x&type=user", "q=x&limit=0"} {
+		if w := doJSON(t, r, http.MethodGet, "/api/search?"+q, "u1", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", q, w.Code)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/tasktree"
+)
+
+// ListSubtasks 获取直接子任务
+// @Summary 获取子任务列表
+// @Description 返回任务的直接子任务，按创建时间正序；完整的子任务树见 GET /api/tasks/{id}
+// @Tags 任务管理
+// @Produce json
+// @Param id path string true "任务ID"
+// @Success 200 {object} []map[string]interface{} "子任务列表"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Router /api/tasks/{id}/subtasks [get]
+func (d *TaskDeps) ListSubtasks(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	id := muxVar(r, "id")
+	if _, err := d.Tasks.Get(ctx, uid, id); err != nil {
+		d.taskError(w, err)
+		return
+	}
+	tasks, err := d.Tasks.List(ctx, repository.TaskFilter{
+		UserID:    uid,
+		ParentIDs: []string{id},
+		Sort:      repository.TaskSort{Field: repository.SortByCreatedAt, Asc: true},
+	})
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	JSON(w, 200, tasksResponse(tasks))
+}
+
+// CreateSubtask 创建子任务
+// @Summary 创建子任务
+// @Description 在任务下创建子任务，请求体与创建任务相同，parentId 以路径为准
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param id path string true "父任务ID"
+// @Param task body taskRequest true "任务信息"
+// @Success 200 {object} map[string]interface{} "创建成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Router /api/tasks/{id}/subtasks [post]
+func (d *TaskDeps) CreateSubtask(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req taskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		bodyError(w, err)
+		return
+	}
+	parentID := muxVar(r, "id")
+	req.ParentID = &parentID
+	d.createTask(w, r, uid, &req)
+}
+
+// AttachSubtask 将已有任务移动到该任务之下
+// @Summary 设置子任务
+// @Description 将 subId 对应的任务（连同其子任务）移动到 id 之下，不能形成环
+// @Tags 任务管理
+// @Produce json
+// @Param id path string true "父任务ID"
+// @Param subId path string true "子任务ID"
+// @Param If-Match header string false "子任务 GET 返回的 ETag"
+// @Success 200 {object} map[string]interface{} "移动后的子任务"
+// @Failure 400 {object} map[string]string "父任务不存在"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Failure 409 {object} map[string]string "父子关系成环"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
+// @Router /api/tasks/{id}/subtasks/{subId} [put]
+func (d *TaskDeps) AttachSubtask(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	parentID := muxVar(r, "id")
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "subId"))
+	if !ok {
+		return
+	}
+	if err := tasktree.CheckParent(ctx, d.Tasks, uid, task.ID, parentID); err != nil {
+		d.parentError(w, err)
+		return
+	}
+	task.ParentID = &parentID
+	task.UpdatedAt = time.Now()
+	if err := d.Tasks.Update(ctx, task); err != nil {
+		d.writeError(ctx, w, uid, task.ID, err)
+		return
+	}
+	d.setETag(ctx, w, uid, task)
+	JSON(w, 200, taskResponse(task))
+}
+
+// DeleteSubtask 删除子任务
+// @Summary 删除子任务
+// @Description 将 id 的直接子任务 subId 及其全部后代移入回收站
+// @Tags 任务管理
+// @Produce json
+// @Param id path string true "父任务ID"
+// @Param subId path string true "子任务ID"
+// @Param If-Match header string false "子任务 GET 返回的 ETag"
+// @Success 200 {object} map[string]string "删除成功"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
+// @Router /api/tasks/{id}/subtasks/{subId} [delete]
+func (d *TaskDeps) DeleteSubtask(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "subId"))
+	if !ok {
+		return
+	}
+	if task.ParentID == nil || *task.ParentID != muxVar(r, "id") {
+		JSON(w, 404, map[string]string{"msg": "Task not found"})
+		return
+	}
+	if err := d.deleteSubtree(ctx, task); err != nil {
+		d.writeError(ctx, w, uid, task.ID, err)
+		return
+	}
+	JSON(w, 200, map[string]string{"msg": "Task removed"})
+}
+
+type checklistRequest struct {
+	Text *string `json:"text"`
+	Done *bool   `json:"done"`
+}
+
+// ListChecklist 获取检查项
+// @Summary 获取任务检查项
+// @Tags 任务管理
+// @Produce json
+// @Param id path string true "任务ID"
+// @Success 200 {object} []models.ChecklistItem "检查项列表"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Router /api/tasks/{id}/checklist [get]
+func (d *TaskDeps) ListChecklist(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, err := d.Tasks.Get(ctx, uid, muxVar(r, "id"))
+	if err != nil {
+		d.taskError(w, err)
+		return
+	}
+	if task.Checklist == nil {
+		task.Checklist = []models.ChecklistItem{}
+	}
+	JSON(w, 200, task.Checklist)
+}
+
+// AddChecklistItem 添加检查项
+// @Summary 添加任务检查项
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param item body checklistRequest true "检查项，text 必填"
+// @Param If-Match header string false "GET 返回的 ETag"
+// @Success 200 {object} models.ChecklistItem "新检查项"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
+// @Router /api/tasks/{id}/checklist [post]
+func (d *TaskDeps) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req checklistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if req.Text == nil || strings.TrimSpace(*req.Text) == "" {
+		JSON(w, 400, map[string]string{"msg": "Text is required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "id"))
+	if !ok {
+		return
+	}
+	now := time.Now()
+	item := models.ChecklistItem{ID: repository.NewID(), Text: *req.Text, CreatedAt: now}
+	if req.Done != nil {
+		item.Done = *req.Done
+	}
+	task.Checklist = append(task.Checklist, item)
+	task.UpdatedAt = now
+	if err := d.Tasks.Update(ctx, task); err != nil {
+		d.writeError(ctx, w, uid, task.ID, err)
+		return
+	}
+	d.setETag(ctx, w, uid, task)
+	JSON(w, 200, item)
+}
+
+// UpdateChecklistItem 修改检查项
+// @Summary 修改任务检查项
+// @Description 修改检查项的文字或完成状态，未提供的字段保持不变
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param itemId path string true "检查项ID"
+// @Param item body checklistRequest true "检查项"
+// @Param If-Match header string false "GET 返回的 ETag"
+// @Success 200 {object} models.ChecklistItem "修改后的检查项"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务或检查项不存在"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
+// @Router /api/tasks/{id}/checklist/{itemId} [put]
+func (d *TaskDeps) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req checklistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if req.Text == nil && req.Done == nil {
+		JSON(w, 400, map[string]string{"msg": "No fields to update"})
+		return
+	}
+	if req.Text != nil && strings.TrimSpace(*req.Text) == "" {
+		JSON(w, 400, map[string]string{"msg": "Text is required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "id"))
+	if !ok {
+		return
+	}
+	i := checklistIndex(task, muxVar(r, "itemId"))
+	if i < 0 {
+		JSON(w, 404, map[string]string{"msg": "Checklist item not found"})
+		return
+	}
+	if req.Text != nil {
+		task.Checklist[i].Text = *req.Text
+	}
+	if req.Done != nil {
+		task.Checklist[i].Done = *req.Done
+	}
+	task.UpdatedAt = time.Now()
+	if err := d.Tasks.Update(ctx, task); err != nil {
+		d.writeError(ctx, w, uid, task.ID, err)
+		return
+	}
+	d.setETag(ctx, w, uid, task)
+	JSON(w, 200, task.Checklist[i])
+}
+
+// DeleteChecklistItem 删除检查项
+// @Summary 删除任务检查项
+// @Tags 任务管理
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param itemId path string true "检查项ID"
+// @Param If-Match header string false "GET 返回的 ETag"
+// @Success 200 {object} map[string]string "删除成功"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务或检查项不存在"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
+// @Router /api/tasks/{id}/checklist/{itemId} [delete]
+func (d *TaskDeps) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "id"))
+	if !ok {
+		return
+	}
+	i := checklistIndex(task, muxVar(r, "itemId"))
+	if i < 0 {
+		JSON(w, 404, map[string]string{"msg": "Checklist item not found"})
+		return
+	}
+	task.Checklist = append(task.Checklist[:i], task.Checklist[i+1:]...)
+	task.UpdatedAt = time.Now()
+	if err := d.Tasks.Update(ctx, task); err != nil {
+		d.writeError(ctx, w, uid, task.ID, err)
+		return
+	}
+	d.setETag(ctx, w, uid, task)
+	JSON(w, 200, map[string]string{"msg": "Checklist item removed"})
+}
+
+// checklistIndex 返回检查项在任务中的下标，不存在时返回 -1
+func checklistIndex(task *models.Task, id string) int {
+	for i, item := range task.Checklist {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
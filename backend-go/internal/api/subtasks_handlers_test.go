@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+// decodeMap 解析 JSON 对象响应，状态码不符时终止测试
+func decodeMap(t *testing.T, w *httptest.ResponseRecorder, code int) map[string]interface{} {
+	t.Helper()
+	if w.Code != code {
+		t.Fatalf("Expected %d, got %d: %s", code, w.Code, w.Body.String())
+	}
+	var m map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &m)
+	return m
+}
+
+func TestSubtasksAndChecklist(t *testing.T) {
+	r := mux.NewRouter()
+	tasks := memory.NewTaskRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks})
+
+	root := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "发布"}), 200)["_id"].(string)
+	a := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks/"+root+"/subtasks", "u1", map[string]string{"title": "测试"}), 200)
+	if a["parentId"] != root {
+		t.Fatalf("Expected parentId %s, got %v", root, a["parentId"])
+	}
+	aID := a["_id"].(string)
+	a1 := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "回归", "parentId": aID}), 200)["_id"].(string)
+	b := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "文档"}), 200)["_id"].(string)
+	if w := doJSON(t, r, http.MethodPost, "/api/tasks/"+root+"/subtasks", "u2", map[string]string{"title": "x"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Subtask under other user's task: expected 400, got %d", w.Code)
+	}
+
+	// 将已有任务设为子任务，不能成环
+	decodeMap(t, doJSON(t, r, http.MethodPut, "/api/tasks/"+root+"/subtasks/"+b, "u1", nil), 200)
+	if w := doJSON(t, r, http.MethodPut, "/api/tasks/"+a1+"/subtasks/"+root, "u1", nil); w.Code != http.StatusConflict {
+		t.Errorf("Cycle via subtasks: expected 409, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodPatch, "/api/tasks/"+aID, "u1", map[string]string{"parentId": aID}); w.Code != http.StatusConflict {
+		t.Errorf("Self parent: expected 409, got %d", w.Code)
+	}
+	w := doJSON(t, r, http.MethodGet, "/api/tasks/"+root+"/subtasks", "u1", nil)
+	var children []map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &children)
+	if len(children) != 2 || children[0]["title"] != "测试" || children[1]["title"] != "文档" {
+		t.Errorf("Expected children [测试 文档], got %v", children)
+	}
+
+	// 检查项
+	item := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks/"+aID+"/checklist", "u1", map[string]string{"text": "单元测试"}), 200)
+	itemID := item["id"].(string)
+	decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks/"+aID+"/checklist", "u1", map[string]string{"text": "集成测试"}), 200)
+	if w := doJSON(t, r, http.MethodPost, "/api/tasks/"+aID+"/checklist", "u1", map[string]string{"text": " "}); w.Code != http.StatusBadRequest {
+		t.Errorf("Empty checklist text: expected 400, got %d", w.Code)
+	}
+	if got := decodeMap(t, doJSON(t, r, http.MethodPut, "/api/tasks/"+aID+"/checklist/"+itemID, "u1", map[string]bool{"done": true}), 200); got["done"] != true || got["text"] != "单元测试" {
+		t.Errorf("Unexpected updated item %v", got)
+	}
+	if w := doJSON(t, r, http.MethodPut, "/api/tasks/"+aID+"/checklist/missing", "u1", map[string]bool{"done": true}); w.Code != http.StatusNotFound {
+		t.Errorf("Missing checklist item: expected 404, got %d", w.Code)
+	}
+
+	// 树与进度：测试 = (1 + 0 + 回归 0) / 3，发布 = (33% + 文档 0) / 2
+	tree := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+root, "u1", nil), 200)
+	subtasks := tree["subtasks"].([]interface{})
+	first := subtasks[0].(map[string]interface{})
+	if len(subtasks) != 2 || first["progress"] != 33.0 || tree["progress"] != 17.0 || len(first["subtasks"].([]interface{})) != 1 {
+		t.Errorf("Unexpected tree %v", tree)
+	}
+
+	// 级联完成
+	decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+root+"?cascade=true", "u1", map[string]string{"status": "Done"}), 200)
+	tree = decodeMap(t, doJSON(t, r, http.MethodGet, "/api/tasks/"+aID, "u1", nil), 200)
+	checklist := tree["checklist"].([]interface{})
+	if tree["status"] != "Done" || tree["progress"] != 100.0 || checklist[1].(map[string]interface{})["done"] != true {
+		t.Errorf("Expected cascaded completion, got %v", tree)
+	}
+
+	// 删除子任务连同其后代
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/tasks/"+aID+"/checklist/"+itemID, "u1", nil), 200)
+	if w := doJSON(t, r, http.MethodDelete, "/api/tasks/"+b+"/subtasks/"+aID, "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Delete subtask of wrong parent: expected 404, got %d", w.Code)
+	}
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/tasks/"+root+"/subtasks/"+aID, "u1", nil), 200)
+	if w := doJSON(t, r, http.MethodGet, "/api/tasks/"+a1, "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected descendant to be deleted, got %d", w.Code)
+	}
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/tasks/"+root, "u1", nil), 200)
+	if w := doJSON(t, r, http.MethodGet, "/api/tasks/"+b, "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected subtree of deleted task to be removed, got %d", w.Code)
+	}
+}
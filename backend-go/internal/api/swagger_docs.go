@@ -19,13 +19,22 @@ package api
 // @Router /api/tasks [post]
 
 // ListTasks 获取任务列表
-// @Summary 获取用户的所有任务
-// @Description 获取当前用户创建的所有任务列表，按创建时间倒序排列
+// @Summary 获取用户的任务
+// @Description 获取当前用户创建的任务列表，支持按状态、优先级、负责人及各日期范围过滤，
+// @Description 按 sort 排序（默认 -createdAt）；设置 limit 后按游标分页，下一页见 Link 响应头。
+// @Description q 为查询语言表达式，语法错误时返回 400，error.pos 为出错位置
 // @Tags 任务管理
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer token" default(Bearer )
+// @Param q query string false "查询语言表达式，如 status:todo due<7d"
+// @Param sort query string false "排序字段，- 前缀表示降序"
+// @Param limit query int false "每页数量"
+// @Param cursor query string false "上一页返回的游标"
 // @Success 200 {array} map[string]interface{} "任务列表"
+// @Header 200 {string} Link "下一页链接"
+// @Header 200 {integer} X-Total-Count "任务总数"
+// @Failure 400 {object} map[string]string "查询参数错误"
 // @Failure 401 {object} map[string]string "未授权"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /api/tasks [get]
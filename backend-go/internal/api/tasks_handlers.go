@@ -3,37 +3,121 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/axfinn/todoIng/backend-go/internal/datetime"
+	"github.com/axfinn/todoIng/backend-go/internal/depgraph"
+	"github.com/axfinn/todoIng/backend-go/internal/models"
 	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/taskcomment"
+	"github.com/axfinn/todoIng/backend-go/internal/taskedit"
+	"github.com/axfinn/todoIng/backend-go/internal/tasklabel"
+	"github.com/axfinn/todoIng/backend-go/internal/taskproject"
+	"github.com/axfinn/todoIng/backend-go/internal/tasktree"
+	"github.com/axfinn/todoIng/backend-go/internal/trash"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-type TaskDeps struct{ DB *mongo.Database }
+type TaskDeps struct {
+	Tasks     repository.TaskRepository
+	Labels    repository.LabelRepository
+	Projects  repository.ProjectRepository
+	Workflows repository.WorkflowRepository
+	History   repository.HistoryRepository
+}
 
+// taskRequest 创建与整体替换任务的请求体，也是 JSON Merge Patch 的合并对象；
+// 省略或为 null 的字段在替换时清空，状态与优先级恢复为工作流的默认值
 type taskRequest struct {
-	Title         string  `json:"title"`
-	Description   string  `json:"description"`
-	Status        string  `json:"status"`
-	Priority      string  `json:"priority"`
-	Assignee      *string `json:"assignee"`
-	Deadline      *string `json:"deadline"`      // 改为 string 类型以兼容前端
-	ScheduledDate *string `json:"scheduledDate"` // 改为 string 类型以兼容前端
-	Comments      []struct {
-		Text      string `json:"text"`
-		CreatedBy string `json:"createdBy,omitempty"`
-		CreatedAt string `json:"createdAt,omitempty"`
-	} `json:"comments"`
-}
-
-var allowedStatus = map[string]bool{"To Do": true, "In Progress": true, "Done": true}
-var allowedPriority = map[string]bool{"Low": true, "Medium": true, "High": true}
+	Title         string             `json:"title"`
+	Description   string             `json:"description"`
+	Status        string             `json:"status"`   // 须属于任务所在的工作流，省略表示工作流的初始状态
+	Priority      string             `json:"priority"` // 须属于任务所在的工作流，省略表示居中的优先级
+	Assignee      *string            `json:"assignee"`
+	Deadline      datetime.Time      `json:"deadline"`      // YYYY-MM-DD 或 RFC3339，格式无效时返回 400
+	ScheduledDate datetime.Time      `json:"scheduledDate"` // 格式同 deadline
+	ParentID      *string            `json:"parentId"`      // 为空表示顶层任务
+	BlockedBy     []string           `json:"blockedBy"`     // 阻塞该任务的任务 ID
+	Recurrence    *models.Recurrence `json:"recurrence"`    // 重复规则，rule 为空表示不重复
+	Labels        []string           `json:"labels"`        // 标签名称，须为已有标签，不区分大小写
+	ProjectID     *string            `json:"projectId"`     // 所属项目，为空表示不归入项目
+	// 追加为新评论，带有已有评论 id 的条目被忽略；编辑与删除见 /api/tasks/{id}/comments
+	Comments []commentInput `json:"comments,omitempty"`
+}
+
+type commentInput struct {
+	ID        string `json:"id,omitempty"`
+	Text      string `json:"text"`
+	CreatedBy string `json:"createdBy,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// editableRequest 返回任务当前可编辑字段组成的请求，作为 Merge Patch 的合并起点
+func editableRequest(t *models.Task) *taskRequest {
+	return &taskRequest{
+		Title:         t.Title,
+		Description:   t.Description,
+		Status:        t.Status,
+		Priority:      t.Priority,
+		Assignee:      t.Assignee,
+		Deadline:      datetime.From(t.Deadline),
+		ScheduledDate: datetime.From(t.ScheduledDate),
+		ParentID:      t.ParentID,
+		BlockedBy:     t.BlockedBy,
+		Recurrence:    t.Recurrence,
+		Labels:        t.Labels,
+		ProjectID:     t.ProjectID,
+	}
+}
+
+// input 转换为 taskedit 的可编辑字段；修改时忽略带有 task 已有评论 id 的评论
+func (req *taskRequest) input(task *models.Task) taskedit.Input {
+	in := taskedit.Input{
+		Title:         req.Title,
+		Description:   req.Description,
+		Status:        req.Status,
+		Priority:      req.Priority,
+		Assignee:      req.Assignee,
+		Deadline:      req.Deadline.Ptr(),
+		ScheduledDate: req.ScheduledDate.Ptr(),
+		ParentID:      req.ParentID,
+		BlockedBy:     req.BlockedBy,
+		Recurrence:    req.Recurrence,
+		Labels:        req.Labels,
+		ProjectID:     req.ProjectID,
+	}
+	for _, c := range req.Comments {
+		if task == nil || c.ID == "" || taskcomment.Index(task, c.ID) < 0 {
+			in.Comments = append(in.Comments, c.Text)
+		}
+	}
+	return in
+}
+
+// editor 返回创建与修改任务的 taskedit 服务，与 gRPC 任务服务共用同一套校验
+func (d *TaskDeps) editor() *taskedit.Service {
+	return &taskedit.Service{Tasks: d.Tasks, Labels: d.Labels, Projects: d.Projects, Workflows: d.Workflows}
+}
+
+// bodyError 写入请求体解码失败的 400 响应，日期格式无效时单独说明
+func bodyError(w http.ResponseWriter, err error) {
+	if errors.Is(err, datetime.ErrInvalid) {
+		JSON(w, 400, map[string]string{"msg": "Invalid date format", "error": err.Error()})
+		return
+	}
+	JSON(w, 400, map[string]string{"msg": "Invalid body"})
+}
 
 // CreateTask 创建新任务
 // @Summary 创建新任务
@@ -56,88 +140,119 @@ func (d *TaskDeps) CreateTask(w http.ResponseWriter, r *http.Request) {
 	var req taskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		observability.CtxLog(r.Context(), "CreateTask decode error: %v", err)
-		JSON(w, 400, map[string]string{"msg": "Invalid body"})
-		return
-	}
-	observability.CtxLog(r.Context(), "CreateTask received: title=%q, description=%q, status=%q, priority=%q", req.Title, req.Description, req.Status, req.Priority)
-	if strings.TrimSpace(req.Title) == "" {
-		JSON(w, 400, map[string]string{"msg": "Title is required"})
-		return
-	}
-	if req.Status == "" {
-		req.Status = "To Do"
-	}
-	if !allowedStatus[req.Status] {
-		JSON(w, 400, map[string]string{"msg": "Invalid status"})
-		return
-	}
-	if req.Priority == "" {
-		req.Priority = "Medium"
-	}
-	if !allowedPriority[req.Priority] {
-		JSON(w, 400, map[string]string{"msg": "Invalid priority"})
+		bodyError(w, err)
 		return
 	}
+	d.createTask(w, r, uid, &req)
+}
 
-	now := time.Now()
-	doc := bson.M{
-		"title":         req.Title,
-		"description":   req.Description,
-		"status":        req.Status,
-		"priority":      req.Priority,
-		"assignee":      req.Assignee,
-		"deadline":      parseDate(req.Deadline),
-		"scheduledDate": parseDate(req.ScheduledDate),
-		"comments":      []bson.M{},
-		"createdBy":     uid,
-		"createdAt":     now,
-		"updatedAt":     now,
-	}
-
-	// 处理评论数据，确保兼容原有格式
-	for _, c := range req.Comments {
-		if strings.TrimSpace(c.Text) != "" {
-			comment := bson.M{
-				"text":      c.Text,
-				"createdBy": uid,
-				"createdAt": now,
-			}
-			doc["comments"] = append(doc["comments"].([]bson.M), comment)
-		}
-	}
-
+// createTask 校验请求并创建任务，设置了 parentId 时父任务须属于当前用户
+func (d *TaskDeps) createTask(w http.ResponseWriter, r *http.Request, uid string, req *taskRequest) {
+	observability.CtxLog(r.Context(), "CreateTask received: title=%q, description=%q, status=%q, priority=%q", req.Title, req.Description, req.Status, req.Priority)
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	res, err := d.DB.Collection("tasks").InsertOne(ctx, doc)
+	task, err := d.editor().Create(ctx, uid, req.input(nil), time.Now())
 	if err != nil {
-		JSON(w, 500, map[string]string{"msg": "DB error"})
+		d.editError(ctx, w, uid, "", err)
 		return
 	}
-	doc["_id"] = res.InsertedID.(primitive.ObjectID).Hex()
-	JSON(w, 200, doc)
+	JSON(w, 200, taskResponse(task))
 }
 
-// 辅助函数：解析日期字符串
-func parseDate(dateStr *string) *time.Time {
-	if dateStr == nil || *dateStr == "" {
-		return nil
-	}
-	if t, err := time.Parse("2006-01-02", *dateStr); err == nil {
-		return &t
+// taskResponse 保持与 Node.js 版本一致的 _id 字段
+func taskResponse(t *models.Task) bson.M {
+	comments := t.Comments
+	if comments == nil {
+		comments = []models.Comment{}
+	}
+	checklist := t.Checklist
+	if checklist == nil {
+		checklist = []models.ChecklistItem{}
+	}
+	blockedBy := t.BlockedBy
+	if blockedBy == nil {
+		blockedBy = []string{}
+	}
+	labels := t.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	return bson.M{
+		"_id":           t.ID,
+		"title":         t.Title,
+		"description":   t.Description,
+		"status":        t.Status,
+		"priority":      t.Priority,
+		"assignee":      t.Assignee,
+		"deadline":      t.Deadline,
+		"scheduledDate": t.ScheduledDate,
+		"comments":      comments,
+		"parentId":      t.ParentID,
+		"checklist":     checklist,
+		"blockedBy":     blockedBy,
+		"recurrence":    t.Recurrence,
+		"labels":        labels,
+		"projectId":     t.ProjectID,
+		"createdBy":     t.CreatedBy,
+		"createdAt":     t.CreatedAt,
+		"updatedAt":     t.UpdatedAt,
+		"deletedAt":     t.DeletedAt,
+		"version":       t.Version,
 	}
-	if t, err := time.Parse(time.RFC3339, *dateStr); err == nil {
-		return &t
+}
+
+// treeResponse 在任务字段之外附带完成度与嵌套的子任务
+func treeResponse(n *tasktree.Node) bson.M {
+	resp := taskResponse(&n.Task)
+	subtasks := make([]bson.M, 0, len(n.Children))
+	for _, c := range n.Children {
+		subtasks = append(subtasks, treeResponse(c))
+	}
+	resp["progress"] = n.Progress
+	resp["subtasks"] = subtasks
+	return resp
+}
+
+// tasksResponse 批量转换任务列表
+func tasksResponse(tasks []models.Task) []bson.M {
+	out := make([]bson.M, 0, len(tasks))
+	for i := range tasks {
+		out = append(out, taskResponse(&tasks[i]))
 	}
-	return nil
+	return out
 }
 
 // ListTasks 获取任务列表
-// @Summary 获取用户的所有任务
-// @Description 获取当前用户创建的所有任务列表，按创建时间倒序排列
+// @Summary 获取用户的任务
+// @Description 获取当前用户创建的任务列表，默认按创建时间倒序返回全部任务。
+// @Description 日期参数支持 YYYY-MM-DD 或 RFC3339，范围含边界，仅有日期的结束时间包含当天全天。
+// @Description q 为查询语言，如 status:"In Progress" priority>=Medium due<7d -assignee:none，与其他参数同时生效；语法错误返回 400 及出错位置。
+// @Description 设置 limit 后按游标分页：响应头 Link 的 rel="next" 指向下一页，X-Total-Count 为满足过滤条件的总数。
 // @Tags 任务管理
 // @Accept json
 // @Produce json
+// @Param q query string false "查询语言表达式"
+// @Param status query string false "状态，须属于用户的某个工作流"
+// @Param priority query string false "优先级，须属于用户的某个工作流"
+// @Param assignee query string false "负责人"
+// @Param parent query string false "只返回该任务的直接子任务"
+// @Param label query []string false "带有其中任一标签，可重复，按名称精确匹配" collectionFormat(multi)
+// @Param project query string false "只返回该项目的任务，none 表示未归入项目的任务"
+// @Param deadlineFrom query string false "截止日期起"
+// @Param deadlineTo query string false "截止日期止"
+// @Param scheduledFrom query string false "计划日期起"
+// @Param scheduledTo query string false "计划日期止"
+// @Param createdFrom query string false "创建时间起"
+// @Param createdTo query string false "创建时间止"
+// @Param updatedFrom query string false "更新时间起"
+// @Param updatedTo query string false "更新时间止"
+// @Param sort query string false "排序字段，- 前缀表示降序" Enums(createdAt, -createdAt, updatedAt, -updatedAt, deadline, -deadline, scheduledDate, -scheduledDate, title, -title)
+// @Param limit query int false "每页数量"
+// @Param cursor query string false "上一页返回的游标"
 // @Success 200 {object} []map[string]interface{} "任务列表"
+// @Header 200 {string} Link "下一页链接"
+// @Header 200 {integer} X-Total-Count "任务总数"
+// @Failure 400 {object} map[string]string "查询参数错误"
 // @Failure 401 {object} map[string]string "未授权"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /api/tasks [get]
@@ -149,28 +264,140 @@ func (d *TaskDeps) ListTasks(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
-	cur, err := d.DB.Collection("tasks").Find(ctx, bson.M{"createdBy": uid}, optionsFindSortCreatedAtDesc())
+	flows, ok := loadWorkflows(ctx, w, d.Workflows, uid)
+	if !ok {
+		return
+	}
+	filter, msg := taskFilterFromQuery(r.URL.Query(), flows)
+	if msg != "" {
+		JSON(w, 400, map[string]string{"msg": msg})
+		return
+	}
+	cond, ok := parseTaskQuery(w, r.URL.Query().Get("q"))
+	if !ok {
+		return
+	}
+	switch {
+	case filter.Query == nil:
+		filter.Query = cond
+	case cond != nil:
+		filter.Query = query.And{filter.Query, cond}
+	}
+	filter.UserID = uid
+
+	total, err := d.Tasks.Count(ctx, filter)
 	if err != nil {
 		JSON(w, 500, map[string]string{"msg": "DB error"})
 		return
 	}
-	defer cur.Close(ctx)
-	tasks := []bson.M{}
-	for cur.Next(ctx) {
-		var m bson.M
-		if err := cur.Decode(&m); err == nil {
-			if id, ok := m["_id"].(primitive.ObjectID); ok {
-				m["_id"] = id.Hex()
-			}
-			tasks = append(tasks, m)
+	tasks, next, err := repository.ListTaskPage(ctx, d.Tasks, filter)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	if next != nil {
+		q := r.URL.Query()
+		q.Set("cursor", filter.Sort.EncodeCursor(next))
+		w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, q.Encode()))
+	}
+	JSON(w, 200, tasksResponse(tasks))
+}
+
+// taskFilterFromQuery 解析任务列表的查询参数，出错时返回错误信息；状态与优先级须属于用户的某个工作流
+func taskFilterFromQuery(q url.Values, flows *workflow.Set) (repository.TaskFilter, string) {
+	var f repository.TaskFilter
+	if f.Status = q.Get("status"); f.Status != "" && !flows.HasStatus(f.Status) {
+		return f, "Invalid status"
+	}
+	if f.Priority = q.Get("priority"); f.Priority != "" && !flows.HasPriority(f.Priority) {
+		return f, "Invalid priority"
+	}
+	f.Assignee = q.Get("assignee")
+	if v := q.Get("parent"); v != "" {
+		f.ParentIDs = []string{v}
+	}
+	if v := q["label"]; len(v) > 0 {
+		f.Labels = v
+	}
+	switch v := q.Get("project"); v {
+	case "":
+	case "none":
+		f.Query = query.Match{Field: query.FieldProject, Op: query.OpNull}
+	default:
+		f.ProjectIDs = []string{v}
+	}
+
+	ranges := []struct {
+		name     string
+		from, to **time.Time
+	}{
+		{"deadline", &f.DeadlineFrom, &f.DeadlineTo},
+		{"scheduled", &f.ScheduledFrom, &f.ScheduledTo},
+		{"created", &f.CreatedFrom, &f.CreatedTo},
+		{"updated", &f.UpdatedFrom, &f.UpdatedTo},
+	}
+	for _, rg := range ranges {
+		var ok bool
+		if *rg.from, ok = queryTime(q.Get(rg.name+"From"), false); !ok {
+			return f, "Invalid " + rg.name + "From"
+		}
+		if *rg.to, ok = queryTime(q.Get(rg.name+"To"), true); !ok {
+			return f, "Invalid " + rg.name + "To"
 		}
 	}
-	JSON(w, 200, tasks)
+
+	var err error
+	if f.Sort, err = repository.ParseTaskSort(q.Get("sort")); err != nil {
+		return f, "Invalid sort"
+	}
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil || f.Limit <= 0 {
+			return f, "Invalid limit"
+		}
+	}
+	if v := q.Get("cursor"); v != "" {
+		if f.After, err = f.Sort.DecodeCursor(v); err != nil {
+			return f, "Invalid cursor"
+		}
+	}
+	return f, ""
+}
+
+// parseTaskQuery 编译查询语言表达式；出错时写入 400 响应并返回 false，
+// 响应中的 error 字段给出出错的字符位置（从 0 开始）
+func parseTaskQuery(w http.ResponseWriter, q string) (query.Cond, bool) {
+	if strings.TrimSpace(q) == "" {
+		return nil, true
+	}
+	cond, err := query.ParseAndCompile(q, time.Now().UTC())
+	if err != nil {
+		JSON(w, 400, map[string]interface{}{"msg": "Invalid query", "error": err})
+		return nil, false
+	}
+	return cond, true
+}
+
+// queryTime 按 datetime 的规则解析可选时间，空串表示未设置；endOfDay 时仅有日期的值取当天最后一刻
+func queryTime(v string, endOfDay bool) (*time.Time, bool) {
+	if v == "" {
+		return nil, true
+	}
+	parse := datetime.Parse
+	if endOfDay {
+		parse = datetime.ParseEnd
+	}
+	t, err := parse(v)
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
 }
 
 // GetTask 获取单个任务详情
 // @Summary 获取任务详情
-// @Description 根据任务ID获取任务的详细信息
+// @Description 根据任务ID获取任务的详细信息，subtasks 为嵌套的全部子任务，progress 为汇总后的完成度（0-100）。
+// @Description ETag 由任务版本与整个响应体生成，子任务或完成度变化时同样改变，可在修改与删除时通过 If-Match 传回
 // @Tags 任务管理
 // @Accept json
 // @Produce json
@@ -186,35 +413,186 @@ func (d *TaskDeps) GetTask(w http.ResponseWriter, r *http.Request) {
 		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
 		return
 	}
-	id := muxVar(r, "id")
-	objID, err := primitive.ObjectIDFromHex(id)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, err := d.Tasks.Get(ctx, uid, muxVar(r, "id"))
 	if err != nil {
-		JSON(w, 404, map[string]string{"msg": "Task not found"})
+		d.taskError(w, err)
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
-	var m bson.M
-	if err := d.DB.Collection("tasks").FindOne(ctx, bson.M{"_id": objID, "createdBy": uid}).Decode(&m); err != nil {
+	resp, tag, err := d.representation(ctx, uid, task)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	w.Header().Set("ETag", tag)
+	JSON(w, 200, resp)
+}
+
+// representation 返回 GET /api/tasks/{id} 的响应体（含子任务树与完成度）及其 ETag，412 响应复用同一形状
+func (d *TaskDeps) representation(ctx context.Context, uid string, task *models.Task) (bson.M, string, error) {
+	flows, err := workflow.Load(ctx, d.Workflows, uid)
+	if err != nil {
+		return nil, "", err
+	}
+	node, err := tasktree.Load(ctx, d.Tasks, flows, task)
+	if err != nil {
+		return nil, "", err
+	}
+	resp := treeResponse(node)
+	return resp, etag(task.Version, resp), nil
+}
+
+// taskError 将存储错误转换为 HTTP 响应
+func (d *TaskDeps) taskError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
 		JSON(w, 404, map[string]string{"msg": "Task not found"})
+	case errors.Is(err, repository.ErrVersion):
+		JSON(w, 412, map[string]string{"msg": "Task was modified"})
+	default:
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+	}
+}
+
+// writableTask 读取要修改的任务 id 并校验 If-Match，不存在或与当前 ETag 不符时写入响应；
+// 修改任务的各个接口（含子任务、检查项与评论）都经由此处读取任务
+func (d *TaskDeps) writableTask(ctx context.Context, w http.ResponseWriter, r *http.Request, uid, id string) (*models.Task, bool) {
+	task, err := d.Tasks.Get(ctx, uid, id)
+	if err != nil {
+		d.taskError(w, err)
+		return nil, false
+	}
+	if !hasIfMatch(r) {
+		return task, true
+	}
+	resp, tag, err := d.representation(ctx, uid, task)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return nil, false
+	}
+	if !ifMatch(r, tag) {
+		preconditionFailed(w, "Task was modified", tag, resp)
+		return nil, false
+	}
+	return task, true
+}
+
+// writeError 转换写入任务时的错误；读取后任务已被其他请求修改时返回 412 及其当前内容
+func (d *TaskDeps) writeError(ctx context.Context, w http.ResponseWriter, uid, id string, err error) {
+	if !errors.Is(err, repository.ErrVersion) {
+		d.taskError(w, err)
+		return
+	}
+	task, err := d.Tasks.Get(ctx, uid, id)
+	if err != nil {
+		d.taskError(w, err)
 		return
 	}
-	m["_id"] = id
-	JSON(w, 200, m)
+	resp, tag, err := d.representation(ctx, uid, task)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	preconditionFailed(w, "Task was modified", tag, resp)
+}
+
+// editError 将创建与修改任务的错误转换为 HTTP 响应：请求不合法返回 400，与父任务、依赖、项目或工作流冲突返回 409，
+// 其余按 writeError 处理
+func (d *TaskDeps) editError(ctx context.Context, w http.ResponseWriter, uid, id string, err error) {
+	var (
+		flowErr    *taskedit.WorkflowError
+		blocked    *taskedit.BlockedError
+		invalidRec *taskedit.RecurrenceError
+		cycle      *depgraph.CycleError
+		unknown    *tasklabel.UnknownError
+	)
+	switch {
+	case errors.Is(err, taskedit.ErrTitle):
+		JSON(w, 400, map[string]string{"msg": "Title is required"})
+	case errors.Is(err, taskedit.ErrParent):
+		JSON(w, 400, map[string]string{"msg": "Parent task not found"})
+	case errors.Is(err, tasktree.ErrCycle):
+		JSON(w, 409, map[string]string{"msg": "Task cannot be moved under itself or one of its subtasks"})
+	case errors.Is(err, taskedit.ErrProject):
+		JSON(w, 400, map[string]string{"msg": "Project not found"})
+	case errors.Is(err, taskproject.ErrArchived):
+		JSON(w, 409, map[string]string{"msg": "Project is archived"})
+	case errors.As(err, &flowErr):
+		workflowCheckError(w, flowErr)
+	case errors.As(err, &cycle):
+		JSON(w, 409, map[string]interface{}{"msg": "Dependency cycle", "path": taskRefs(cycle.Path)})
+	case errors.Is(err, depgraph.ErrMissing):
+		JSON(w, 400, map[string]string{"msg": "Blocking task not found"})
+	case errors.As(err, &blocked):
+		JSON(w, 409, map[string]interface{}{"msg": "Task is blocked", "blockedBy": taskRefs(blocked.Open)})
+	case errors.As(err, &invalidRec):
+		JSON(w, 400, map[string]string{"msg": "Invalid recurrence", "error": invalidRec.Error()})
+	case errors.As(err, &unknown):
+		JSON(w, 400, map[string]string{"msg": "Label not found", "label": unknown.Name})
+	default:
+		d.writeError(ctx, w, uid, id, err)
+	}
+}
+
+// setETag 在修改成功的响应中设置任务新的 ETag；写入已生效，读取新的表示失败时只省略 ETag
+func (d *TaskDeps) setETag(ctx context.Context, w http.ResponseWriter, uid string, task *models.Task) {
+	if _, tag, err := d.representation(ctx, uid, task); err == nil {
+		w.Header().Set("ETag", tag)
+	}
+}
+
+// parentError 将父任务校验错误转换为 HTTP 响应
+func (d *TaskDeps) parentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		JSON(w, 400, map[string]string{"msg": "Parent task not found"})
+	case errors.Is(err, tasktree.ErrCycle):
+		JSON(w, 409, map[string]string{"msg": "Task cannot be moved under itself or one of its subtasks"})
+	default:
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+	}
+}
+
+// taskRefs 只保留 _id 与标题的任务引用
+func taskRefs(tasks []*models.Task) []bson.M {
+	out := make([]bson.M, 0, len(tasks))
+	for _, t := range tasks {
+		out = append(out, bson.M{"_id": t.ID, "title": t.Title})
+	}
+	return out
+}
+
+// deleteSubtree 将任务及其后代移入回收站，依赖关系在永久删除时才清理
+func (d *TaskDeps) deleteSubtree(ctx context.Context, task *models.Task) error {
+	_, err := trash.TrashTask(ctx, d.Tasks, task, time.Now())
+	return err
 }
 
-// UpdateTask 更新任务
-// @Summary 更新任务信息
-// @Description 根据任务ID更新任务的详细信息
+// UpdateTask 整体替换任务
+// @Summary 整体替换任务信息
+// @Description 以请求体整体替换任务的可编辑字段：省略或为 null 的字段被清空，status 与 priority 省略时恢复为工作流的默认值，title 不能为空；
+// @Description 只修改部分字段请使用 PATCH。日期为 YYYY-MM-DD 或 RFC3339，格式无效时返回 400。
+// @Description parentId 可移动任务，不能移到自身或其子任务之下。cascade=true 且状态改为 Done 时同时完成全部子任务与检查项。
+// @Description blockedBy 成环时返回 409 及成环路径；仍有未完成的阻塞任务时不能改为 Done，除非 force=true。
+// @Description 重复任务改为 Done 时按规则创建下一次实例并在 next 中返回，重复规则随之转移到新实例。
+// @Description labels 引用不存在的标签时返回 400。projectId 只移动该任务本身，移动整个子树请使用 POST /api/projects/{id}/tasks。
+// @Description comments 追加为新评论，带有已有评论 id 的条目被忽略；编辑、删除与回复评论请使用 /api/tasks/{id}/comments。
+// @Description 携带 If-Match 时只在与 GET 返回的 ETag 一致时修改，否则返回 412，current 与 GET 的响应体相同
 // @Tags 任务管理
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
-// @Param task body taskRequest true "更新的任务信息"
+// @Param If-Match header string false "GET 返回的 ETag"
+// @Param cascade query bool false "完成时级联完成子任务"
+// @Param force query bool false "忽略未完成的阻塞任务"
+// @Param task body taskRequest true "任务的完整内容"
 // @Success 200 {object} map[string]interface{} "更新成功"
 // @Failure 400 {object} map[string]string "请求参数错误"
 // @Failure 401 {object} map[string]string "未授权"
 // @Failure 404 {object} map[string]string "任务不存在"
+// @Failure 409 {object} map[string]interface{} "父子关系或依赖成环，或任务仍被阻塞"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /api/tasks/{id} [put]
 func (d *TaskDeps) UpdateTask(w http.ResponseWriter, r *http.Request) {
@@ -223,86 +601,152 @@ func (d *TaskDeps) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
 		return
 	}
-	id := muxVar(r, "id")
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		JSON(w, 404, map[string]string{"msg": "Task not found"})
-		return
-	}
 	var req taskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		bodyError(w, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "id"))
+	if !ok {
+		return
+	}
+	d.replaceTask(ctx, w, r, uid, task, &req)
+}
+
+// PatchTask 部分修改任务
+// @Summary 按 JSON Merge Patch 修改任务
+// @Description 按 RFC 7396 将请求体合并到任务的可编辑字段（与 PUT 的请求体相同）：省略的字段保持不变，null 清空字段，
+// @Description recurrence 等对象逐字段合并，数组整体替换；合并结果按 PUT 的规则校验与保存，cascade、force 与 If-Match 同样适用。
+// @Description Content-Type 须为 application/merge-patch+json 或 application/json
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Param id path string true "任务ID"
+// @Param If-Match header string false "GET 返回的 ETag"
+// @Param cascade query bool false "完成时级联完成子任务"
+// @Param force query bool false "忽略未完成的阻塞任务"
+// @Param patch body taskRequest true "要修改的字段，null 表示清空"
+// @Success 200 {object} map[string]interface{} "更新成功"
+// @Failure 400 {object} map[string]string "请求参数错误"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "任务不存在"
+// @Failure 409 {object} map[string]interface{} "父子关系或依赖成环，或任务仍被阻塞"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
+// @Failure 415 {object} map[string]string "不支持的 Content-Type"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/tasks/{id} [patch]
+func (d *TaskDeps) PatchTask(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	if !mergePatchType(r.Header.Get("Content-Type")) {
+		JSON(w, 415, map[string]string{"msg": "Unsupported media type"})
+		return
+	}
+	var patch map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
 		JSON(w, 400, map[string]string{"msg": "Invalid body"})
 		return
 	}
-	update := bson.M{}
-	if req.Title != "" {
-		update["title"] = req.Title
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "id"))
+	if !ok {
+		return
 	}
-	if req.Description != "" {
-		update["description"] = req.Description
+	req, err := patchRequest(task, patch)
+	if err != nil {
+		bodyError(w, err)
+		return
 	}
-	if req.Status != "" {
-		if !allowedStatus[req.Status] {
-			JSON(w, 400, map[string]string{"msg": "Invalid status"})
-			return
-		}
-		update["status"] = req.Status
+	d.replaceTask(ctx, w, r, uid, task, req)
+}
+
+// mergePatchType 判断 PATCH 的 Content-Type 是否为 Merge Patch 或普通 JSON，未设置时按 JSON 处理
+func mergePatchType(contentType string) bool {
+	if contentType == "" {
+		return true
 	}
-	if req.Priority != "" {
-		if !allowedPriority[req.Priority] {
-			JSON(w, 400, map[string]string{"msg": "Invalid priority"})
-			return
-		}
-		update["priority"] = req.Priority
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/merge-patch+json" || mediaType == "application/json")
+}
+
+// patchRequest 将 patch 合并到任务当前的可编辑字段，得到整体替换用的请求
+func patchRequest(task *models.Task, patch map[string]interface{}) (*taskRequest, error) {
+	data, err := json.Marshal(editableRequest(task))
+	if err != nil {
+		return nil, err
 	}
-	if req.Assignee != nil {
-		update["assignee"] = req.Assignee
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
 	}
-	if req.Deadline != nil {
-		update["deadline"] = req.Deadline
+	if data, err = json.Marshal(mergePatch(doc, patch)); err != nil {
+		return nil, err
 	}
-	if req.ScheduledDate != nil {
-		update["scheduledDate"] = req.ScheduledDate
+	var req taskRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
 	}
-	if len(req.Comments) > 0 { // replace comments
-		now := time.Now()
-		comments := make([]bson.M, 0, len(req.Comments))
-		for _, c := range req.Comments {
-			if strings.TrimSpace(c.Text) != "" {
-				comments = append(comments, bson.M{"text": c.Text, "createdBy": uid, "createdAt": now})
-			}
+	return &req, nil
+}
+
+// mergePatch 按 RFC 7396 将 patch 合并到 target：null 删除字段，对象递归合并，其他值整体替换
+func mergePatch(target, patch interface{}) interface{} {
+	fields, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	doc, ok := target.(map[string]interface{})
+	if !ok {
+		doc = map[string]interface{}{}
+	}
+	for k, v := range fields {
+		if v == nil {
+			delete(doc, k)
+			continue
 		}
-		update["comments"] = comments
+		doc[k] = mergePatch(doc[k], v)
 	}
-	if len(update) == 0 {
-		JSON(w, 400, map[string]string{"msg": "No fields to update"})
-		return
+	return doc
+}
+
+// replaceTask 以 req 整体替换任务的可编辑字段并保存，校验规则见 taskedit.Service.Replace
+func (d *TaskDeps) replaceTask(ctx context.Context, w http.ResponseWriter, r *http.Request, uid string, task *models.Task, req *taskRequest) {
+	opts := taskedit.Options{
+		Cascade: r.URL.Query().Get("cascade") == "true",
+		Force:   r.URL.Query().Get("force") == "true",
 	}
-	update["updatedAt"] = time.Now()
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
-	res := d.DB.Collection("tasks").FindOneAndUpdate(ctx, bson.M{"_id": objID, "createdBy": uid}, bson.M{"$set": update}, optionsFindOneAndUpdateReturnAfter())
-	var m bson.M
-	if err := res.Decode(&m); err != nil {
-		JSON(w, 404, map[string]string{"msg": "Task not found"})
+	next, err := d.editor().Replace(ctx, uid, task, req.input(task), opts, time.Now())
+	if err != nil {
+		d.editError(ctx, w, uid, task.ID, err)
 		return
 	}
-	if idObj, ok := m["_id"].(primitive.ObjectID); ok {
-		m["_id"] = idObj.Hex()
+	resp := taskResponse(task)
+	if next != nil {
+		resp["next"] = taskResponse(next)
 	}
-	JSON(w, 200, m)
+	d.setETag(ctx, w, uid, task)
+	JSON(w, 200, resp)
 }
 
 // DeleteTask 删除任务
 // @Summary 删除任务
-// @Description 根据任务ID删除指定的任务
+// @Description 将指定的任务及其全部子任务移入回收站，可在 /api/trash 中恢复或永久删除；
+// @Description 携带 If-Match 时只在与 GET 返回的 ETag 一致时删除，否则返回 412，current 与 GET 的响应体相同
 // @Tags 任务管理
 // @Accept json
 // @Produce json
 // @Param id path string true "任务ID"
+// @Param If-Match header string false "GET 返回的 ETag"
 // @Success 200 {object} map[string]string "删除成功"
 // @Failure 401 {object} map[string]string "未授权"
 // @Failure 404 {object} map[string]string "任务不存在"
+// @Failure 412 {object} map[string]interface{} "任务已被修改，current 为当前内容"
 // @Failure 500 {object} map[string]string "服务器内部错误"
 // @Router /api/tasks/{id} [delete]
 func (d *TaskDeps) DeleteTask(w http.ResponseWriter, r *http.Request) {
@@ -311,50 +755,40 @@ func (d *TaskDeps) DeleteTask(w http.ResponseWriter, r *http.Request) {
 		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
 		return
 	}
-	id := muxVar(r, "id")
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		JSON(w, 404, map[string]string{"msg": "Task not found"})
-		return
-	}
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
-	res, err := d.DB.Collection("tasks").DeleteOne(ctx, bson.M{"_id": objID, "createdBy": uid})
-	if err != nil || res.DeletedCount == 0 {
-		JSON(w, 404, map[string]string{"msg": "Task not found"})
+	task, ok := d.writableTask(ctx, w, r, uid, muxVar(r, "id"))
+	if !ok {
+		return
+	}
+	if err := d.deleteSubtree(ctx, task); err != nil {
+		d.writeError(ctx, w, uid, task.ID, err)
 		return
 	}
 	JSON(w, 200, map[string]string{"msg": "Task removed"})
 }
 
-// GET /api/tasks/export/all
+// GET /api/tasks/export/all?q=
 func (d *TaskDeps) ExportAll(w http.ResponseWriter, r *http.Request) {
 	uid := GetUserID(r)
 	if uid == "" {
 		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
 		return
 	}
+	cond, ok := parseTaskQuery(w, r.URL.Query().Get("q"))
+	if !ok {
+		return
+	}
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
-	cur, err := d.DB.Collection("tasks").Find(ctx, bson.M{"createdBy": uid})
+	tasks, err := d.Tasks.List(ctx, repository.TaskFilter{UserID: uid, Query: cond})
 	if err != nil {
 		JSON(w, 500, map[string]string{"msg": "DB error"})
 		return
 	}
-	defer cur.Close(ctx)
-	tasks := []bson.M{}
-	for cur.Next(ctx) {
-		var m bson.M
-		if cur.Decode(&m) == nil {
-			if idObj, ok := m["_id"].(primitive.ObjectID); ok {
-				m["_id"] = idObj.Hex()
-			}
-			tasks = append(tasks, m)
-		}
-	}
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Content-Disposition", "attachment; filename=todoing-backup-"+time.Now().Format("2006-01-02")+".json")
-	_ = json.NewEncoder(w).Encode(tasks)
+	_ = json.NewEncoder(w).Encode(tasksResponse(tasks))
 }
 
 // POST /api/tasks/import
@@ -377,7 +811,11 @@ func (d *TaskDeps) ImportTasks(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
 	defer cancel()
-	col := d.DB.Collection("tasks")
+	flows, ok := loadWorkflows(ctx, w, d.Workflows, uid)
+	if !ok {
+		return
+	}
+	flow := flows.For(nil)
 	imported := 0
 	var errorsArr []map[string]any
 	for i, t := range body.Tasks {
@@ -388,26 +826,29 @@ func (d *TaskDeps) ImportTasks(w http.ResponseWriter, r *http.Request) {
 		now := time.Now()
 		status, _ := t["status"].(string)
 		priority, _ := t["priority"].(string)
-		if status == "" || !allowedStatus[status] {
-			status = "To Do"
+		if workflow.Find(flow, status) == nil {
+			status = workflow.Initial(flow)
 		}
-		if priority == "" || !allowedPriority[priority] {
-			priority = "Medium"
+		if !workflow.HasPriority(flow, priority) {
+			priority = workflow.DefaultPriority(flow)
 		}
-		doc := bson.M{
-			"title":         title,
-			"description":   t["description"],
-			"status":        status,
-			"priority":      priority,
-			"assignee":      t["assignee"],
-			"deadline":      t["deadline"],
-			"scheduledDate": t["scheduledDate"],
-			"comments":      []bson.M{},
-			"createdBy":     uid,
-			"createdAt":     now,
-			"updatedAt":     now,
+		description, _ := t["description"].(string)
+		task := &models.Task{
+			Title:         title,
+			Description:   description,
+			Status:        status,
+			Priority:      priority,
+			Deadline:      importDate(t["deadline"]),
+			ScheduledDate: importDate(t["scheduledDate"]),
+			Comments:      []models.Comment{},
+			CreatedBy:     uid,
+			CreatedAt:     now,
+			UpdatedAt:     now,
 		}
-		if _, err := col.InsertOne(ctx, doc); err != nil {
+		if assignee, ok := t["assignee"].(string); ok {
+			task.Assignee = &assignee
+		}
+		if err := d.Tasks.Create(ctx, task); err != nil {
 			errorsArr = append(errorsArr, map[string]any{"index": i, "error": err.Error()})
 			continue
 		}
@@ -419,12 +860,14 @@ func (d *TaskDeps) ImportTasks(w http.ResponseWriter, r *http.Request) {
 // Helper utilities
 func muxVar(r *http.Request, key string) string { return mux.Vars(r)[key] }
 
-func optionsFindOneAndUpdateReturnAfter() *options.FindOneAndUpdateOptions {
-	return &options.FindOneAndUpdateOptions{ReturnDocument: func(rd options.ReturnDocument) *options.ReturnDocument { v := options.After; return &v }(options.After)}
-}
-
-func optionsFindSortCreatedAtDesc() *options.FindOptions {
-	return &options.FindOptions{Sort: bson.D{{Key: "createdAt", Value: -1}}}
+// importDate 解析导入数据中的日期字段，格式无效时按未设置导入
+func importDate(v any) *time.Time {
+	s, _ := v.(string)
+	t, err := datetime.Parse(s)
+	if err != nil {
+		return nil
+	}
+	return &t
 }
 
 func SetupTaskRoutes(r *mux.Router, deps *TaskDeps) {
@@ -433,7 +876,24 @@ func SetupTaskRoutes(r *mux.Router, deps *TaskDeps) {
 	s.Handle("", Auth(http.HandlerFunc(deps.CreateTask))).Methods(http.MethodPost)
 	s.Handle("/export/all", Auth(http.HandlerFunc(deps.ExportAll))).Methods(http.MethodGet)
 	s.Handle("/import", Auth(http.HandlerFunc(deps.ImportTasks))).Methods(http.MethodPost)
+	s.Handle("/bulk", Auth(http.HandlerFunc(deps.BulkTasks))).Methods(http.MethodPost)
+	s.Handle("/graph", Auth(http.HandlerFunc(deps.GraphTasks))).Methods(http.MethodGet)
 	s.Handle("/{id}", Auth(http.HandlerFunc(deps.GetTask))).Methods(http.MethodGet)
 	s.Handle("/{id}", Auth(http.HandlerFunc(deps.UpdateTask))).Methods(http.MethodPut)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.PatchTask))).Methods(http.MethodPatch)
 	s.Handle("/{id}", Auth(http.HandlerFunc(deps.DeleteTask))).Methods(http.MethodDelete)
+	s.Handle("/{id}/history", Auth(http.HandlerFunc(deps.ListHistory))).Methods(http.MethodGet)
+	s.Handle("/{id}/occurrences", Auth(http.HandlerFunc(deps.ListOccurrences))).Methods(http.MethodGet)
+	s.Handle("/{id}/subtasks", Auth(http.HandlerFunc(deps.ListSubtasks))).Methods(http.MethodGet)
+	s.Handle("/{id}/subtasks", Auth(http.HandlerFunc(deps.CreateSubtask))).Methods(http.MethodPost)
+	s.Handle("/{id}/subtasks/{subId}", Auth(http.HandlerFunc(deps.AttachSubtask))).Methods(http.MethodPut)
+	s.Handle("/{id}/subtasks/{subId}", Auth(http.HandlerFunc(deps.DeleteSubtask))).Methods(http.MethodDelete)
+	s.Handle("/{id}/checklist", Auth(http.HandlerFunc(deps.ListChecklist))).Methods(http.MethodGet)
+	s.Handle("/{id}/checklist", Auth(http.HandlerFunc(deps.AddChecklistItem))).Methods(http.MethodPost)
+	s.Handle("/{id}/checklist/{itemId}", Auth(http.HandlerFunc(deps.UpdateChecklistItem))).Methods(http.MethodPut)
+	s.Handle("/{id}/checklist/{itemId}", Auth(http.HandlerFunc(deps.DeleteChecklistItem))).Methods(http.MethodDelete)
+	s.Handle("/{id}/comments", Auth(http.HandlerFunc(deps.ListComments))).Methods(http.MethodGet)
+	s.Handle("/{id}/comments", Auth(http.HandlerFunc(deps.AddComment))).Methods(http.MethodPost)
+	s.Handle("/{id}/comments/{commentId}", Auth(http.HandlerFunc(deps.UpdateComment))).Methods(http.MethodPatch)
+	s.Handle("/{id}/comments/{commentId}", Auth(http.HandlerFunc(deps.DeleteComment))).Methods(http.MethodDelete)
 }
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/auth"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+// doJSON 以指定用户身份发送 JSON 请求
+func doJSON(t *testing.T, h http.Handler, method, path, userID string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("Failed to marshal request body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		token, err := auth.GenerateJWT(userID)
+		if err != nil {
+			t.Fatalf("Failed to generate JWT: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+// withContentType 将经过 h 的请求的 Content-Type 改为 contentType
+func withContentType(h http.Handler, contentType string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("Content-Type", contentType)
+		h.ServeHTTP(w, r)
+	})
+}
+
+func TestTaskHandlersCRUD(t *testing.T) {
+	r := mux.NewRouter()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: memory.NewTaskRepository()})
+
+	w := doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{
+		"title":    "写周报",
+		"deadline": "2024-01-05",
+		"comments": []map[string]string{{"text": "先列提纲"}},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Create: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var created map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	id, _ := created["_id"].(string)
+	if id == "" || created["status"] != "To Do" || created["priority"] != "Medium" {
+		t.Fatalf("Unexpected created task: %v", created)
+	}
+	if created["deadline"] != "2024-01-05T00:00:00Z" {
+		t.Errorf("Expected parsed deadline, got %v", created["deadline"])
+	}
+
+	w = doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"status": "Done"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Update: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var updated map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &updated)
+	if updated["status"] != "Done" || updated["title"] != "写周报" {
+		t.Errorf("Unexpected updated task: %v", updated)
+	}
+
+	// 其他用户看不到该任务
+	if w = doJSON(t, r, http.MethodGet, "/api/tasks/"+id, "u2", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Get by other user: expected 404, got %d", w.Code)
+	}
+
+	w = doJSON(t, r, http.MethodGet, "/api/tasks", "u1", nil)
+	var list []map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list) != 1 || list[0]["_id"] != id {
+		t.Errorf("Expected one task in list, got %v", list)
+	}
+
+	if w = doJSON(t, r, http.MethodDelete, "/api/tasks/"+id, "u1", nil); w.Code != http.StatusOK {
+		t.Errorf("Delete: expected 200, got %d", w.Code)
+	}
+	if w = doJSON(t, r, http.MethodGet, "/api/tasks/"+id, "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Get after delete: expected 404, got %d", w.Code)
+	}
+}
+
+func TestTaskHandlersValidation(t *testing.T) {
+	r := mux.NewRouter()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: memory.NewTaskRepository()})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		userID         string
+		body           interface{}
+		expectedStatus int
+	}{
+		{"缺少令牌", http.MethodGet, "/api/tasks", "", nil, http.StatusUnauthorized},
+		{"缺少标题", http.MethodPost, "/api/tasks", "u1", map[string]string{"title": " "}, http.StatusBadRequest},
+		{"无效状态", http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "t", "status": "Blocked"}, http.StatusBadRequest},
+		{"无效日期", http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "t", "deadline": "2024/01/05"}, http.StatusBadRequest},
+		{"无效优先级", http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "t", "priority": "Urgent"}, http.StatusBadRequest},
+		{"更新不存在的任务", http.MethodPut, "/api/tasks/missing", "u1", map[string]string{"title": "t"}, http.StatusNotFound},
+		{"合并补丁不是对象", http.MethodPatch, "/api/tasks/missing", "u1", []string{"title"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doJSON(t, r, tt.method, tt.path, tt.userID, tt.body)
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Response: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPatchTask(t *testing.T) {
+	r := mux.NewRouter()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: memory.NewTaskRepository()})
+	id := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{
+		"title": "写周报", "description": "本周进展", "priority": "High", "deadline": "2024-01-05",
+		"recurrence": map[string]string{"rule": "FREQ=WEEKLY"}}), 200)["_id"].(string)
+
+	// 省略的字段保持不变，null 清空字段，对象逐字段合并
+	patched := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]interface{}{
+		"title": "写月报", "description": nil, "recurrence": map[string]string{"tzid": "Asia/Shanghai"}}), 200)
+	rec, _ := patched["recurrence"].(map[string]interface{})
+	if patched["title"] != "写月报" || patched["description"] != "" || patched["priority"] != "High" ||
+		patched["deadline"] != "2024-01-05T00:00:00Z" || rec["rule"] != "FREQ=WEEKLY" || rec["tzid"] != "Asia/Shanghai" {
+		t.Errorf("Unexpected patched task: %v", patched)
+	}
+	if cleared := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]interface{}{
+		"recurrence": nil, "deadline": nil}), 200); cleared["deadline"] != nil || cleared["recurrence"] != nil {
+		t.Errorf("Expected deadline and recurrence cleared, got %v", cleared)
+	}
+	if w := doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]interface{}{"title": nil}); w.Code != http.StatusBadRequest {
+		t.Errorf("Null title: expected 400, got %d", w.Code)
+	}
+	if msg := decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"deadline": "下周五"}), 400)["msg"]; msg != "Invalid date format" {
+		t.Errorf("Invalid deadline: got %v", msg)
+	}
+	if w := doJSON(t, withContentType(r, "text/plain"), http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"title": "x"}); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Wrong content type: expected 415, got %d", w.Code)
+	}
+	decodeMap(t, doJSON(t, withContentType(r, "application/merge-patch+json; charset=utf-8"), http.MethodPatch, "/api/tasks/"+id, "u1", map[string]string{"assignee": "alice"}), 200)
+
+	// PUT 整体替换，省略的字段恢复为空或默认值
+	replaced := decodeMap(t, doJSON(t, r, http.MethodPut, "/api/tasks/"+id, "u1", map[string]string{"title": "写年报", "scheduledDate": "2024-02-01 09:00:00"}), 200)
+	if replaced["assignee"] != nil || replaced["priority"] != "Medium" || replaced["status"] != "To Do" ||
+		replaced["scheduledDate"] != "2024-02-01T09:00:00Z" || replaced["version"] != 5.0 {
+		t.Errorf("Unexpected replaced task: %v", replaced)
+	}
+	if w := doJSON(t, r, http.MethodPut, "/api/tasks/"+id, "u1", map[string]string{"description": "缺少标题"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Replace without title: expected 400, got %d", w.Code)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	// RFC 7396 附录 A 的示例
+	for _, tc := range []struct{ target, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	} {
+		var target, patch interface{}
+		_ = json.Unmarshal([]byte(tc.target), &target)
+		_ = json.Unmarshal([]byte(tc.patch), &patch)
+		if got, _ := json.Marshal(mergePatch(target, patch)); string(got) != tc.want {
+			t.Errorf("mergePatch(%s, %s) = %s, want %s", tc.target, tc.patch, got, tc.want)
+		}
+	}
+}
+
+func TestListTasksQuery(t *testing.T) {
+	r := mux.NewRouter()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: memory.NewTaskRepository()})
+	for _, task := range []map[string]string{
+		{"title": "b", "priority": "High", "deadline": "2024-02-01", "assignee": "alice"},
+		{"title": "a", "priority": "Low", "deadline": "2024-01-15"},
+		{"title": "d", "priority": "High"},
+		{"title": "c", "priority": "High", "deadline": "2024-01-31", "assignee": "alice"},
+	} {
+		if w := doJSON(t, r, http.MethodPost, "/api/tasks", "u1", task); w.Code != http.StatusOK {
+			t.Fatalf("Create: expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	list := func(w *httptest.ResponseRecorder) []string {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("List: expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var tasks []map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &tasks)
+		titles := []string{}
+		for _, task := range tasks {
+			titles = append(titles, task["title"].(string))
+		}
+		return titles
+	}
+
+	// 截止日期止于 1 月 31 日当天（含），空截止日期不参与
+	w := doJSON(t, r, http.MethodGet, "/api/tasks?priority=High&deadlineTo=2024-01-31&sort=title", "u1", nil)
+	if got := list(w); len(got) != 1 || got[0] != "c" {
+		t.Errorf("Expected [c], got %v", got)
+	}
+	w = doJSON(t, r, http.MethodGet, "/api/tasks?assignee=alice&sort=-deadline", "u1", nil)
+	if got := list(w); len(got) != 2 || got[0] != "b" || w.Header().Get("X-Total-Count") != "2" {
+		t.Errorf("Expected [b c] with total 2, got %v (%s)", got, w.Header().Get("X-Total-Count"))
+	}
+
+	// 按 Link 头逐页读取
+	var titles []string
+	path := "/api/tasks?sort=title&limit=3"
+	for path != "" {
+		w = doJSON(t, r, http.MethodGet, path, "u1", nil)
+		titles = append(titles, list(w)...)
+		if w.Header().Get("X-Total-Count") != "4" {
+			t.Errorf("Expected X-Total-Count 4, got %q", w.Header().Get("X-Total-Count"))
+		}
+		path = ""
+		if link := w.Header().Get("Link"); link != "" {
+			if !strings.HasPrefix(link, "</api/tasks?") || !strings.HasSuffix(link, `>; rel="next"`) {
+				t.Fatalf("Unexpected Link header %q", link)
+			}
+			path = strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`)
+		}
+	}
+	if strings.Join(titles, "") != "abcd" {
+		t.Errorf("Expected abcd across pages, got %v", titles)
+	}
+
+	for _, q := range []string{"sort=priority", "limit=0", "limit=x", "cursor=bogus", "createdFrom=yesterday", "status=Blocked"} {
+		if w := doJSON(t, r, http.MethodGet, "/api/tasks?"+q, "u1", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", q, w.Code)
+		}
+	}
+
+	// 查询语言与普通参数同时生效
+	w = doJSON(t, r, http.MethodGet, "/api/tasks?sort=title&q="+url.QueryEscape("priority:high -assignee:none OR due:none"), "u1", nil)
+	if got := strings.Join(list(w), ""); got != "bcd" {
+		t.Errorf("Expected bcd, got %v", got)
+	}
+	w = doJSON(t, r, http.MethodGet, "/api/tasks?sort=title&priority=High&q="+url.QueryEscape("due<2024-02-01"), "u1", nil)
+	if got := strings.Join(list(w), ""); got != "c" {
+		t.Errorf("Expected c, got %v", got)
+	}
+
+	// 语法错误返回出错位置
+	w = doJSON(t, r, http.MethodGet, "/api/tasks?q="+url.QueryEscape("priority:high (due<7d"), "u1", nil)
+	var body struct {
+		Msg   string `json:"msg"`
+		Error struct {
+			Pos     int    `json:"pos"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusBadRequest || body.Msg != "Invalid query" || body.Error.Pos != 14 || body.Error.Message == "" {
+		t.Errorf("Expected 400 with position 14, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/trash"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type TrashDeps struct {
+	Tasks   repository.TaskRepository
+	Reports repository.ReportRepository
+}
+
+// ListTrash 获取回收站
+// @Summary 获取回收站中的任务与报表
+// @Description 返回已删除但尚未永久删除的任务与报表，均按删除时间倒序排列；超过保留期限的记录会被定时清理
+// @Tags 回收站
+// @Produce json
+// @Success 200 {object} map[string]interface{} "回收站内容"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/trash [get]
+func (d *TrashDeps) ListTrash(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	tasks, err := d.Tasks.List(ctx, repository.TaskFilter{UserID: uid, Trash: repository.OnlyTrashed})
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	reports, err := d.Reports.List(ctx, repository.ReportFilter{UserID: uid, Trash: repository.OnlyTrashed})
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].DeletedAt.After(*tasks[j].DeletedAt) })
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].DeletedAt.After(*reports[j].DeletedAt) })
+	taskOut := make([]bson.M, 0, len(tasks))
+	for i := range tasks {
+		taskOut = append(taskOut, taskResponse(&tasks[i]))
+	}
+	reportOut := make([]bson.M, 0, len(reports))
+	for i := range reports {
+		reportOut = append(reportOut, reportResponse(&reports[i]))
+	}
+	JSON(w, 200, map[string]interface{}{"tasks": taskOut, "reports": reportOut})
+}
+
+// RestoreTask 恢复任务
+// @Summary 从回收站恢复任务
+// @Description 恢复任务及与其一同删除的子任务；父任务已不存在时恢复为顶层任务
+// @Tags 回收站
+// @Produce json
+// @Param id path string true "任务ID"
+// @Success 200 {object} map[string]interface{} "恢复成功"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "回收站中没有该任务"
+// @Router /api/trash/tasks/{id}/restore [post]
+func (d *TrashDeps) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	n, err := trash.RestoreTask(ctx, d.Tasks, uid, mux.Vars(r)["id"])
+	if err != nil {
+		trashTaskError(w, err)
+		return
+	}
+	JSON(w, 200, map[string]interface{}{"msg": "Task restored", "tasksRestored": n})
+}
+
+// PurgeTask 永久删除任务
+// @Summary 永久删除回收站中的任务
+// @Description 永久删除任务及其全部子任务，并从其他任务的阻塞列表中移除；报表中将不再显示这些任务
+// @Tags 回收站
+// @Produce json
+// @Param id path string true "任务ID"
+// @Success 200 {object} map[string]interface{} "删除成功"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "回收站中没有该任务"
+// @Router /api/trash/tasks/{id} [delete]
+func (d *TrashDeps) PurgeTask(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	n, err := trash.PurgeTask(ctx, d.Tasks, uid, mux.Vars(r)["id"], time.Now())
+	if err != nil {
+		trashTaskError(w, err)
+		return
+	}
+	JSON(w, 200, map[string]interface{}{"msg": "Task permanently deleted", "tasksDeleted": n})
+}
+
+// trashTaskError 将回收站任务操作的错误转换为 HTTP 响应
+func trashTaskError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrNotFound) {
+		JSON(w, 404, map[string]string{"msg": "Task not found in trash"})
+		return
+	}
+	JSON(w, 500, map[string]string{"msg": "DB error"})
+}
+
+// POST /api/trash/reports/{id}/restore
+func (d *TrashDeps) RestoreReport(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if err := trash.RestoreReport(ctx, d.Reports, uid, mux.Vars(r)["id"]); err != nil {
+		trashReportError(w, err)
+		return
+	}
+	JSON(w, 200, map[string]string{"msg": "Report restored"})
+}
+
+// DELETE /api/trash/reports/{id}
+func (d *TrashDeps) PurgeReport(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if err := trash.PurgeReport(ctx, d.Reports, uid, mux.Vars(r)["id"]); err != nil {
+		trashReportError(w, err)
+		return
+	}
+	JSON(w, 200, map[string]string{"msg": "Report permanently deleted"})
+}
+
+// trashReportError 将回收站报表操作的错误转换为 HTTP 响应
+func trashReportError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrNotFound) {
+		JSON(w, 404, map[string]string{"msg": "Report not found in trash"})
+		return
+	}
+	JSON(w, 500, map[string]string{"msg": "DB error"})
+}
+
+func SetupTrashRoutes(r *mux.Router, deps *TrashDeps) {
+	s := r.PathPrefix("/api/trash").Subrouter()
+	s.Handle("", Auth(http.HandlerFunc(deps.ListTrash))).Methods(http.MethodGet)
+	s.Handle("/tasks/{id}/restore", Auth(http.HandlerFunc(deps.RestoreTask))).Methods(http.MethodPost)
+	s.Handle("/tasks/{id}", Auth(http.HandlerFunc(deps.PurgeTask))).Methods(http.MethodDelete)
+	s.Handle("/reports/{id}/restore", Auth(http.HandlerFunc(deps.RestoreReport))).Methods(http.MethodPost)
+	s.Handle("/reports/{id}", Auth(http.HandlerFunc(deps.PurgeReport))).Methods(http.MethodDelete)
+}
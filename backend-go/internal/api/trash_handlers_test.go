@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestTrash(t *testing.T) {
+	r := mux.NewRouter()
+	tasks := memory.NewTaskRepository()
+	reports := memory.NewReportRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks})
+	SetupReportRoutes(r, &ReportDeps{Reports: reports, Tasks: tasks})
+	SetupTrashRoutes(r, &TrashDeps{Tasks: tasks, Reports: reports})
+
+	parent := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]string{"title": "发布"}), 200)["_id"].(string)
+	decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks/"+parent+"/subtasks", "u1", map[string]string{"title": "打包"}), 200)
+	rep := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/reports/generate", "u1", map[string]string{
+		"type": "weekly", "period": "W1", "startDate": "2000-01-01", "endDate": "2999-12-31"}), 200)["_id"].(string)
+
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/tasks/"+parent, "u1", nil), 200)
+	if w := doJSON(t, r, http.MethodGet, "/api/tasks/"+parent, "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Trashed task: expected 404, got %d", w.Code)
+	}
+
+	// 报表仍展示已删除的任务，并带有删除时间
+	populated := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/reports/"+rep, "u1", nil), 200)["tasks"].([]interface{})
+	if len(populated) != 2 {
+		t.Fatalf("Expected deleted tasks in report, got %v", populated)
+	}
+	for _, p := range populated {
+		if p.(map[string]interface{})["deletedAt"] == nil {
+			t.Errorf("Expected deleted task to carry deletedAt, got %v", p)
+		}
+	}
+
+	trash := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/trash", "u1", nil), 200)
+	if len(trash["tasks"].([]interface{})) != 2 || len(trash["reports"].([]interface{})) != 0 {
+		t.Errorf("Unexpected trash %v", trash)
+	}
+	if got := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/trash", "u2", nil), 200); len(got["tasks"].([]interface{})) != 0 {
+		t.Errorf("Expected other user's trash to be empty, got %v", got)
+	}
+	if w := doJSON(t, r, http.MethodPost, "/api/trash/tasks/"+parent+"/restore", "u2", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Other user's restore: expected 404, got %d", w.Code)
+	}
+	if got := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/trash/tasks/"+parent+"/restore", "u1", nil), 200); got["tasksRestored"] != 2.0 {
+		t.Errorf("Expected 2 tasks restored, got %v", got)
+	}
+	if w := doJSON(t, r, http.MethodDelete, "/api/trash/tasks/"+parent, "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Purge live task: expected 404, got %d", w.Code)
+	}
+
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/tasks/"+parent, "u1", nil), 200)
+	if got := decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/trash/tasks/"+parent, "u1", nil), 200); got["tasksDeleted"] != 2.0 {
+		t.Errorf("Expected 2 tasks deleted, got %v", got)
+	}
+	if populated := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/reports/"+rep, "u1", nil), 200)["tasks"].([]interface{}); len(populated) != 0 {
+		t.Errorf("Expected purged tasks to leave the report, got %v", populated)
+	}
+
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/reports/"+rep, "u1", nil), 200)
+	if w := doJSON(t, r, http.MethodGet, "/api/reports/"+rep, "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Trashed report: expected 404, got %d", w.Code)
+	}
+	if got := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/trash", "u1", nil), 200); len(got["reports"].([]interface{})) != 1 {
+		t.Errorf("Expected report in trash, got %v", got)
+	}
+	decodeMap(t, doJSON(t, r, http.MethodPost, "/api/trash/reports/"+rep+"/restore", "u1", nil), 200)
+	decodeMap(t, doJSON(t, r, http.MethodGet, "/api/reports/"+rep, "u1", nil), 200)
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/reports/"+rep, "u1", nil), 200)
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/trash/reports/"+rep, "u1", nil), 200)
+	if w := doJSON(t, r, http.MethodPost, "/api/trash/reports/"+rep+"/restore", "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Restore purged report: expected 404, got %d", w.Code)
+	}
+}
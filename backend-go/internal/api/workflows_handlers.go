@@ -0,0 +1,355 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/taskedit"
+	"github.com/axfinn/todoIng/backend-go/internal/taskproject"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+// WorkflowDeps 工作流管理；Projects 用于校验项目工作流所属的项目，Tasks 用于检查被移除的状态是否仍在使用
+type WorkflowDeps struct {
+	Workflows repository.WorkflowRepository
+	Projects  repository.ProjectRepository
+	Tasks     repository.TaskRepository
+}
+
+type workflowRequest struct {
+	Name        *string                      `json:"name"`
+	ProjectID   *string                      `json:"projectId"` // 仅创建时有效，省略表示用户默认工作流
+	Statuses    *[]models.WorkflowStatus     `json:"statuses"`
+	Transitions *[]models.WorkflowTransition `json:"transitions"` // 为空表示任意状态之间均可流转
+	Priorities  *[]string                    `json:"priorities"`  // 从低到高排列，省略时使用 Low、Medium、High
+}
+
+// workflowResponse 与其他资源一致使用 _id 字段；内置工作流的 _id 为空
+func workflowResponse(wf *models.Workflow) bson.M {
+	transitions := wf.Transitions
+	if transitions == nil {
+		transitions = []models.WorkflowTransition{}
+	}
+	return bson.M{
+		"_id":         wf.ID,
+		"name":        wf.Name,
+		"projectId":   wf.ProjectID,
+		"statuses":    wf.Statuses,
+		"transitions": transitions,
+		"priorities":  wf.Priorities,
+		"createdAt":   wf.CreatedAt,
+		"updatedAt":   wf.UpdatedAt,
+	}
+}
+
+// workflowError 将工作流存储与校验错误转换为 HTTP 响应
+func workflowError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		JSON(w, 404, map[string]string{"msg": "Workflow not found"})
+	case errors.Is(err, repository.ErrConflict):
+		JSON(w, 409, map[string]string{"msg": "Workflow already exists for this scope"})
+	case errors.Is(err, workflow.ErrInvalid):
+		JSON(w, 400, map[string]string{"msg": "Invalid workflow", "error": err.Error()})
+	default:
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+	}
+}
+
+// loadWorkflows 加载用户的工作流，出错时写入 500 响应
+func loadWorkflows(ctx context.Context, w http.ResponseWriter, repo repository.WorkflowRepository, uid string) (*workflow.Set, bool) {
+	flows, err := workflow.Load(ctx, repo, uid)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return nil, false
+	}
+	return flows, true
+}
+
+// workflowCheckError 将状态或优先级不符合工作流的错误转换为 HTTP 响应，流转不被允许或超过 WIP 上限时返回 409
+func workflowCheckError(w http.ResponseWriter, err *taskedit.WorkflowError) {
+	switch {
+	case errors.Is(err, workflow.ErrStatus):
+		JSON(w, 400, map[string]string{"msg": "Invalid status"})
+	case errors.Is(err, workflow.ErrPriority):
+		JSON(w, 400, map[string]string{"msg": "Invalid priority"})
+	case errors.Is(err, workflow.ErrTransition):
+		JSON(w, 409, map[string]string{"msg": "Status transition not allowed", "from": err.From, "to": err.To})
+	default:
+		JSON(w, 409, map[string]string{"msg": "WIP limit reached", "status": err.To})
+	}
+}
+
+// apply 将请求中提供的字段写入工作流
+func (req *workflowRequest) apply(wf *models.Workflow) {
+	if req.Name != nil {
+		wf.Name = *req.Name
+	}
+	if req.Statuses != nil {
+		wf.Statuses = *req.Statuses
+	}
+	if req.Transitions != nil {
+		wf.Transitions = *req.Transitions
+	}
+	if req.Priorities != nil {
+		wf.Priorities = *req.Priorities
+	}
+}
+
+// ListWorkflows 获取工作流列表
+// @Summary 获取用户的工作流
+// @Description 返回当前用户的默认工作流与各项目的工作流，默认工作流在前；未配置任何工作流时返回空列表
+// @Tags 工作流
+// @Produce json
+// @Success 200 {object} []map[string]interface{} "工作流列表"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/workflows [get]
+func (d *WorkflowDeps) ListWorkflows(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	list, err := d.Workflows.List(ctx, uid)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return
+	}
+	out := make([]bson.M, 0, len(list))
+	for i := range list {
+		out = append(out, workflowResponse(&list[i]))
+	}
+	JSON(w, 200, out)
+}
+
+// EffectiveWorkflow 获取生效的工作流
+// @Summary 获取生效的工作流
+// @Description 返回 project 内任务实际使用的工作流：项目工作流优先，其次为用户默认工作流，都未配置时为内置的 To Do / In Progress / Done
+// @Tags 工作流
+// @Produce json
+// @Param project query string false "项目ID，省略表示不属于项目的任务"
+// @Success 200 {object} map[string]interface{} "工作流"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 500 {object} map[string]string "服务器内部错误"
+// @Router /api/workflows/effective [get]
+func (d *WorkflowDeps) EffectiveWorkflow(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	flows, ok := loadWorkflows(ctx, w, d.Workflows, uid)
+	if !ok {
+		return
+	}
+	var projectID *string
+	if v := r.URL.Query().Get("project"); v != "" {
+		projectID = &v
+	}
+	JSON(w, 200, workflowResponse(flows.For(projectID)))
+}
+
+// CreateWorkflow 创建工作流
+// @Summary 创建工作流
+// @Description 每个用户至多一个默认工作流，每个项目至多一个工作流。状态的 category 为 open/active/done，
+// @Description 至少需要一个 done 状态；第一个状态为新任务的初始状态；wipLimit 大于 0 时限制处于该状态的任务数
+// @Tags 工作流
+// @Accept json
+// @Produce json
+// @Param workflow body workflowRequest true "工作流定义"
+// @Success 200 {object} map[string]interface{} "创建成功"
+// @Failure 400 {object} map[string]string "工作流不合法或项目不存在"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 409 {object} map[string]string "该范围已有工作流"
+// @Router /api/workflows [post]
+func (d *WorkflowDeps) CreateWorkflow(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req workflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	now := time.Now()
+	wf := &models.Workflow{UserID: uid, CreatedAt: now, UpdatedAt: now}
+	req.apply(wf)
+	if err := workflow.Validate(wf); err != nil {
+		workflowError(w, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	if req.ProjectID != nil && *req.ProjectID != "" {
+		project, err := taskproject.Get(ctx, d.Projects, uid, *req.ProjectID)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			JSON(w, 400, map[string]string{"msg": "Project not found"})
+			return
+		case err != nil:
+			JSON(w, 500, map[string]string{"msg": "DB error"})
+			return
+		}
+		wf.ProjectID = &project.ID
+	}
+	if err := d.Workflows.Create(ctx, wf); err != nil {
+		workflowError(w, err)
+		return
+	}
+	JSON(w, 200, workflowResponse(wf))
+}
+
+// GetWorkflow 获取工作流详情
+// @Summary 获取工作流详情
+// @Tags 工作流
+// @Produce json
+// @Param id path string true "工作流ID"
+// @Success 200 {object} map[string]interface{} "工作流"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "工作流不存在"
+// @Router /api/workflows/{id} [get]
+func (d *WorkflowDeps) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	wf, err := d.Workflows.Get(ctx, uid, mux.Vars(r)["id"])
+	if err != nil {
+		workflowError(w, err)
+		return
+	}
+	JSON(w, 200, workflowResponse(wf))
+}
+
+// UpdateWorkflow 修改工作流
+// @Summary 修改工作流
+// @Description 未提供的字段保持不变，statuses、transitions、priorities 整体替换；所属范围不能修改。
+// @Description 移除仍有任务使用的状态时返回 409 及这些状态，需先将任务改为其他状态
+// @Tags 工作流
+// @Accept json
+// @Produce json
+// @Param id path string true "工作流ID"
+// @Param workflow body workflowRequest true "工作流定义"
+// @Success 200 {object} map[string]interface{} "修改后的工作流"
+// @Failure 400 {object} map[string]string "工作流不合法"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "工作流不存在"
+// @Failure 409 {object} map[string]interface{} "被移除的状态仍在使用"
+// @Router /api/workflows/{id} [put]
+func (d *WorkflowDeps) UpdateWorkflow(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	var req workflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		JSON(w, 400, map[string]string{"msg": "Invalid body"})
+		return
+	}
+	if req.Name == nil && req.Statuses == nil && req.Transitions == nil && req.Priorities == nil {
+		JSON(w, 400, map[string]string{"msg": "No fields to update"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	old, err := d.Workflows.Get(ctx, uid, mux.Vars(r)["id"])
+	if err != nil {
+		workflowError(w, err)
+		return
+	}
+	wf := *old
+	req.apply(&wf)
+	if err := workflow.Validate(&wf); err != nil {
+		workflowError(w, err)
+		return
+	}
+	flows, ok := loadWorkflows(ctx, w, d.Workflows, uid)
+	if !ok || !d.checkUnused(ctx, w, flows, old, &wf) {
+		return
+	}
+	wf.UpdatedAt = time.Now()
+	if err := d.Workflows.Update(ctx, &wf); err != nil {
+		workflowError(w, err)
+		return
+	}
+	JSON(w, 200, workflowResponse(&wf))
+}
+
+// DeleteWorkflow 删除工作流
+// @Summary 删除工作流
+// @Description 删除后项目内的任务改用用户默认工作流，默认工作流删除后改用内置工作流；
+// @Description 任务使用的状态在回退后的工作流中不存在时返回 409
+// @Tags 工作流
+// @Produce json
+// @Param id path string true "工作流ID"
+// @Success 200 {object} map[string]string "删除成功"
+// @Failure 401 {object} map[string]string "未授权"
+// @Failure 404 {object} map[string]string "工作流不存在"
+// @Failure 409 {object} map[string]interface{} "状态仍在使用"
+// @Router /api/workflows/{id} [delete]
+func (d *WorkflowDeps) DeleteWorkflow(w http.ResponseWriter, r *http.Request) {
+	uid := GetUserID(r)
+	if uid == "" {
+		JSON(w, 401, map[string]string{"msg": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	old, err := d.Workflows.Get(ctx, uid, mux.Vars(r)["id"])
+	if err != nil {
+		workflowError(w, err)
+		return
+	}
+	flows, ok := loadWorkflows(ctx, w, d.Workflows, uid)
+	if !ok || !d.checkUnused(ctx, w, flows, old, flows.Fallback(old)) {
+		return
+	}
+	if err := d.Workflows.Delete(ctx, uid, old.ID); err != nil {
+		workflowError(w, err)
+		return
+	}
+	JSON(w, 200, map[string]string{"msg": "Workflow removed"})
+}
+
+// checkUnused 确认 old 中被 next 移除的状态没有任务在使用，否则返回 409
+func (d *WorkflowDeps) checkUnused(ctx context.Context, w http.ResponseWriter, flows *workflow.Set, old, next *models.Workflow) bool {
+	used, err := flows.InUse(ctx, d.Tasks, old, next)
+	if err != nil {
+		JSON(w, 500, map[string]string{"msg": "DB error"})
+		return false
+	}
+	if len(used) > 0 {
+		JSON(w, 409, map[string]interface{}{"msg": "Statuses still in use", "statuses": used})
+		return false
+	}
+	return true
+}
+
+func SetupWorkflowRoutes(r *mux.Router, deps *WorkflowDeps) {
+	s := r.PathPrefix("/api/workflows").Subrouter()
+	s.Handle("", Auth(http.HandlerFunc(deps.ListWorkflows))).Methods(http.MethodGet)
+	s.Handle("", Auth(http.HandlerFunc(deps.CreateWorkflow))).Methods(http.MethodPost)
+	s.Handle("/effective", Auth(http.HandlerFunc(deps.EffectiveWorkflow))).Methods(http.MethodGet)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.GetWorkflow))).Methods(http.MethodGet)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.UpdateWorkflow))).Methods(http.MethodPut)
+	s.Handle("/{id}", Auth(http.HandlerFunc(deps.DeleteWorkflow))).Methods(http.MethodDelete)
+}
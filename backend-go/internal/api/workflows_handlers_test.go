@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestWorkflows(t *testing.T) {
+	r := mux.NewRouter()
+	tasks, projects, workflows := memory.NewTaskRepository(), memory.NewProjectRepository(), memory.NewWorkflowRepository()
+	SetupTaskRoutes(r, &TaskDeps{Tasks: tasks, Projects: projects, Workflows: workflows})
+	SetupProjectRoutes(r, &ProjectDeps{Projects: projects, Tasks: tasks, Workflows: workflows})
+	SetupWorkflowRoutes(r, &WorkflowDeps{Workflows: workflows, Projects: projects, Tasks: tasks})
+
+	// 未配置工作流时使用内置工作流
+	builtin := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/workflows/effective", "u1", nil), 200)
+	if builtin["_id"] != "" || len(builtin["statuses"].([]interface{})) != 3 {
+		t.Errorf("Unexpected built-in workflow %v", builtin)
+	}
+
+	project := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/projects", "u1", map[string]string{"name": "开发"}), 200)
+	projectID := project["_id"].(string)
+	flow := map[string]interface{}{
+		"name":      "看板",
+		"projectId": projectID,
+		"statuses": []map[string]interface{}{
+			{"name": "Backlog", "category": "open"},
+			{"name": "Code Review", "category": "active", "wipLimit": 1},
+			{"name": "Shipped", "category": "done"},
+		},
+		"transitions": []map[string]string{
+			{"from": "Backlog", "to": "Code Review"},
+			{"from": "Code Review", "to": "Shipped"},
+		},
+		"priorities": []string{"P3", "P2", "P1"},
+	}
+	created := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/workflows", "u1", flow), 200)
+	flowID := created["_id"].(string)
+	if w := doJSON(t, r, http.MethodPost, "/api/workflows", "u1", flow); w.Code != http.StatusConflict {
+		t.Errorf("Second workflow for project: expected 409, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodPost, "/api/workflows", "u1", map[string]interface{}{
+		"name": "无完成状态", "statuses": []map[string]string{{"name": "Open", "category": "open"}},
+	}); w.Code != http.StatusBadRequest {
+		t.Errorf("Workflow without done status: expected 400, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodGet, "/api/workflows/"+flowID, "u2", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Other user's workflow: expected 404, got %d", w.Code)
+	}
+
+	// 项目内的任务使用项目工作流的初始状态与居中的优先级
+	task := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{"title": "登录页", "projectId": projectID}), 200)
+	taskID := task["_id"].(string)
+	if task["status"] != "Backlog" || task["priority"] != "P2" {
+		t.Errorf("Expected workflow defaults, got %v %v", task["status"], task["priority"])
+	}
+	if w := doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{"title": "x", "projectId": projectID, "status": "To Do"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Status outside workflow: expected 400, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{"title": "x", "status": "Backlog"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Project status outside project: expected 400, got %d", w.Code)
+	}
+
+	// 流转受限，WIP 上限按状态计数
+	if w := doJSON(t, r, http.MethodPatch, "/api/tasks/"+taskID, "u1", map[string]string{"status": "Shipped"}); w.Code != http.StatusConflict {
+		t.Errorf("Disallowed transition: expected 409, got %d", w.Code)
+	}
+	decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+taskID, "u1", map[string]string{"status": "Code Review"}), 200)
+	other := decodeMap(t, doJSON(t, r, http.MethodPost, "/api/tasks", "u1", map[string]interface{}{"title": "注册页", "projectId": projectID}), 200)
+	if w := doJSON(t, r, http.MethodPatch, "/api/tasks/"+other["_id"].(string), "u1", map[string]string{"status": "Code Review"}); w.Code != http.StatusConflict {
+		t.Errorf("WIP limit: expected 409, got %d", w.Code)
+	}
+	decodeMap(t, doJSON(t, r, http.MethodPatch, "/api/tasks/"+taskID, "u1", map[string]string{"status": "Shipped"}), 200)
+
+	// 统计按状态类别计算
+	got := decodeMap(t, doJSON(t, r, http.MethodGet, "/api/projects/"+projectID, "u1", nil), 200)
+	if stats := got["statistics"].(map[string]interface{}); stats["totalTasks"] != float64(2) || stats["completedTasks"] != float64(1) {
+		t.Errorf("Unexpected statistics %v", stats)
+	}
+	w := doJSON(t, r, http.MethodGet, "/api/tasks?status=Shipped", "u1", nil)
+	var shipped []interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &shipped); err != nil || len(shipped) != 1 {
+		t.Errorf("Expected 1 shipped task, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// 仍有任务使用的状态不能删除
+	if w := doJSON(t, r, http.MethodPut, "/api/workflows/"+flowID, "u1", map[string]interface{}{
+		"statuses":    []map[string]string{{"name": "Backlog", "category": "open"}, {"name": "Done", "category": "done"}},
+		"transitions": []map[string]string{},
+	}); w.Code != http.StatusConflict {
+		t.Errorf("Removing used status: expected 409, got %d", w.Code)
+	}
+	if w := doJSON(t, r, http.MethodDelete, "/api/workflows/"+flowID, "u1", nil); w.Code != http.StatusConflict {
+		t.Errorf("Deleting used workflow: expected 409, got %d", w.Code)
+	}
+	renamed := decodeMap(t, doJSON(t, r, http.MethodPut, "/api/workflows/"+flowID, "u1", map[string]string{"name": "发布流程"}), 200)
+	if renamed["name"] != "发布流程" || renamed["projectId"] != projectID {
+		t.Errorf("Unexpected updated workflow %v", renamed)
+	}
+
+	// 删除项目时一并删除项目工作流
+	decodeMap(t, doJSON(t, r, http.MethodDelete, "/api/projects/"+projectID, "u1", nil), 200)
+	if w := doJSON(t, r, http.MethodGet, "/api/workflows/"+flowID, "u1", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Workflow of deleted project: expected 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,257 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	httpSwagger "github.com/swaggo/http-swagger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/axfinn/todoIng/backend-go/internal/api"
+	"github.com/axfinn/todoIng/backend-go/internal/captcha"
+	"github.com/axfinn/todoIng/backend-go/internal/email"
+	"github.com/axfinn/todoIng/backend-go/internal/gateway"
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/mongodb"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/sqlstore"
+	"github.com/axfinn/todoIng/backend-go/internal/search"
+	"github.com/axfinn/todoIng/backend-go/internal/services"
+	"github.com/axfinn/todoIng/backend-go/internal/taskhistory"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+)
+
+// App 各入口共享的运行时依赖：数据仓库和一套验证码存储
+type App struct {
+	Tasks      repository.TaskRepository
+	Reports    repository.ReportRepository
+	Users      repository.UserRepository
+	Labels     repository.LabelRepository
+	Projects   repository.ProjectRepository
+	Workflows  repository.WorkflowRepository
+	History    repository.HistoryRepository
+	Index      repository.SearchRepository
+	Search     *search.Service
+	EmailCodes *email.Store
+	Captchas   *captcha.Store
+
+	services gateway.Servers             // 各服务实现，只在 New 中创建一次
+	closeDB  func(context.Context) error // 内存模式下为 nil
+}
+
+// New 选择存储后端并初始化验证码存储：STORAGE=memory 使用进程内存储，无需外部服务；
+// 否则按 DATABASE_URI（未设置时回退到 MONGO_URI）的 scheme 选择 MongoDB 或 SQLite/PostgreSQL
+func New(ctx context.Context) (*App, error) {
+	a := &App{
+		EmailCodes: email.NewStore(10*time.Minute, 3),
+		Captchas:   captcha.NewStore(5 * time.Minute),
+	}
+
+	switch storage := os.Getenv("STORAGE"); storage {
+	case "memory":
+		observability.LogWarn("Using in-memory storage, data will be lost on restart")
+		a.Tasks = memory.NewTaskRepository()
+		a.Reports = memory.NewReportRepository()
+		a.Users = memory.NewUserRepository()
+		a.Labels = memory.NewLabelRepository()
+		a.Projects = memory.NewProjectRepository()
+		a.Workflows = memory.NewWorkflowRepository()
+		a.History = memory.NewHistoryRepository()
+		a.Index = memory.NewSearchRepository()
+	case "", "mongo":
+		if err := a.connect(ctx); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown STORAGE %q", storage)
+	}
+
+	// 任务历史包装在存储之外，HTTP 与 gRPC 的写操作都会记录历史
+	a.Tasks = taskhistory.NewTaskRepository(a.Tasks, a.History)
+
+	// 全文索引包装在存储之外，HTTP 与 gRPC 的写操作都会同步更新数据库中的索引
+	a.Tasks = search.NewTaskRepository(a.Tasks, a.Index)
+	a.Reports = search.NewReportRepository(a.Reports, a.Index)
+	a.Search = &search.Service{Store: a.Index, Tasks: a.Tasks, Reports: a.Reports}
+
+	// gRPC 与 /api/v2 网关共用同一组服务实例
+	a.services = gateway.Servers{
+		Auth:    services.NewAuthService(a.Users, a.EmailCodes),
+		Captcha: services.NewCaptchaService(a.Captchas),
+		Task:    services.NewTaskService(a.Tasks, a.Labels, a.Projects, a.Workflows),
+		Report:  services.NewReportService(a.Reports, a.Tasks, a.Projects, a.Workflows, a.History),
+	}
+	return a, nil
+}
+
+// connect 按连接串的 scheme 连接数据库并创建对应的数据仓库
+func (a *App) connect(ctx context.Context) error {
+	uri := os.Getenv("DATABASE_URI")
+	if uri == "" {
+		uri = os.Getenv("MONGO_URI")
+	}
+	switch {
+	case uri == "":
+		return errors.New("DATABASE_URI not set")
+	case strings.HasPrefix(uri, "mongodb://"), strings.HasPrefix(uri, "mongodb+srv://"):
+		return a.connectMongo(ctx, uri)
+	case sqlstore.IsURI(uri):
+		return a.connectSQL(ctx, uri)
+	default:
+		return fmt.Errorf("unsupported database URI scheme in %q", uri)
+	}
+}
+
+// connectSQL 打开 SQLite/PostgreSQL 数据库，执行迁移并创建对应的数据仓库
+func (a *App) connectSQL(ctx context.Context, uri string) error {
+	db, err := sqlstore.Open(ctx, uri)
+	if err != nil {
+		return err
+	}
+	observability.LogInfo("SQL database connected successfully")
+
+	a.closeDB = func(context.Context) error { return db.Close() }
+	a.Tasks = sqlstore.NewTaskRepository(db)
+	a.Reports = sqlstore.NewReportRepository(db)
+	a.Users = sqlstore.NewUserRepository(db)
+	a.Labels = sqlstore.NewLabelRepository(db)
+	a.Projects = sqlstore.NewProjectRepository(db)
+	a.Workflows = sqlstore.NewWorkflowRepository(db)
+	a.History = sqlstore.NewHistoryRepository(db)
+	a.Index = sqlstore.NewSearchRepository(db)
+	return nil
+}
+
+// connectMongo 连接 MongoDB 并创建对应的数据仓库
+func (a *App) connectMongo(ctx context.Context, mongoURI string) error {
+	observability.LogInfo("Connecting to MongoDB at %s", mongoURI)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	if err != nil {
+		return err
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return err
+	}
+	observability.LogInfo("MongoDB connected successfully")
+
+	db := client.Database("todoing")
+	a.closeDB = client.Disconnect
+	a.Tasks = mongodb.NewTaskRepository(db)
+	a.Reports = mongodb.NewReportRepository(db)
+	a.Users = mongodb.NewUserRepository(db)
+	a.Labels = mongodb.NewLabelRepository(db)
+	a.Projects = mongodb.NewProjectRepository(db)
+	a.Workflows = mongodb.NewWorkflowRepository(db)
+	a.History = mongodb.NewHistoryRepository(db)
+	a.Index = mongodb.NewSearchRepository(db)
+	return nil
+}
+
+// HTTPHandler 构建 HTTP 路由：/api 旧接口、/api/v2 网关、文档与健康检查
+func (a *App) HTTPHandler(ctx context.Context) (http.Handler, error) {
+	r := api.NewRouter()
+
+	// Swagger 文档路由
+	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+
+	// 静态文件服务 - API 文档
+	docsHandler := http.StripPrefix("/docs/", http.FileServer(http.Dir("docs/")))
+	r.PathPrefix("/docs/").Handler(docsHandler)
+
+	// 完整 API 文档路由
+	r.HandleFunc("/api-docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		http.ServeFile(w, r, "docs/api_complete.json")
+	}).Methods(http.MethodGet)
+
+	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}).Methods(http.MethodGet)
+
+	api.SetupAuthRoutes(r, &api.AuthDeps{Users: a.Users, EmailCodes: a.EmailCodes})
+	api.SetupCaptchaRoutes(r, &api.CaptchaDeps{Store: a.Captchas})
+	api.SetupTaskRoutes(r, &api.TaskDeps{Tasks: a.Tasks, Labels: a.Labels, Projects: a.Projects, Workflows: a.Workflows, History: a.History})
+	api.SetupLabelRoutes(r, &api.LabelDeps{Labels: a.Labels, Tasks: a.Tasks})
+	api.SetupProjectRoutes(r, &api.ProjectDeps{Projects: a.Projects, Tasks: a.Tasks, Workflows: a.Workflows})
+	api.SetupWorkflowRoutes(r, &api.WorkflowDeps{Workflows: a.Workflows, Projects: a.Projects, Tasks: a.Tasks})
+	api.SetupReportRoutes(r, &api.ReportDeps{Reports: a.Reports, Tasks: a.Tasks, Projects: a.Projects, Workflows: a.Workflows, History: a.History})
+	api.SetupSearchRoutes(r, &api.SearchDeps{Service: a.Search})
+	api.SetupTrashRoutes(r, &api.TrashDeps{Tasks: a.Tasks, Reports: a.Reports})
+
+	// /api/v2: 基于 proto HTTP 注解的 REST 网关，与 gRPC 共用同一套服务实现
+	gw, err := gateway.NewHandler(ctx, a.services)
+	if err != nil {
+		return nil, err
+	}
+	r.PathPrefix("/api/v2/").Handler(gw)
+	observability.LogInfo("All API routes configured")
+
+	return r, nil
+}
+
+// GRPCServer 创建注册了全部服务和认证拦截器的 gRPC 服务器
+func (a *App) GRPCServer() *grpc.Server {
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(services.UnaryAuthInterceptor(services.PublicMethods)),
+		grpc.StreamInterceptor(services.StreamAuthInterceptor(services.PublicMethods)),
+	)
+
+	pb.RegisterAuthServiceServer(grpcServer, a.services.Auth)
+	pb.RegisterTaskServiceServer(grpcServer, a.services.Task)
+	pb.RegisterReportServiceServer(grpcServer, a.services.Report)
+	pb.RegisterCaptchaServiceServer(grpcServer, a.services.Captcha)
+
+	// 启用反射（用于 grpcurl 等工具）
+	reflection.Register(grpcServer)
+	return grpcServer
+}
+
+// EnsureDefaultUser 按 DEFAULT_USERNAME/DEFAULT_PASSWORD/DEFAULT_EMAIL 创建默认用户
+func (a *App) EnsureDefaultUser(ctx context.Context) {
+	observability.LogInfo("Starting default user creation check...")
+	username := os.Getenv("DEFAULT_USERNAME")
+	password := os.Getenv("DEFAULT_PASSWORD")
+	emailAddr := os.Getenv("DEFAULT_EMAIL")
+	if username == "" || password == "" || emailAddr == "" {
+		observability.LogWarn("Default user environment variables not set, skipping default user creation")
+		return
+	}
+	ctxDef, cancelDef := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelDef()
+	_, err := a.Users.FindByEmailOrUsername(ctxDef, emailAddr, username)
+	if errors.Is(err, repository.ErrNotFound) {
+		hash, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+		user := &models.User{Username: username, Email: emailAddr, Password: string(hash), CreatedAt: time.Now()}
+		if errIns := a.Users.Create(ctxDef, user); errIns != nil {
+			observability.LogError("Failed to create default user: %v", errIns)
+		} else {
+			observability.LogInfo("Default user created successfully: %s (%s)", username, emailAddr)
+		}
+	} else if err == nil {
+		observability.LogInfo("Default user already exists: %s", username)
+	} else {
+		observability.LogError("Error checking for default user: %v", err)
+	}
+}
+
+// Close 断开数据库连接
+func (a *App) Close(ctx context.Context) error {
+	if a.closeDB == nil {
+		return nil
+	}
+	return a.closeDB(ctx)
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// Server 在同一端口上按协议分流：HTTP/2 且 Content-Type 为 application/grpc 的请求
+// 交给 gRPC 服务器，其余交给 HTTP 路由。h2c 使明文 HTTP/2 (gRPC 客户端默认方式) 可用。
+//
+// h2c 连接被从 http.Server 接管，Shutdown 不会等待其上的请求；grpc.Server.ServeHTTP
+// 也不支持 GracefulStop。因此 Server 自行跟踪 HTTP/2 请求，关闭时先拒绝新请求并等待进行中的完成
+type Server struct {
+	http *http.Server
+	grpc *grpc.Server
+
+	mu       sync.Mutex
+	draining bool
+	inflight sync.WaitGroup
+}
+
+// NewServer 创建监听 addr 的服务器
+func NewServer(addr string, grpcServer *grpc.Server, httpHandler http.Handler) (*Server, error) {
+	s := &Server{grpc: grpcServer}
+	h2s := &http2.Server{}
+	s.http = &http.Server{Addr: addr, Handler: h2c.NewHandler(s.route(httpHandler), h2s)}
+	// 使 http.Server.Shutdown 向 h2c 连接发送 GOAWAY，客户端不再在其上发起新请求
+	if err := http2.ConfigureServer(s.http, h2s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ListenAndServe 监听 addr 并提供服务，Shutdown 后返回 http.ErrServerClosed
+func (s *Server) ListenAndServe() error {
+	return s.http.ListenAndServe()
+}
+
+// Serve 在 lis 上提供服务，Shutdown 后返回 http.ErrServerClosed
+func (s *Server) Serve(lis net.Listener) error {
+	return s.http.Serve(lis)
+}
+
+// Shutdown 优雅关闭：拒绝新的 HTTP/2 请求并等待进行中的 gRPC 调用与 HTTP/2 请求完成，
+// 再关闭 HTTP 服务器；ctx 结束时强制关闭剩余的连接与 gRPC 流
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.drain(ctx)
+	if herr := s.http.Shutdown(ctx); err == nil {
+		err = herr
+	}
+	if err != nil {
+		_ = s.http.Close()
+	}
+	s.grpc.Stop()
+	return err
+}
+
+// drain 拒绝新的 HTTP/2 请求并等待进行中的完成
+func (s *Server) drain(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// begin 登记一个 HTTP/2 请求，关闭过程中返回 false
+func (s *Server) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.draining {
+		return false
+	}
+	s.inflight.Add(1)
+	return true
+}
+
+// route 按协议分流；HTTP/1 请求由 http.Server.Shutdown 跟踪，这里只登记 HTTP/2 请求
+func (s *Server) route(httpHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 {
+			if !s.begin() {
+				unavailable(w, r)
+				return
+			}
+			defer s.inflight.Done()
+		}
+		if isGRPC(r) {
+			s.grpc.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
+}
+
+// unavailable 拒绝关闭过程中到达的请求，gRPC 调用以 UNAVAILABLE 结束，客户端可重连后重试
+func unavailable(w http.ResponseWriter, r *http.Request) {
+	if !isGRPC(r) {
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Grpc-Status", strconv.Itoa(int(codes.Unavailable)))
+	w.Header().Set("Grpc-Message", "server is shutting down")
+	w.WriteHeader(http.StatusOK)
+}
+
+// isGRPC 判断请求是否为 gRPC 调用
+func isGRPC(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
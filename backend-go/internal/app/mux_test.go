@@ -0,0 +1,139 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/axfinn/todoIng/backend-go/internal/captcha"
+	"github.com/axfinn/todoIng/backend-go/internal/services"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+)
+
+func TestMuxServesHTTPAndGRPC(t *testing.T) {
+	t.Setenv("ENABLE_CAPTCHA", "false")
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterCaptchaServiceServer(grpcServer, services.NewCaptchaService(captcha.NewStore(time.Minute)))
+	httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server, err := NewServer("", grpcServer, httpHandler)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	go func() { _ = server.Serve(lis) }()
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	addr := lis.Addr().String()
+
+	resp, err := http.Get("http://" + addr + "/health")
+	if err != nil {
+		t.Fatalf("HTTP request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("Unexpected HTTP response: %d %q", resp.StatusCode, body)
+	}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got, err := pb.NewCaptchaServiceClient(conn).GetCaptcha(ctx, &pb.GetCaptchaRequest{})
+	if err != nil {
+		t.Fatalf("gRPC request failed: %v", err)
+	}
+	if got.Captcha.GetId() != captcha.DisabledID {
+		t.Errorf("Expected captcha id %q, got %q", captcha.DisabledID, got.Captcha.GetId())
+	}
+}
+
+func TestServerDrainsGRPCOnShutdown(t *testing.T) {
+	t.Setenv("ENABLE_CAPTCHA", "false")
+
+	// 第一个调用阻塞到 release 关闭，模拟关闭时仍在进行中的请求
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+		return handler(ctx, req)
+	}))
+	pb.RegisterCaptchaServiceServer(grpcServer, services.NewCaptchaService(captcha.NewStore(time.Minute)))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server, err := NewServer("", grpcServer, http.NotFoundHandler())
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	go func() { _ = server.Serve(lis) }()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewCaptchaServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	inflight := make(chan error, 1)
+	go func() {
+		_, err := client.GetCaptcha(ctx, &pb.GetCaptchaRequest{})
+		inflight <- err
+	}()
+	<-started
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- server.Shutdown(ctx) }()
+	for {
+		server.mu.Lock()
+		draining := server.draining
+		server.mu.Unlock()
+		if draining {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// 关闭过程中的新调用被拒绝，进行中的调用不受影响
+	if _, err := client.GetCaptcha(ctx, &pb.GetCaptchaRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected UNAVAILABLE during shutdown, got %v", err)
+	}
+	select {
+	case err := <-stopped:
+		t.Fatalf("Shutdown returned before in-flight call finished: %v", err)
+	default:
+	}
+	close(release)
+	if err := <-inflight; err != nil {
+		t.Errorf("In-flight call failed: %v", err)
+	}
+	if err := <-stopped; err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+}
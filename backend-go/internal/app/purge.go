@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/trash"
+)
+
+const (
+	defaultTrashRetentionDays = 30
+	trashPurgeInterval        = time.Hour
+)
+
+// trashRetention 读取 TRASH_RETENTION_DAYS，未设置或无效时使用默认值；0 表示不自动清理
+func trashRetention() time.Duration {
+	days := defaultTrashRetentionDays
+	if v := os.Getenv("TRASH_RETENTION_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			observability.LogWarn("Invalid TRASH_RETENTION_DAYS %q, using %d", v, defaultTrashRetentionDays)
+		} else {
+			days = n
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// StartTrashPurge 启动后台任务，启动时及之后每小时永久删除超过保留期限的回收站记录；
+// 返回的函数停止该任务
+func (a *App) StartTrashPurge() (stop func()) {
+	retention := trashRetention()
+	if retention == 0 {
+		observability.LogInfo("Trash purge disabled")
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+		for {
+			a.purgeTrash(ctx, retention)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// purgeTrash 执行一次清理
+func (a *App) purgeTrash(ctx context.Context, retention time.Duration) {
+	now := time.Now()
+	tasks, reports, err := trash.Purge(ctx, a.Tasks, a.Reports, now.Add(-retention), now)
+	if err != nil && ctx.Err() == nil {
+		observability.LogError("Trash purge failed: %v", err)
+	}
+	if tasks > 0 || reports > 0 {
+		observability.LogInfo("Trash purge removed %d tasks and %d reports", tasks, reports)
+	}
+}
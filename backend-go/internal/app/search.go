@@ -0,0 +1,27 @@
+package app
+
+import (
+	"context"
+
+	"github.com/axfinn/todoIng/backend-go/internal/observability"
+)
+
+// StartSearchBackfill 在后台为尚未索引的任务与报表补建全文索引，如升级前已有的数据或索引写入失败的新任务，
+// 不阻塞启动；已索引的文档不被覆盖，多个进程同时补建也不冲突。返回的函数取消补建并等待其结束
+func (a *App) StartSearchBackfill() (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		n, err := a.Search.Backfill(ctx)
+		if err != nil && ctx.Err() == nil {
+			observability.LogError("Search backfill failed after %d documents: %v", n, err)
+			return
+		}
+		observability.LogInfo("Search backfill checked %d documents", n)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -43,13 +43,14 @@ func ProtoToUser(pbUser *pb.User) *models.User {
 }
 
 // TaskStatusToProto 将内部任务状态转换为 protobuf 状态
+// 同时兼容数据库中存储的 "To Do" / "In Progress" / "Done"
 func TaskStatusToProto(status string) pb.TaskStatus {
 	switch status {
-	case "todo":
+	case "todo", "To Do":
 		return pb.TaskStatus_TASK_STATUS_TODO
-	case "in_progress":
+	case "in_progress", "In Progress":
 		return pb.TaskStatus_TASK_STATUS_IN_PROGRESS
-	case "done":
+	case "done", "Done":
 		return pb.TaskStatus_TASK_STATUS_DONE
 	default:
 		return pb.TaskStatus_TASK_STATUS_UNSPECIFIED
@@ -70,14 +71,30 @@ func ProtoToTaskStatus(status pb.TaskStatus) string {
 	}
 }
 
+// TaskStatusName 将 protobuf 状态转换为 REST API 与数据库使用的状态名称
+// 未指定或未知的状态返回空字符串
+func TaskStatusName(status pb.TaskStatus) string {
+	switch status {
+	case pb.TaskStatus_TASK_STATUS_TODO:
+		return "To Do"
+	case pb.TaskStatus_TASK_STATUS_IN_PROGRESS:
+		return "In Progress"
+	case pb.TaskStatus_TASK_STATUS_DONE:
+		return "Done"
+	default:
+		return ""
+	}
+}
+
 // TaskPriorityToProto 将内部任务优先级转换为 protobuf 优先级
+// 同时兼容数据库中存储的 "Low" / "Medium" / "High"
 func TaskPriorityToProto(priority string) pb.TaskPriority {
 	switch priority {
-	case "low":
+	case "low", "Low":
 		return pb.TaskPriority_TASK_PRIORITY_LOW
-	case "medium":
+	case "medium", "Medium":
 		return pb.TaskPriority_TASK_PRIORITY_MEDIUM
-	case "high":
+	case "high", "High":
 		return pb.TaskPriority_TASK_PRIORITY_HIGH
 	default:
 		return pb.TaskPriority_TASK_PRIORITY_UNSPECIFIED
@@ -98,6 +115,21 @@ func ProtoToTaskPriority(priority pb.TaskPriority) string {
 	}
 }
 
+// TaskPriorityName 将 protobuf 优先级转换为 REST API 与数据库使用的优先级名称
+// 未指定或未知的优先级返回空字符串
+func TaskPriorityName(priority pb.TaskPriority) string {
+	switch priority {
+	case pb.TaskPriority_TASK_PRIORITY_LOW:
+		return "Low"
+	case pb.TaskPriority_TASK_PRIORITY_MEDIUM:
+		return "Medium"
+	case pb.TaskPriority_TASK_PRIORITY_HIGH:
+		return "High"
+	default:
+		return ""
+	}
+}
+
 // TaskToProto 将内部任务模型转换为 protobuf 模型
 func TaskToProto(task *models.Task) *pb.Task {
 	if task == nil {
@@ -108,20 +140,95 @@ func TaskToProto(task *models.Task) *pb.Task {
 	if task.Deadline != nil && !task.Deadline.IsZero() {
 		dueDate = timestamppb.New(*task.Deadline)
 	}
+	var scheduledDate *timestamppb.Timestamp
+	if task.ScheduledDate != nil && !task.ScheduledDate.IsZero() {
+		scheduledDate = timestamppb.New(*task.ScheduledDate)
+	}
+	assignee := ""
+	if task.Assignee != nil {
+		assignee = *task.Assignee
+	}
+	comments := make([]*pb.Comment, 0, len(task.Comments))
+	for _, c := range task.Comments {
+		comment := &pb.Comment{
+			Id:        c.ID,
+			Text:      c.Text,
+			CreatedBy: c.CreatedBy,
+			CreatedAt: timestamppb.New(c.CreatedAt),
+		}
+		if c.ParentID != nil {
+			comment.ParentId = *c.ParentID
+		}
+		if c.UpdatedAt != nil {
+			comment.UpdatedAt = timestamppb.New(*c.UpdatedAt)
+		}
+		comments = append(comments, comment)
+	}
+
+	checklist := make([]*pb.ChecklistItem, 0, len(task.Checklist))
+	for _, item := range task.Checklist {
+		checklist = append(checklist, &pb.ChecklistItem{
+			Id:        item.ID,
+			Text:      item.Text,
+			Done:      item.Done,
+			CreatedAt: timestamppb.New(item.CreatedAt),
+		})
+	}
+	parentID := ""
+	if task.ParentID != nil {
+		parentID = *task.ParentID
+	}
+	projectID := ""
+	if task.ProjectID != nil {
+		projectID = *task.ProjectID
+	}
+	var deletedAt *timestamppb.Timestamp
+	if task.DeletedAt != nil {
+		deletedAt = timestamppb.New(*task.DeletedAt)
+	}
 
 	return &pb.Task{
-		Id:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      TaskStatusToProto(task.Status),
-		Priority:    TaskPriorityToProto(task.Priority),
-		DueDate:     dueDate,
-		UserId:      task.CreatedBy, // 使用 CreatedBy 作为 UserId
-		CreatedAt:   timestamppb.New(task.CreatedAt),
-		UpdatedAt:   timestamppb.New(task.UpdatedAt),
+		Id:            task.ID,
+		Title:         task.Title,
+		Description:   task.Description,
+		Status:        TaskStatusToProto(task.Status),
+		Priority:      TaskPriorityToProto(task.Priority),
+		DueDate:       dueDate,
+		UserId:        task.CreatedBy, // 使用 CreatedBy 作为 UserId
+		CreatedAt:     timestamppb.New(task.CreatedAt),
+		UpdatedAt:     timestamppb.New(task.UpdatedAt),
+		Assignee:      assignee,
+		ScheduledDate: scheduledDate,
+		Comments:      comments,
+		ParentId:      parentID,
+		Checklist:     checklist,
+		BlockedBy:     task.BlockedBy,
+		Recurrence:    RecurrenceToProto(task.Recurrence),
+		Labels:        task.Labels,
+		ProjectId:     projectID,
+		StatusName:    task.Status,
+		PriorityName:  task.Priority,
+		DeletedAt:     deletedAt,
+		Version:       task.Version,
 	}
 }
 
+// RecurrenceToProto 转换重复规则，nil 表示不重复
+func RecurrenceToProto(r *models.Recurrence) *pb.Recurrence {
+	if r == nil {
+		return nil
+	}
+	return &pb.Recurrence{Rule: r.Rule, Tzid: r.TZID}
+}
+
+// ProtoToRecurrence 转换重复规则，未设置或 rule 为空时返回 nil
+func ProtoToRecurrence(r *pb.Recurrence) *models.Recurrence {
+	if r.GetRule() == "" {
+		return nil
+	}
+	return &models.Recurrence{Rule: r.Rule, TZID: r.Tzid}
+}
+
 // ProtoToTask 将 protobuf 任务模型转换为内部模型
 func ProtoToTask(pbTask *pb.Task) *models.Task {
 	if pbTask == nil {
@@ -178,33 +285,68 @@ func ProtoToReportType(reportType pb.ReportType) string {
 }
 
 // ReportToProto 将内部报表模型转换为 protobuf 模型
+// 内部模型的 Tasks 仅保存任务 ID，需要完整任务时由调用方填充 Tasks 字段
 func ReportToProto(report *models.Report) *pb.Report {
 	if report == nil {
 		return nil
 	}
 
-	// 注意：这里的 Tasks 字段在内部模型中是 []string，但 proto 中期望的是 []*pb.Task
-	// 实际使用时可能需要额外的查询来获取完整的任务信息
-	pbTasks := make([]*pb.Task, 0) // 暂时为空数组
+	labelStats := make([]*pb.LabelStats, 0, len(report.LabelStatistics))
+	for _, ls := range report.LabelStatistics {
+		labelStats = append(labelStats, &pb.LabelStats{Label: ls.Label, Stats: StatsToProto(ls.Statistics)})
+	}
 
-	stats := &pb.ReportStats{
-		TotalTasks:      int32(report.Statistics.TotalTasks),
-		CompletedTasks:  int32(report.Statistics.CompletedTasks),
-		PendingTasks:    int32(report.Statistics.TotalTasks - report.Statistics.CompletedTasks - report.Statistics.InProgressTasks),
-		InProgressTasks: int32(report.Statistics.InProgressTasks),
-		CompletionRate:  float64(report.Statistics.CompletionRate),
+	// 旧数据没有起止日期，回退到创建/更新时间
+	startDate, endDate := report.StartDate, report.EndDate
+	if startDate.IsZero() {
+		startDate = report.CreatedAt
+	}
+	if endDate.IsZero() {
+		endDate = report.UpdatedAt
+	}
+	polished := ""
+	if report.PolishedContent != nil {
+		polished = *report.PolishedContent
+	}
+	projectID := ""
+	if report.ProjectID != nil {
+		projectID = *report.ProjectID
+	}
+	var deletedAt *timestamppb.Timestamp
+	if report.DeletedAt != nil {
+		deletedAt = timestamppb.New(*report.DeletedAt)
 	}
 
 	return &pb.Report{
-		Id:        report.ID,
-		Title:     report.Title,
-		Type:      ReportTypeToProto(report.Type),
-		StartDate: timestamppb.New(report.CreatedAt), // 使用 CreatedAt 作为开始时间
-		EndDate:   timestamppb.New(report.UpdatedAt), // 使用 UpdatedAt 作为结束时间
-		UserId:    report.UserID,
-		CreatedAt: timestamppb.New(report.CreatedAt),
-		Tasks:     pbTasks,
-		Stats:     stats,
+		Id:              report.ID,
+		Title:           report.Title,
+		Type:            ReportTypeToProto(report.Type),
+		StartDate:       timestamppb.New(startDate),
+		EndDate:         timestamppb.New(endDate),
+		UserId:          report.UserID,
+		CreatedAt:       timestamppb.New(report.CreatedAt),
+		UpdatedAt:       timestamppb.New(report.UpdatedAt),
+		Tasks:           make([]*pb.Task, 0),
+		Stats:           StatsToProto(report.Statistics),
+		LabelStats:      labelStats,
+		Period:          report.Period,
+		Content:         report.Content,
+		PolishedContent: polished,
+		ProjectId:       projectID,
+		DeletedAt:       deletedAt,
+		Version:         report.Version,
+	}
+}
+
+// StatsToProto 转换报表统计，pending 为未完成且未进行中的任务数
+func StatsToProto(st models.Statistics) *pb.ReportStats {
+	return &pb.ReportStats{
+		TotalTasks:      int32(st.TotalTasks),
+		CompletedTasks:  int32(st.CompletedTasks),
+		PendingTasks:    int32(st.TotalTasks - st.CompletedTasks - st.InProgressTasks),
+		InProgressTasks: int32(st.InProgressTasks),
+		CompletionRate:  float64(st.CompletionRate),
+		OverdueTasks:    int32(st.OverdueTasks),
 	}
 }
 
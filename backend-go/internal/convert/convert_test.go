@@ -130,6 +130,62 @@ func TestTaskPriorityConversion(t *testing.T) {
 	}
 }
 
+func TestTaskNameConversion(t *testing.T) {
+	statuses := []string{"To Do", "In Progress", "Done"}
+	for _, name := range statuses {
+		if got := TaskStatusName(TaskStatusToProto(name)); got != name {
+			t.Errorf("Expected status %s, got %s", name, got)
+		}
+	}
+	if TaskStatusName(pb.TaskStatus_TASK_STATUS_UNSPECIFIED) != "" {
+		t.Error("Expected empty name for unspecified status")
+	}
+
+	priorities := []string{"Low", "Medium", "High"}
+	for _, name := range priorities {
+		if got := TaskPriorityName(TaskPriorityToProto(name)); got != name {
+			t.Errorf("Expected priority %s, got %s", name, got)
+		}
+	}
+	if TaskPriorityName(pb.TaskPriority(99)) != "" {
+		t.Error("Expected empty name for unknown priority")
+	}
+}
+
+func TestTaskToProtoOptionalFields(t *testing.T) {
+	assignee := "alice"
+	scheduled := time.Now()
+	task := &models.Task{
+		ID:            "60d5ecb74eb3b8001f8b4567",
+		Title:         "Test",
+		Status:        "In Progress",
+		Priority:      "High",
+		Assignee:      &assignee,
+		ScheduledDate: &scheduled,
+		Comments:      []models.Comment{{Text: "hi", CreatedBy: "u1", CreatedAt: scheduled}},
+	}
+
+	pbTask := TaskToProto(task)
+	if pbTask.Status != pb.TaskStatus_TASK_STATUS_IN_PROGRESS {
+		t.Errorf("Expected in progress status, got %v", pbTask.Status)
+	}
+	if pbTask.Priority != pb.TaskPriority_TASK_PRIORITY_HIGH {
+		t.Errorf("Expected high priority, got %v", pbTask.Priority)
+	}
+	if pbTask.Assignee != assignee {
+		t.Errorf("Expected assignee %s, got %s", assignee, pbTask.Assignee)
+	}
+	if pbTask.ScheduledDate == nil || pbTask.ScheduledDate.AsTime().Unix() != scheduled.Unix() {
+		t.Errorf("Expected scheduled date %v, got %v", scheduled, pbTask.ScheduledDate)
+	}
+	if pbTask.DueDate != nil {
+		t.Error("Expected nil due date")
+	}
+	if len(pbTask.Comments) != 1 || pbTask.Comments[0].Text != "hi" {
+		t.Errorf("Expected one comment, got %v", pbTask.Comments)
+	}
+}
+
 func TestNilInputs(t *testing.T) {
 	// 测试 nil 输入的处理
 	if UserToProto(nil) != nil {
@@ -0,0 +1,127 @@
+package convert
+
+import (
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/taskcomment"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DocToTask 将 Mongo 原始文档转换为任务模型
+// 兼容历史数据中以字符串形式保存的日期字段
+func DocToTask(m bson.M) models.Task {
+	var t models.Task
+	switch id := m["_id"].(type) {
+	case primitive.ObjectID:
+		t.ID = id.Hex()
+	case string:
+		t.ID = id
+	}
+	t.Title, _ = m["title"].(string)
+	t.Description, _ = m["description"].(string)
+	t.Status, _ = m["status"].(string)
+	t.Priority, _ = m["priority"].(string)
+	t.CreatedBy, _ = m["createdBy"].(string)
+	if a, ok := m["assignee"].(string); ok {
+		t.Assignee = &a
+	}
+	if v := docTime(m["createdAt"]); v != nil {
+		t.CreatedAt = *v
+	}
+	if v := docTime(m["updatedAt"]); v != nil {
+		t.UpdatedAt = *v
+	}
+	if p, ok := m["parentId"].(string); ok {
+		t.ParentID = &p
+	}
+	if p, ok := m["projectId"].(string); ok {
+		t.ProjectID = &p
+	}
+	t.Deadline = docTime(m["deadline"])
+	t.ScheduledDate = docTime(m["scheduledDate"])
+	t.DeletedAt = docTime(m["deletedAt"])
+	switch v := m["version"].(type) {
+	case int64:
+		t.Version = v
+	case int32:
+		t.Version = int64(v)
+	}
+	if arr, ok := m["comments"].(primitive.A); ok {
+		for i, item := range arr {
+			c, ok := item.(bson.M)
+			if !ok {
+				continue
+			}
+			var comment models.Comment
+			if comment.ID, _ = c["id"].(string); comment.ID == "" {
+				comment.ID = taskcomment.LegacyID(i)
+			}
+			if p, ok := c["parentId"].(string); ok {
+				comment.ParentID = &p
+			}
+			comment.Text, _ = c["text"].(string)
+			comment.CreatedBy, _ = c["createdBy"].(string)
+			if v := docTime(c["createdAt"]); v != nil {
+				comment.CreatedAt = *v
+			}
+			comment.UpdatedAt = docTime(c["updatedAt"])
+			t.Comments = append(t.Comments, comment)
+		}
+	}
+	if r, ok := m["recurrence"].(bson.M); ok {
+		t.Recurrence = &models.Recurrence{}
+		t.Recurrence.Rule, _ = r["rule"].(string)
+		t.Recurrence.TZID, _ = r["tzid"].(string)
+	}
+	if arr, ok := m["blockedBy"].(primitive.A); ok {
+		for _, v := range arr {
+			if id, ok := v.(string); ok {
+				t.BlockedBy = append(t.BlockedBy, id)
+			}
+		}
+	}
+	if arr, ok := m["labels"].(primitive.A); ok {
+		for _, v := range arr {
+			if name, ok := v.(string); ok {
+				t.Labels = append(t.Labels, name)
+			}
+		}
+	}
+	if arr, ok := m["checklist"].(primitive.A); ok {
+		for _, v := range arr {
+			c, ok := v.(bson.M)
+			if !ok {
+				continue
+			}
+			var item models.ChecklistItem
+			item.ID, _ = c["id"].(string)
+			item.Text, _ = c["text"].(string)
+			item.Done, _ = c["done"].(bool)
+			if v := docTime(c["createdAt"]); v != nil {
+				item.CreatedAt = *v
+			}
+			t.Checklist = append(t.Checklist, item)
+		}
+	}
+	return t
+}
+
+// docTime 解析文档中的日期值，支持 BSON 日期与字符串
+func docTime(v interface{}) *time.Time {
+	switch d := v.(type) {
+	case primitive.DateTime:
+		t := d.Time()
+		return &t
+	case time.Time:
+		return &d
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if t, err := time.Parse(layout, d); err == nil {
+				return &t
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Package datetime 统一解析接口传入的日期：YYYY-MM-DD、RFC3339，以及前端使用的不带时区的日期时间（按 UTC）。
+// 创建、更新、批量修改任务，列表过滤与生成报表都经由这里解析，格式无效时一律报错而不是忽略
+package datetime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalid 日期格式无效
+var ErrInvalid = errors.New("invalid date")
+
+const dateLayout = "2006-01-02"
+
+// layouts 依次尝试的格式，仅有日期的值取当天 UTC 零点
+var layouts = []string{
+	dateLayout,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// Parse 解析日期或日期时间
+func Parse(s string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w %q: use YYYY-MM-DD or RFC3339", ErrInvalid, s)
+}
+
+// ParseEnd 与 Parse 相同，但仅有日期的值取当天最后一刻，用于含边界的范围终点
+func ParseEnd(s string) (time.Time, error) {
+	t, err := Parse(s)
+	if err == nil && len(s) == len(dateLayout) {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, err
+}
+
+// Time 请求体中的可选日期，JSON 中为 Parse 支持的字符串；null 与空串表示未设置。
+// 格式无效时 JSON 解码返回包装了 ErrInvalid 的错误
+type Time struct {
+	t *time.Time
+}
+
+// From 由可选时间构造 Time
+func From(t *time.Time) Time {
+	if t == nil {
+		return Time{}
+	}
+	v := *t
+	return Time{t: &v}
+}
+
+// Ptr 返回时间的副本，未设置时返回 nil
+func (d Time) Ptr() *time.Time {
+	if d.t == nil {
+		return nil
+	}
+	v := *d.t
+	return &v
+}
+
+// UnmarshalJSON 解析字符串日期，null 与空串保持未设置
+func (d *Time) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		d.t = nil
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("%w: expected a string, got %s", ErrInvalid, b)
+	}
+	if s == "" {
+		d.t = nil
+		return nil
+	}
+	t, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	d.t = &t
+	return nil
+}
+
+// MarshalJSON 未设置时为 null，否则为 RFC3339 字符串
+func (d Time) MarshalJSON() ([]byte, error) {
+	if d.t == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.t.Format(time.RFC3339Nano))
+}
@@ -0,0 +1,65 @@
+package datetime
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want time.Time
+	}{
+		{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-01T08:30:00Z", time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)},
+		{"2024-03-01T08:30:00.5+08:00", time.Date(2024, 3, 1, 0, 30, 0, 5e8, time.UTC)},
+		{"2024-03-01T08:30:00", time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)},
+		{"2024-03-01 08:30:00", time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)},
+	} {
+		got, err := Parse(tc.in)
+		if err != nil || !got.Equal(tc.want) {
+			t.Errorf("Parse(%q) = %v (%v), want %v", tc.in, got, err, tc.want)
+		}
+	}
+	for _, in := range []string{"", "tomorrow", "2024-13-01", "01/03/2024"} {
+		if _, err := Parse(in); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q): expected ErrInvalid, got %v", in, err)
+		}
+	}
+	if got, _ := ParseEnd("2024-03-01"); !got.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)) {
+		t.Errorf("ParseEnd date: got %v", got)
+	}
+	if got, _ := ParseEnd("2024-03-01T08:30:00Z"); !got.Equal(time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("ParseEnd date time: got %v", got)
+	}
+}
+
+func TestTimeJSON(t *testing.T) {
+	var v struct {
+		Deadline Time `json:"deadline"`
+	}
+	for _, body := range []string{`{}`, `{"deadline": null}`, `{"deadline": ""}`} {
+		v.Deadline = From(&time.Time{})
+		if err := json.Unmarshal([]byte(body), &v); err != nil || (body != `{}` && v.Deadline.Ptr() != nil) {
+			t.Errorf("Unmarshal(%s) = %v (%v)", body, v.Deadline.Ptr(), err)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"deadline": "2024-03-01"}`), &v); err != nil || !v.Deadline.Ptr().Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unmarshal date: %v (%v)", v.Deadline.Ptr(), err)
+	}
+	for _, body := range []string{`{"deadline": "next week"}`, `{"deadline": 20240301}`} {
+		if err := json.Unmarshal([]byte(body), &v); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Unmarshal(%s): expected ErrInvalid, got %v", body, err)
+		}
+	}
+
+	data, _ := json.Marshal(v)
+	if string(data) != `{"deadline":"2024-03-01T00:00:00Z"}` {
+		t.Errorf("Marshal: got %s", data)
+	}
+	if data, _ := json.Marshal(struct{ D Time }{}); string(data) != `{"D":null}` {
+		t.Errorf("Marshal unset: got %s", data)
+	}
+}
@@ -0,0 +1,315 @@
+// Package depgraph 任务依赖图（"blocked by"）：写入时校验依赖存在且不成环，
+// 判断任务是否仍被阻塞，并按截止日期推算关键路径。边的方向为任务指向阻塞它的任务。
+package depgraph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+// CycleError 写入的依赖会成环，Path 从被修改的任务出发沿阻塞关系回到它自身
+type CycleError struct {
+	Path []*models.Task
+}
+
+func (e *CycleError) Error() string {
+	titles := make([]string, 0, len(e.Path))
+	for _, t := range e.Path {
+		titles = append(titles, t.Title)
+	}
+	return "dependency cycle: " + strings.Join(titles, " -> ")
+}
+
+// ErrMissing 依赖的任务不存在或不属于当前用户
+var ErrMissing = errors.New("blocking task not found")
+
+// Graph 某个用户全部任务的依赖图
+type Graph struct {
+	tasks map[string]*models.Task
+	ids   []string      // 按创建时间正序，保证输出稳定
+	flows *workflow.Set // 判断阻塞任务是否已完成
+}
+
+// Load 加载用户的全部任务构建依赖图，flows 为用户的工作流
+func Load(ctx context.Context, repo repository.TaskRepository, flows *workflow.Set, userID string) (*Graph, error) {
+	tasks, err := repo.List(ctx, repository.TaskFilter{
+		UserID: userID,
+		Sort:   repository.TaskSort{Field: repository.SortByCreatedAt, Asc: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	g := New(tasks)
+	g.flows = flows
+	return g, nil
+}
+
+// New 由任务列表构建依赖图，按内置工作流判断完成状态；指向不存在任务的依赖被忽略
+func New(tasks []models.Task) *Graph {
+	g := &Graph{tasks: make(map[string]*models.Task, len(tasks))}
+	for i := range tasks {
+		g.tasks[tasks[i].ID] = &tasks[i]
+		g.ids = append(g.ids, tasks[i].ID)
+	}
+	return g
+}
+
+// Task 返回图中的任务
+func (g *Graph) Task(id string) *models.Task {
+	return g.tasks[id]
+}
+
+// blockers 返回仍存在的阻塞任务
+func (g *Graph) blockers(t *models.Task) []*models.Task {
+	out := make([]*models.Task, 0, len(t.BlockedBy))
+	for _, id := range t.BlockedBy {
+		if b, ok := g.tasks[id]; ok {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// Normalize 去掉空值与重复项，保持原有顺序
+func Normalize(ids []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// SetBlockedBy 校验并设置 task 的阻塞任务；task 可以是尚未保存的新任务（ID 为空）。
+// 依赖不存在时返回 ErrMissing，成环时返回 *CycleError，校验通过后图与 task 同时更新
+func (g *Graph) SetBlockedBy(task *models.Task, blockedBy []string) error {
+	blockedBy = Normalize(blockedBy)
+	for _, id := range blockedBy {
+		if g.tasks[id] == nil {
+			return ErrMissing
+		}
+	}
+	if task.ID != "" {
+		for _, id := range blockedBy {
+			if path := g.path(id, task.ID, map[string]bool{}); path != nil {
+				return &CycleError{Path: append([]*models.Task{task}, path...)}
+			}
+		}
+	}
+	task.BlockedBy = blockedBy
+	if task.ID != "" {
+		if t, ok := g.tasks[task.ID]; ok {
+			t.BlockedBy = blockedBy
+		}
+	}
+	return nil
+}
+
+// path 沿阻塞关系查找从 from 到 to 的路径（含两端），不存在时返回 nil
+func (g *Graph) path(from, to string, seen map[string]bool) []*models.Task {
+	t := g.tasks[from]
+	if t == nil || seen[from] {
+		return nil
+	}
+	if from == to {
+		return []*models.Task{t}
+	}
+	seen[from] = true
+	for _, b := range g.blockers(t) {
+		if p := g.path(b.ID, to, seen); p != nil {
+			return append([]*models.Task{t}, p...)
+		}
+	}
+	return nil
+}
+
+// OpenBlockers 返回尚未完成的阻塞任务
+func (g *Graph) OpenBlockers(task *models.Task) []*models.Task {
+	var out []*models.Task
+	for _, b := range g.blockers(task) {
+		if !g.flows.Done(b) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// Node 关键路径视图中的节点。EarliestFinish 为考虑未完成阻塞任务后最早可完成的时间：
+// 取自身截止日期与各未完成阻塞任务 EarliestFinish 的最大值，均未设置时为空；
+// Late 表示 EarliestFinish 晚于自身截止日期
+type Node struct {
+	Task           *models.Task
+	BlockedBy      []string
+	EarliestFinish *time.Time
+	Late           bool
+	Critical       bool
+}
+
+// Edge 依赖边，From 阻塞 To
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Plan 依赖图与目标任务的关键路径
+type Plan struct {
+	Nodes        []Node
+	Edges        []Edge
+	Target       string   // 目标任务，未指定时为 EarliestFinish 最晚的未完成任务
+	CriticalPath []string // 从最上游的阻塞任务到目标任务
+}
+
+// schedule 记忆化计算的最早完成时间与未完成依赖链长度
+type schedule struct {
+	g      *Graph
+	finish map[string]*time.Time
+	depth  map[string]int
+	state  map[string]int // 1 计算中，2 已完成；防御历史数据中的环
+}
+
+func (s *schedule) visit(t *models.Task) {
+	if s.state[t.ID] != 0 {
+		return
+	}
+	s.state[t.ID] = 1
+	var finish *time.Time
+	if !s.g.flows.Done(t) {
+		finish = t.Deadline
+	}
+	depth := 0
+	for _, b := range s.g.blockers(t) {
+		if s.g.flows.Done(b) {
+			continue
+		}
+		s.visit(b)
+		if s.state[b.ID] != 2 {
+			continue
+		}
+		if f := s.finish[b.ID]; f != nil && (finish == nil || f.After(*finish)) {
+			finish = f
+		}
+		if s.depth[b.ID] > depth {
+			depth = s.depth[b.ID]
+		}
+	}
+	s.finish[t.ID] = finish
+	if !s.g.flows.Done(t) {
+		depth++
+	}
+	s.depth[t.ID] = depth
+	s.state[t.ID] = 2
+}
+
+// later 比较两个任务谁更拖后：最早完成时间更晚者优先，其次依赖链更长者，最后按 ID
+func (s *schedule) later(a, b string) bool {
+	fa, fb := s.finish[a], s.finish[b]
+	switch {
+	case fa != nil && fb == nil:
+		return true
+	case fa == nil && fb != nil:
+		return false
+	case fa != nil && !fa.Equal(*fb):
+		return fa.After(*fb)
+	case s.depth[a] != s.depth[b]:
+		return s.depth[a] > s.depth[b]
+	}
+	return a < b
+}
+
+// CriticalPath 计算依赖图与关键路径：从目标任务出发，每一步选择最拖后的未完成阻塞任务。
+// target 为空时选取最拖后的未完成任务；target 不存在时返回 repository.ErrNotFound
+func (g *Graph) CriticalPath(target string) (*Plan, error) {
+	s := &schedule{g: g, finish: map[string]*time.Time{}, depth: map[string]int{}, state: map[string]int{}}
+	for _, id := range g.ids {
+		s.visit(g.tasks[id])
+	}
+	if target == "" {
+		for _, id := range g.ids {
+			if !g.flows.Done(g.tasks[id]) && (target == "" || s.later(id, target)) {
+				target = id
+			}
+		}
+	} else if g.tasks[target] == nil {
+		return nil, repository.ErrNotFound
+	}
+
+	plan := &Plan{Target: target, CriticalPath: []string{}}
+	critical := map[string]bool{}
+	for id := target; id != "" && !critical[id]; {
+		critical[id] = true
+		plan.CriticalPath = append(plan.CriticalPath, id)
+		next := ""
+		for _, b := range g.blockers(g.tasks[id]) {
+			if !g.flows.Done(b) && !critical[b.ID] && (next == "" || s.later(b.ID, next)) {
+				next = b.ID
+			}
+		}
+		id = next
+	}
+	for i, j := 0, len(plan.CriticalPath)-1; i < j; i, j = i+1, j-1 {
+		plan.CriticalPath[i], plan.CriticalPath[j] = plan.CriticalPath[j], plan.CriticalPath[i]
+	}
+
+	// 只输出参与依赖关系的任务以及目标任务
+	linked := map[string]bool{target: target != ""}
+	for _, id := range g.ids {
+		for _, b := range g.blockers(g.tasks[id]) {
+			plan.Edges = append(plan.Edges, Edge{From: b.ID, To: id})
+			linked[id], linked[b.ID] = true, true
+		}
+	}
+	for _, id := range g.ids {
+		if !linked[id] {
+			continue
+		}
+		t := g.tasks[id]
+		n := Node{Task: t, BlockedBy: []string{}, EarliestFinish: s.finish[id], Critical: critical[id]}
+		for _, b := range g.blockers(t) {
+			n.BlockedBy = append(n.BlockedBy, b.ID)
+		}
+		n.Late = n.EarliestFinish != nil && t.Deadline != nil && n.EarliestFinish.After(*t.Deadline)
+		plan.Nodes = append(plan.Nodes, n)
+	}
+	return plan, nil
+}
+
+// Unlink 从其他任务（含回收站中的任务）的阻塞列表中移除已永久删除的任务
+func Unlink(ctx context.Context, repo repository.TaskRepository, userID string, deleted []string, now time.Time) error {
+	if len(deleted) == 0 {
+		return nil
+	}
+	gone := make(map[string]bool, len(deleted))
+	for _, id := range deleted {
+		gone[id] = true
+	}
+	tasks, err := repo.List(ctx, repository.TaskFilter{UserID: userID, BlockedBy: deleted, Trash: repository.WithTrashed})
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		t := &tasks[i]
+		kept := t.BlockedBy[:0]
+		for _, id := range t.BlockedBy {
+			if !gone[id] {
+				kept = append(kept, id)
+			}
+		}
+		t.BlockedBy = kept
+		t.UpdatedAt = now
+		if err := repo.Update(ctx, t); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
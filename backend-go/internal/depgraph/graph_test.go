@@ -0,0 +1,147 @@
+package depgraph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func day(d int) *time.Time {
+	t := time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// chain 构造 a <- b <- c 与独立的 d <- c：c 同时被 b、d 阻塞
+func chain() *Graph {
+	return New([]models.Task{
+		{ID: "a", Title: "a", Status: "To Do", Deadline: day(5)},
+		{ID: "b", Title: "b", Status: "To Do", Deadline: day(3), BlockedBy: []string{"a"}},
+		{ID: "c", Title: "c", Status: "To Do", Deadline: day(4), BlockedBy: []string{"b", "d"}},
+		{ID: "d", Title: "d", Status: "To Do", Deadline: day(2)},
+		{ID: "e", Title: "e", Status: "Done"},
+	})
+}
+
+func TestSetBlockedBy(t *testing.T) {
+	cases := []struct {
+		name      string
+		task      string
+		blockedBy []string
+		want      error
+		path      string
+	}{
+		{"valid", "d", []string{"e", " e", ""}, nil, ""},
+		{"missing", "d", []string{"x"}, ErrMissing, ""},
+		{"self", "a", []string{"a"}, &CycleError{}, "dependency cycle: a -> a"},
+		{"indirect", "a", []string{"e", "c"}, &CycleError{}, "dependency cycle: a -> c -> b -> a"},
+		{"new task", "", []string{"c"}, nil, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := chain()
+			task := &models.Task{Title: "new"}
+			if tc.task != "" {
+				copied := *g.Task(tc.task)
+				task = &copied
+			}
+			err := g.SetBlockedBy(task, tc.blockedBy)
+			var cycle *CycleError
+			switch {
+			case tc.want == nil:
+				if err != nil {
+					t.Fatalf("Unexpected error %v", err)
+				}
+				if tc.task != "" && len(g.Task(tc.task).BlockedBy) != len(task.BlockedBy) {
+					t.Errorf("Graph not updated: %v", g.Task(tc.task).BlockedBy)
+				}
+			case errors.As(tc.want, &cycle):
+				if !errors.As(err, &cycle) || err.Error() != tc.path {
+					t.Errorf("Expected %q, got %v", tc.path, err)
+				}
+				if len(task.BlockedBy) != len(g.Task(tc.task).BlockedBy) {
+					t.Error("Task must not change on cycle")
+				}
+			default:
+				if !errors.Is(err, tc.want) {
+					t.Errorf("Expected %v, got %v", tc.want, err)
+				}
+			}
+		})
+	}
+	g := chain()
+	task := g.Task("d")
+	if err := g.SetBlockedBy(task, []string{"e", "e"}); err != nil || len(task.BlockedBy) != 1 {
+		t.Errorf("Expected duplicates to be removed, got %v (%v)", task.BlockedBy, err)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	g := chain()
+	if open := g.OpenBlockers(g.Task("c")); len(open) != 2 {
+		t.Errorf("Expected 2 open blockers, got %d", len(open))
+	}
+
+	plan, err := g.CriticalPath("")
+	if err != nil {
+		t.Fatalf("CriticalPath failed: %v", err)
+	}
+	if plan.Target != "c" || len(plan.CriticalPath) != 3 || plan.CriticalPath[0] != "a" || plan.CriticalPath[2] != "c" {
+		t.Errorf("Unexpected plan %+v", plan)
+	}
+	if len(plan.Nodes) != 4 || len(plan.Edges) != 3 {
+		t.Errorf("Expected 4 nodes and 3 edges, got %d/%d", len(plan.Nodes), len(plan.Edges))
+	}
+	// b、c 须等 a 在 5 日完成，晚于各自的截止日期
+	for _, n := range plan.Nodes {
+		late := n.Task.ID == "b" || n.Task.ID == "c"
+		finish := day(5)
+		if n.Task.ID == "d" {
+			finish = day(2)
+		}
+		if n.Late != late || !n.EarliestFinish.Equal(*finish) {
+			t.Errorf("Unexpected node %s: late=%v finish=%v", n.Task.ID, n.Late, n.EarliestFinish)
+		}
+	}
+
+	// 完成的阻塞任务不再参与关键路径
+	g.Task("a").Status = "Done"
+	plan, _ = g.CriticalPath("c")
+	if len(plan.CriticalPath) != 2 || plan.CriticalPath[0] != "b" {
+		t.Errorf("Expected [b c], got %v", plan.CriticalPath)
+	}
+	plan, _ = g.CriticalPath("d")
+	if len(plan.CriticalPath) != 1 || plan.CriticalPath[0] != "d" {
+		t.Errorf("Expected [d], got %v", plan.CriticalPath)
+	}
+	if _, err := g.CriticalPath("x"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// 历史数据中的环不会导致死循环
+	g = New([]models.Task{{ID: "x", BlockedBy: []string{"y"}}, {ID: "y", BlockedBy: []string{"x"}}})
+	if plan, err := g.CriticalPath(""); err != nil || len(plan.CriticalPath) == 0 {
+		t.Errorf("Unexpected result on cyclic data: %+v (%v)", plan, err)
+	}
+}
+
+func TestUnlink(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewTaskRepository()
+	a := &models.Task{Title: "a", CreatedBy: "u1"}
+	_ = repo.Create(ctx, a)
+	b := &models.Task{Title: "b", CreatedBy: "u1", BlockedBy: []string{a.ID, "other"}}
+	_ = repo.Create(ctx, b)
+
+	if err := Unlink(ctx, repo, "u1", []string{a.ID}, time.Now()); err != nil {
+		t.Fatalf("Unlink failed: %v", err)
+	}
+	got, _ := repo.Get(ctx, "u1", b.ID)
+	if len(got.BlockedBy) != 1 || got.BlockedBy[0] != "other" {
+		t.Errorf("Expected [other], got %v", got.BlockedBy)
+	}
+}
@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/axfinn/todoIng/backend-go/internal/auth"
+	"github.com/axfinn/todoIng/backend-go/internal/services"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Servers 网关转发到的 gRPC 服务实现
+type Servers struct {
+	Auth    pb.AuthServiceServer
+	Captcha pb.CaptchaServiceServer
+	Task    pb.TaskServiceServer
+	Report  pb.ReportServiceServer
+}
+
+// NewHandler 创建进程内的 REST 网关，按 proto 中的 google.api.http 注解
+// 将 /api/v2 下的 JSON 请求直接转交给服务实现，不经过网络
+func NewHandler(ctx context.Context, s Servers) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+	)
+	if err := pb.RegisterAuthServiceHandlerServer(ctx, mux, s.Auth); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterCaptchaServiceHandlerServer(ctx, mux, s.Captcha); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterTaskServiceHandlerServer(ctx, mux, s.Task); err != nil {
+		return nil, err
+	}
+	if err := pb.RegisterReportServiceHandlerServer(ctx, mux, s.Report); err != nil {
+		return nil, err
+	}
+	return withUser(mux), nil
+}
+
+// withUser 进程内调用不会经过 gRPC 拦截器，这里解析 Bearer 令牌并写入用户 ID；
+// 令牌缺失或无效时不写入，由需要认证的服务返回 Unauthenticated（HTTP 401）
+func withUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+		if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+			if claims, err := auth.Parse(parts[1]); err == nil {
+				r = r.WithContext(services.ContextWithUserID(r.Context(), claims.UserID))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/captcha"
+	"github.com/axfinn/todoIng/backend-go/internal/services"
+)
+
+func newTestHandler(t *testing.T) http.Handler {
+	h, err := NewHandler(context.Background(), Servers{
+		Auth:    services.NewAuthService(nil, nil),
+		Captcha: services.NewCaptchaService(captcha.NewStore(time.Minute)),
+		Task:    services.NewTaskService(nil, nil, nil, nil),
+		Report:  services.NewReportService(nil, nil, nil, nil, nil),
+	})
+	if err != nil {
+		t.Fatalf("NewHandler failed: %v", err)
+	}
+	return h
+}
+
+func TestGatewayGetCaptcha(t *testing.T) {
+	t.Setenv("ENABLE_CAPTCHA", "false")
+	rr := httptest.NewRecorder()
+	newTestHandler(t).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v2/auth/captcha", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Captcha struct {
+			ID string `json:"id"`
+		} `json:"captcha"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Captcha.ID != captcha.DisabledID {
+		t.Errorf("Expected captcha id %q, got %q", captcha.DisabledID, resp.Captcha.ID)
+	}
+}
+
+func TestGatewayRequiresToken(t *testing.T) {
+	h := newTestHandler(t)
+	for _, path := range []string{"/api/v2/tasks", "/api/v2/reports"} {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer invalid")
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected status 401, got %d", path, rr.Code)
+		}
+	}
+}
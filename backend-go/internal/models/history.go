@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// 任务历史的操作类型
+const (
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionCommented = "commented"
+	ActionDeleted   = "deleted"  // 移入回收站
+	ActionRestored  = "restored" // 从回收站恢复
+	ActionPurged    = "purged"   // 永久删除
+)
+
+// FieldChange 一个字段的新旧值，nil 表示未设置；日期为 RFC3339，列表与重复规则为 JSON。
+// 评论与检查项的变化以 ItemID 区分具体条目
+type FieldChange struct {
+	Field  string  `bson:"field" json:"field"`
+	ItemID string  `bson:"itemId,omitempty" json:"itemId,omitempty"`
+	Old    *string `bson:"old" json:"old"`
+	New    *string `bson:"new" json:"new"`
+}
+
+// TaskEvent 任务的一条历史记录，写入后不再修改；任务删除后仍然保留
+type TaskEvent struct {
+	ID        string        `bson:"_id,omitempty" json:"id"`
+	UserID    string        `bson:"userId" json:"userId"` // 任务所属用户
+	TaskID    string        `bson:"taskId" json:"taskId"`
+	Actor     string        `bson:"actor" json:"actor"` // 执行操作的用户
+	Action    string        `bson:"action" json:"action"`
+	Changes   []FieldChange `bson:"changes" json:"changes"`
+	CreatedAt time.Time     `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Label 用户自定义标签。任务按名称引用标签，名称在同一用户内不区分大小写唯一，
+// 重命名或合并标签时同步改写相关任务
+type Label struct {
+	ID          string    `bson:"_id,omitempty" json:"id"`
+	UserID      string    `bson:"userId" json:"userId"`
+	Name        string    `bson:"name" json:"name"`
+	Color       string    `bson:"color" json:"color"` // #RRGGBB，可为空
+	Description string    `bson:"description" json:"description"`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt"`
+}
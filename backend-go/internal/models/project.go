@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Project 用于分组任务的项目（清单）。归档的项目保留任务但不再接收新任务，
+// Position 决定项目列表中的显示顺序
+type Project struct {
+	ID          string    `bson:"_id,omitempty" json:"id"`
+	UserID      string    `bson:"userId" json:"userId"`
+	Name        string    `bson:"name" json:"name"`
+	Description string    `bson:"description" json:"description"`
+	Archived    bool      `bson:"archived" json:"archived"`
+	Position    int       `bson:"position" json:"position"`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt"`
+}
@@ -10,16 +10,28 @@ type Statistics struct {
 	CompletionRate  int `bson:"completionRate" json:"completionRate"`
 }
 
+// LabelStatistics 带有某个标签的任务统计，Label 为空表示未打标签的任务
+type LabelStatistics struct {
+	Label      string `bson:"label" json:"label"`
+	Statistics `bson:",inline"`
+}
+
 type Report struct {
-	ID              string     `bson:"_id,omitempty" json:"id"`
-	UserID          string     `bson:"userId" json:"userId"`
-	Type            string     `bson:"type" json:"type"`
-	Period          string     `bson:"period" json:"period"`
-	Title           string     `bson:"title" json:"title"`
-	Content         string     `bson:"content" json:"content"`
-	PolishedContent *string    `bson:"polishedContent" json:"polishedContent"`
-	Tasks           []string   `bson:"tasks" json:"tasks"`
-	Statistics      Statistics `bson:"statistics" json:"statistics"`
-	CreatedAt       time.Time  `bson:"createdAt" json:"createdAt"`
-	UpdatedAt       time.Time  `bson:"updatedAt" json:"updatedAt"`
+	ID              string            `bson:"_id,omitempty" json:"id"`
+	UserID          string            `bson:"userId" json:"userId"`
+	Type            string            `bson:"type" json:"type"`
+	Period          string            `bson:"period" json:"period"`
+	Title           string            `bson:"title" json:"title"`
+	Content         string            `bson:"content" json:"content"`
+	PolishedContent *string           `bson:"polishedContent" json:"polishedContent"`
+	Tasks           []string          `bson:"tasks" json:"tasks"`
+	Statistics      Statistics        `bson:"statistics" json:"statistics"`
+	LabelStatistics []LabelStatistics `bson:"labelStatistics" json:"labelStatistics"` // 按标签分组，一个任务可计入多个标签
+	ProjectID       *string           `bson:"projectId" json:"projectId"`             // 报表限定的项目，nil 表示不限项目
+	StartDate       time.Time         `bson:"startDate" json:"startDate"`
+	EndDate         time.Time         `bson:"endDate" json:"endDate"`
+	CreatedAt       time.Time         `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time         `bson:"updatedAt" json:"updatedAt"`
+	DeletedAt       *time.Time        `bson:"deletedAt" json:"deletedAt"` // 移入回收站的时间，nil 表示未删除
+	Version         int64             `bson:"version" json:"version"`     // 每次写入递增，用于乐观并发控制
 }
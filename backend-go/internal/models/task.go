@@ -2,23 +2,50 @@ package models
 
 import "time"
 
+// Comment 任务评论，ID 在任务内唯一；ParentID 为所回复的评论，nil 表示顶层评论
 type Comment struct {
+	ID        string     `bson:"id" json:"id"`
+	ParentID  *string    `bson:"parentId" json:"parentId"`
+	Text      string     `bson:"text" json:"text"`
+	CreatedBy string     `bson:"createdBy" json:"createdBy"`
+	CreatedAt time.Time  `bson:"createdAt" json:"createdAt"`
+	UpdatedAt *time.Time `bson:"updatedAt" json:"updatedAt"` // 最后一次编辑的时间，nil 表示未编辑
+}
+
+// ChecklistItem 任务内的检查项，ID 在任务内唯一
+type ChecklistItem struct {
+	ID        string    `bson:"id" json:"id"`
 	Text      string    `bson:"text" json:"text"`
-	CreatedBy string    `bson:"createdBy" json:"createdBy"`
+	Done      bool      `bson:"done" json:"done"`
 	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
 }
 
+// Recurrence 任务的重复规则：Rule 为 RRULE（如 FREQ=WEEKLY;BYDAY=MO），
+// TZID 为展开规则所用的 IANA 时区，空表示 UTC
+type Recurrence struct {
+	Rule string `bson:"rule" json:"rule"`
+	TZID string `bson:"tzid" json:"tzid"`
+}
+
 type Task struct {
-	ID            string     `bson:"_id,omitempty" json:"id"`
-	Title         string     `bson:"title" json:"title"`
-	Description   string     `bson:"description" json:"description"`
-	Status        string     `bson:"status" json:"status"`
-	Priority      string     `bson:"priority" json:"priority"`
-	Assignee      *string    `bson:"assignee" json:"assignee"`
-	CreatedBy     string     `bson:"createdBy" json:"createdBy"`
-	CreatedAt     time.Time  `bson:"createdAt" json:"createdAt"`
-	UpdatedAt     time.Time  `bson:"updatedAt" json:"updatedAt"`
-	Deadline      *time.Time `bson:"deadline" json:"deadline"`
-	ScheduledDate *time.Time `bson:"scheduledDate" json:"scheduledDate"`
-	Comments      []Comment  `bson:"comments" json:"comments"`
+	ID            string          `bson:"_id,omitempty" json:"id"`
+	Title         string          `bson:"title" json:"title"`
+	Description   string          `bson:"description" json:"description"`
+	Status        string          `bson:"status" json:"status"`
+	Priority      string          `bson:"priority" json:"priority"`
+	Assignee      *string         `bson:"assignee" json:"assignee"`
+	CreatedBy     string          `bson:"createdBy" json:"createdBy"`
+	CreatedAt     time.Time       `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time       `bson:"updatedAt" json:"updatedAt"`
+	Deadline      *time.Time      `bson:"deadline" json:"deadline"`
+	ScheduledDate *time.Time      `bson:"scheduledDate" json:"scheduledDate"`
+	Comments      []Comment       `bson:"comments" json:"comments"`
+	ParentID      *string         `bson:"parentId" json:"parentId"` // 父任务 ID，nil 表示顶层任务
+	Checklist     []ChecklistItem `bson:"checklist" json:"checklist"`
+	BlockedBy     []string        `bson:"blockedBy" json:"blockedBy"`   // 阻塞本任务的任务 ID
+	Recurrence    *Recurrence     `bson:"recurrence" json:"recurrence"` // nil 表示不重复
+	Labels        []string        `bson:"labels" json:"labels"`         // 标签名称
+	ProjectID     *string         `bson:"projectId" json:"projectId"`   // 所属项目 ID，nil 表示未归入项目
+	DeletedAt     *time.Time      `bson:"deletedAt" json:"deletedAt"`   // 移入回收站的时间，nil 表示未删除
+	Version       int64           `bson:"version" json:"version"`       // 每次写入递增，用于乐观并发控制
 }
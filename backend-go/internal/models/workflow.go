@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// 状态类别：报表统计、子任务完成度与依赖阻塞均按类别判断，不依赖状态名称
+const (
+	CategoryOpen   = "open"   // 未开始
+	CategoryActive = "active" // 进行中
+	CategoryDone   = "done"   // 已完成
+)
+
+// WorkflowStatus 工作流中的一个状态；WIPLimit 为处于该状态的任务数上限，0 表示不限制
+type WorkflowStatus struct {
+	Name     string `bson:"name" json:"name"`
+	Category string `bson:"category" json:"category"`
+	WIPLimit int    `bson:"wipLimit" json:"wipLimit"`
+}
+
+// WorkflowTransition 允许的状态流转
+type WorkflowTransition struct {
+	From string `bson:"from" json:"from"`
+	To   string `bson:"to" json:"to"`
+}
+
+// Workflow 用户或项目自定义的工作流。ProjectID 为 nil 时是用户的默认工作流，
+// 否则只作用于该项目内的任务；Statuses 的第一个状态为新任务的初始状态，
+// Transitions 为空表示任意状态之间均可流转；Priorities 按从低到高排列
+type Workflow struct {
+	ID          string               `bson:"_id,omitempty" json:"id"`
+	UserID      string               `bson:"userId" json:"userId"`
+	ProjectID   *string              `bson:"projectId" json:"projectId"`
+	Name        string               `bson:"name" json:"name"`
+	Statuses    []WorkflowStatus     `bson:"statuses" json:"statuses"`
+	Transitions []WorkflowTransition `bson:"transitions" json:"transitions"`
+	Priorities  []string             `bson:"priorities" json:"priorities"`
+	CreatedAt   time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
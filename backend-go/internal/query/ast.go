@@ -0,0 +1,65 @@
+// Package query 实现任务查询语言：词法分析、语法分析得到类型化的语法树，
+// 再由 Compile 结合当前时间编译为与存储无关的条件树，各存储实现将条件树翻译为各自的查询。
+//
+// 语法示例：
+//
+//	status:"In Progress" priority>=Medium due<7d -assignee:none
+//	(title:周报 OR description:周报) AND NOT status:done
+//
+// 相邻条件默认为 AND；支持 AND、OR、NOT、"-" 前缀取反与括号，OR 的优先级低于 AND。
+package query
+
+import "strconv"
+
+// Expr 语法树节点
+type Expr interface {
+	// Pos 返回节点在查询中的起始字符位置（从 0 开始，按字符计）
+	Pos() int
+}
+
+// BinaryExpr AND / OR 组合
+type BinaryExpr struct {
+	Op   string // "AND" 或 "OR"
+	X, Y Expr
+}
+
+// NotExpr 取反
+type NotExpr struct {
+	NotPos int
+	X      Expr
+}
+
+// FieldExpr 字段条件，如 priority>=High
+type FieldExpr struct {
+	Field    string
+	FieldPos int
+	Op       string // ":" "=" "!=" "<" "<=" ">" ">="
+	Value    string
+	ValuePos int
+	Quoted   bool // 带引号的值按字面处理，不解释 none、today 等关键字
+}
+
+// TextExpr 不带字段的关键词，匹配标题或描述
+type TextExpr struct {
+	Value    string
+	ValuePos int
+}
+
+func (e *BinaryExpr) Pos() int { return e.X.Pos() }
+func (e *NotExpr) Pos() int    { return e.NotPos }
+func (e *FieldExpr) Pos() int  { return e.FieldPos }
+func (e *TextExpr) Pos() int   { return e.ValuePos }
+
+// Error 带位置的查询错误
+type Error struct {
+	Pos int    `json:"pos"`
+	Msg string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return "position " + strconv.Itoa(e.Pos) + ": " + e.Msg
+}
+
+func errorf(pos int, msg string) *Error {
+	return &Error{Pos: pos, Msg: msg}
+}
@@ -0,0 +1,309 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field 可查询的任务字段
+type Field string
+
+const (
+	FieldTitle         Field = "title"
+	FieldDescription   Field = "description"
+	FieldStatus        Field = "status"
+	FieldPriority      Field = "priority"
+	FieldAssignee      Field = "assignee"
+	FieldCreatedAt     Field = "createdAt"
+	FieldUpdatedAt     Field = "updatedAt"
+	FieldDeadline      Field = "deadline"
+	FieldScheduledDate Field = "scheduledDate"
+	FieldLabels        Field = "labels" // 多值字段，任一标签相等即匹配，没有标签视为空
+	FieldProject       Field = "projectId"
+)
+
+// fieldNames 查询中可用的字段名（含别名）
+var fieldNames = map[string]Field{
+	"title":         FieldTitle,
+	"description":   FieldDescription,
+	"desc":          FieldDescription,
+	"status":        FieldStatus,
+	"priority":      FieldPriority,
+	"assignee":      FieldAssignee,
+	"created":       FieldCreatedAt,
+	"createdat":     FieldCreatedAt,
+	"updated":       FieldUpdatedAt,
+	"updatedat":     FieldUpdatedAt,
+	"due":           FieldDeadline,
+	"deadline":      FieldDeadline,
+	"scheduled":     FieldScheduledDate,
+	"scheduleddate": FieldScheduledDate,
+	"label":         FieldLabels,
+	"labels":        FieldLabels,
+	"tag":           FieldLabels,
+	"tags":          FieldLabels,
+	"project":       FieldProject,
+	"projectid":     FieldProject,
+}
+
+// IsTime 字段是否为时间类型
+func (f Field) IsTime() bool {
+	return f == FieldCreatedAt || f == FieldUpdatedAt || f == FieldDeadline || f == FieldScheduledDate
+}
+
+// Nullable 字段是否可能为空
+func (f Field) Nullable() bool {
+	return f == FieldAssignee || f == FieldDeadline || f == FieldScheduledDate || f == FieldLabels || f == FieldProject
+}
+
+// Op 编译后的基本比较
+type Op int
+
+const (
+	OpEq       Op = iota // 等于 Values[0]
+	OpIn                 // 等于 Values 中任意一个
+	OpContains           // 不区分大小写地包含 Values[0]
+	OpLt                 // 早于 Time
+	OpGte                // 不早于 Time
+	OpNull               // 字段为空
+)
+
+// Cond 编译后的条件树，由 And、Or、Not 与 Match 组成；
+// 字段为空时 Match 中除 OpNull 外的比较均不成立，取反后成立
+type Cond interface{ cond() }
+
+type (
+	And   []Cond
+	Or    []Cond
+	Not   struct{ Cond Cond }
+	Match struct {
+		Field  Field
+		Op     Op
+		Values []string
+		Time   time.Time
+	}
+)
+
+func (And) cond()   {}
+func (Or) cond()    {}
+func (Not) cond()   {}
+func (Match) cond() {}
+
+var statusNames = map[string]string{
+	"todo": "To Do", "to do": "To Do", "to-do": "To Do",
+	"inprogress": "In Progress", "in progress": "In Progress", "in-progress": "In Progress", "doing": "In Progress",
+	"done": "Done",
+}
+
+// priorities 按从低到高排列，用于优先级的大小比较
+var priorities = []string{"Low", "Medium", "High"}
+
+// ParseAndCompile 解析并编译查询；now 决定相对日期的基准，日期按 now 所在时区的自然日计算
+func ParseAndCompile(input string, now time.Time) (Cond, error) {
+	expr, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(expr, now)
+}
+
+// Compile 将语法树编译为条件树，校验字段、运算符与取值；错误为 *Error
+func Compile(expr Expr, now time.Time) (Cond, error) {
+	c := &compiler{today: startOfDay(now)}
+	return c.compile(expr)
+}
+
+type compiler struct {
+	today time.Time
+}
+
+func (c *compiler) compile(expr Expr) (Cond, error) {
+	switch e := expr.(type) {
+	case *BinaryExpr:
+		x, err := c.compile(e.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := c.compile(e.Y)
+		if err != nil {
+			return nil, err
+		}
+		if e.Op == "OR" {
+			return flatten(Or{}, x, y), nil
+		}
+		return flatten(And{}, x, y), nil
+	case *NotExpr:
+		x, err := c.compile(e.X)
+		if err != nil {
+			return nil, err
+		}
+		return Not{x}, nil
+	case *TextExpr:
+		return Or{
+			Match{Field: FieldTitle, Op: OpContains, Values: []string{e.Value}},
+			Match{Field: FieldDescription, Op: OpContains, Values: []string{e.Value}},
+		}, nil
+	case *FieldExpr:
+		return c.field(e)
+	}
+	return nil, errorf(expr.Pos(), "unsupported expression")
+}
+
+// flatten 合并同类的嵌套 And/Or
+func flatten[T And | Or](out T, xs ...Cond) T {
+	for _, x := range xs {
+		if inner, ok := x.(T); ok {
+			out = append(out, inner...)
+		} else {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func (c *compiler) field(e *FieldExpr) (Cond, error) {
+	f, ok := fieldNames[strings.ToLower(e.Field)]
+	if !ok {
+		return nil, errorf(e.FieldPos, "unknown field '"+e.Field+"'")
+	}
+	if e.Op == "!=" {
+		eq := *e
+		eq.Op = ":"
+		x, err := c.field(&eq)
+		if err != nil {
+			return nil, err
+		}
+		return Not{x}, nil
+	}
+	if f.Nullable() && !e.Quoted && strings.EqualFold(e.Value, "none") {
+		if e.Op != ":" && e.Op != "=" {
+			return nil, errorf(e.ValuePos, "'none' can only be used with ':' or '='")
+		}
+		return Match{Field: f, Op: OpNull}, nil
+	}
+	if f.IsTime() {
+		return c.timeField(f, e)
+	}
+
+	switch f {
+	case FieldTitle, FieldDescription:
+		switch e.Op {
+		case ":":
+			return Match{Field: f, Op: OpContains, Values: []string{e.Value}}, nil
+		case "=":
+			return Match{Field: f, Op: OpEq, Values: []string{e.Value}}, nil
+		}
+	case FieldAssignee, FieldLabels, FieldProject:
+		if e.Op == ":" || e.Op == "=" {
+			return Match{Field: f, Op: OpEq, Values: []string{e.Value}}, nil
+		}
+	case FieldStatus:
+		if e.Op == ":" || e.Op == "=" {
+			s, ok := statusNames[strings.ToLower(e.Value)]
+			switch {
+			case !ok && e.Quoted:
+				s = e.Value // 自定义工作流的状态，按名称精确匹配
+			case !ok:
+				return nil, errorf(e.ValuePos, "unknown status '"+e.Value+"', expected one of: todo, inprogress, done, or a quoted custom status")
+			}
+			return Match{Field: f, Op: OpEq, Values: []string{s}}, nil
+		}
+	case FieldPriority:
+		return priorityMatch(e)
+	}
+	return nil, errorf(e.FieldPos+len([]rune(e.Field)), "operator '"+e.Op+"' is not supported for "+string(f))
+}
+
+// priorityMatch 优先级支持大小比较，编译为优先级集合
+func priorityMatch(e *FieldExpr) (Cond, error) {
+	rank := -1
+	for i, p := range priorities {
+		if strings.EqualFold(p, e.Value) {
+			rank = i
+		}
+	}
+	if rank < 0 && e.Quoted && (e.Op == ":" || e.Op == "=") {
+		// 自定义工作流的优先级，只支持按名称精确匹配
+		return Match{Field: FieldPriority, Op: OpEq, Values: []string{e.Value}}, nil
+	}
+	if rank < 0 {
+		return nil, errorf(e.ValuePos, "unknown priority '"+e.Value+"', expected one of: low, medium, high, or a quoted custom priority")
+	}
+	var values []string
+	switch e.Op {
+	case ":", "=":
+		values = priorities[rank : rank+1]
+	case "<":
+		values = priorities[:rank]
+	case "<=":
+		values = priorities[:rank+1]
+	case ">":
+		values = priorities[rank+1:]
+	case ">=":
+		values = priorities[rank:]
+	}
+	return Match{Field: FieldPriority, Op: OpIn, Values: append([]string{}, values...)}, nil
+}
+
+// timeField 日期值表示区间 [start, end)：自然日为当天，精确时间为该时刻
+func (c *compiler) timeField(f Field, e *FieldExpr) (Cond, error) {
+	start, end, ok := c.parseTime(e.Value)
+	if !ok {
+		return nil, errorf(e.ValuePos, "invalid date '"+e.Value+"', expected YYYY-MM-DD, RFC3339, today, yesterday, tomorrow or an offset like 7d, -2w, 1m")
+	}
+	switch e.Op {
+	case ":", "=":
+		return And{Match{Field: f, Op: OpGte, Time: start}, Match{Field: f, Op: OpLt, Time: end}}, nil
+	case "<":
+		return Match{Field: f, Op: OpLt, Time: start}, nil
+	case "<=":
+		return Match{Field: f, Op: OpLt, Time: end}, nil
+	case ">":
+		return Match{Field: f, Op: OpGte, Time: end}, nil
+	}
+	return Match{Field: f, Op: OpGte, Time: start}, nil
+}
+
+func (c *compiler) parseTime(v string) (start, end time.Time, ok bool) {
+	loc := c.today.Location()
+	day := func(t time.Time) (time.Time, time.Time, bool) { return t, t.AddDate(0, 0, 1), true }
+	switch strings.ToLower(v) {
+	case "today":
+		return day(c.today)
+	case "yesterday":
+		return day(c.today.AddDate(0, 0, -1))
+	case "tomorrow":
+		return day(c.today.AddDate(0, 0, 1))
+	}
+	if t, err := time.ParseInLocation("2006-01-02", v, loc); err == nil {
+		return day(t)
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, t.Add(time.Nanosecond), true
+	}
+	// 相对日期：[+-]N(d|w|m|y)
+	if len(v) < 2 {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(v[:len(v)-1], "+"))
+	if err != nil {
+		return
+	}
+	switch v[len(v)-1] {
+	case 'd':
+		return day(c.today.AddDate(0, 0, n))
+	case 'w':
+		return day(c.today.AddDate(0, 0, 7*n))
+	case 'm':
+		return day(c.today.AddDate(0, n, 0))
+	case 'y':
+		return day(c.today.AddDate(n, 0, 0))
+	}
+	return
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+)
+
+var now = time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Cond
+	}{
+		{`status:"in progress"`, Match{Field: FieldStatus, Op: OpEq, Values: []string{"In Progress"}}},
+		{`priority>=medium`, Match{Field: FieldPriority, Op: OpIn, Values: []string{"Medium", "High"}}},
+		{`priority<low`, Match{Field: FieldPriority, Op: OpIn, Values: []string{}}},
+		{`due<7d`, Match{Field: FieldDeadline, Op: OpLt, Time: day(2024, 3, 22)}},
+		{`due<=today`, Match{Field: FieldDeadline, Op: OpLt, Time: day(2024, 3, 16)}},
+		{`created:yesterday`, And{
+			Match{Field: FieldCreatedAt, Op: OpGte, Time: day(2024, 3, 14)},
+			Match{Field: FieldCreatedAt, Op: OpLt, Time: day(2024, 3, 15)},
+		}},
+		{`scheduled>-1m`, Match{Field: FieldScheduledDate, Op: OpGte, Time: day(2024, 2, 16)}},
+		{`updated>=2024-01-01T08:00:00Z`, Match{Field: FieldUpdatedAt, Op: OpGte, Time: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)}},
+		{`assignee:none`, Match{Field: FieldAssignee, Op: OpNull}},
+		{`assignee:"none"`, Match{Field: FieldAssignee, Op: OpEq, Values: []string{"none"}}},
+		{`tag:none`, Match{Field: FieldLabels, Op: OpNull}},
+		{`label:"紧急 事项"`, Match{Field: FieldLabels, Op: OpEq, Values: []string{"紧急 事项"}}},
+		{`project:none`, Match{Field: FieldProject, Op: OpNull}},
+		{`status:"Code Review"`, Match{Field: FieldStatus, Op: OpEq, Values: []string{"Code Review"}}},
+		{`priority:"P1"`, Match{Field: FieldPriority, Op: OpEq, Values: []string{"P1"}}},
+		{`status!=done`, Not{Match{Field: FieldStatus, Op: OpEq, Values: []string{"Done"}}}},
+		{`a b c`, And{
+			Or{Match{Field: FieldTitle, Op: OpContains, Values: []string{"a"}}, Match{Field: FieldDescription, Op: OpContains, Values: []string{"a"}}},
+			Or{Match{Field: FieldTitle, Op: OpContains, Values: []string{"b"}}, Match{Field: FieldDescription, Op: OpContains, Values: []string{"b"}}},
+			Or{Match{Field: FieldTitle, Op: OpContains, Values: []string{"c"}}, Match{Field: FieldDescription, Op: OpContains, Values: []string{"c"}}},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := ParseAndCompile(tt.in, now)
+		if err != nil {
+			t.Errorf("ParseAndCompile(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAndCompile(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		in  string
+		pos int
+	}{
+		{`-color:home`, 1},
+		{`status:blocked`, 7},
+		{`priority:urgent`, 9},
+		{`priority>"P1"`, 9},
+		{`due<soon`, 4},
+		{`title<x`, 5},
+		{`due<none`, 4},
+		{`status:done created:2024-13-01`, 20},
+	}
+	for _, tt := range tests {
+		_, err := ParseAndCompile(tt.in, now)
+		var qe *Error
+		if !errors.As(err, &qe) {
+			t.Errorf("ParseAndCompile(%q): expected *Error, got %v", tt.in, err)
+			continue
+		}
+		if qe.Pos != tt.pos {
+			t.Errorf("ParseAndCompile(%q): expected position %d, got %d (%s)", tt.in, tt.pos, qe.Pos, qe.Msg)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	deadline := day(2024, 3, 18)
+	bob := "bob"
+	task := &models.Task{
+		Title:       "写周报",
+		Description: "Weekly Report",
+		Status:      "In Progress",
+		Priority:    "High",
+		Assignee:    &bob,
+		Deadline:    &deadline,
+		Labels:      []string{"工作", "Q1"},
+		CreatedAt:   day(2024, 3, 1),
+		UpdatedAt:   now,
+	}
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{`周报`, true},
+		{`report`, true},
+		{`status:inprogress priority>medium due<7d`, true},
+		{`due:2024-03-18`, true},
+		{`due>7d`, false},
+		{`scheduled<today`, false},
+		{`-scheduled<today`, true},
+		{`scheduled:none assignee:bob`, true},
+		{`assignee:none OR status:done`, false},
+		{`NOT (title:周报 OR status:done)`, false},
+		{`created<=2024-03-01 updated:today`, true},
+		{`label:Q1 -tag:none`, true},
+		{`tag:q1`, false},
+	}
+	for _, tt := range tests {
+		c, err := ParseAndCompile(tt.in, now)
+		if err != nil {
+			t.Fatalf("ParseAndCompile(%q) failed: %v", tt.in, err)
+		}
+		if got := Eval(c, task); got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
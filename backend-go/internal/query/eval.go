@@ -0,0 +1,117 @@
+package query
+
+import (
+	"strings"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+)
+
+// Eval 判断任务是否满足条件，供内存存储使用；其他存储将条件翻译为各自的查询并保持相同语义
+func Eval(c Cond, t *models.Task) bool {
+	switch c := c.(type) {
+	case And:
+		for _, x := range c {
+			if !Eval(x, t) {
+				return false
+			}
+		}
+		return true
+	case Or:
+		for _, x := range c {
+			if Eval(x, t) {
+				return true
+			}
+		}
+		return false
+	case Not:
+		return !Eval(c.Cond, t)
+	case Match:
+		return evalMatch(c, t)
+	}
+	return false
+}
+
+func evalMatch(m Match, t *models.Task) bool {
+	if m.Field.IsTime() {
+		v := timeValue(m.Field, t)
+		switch {
+		case m.Op == OpNull:
+			return v == nil
+		case v == nil:
+			return false
+		case m.Op == OpLt:
+			return v.Before(m.Time)
+		case m.Op == OpGte:
+			return !v.Before(m.Time)
+		}
+		return false
+	}
+
+	if m.Field == FieldLabels {
+		if m.Op == OpNull {
+			return len(t.Labels) == 0
+		}
+		for _, label := range t.Labels {
+			if label == m.Values[0] {
+				return true
+			}
+		}
+		return false
+	}
+
+	v, ok := stringValue(m.Field, t)
+	switch {
+	case m.Op == OpNull:
+		return !ok
+	case !ok:
+		return false
+	case m.Op == OpContains:
+		return strings.Contains(strings.ToLower(v), strings.ToLower(m.Values[0]))
+	case m.Op == OpEq || m.Op == OpIn:
+		for _, want := range m.Values {
+			if v == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func stringValue(f Field, t *models.Task) (string, bool) {
+	switch f {
+	case FieldTitle:
+		return t.Title, true
+	case FieldDescription:
+		return t.Description, true
+	case FieldStatus:
+		return t.Status, true
+	case FieldPriority:
+		return t.Priority, true
+	case FieldAssignee:
+		if t.Assignee == nil {
+			return "", false
+		}
+		return *t.Assignee, true
+	case FieldProject:
+		if t.ProjectID == nil {
+			return "", false
+		}
+		return *t.ProjectID, true
+	}
+	return "", false
+}
+
+func timeValue(f Field, t *models.Task) *time.Time {
+	switch f {
+	case FieldCreatedAt:
+		return &t.CreatedAt
+	case FieldUpdatedAt:
+		return &t.UpdatedAt
+	case FieldDeadline:
+		return t.Deadline
+	case FieldScheduledDate:
+		return t.ScheduledDate
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tEOF tokenKind = iota
+	tWord
+	tOp
+	tLParen
+	tRParen
+	tNot
+	tAnd
+	tOr
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	pos    int
+	quoted bool
+}
+
+// describe 用于错误信息中描述 token
+func (t token) describe() string {
+	switch t.kind {
+	case tEOF:
+		return "end of query"
+	case tWord:
+		if t.quoted {
+			return `"` + t.text + `"`
+		}
+		return "'" + t.text + "'"
+	}
+	return "'" + t.text + "'"
+}
+
+// wordRune 判断字符能否出现在未加引号的词中；值中还允许出现运算符字符
+func wordRune(r rune, value bool) bool {
+	if unicode.IsSpace(r) || strings.ContainsRune(`()"`, r) {
+		return false
+	}
+	return value || !strings.ContainsRune(`:=<>!`, r)
+}
+
+// lex 将查询切分为 token，位置按字符计
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var toks []token
+	prevOp := func() bool { return len(toks) > 0 && toks[len(toks)-1].kind == tOp }
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{kind: tLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			toks = append(toks, token{kind: tRParen, text: ")", pos: i})
+			i++
+		case r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, errorf(i, "unterminated quoted string")
+			}
+			toks = append(toks, token{kind: tWord, text: b.String(), pos: i, quoted: true})
+			i = j + 1
+		case r == ':' || r == '=':
+			toks = append(toks, token{kind: tOp, text: string(r), pos: i})
+			i++
+		case r == '<' || r == '>' || r == '!':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, errorf(i, "unexpected '!', did you mean '!='?")
+			}
+			toks = append(toks, token{kind: tOp, text: op, pos: i})
+			i += len(op)
+		case r == '-' && !prevOp() && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			toks = append(toks, token{kind: tNot, text: "-", pos: i})
+			i++
+		default:
+			// 运算符之后的值允许包含 ':' 等字符，便于直接书写 RFC3339 时间
+			value := prevOp()
+			j := i
+			for j < len(runes) && wordRune(runes[j], value) {
+				j++
+			}
+			word := string(runes[i:j])
+			tok := token{kind: tWord, text: word, pos: i}
+			if !prevOp() {
+				switch word {
+				case "AND":
+					tok.kind = tAnd
+				case "OR":
+					tok.kind = tOr
+				case "NOT":
+					tok.kind = tNot
+				}
+			}
+			toks = append(toks, tok)
+			i = j
+		}
+	}
+	return append(toks, token{kind: tEOF, pos: len(runes)}), nil
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tEOF {
+		p.i++
+	}
+	return t
+}
+
+// Parse 解析查询语句，返回语法树；错误为 *Error
+func Parse(input string) (Expr, error) {
+	toks, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	if p.peek().kind == tEOF {
+		return nil, errorf(0, "empty query")
+	}
+	x, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tEOF {
+		return nil, errorf(t.pos, "unexpected "+t.describe())
+	}
+	return x, nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tOr {
+		p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: "OR", X: x, Y: y}
+	}
+	return x, nil
+}
+
+// parseAnd 相邻的条件之间隐含 AND
+func (p *parser) parseAnd() (Expr, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tAnd:
+			p.next()
+		case tWord, tLParen, tNot:
+		default:
+			return x, nil
+		}
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: "AND", X: x, Y: y}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if t := p.peek(); t.kind == tNot {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{NotPos: t.pos, X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tRParen {
+			return nil, errorf(t.pos, "missing closing parenthesis")
+		}
+		p.next()
+		return x, nil
+	case tWord:
+		if p.peek().kind != tOp {
+			return &TextExpr{Value: t.text, ValuePos: t.pos}, nil
+		}
+		if t.quoted {
+			return nil, errorf(t.pos, "field name must not be quoted")
+		}
+		op := p.next()
+		v := p.peek()
+		if v.kind != tWord {
+			return nil, errorf(v.pos, "expected value after '"+op.text+"', got "+v.describe())
+		}
+		p.next()
+		return &FieldExpr{Field: t.text, FieldPos: t.pos, Op: op.text, Value: v.text, ValuePos: v.pos, Quoted: v.quoted}, nil
+	}
+	return nil, errorf(t.pos, "unexpected "+t.describe())
+}
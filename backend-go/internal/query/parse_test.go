@@ -0,0 +1,81 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// format 以 S 表达式形式输出语法树，便于断言结构
+func format(e Expr) string {
+	switch e := e.(type) {
+	case *BinaryExpr:
+		return fmt.Sprintf("(%s %s %s)", e.Op, format(e.X), format(e.Y))
+	case *NotExpr:
+		return fmt.Sprintf("(NOT %s)", format(e.X))
+	case *FieldExpr:
+		v := e.Value
+		if e.Quoted {
+			v = `"` + v + `"`
+		}
+		return e.Field + e.Op + v
+	case *TextExpr:
+		return e.Value
+	}
+	return "?"
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`status:"In Progress" priority:High due<7d -assignee:none`,
+			`(AND (AND (AND status:"In Progress" priority:High) due<7d) (NOT assignee:none))`},
+		{`a OR b c`, `(OR a (AND b c))`},
+		{`(a OR b) AND NOT c`, `(AND (OR a b) (NOT c))`},
+		{`due>=-3d updated<=2024-01-02`, `(AND due>=-3d updated<=2024-01-02)`},
+		{`priority!=low`, `priority!=low`},
+		{`title:"say \"hi\""`, `title:"say "hi""`},
+		{`周报 -title:草稿`, `(AND 周报 (NOT title:草稿))`},
+		{`title:AND`, `title:AND`},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got := format(expr); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in  string
+		pos int
+	}{
+		{``, 0},
+		{`status:`, 7},
+		{`(a OR b`, 0},
+		{`a )`, 2},
+		{`title:"unterminated`, 6},
+		{`a OR`, 4},
+		{`a ! b`, 2},
+		{`"title":x`, 0},
+		{`周报 status::done`, 10},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in)
+		var qe *Error
+		if !errors.As(err, &qe) {
+			t.Errorf("Parse(%q): expected *Error, got %v", tt.in, err)
+			continue
+		}
+		if qe.Pos != tt.pos {
+			t.Errorf("Parse(%q): expected position %d, got %d (%s)", tt.in, tt.pos, qe.Pos, qe.Msg)
+		}
+	}
+}
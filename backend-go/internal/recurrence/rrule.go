@@ -0,0 +1,381 @@
+// Package recurrence 解析并展开 RFC 5545 RRULE 的子集：FREQ 为 DAILY、WEEKLY 或 MONTHLY，
+// 支持 INTERVAL、COUNT、UNTIL 与 BYDAY（仅 MONTHLY 可带序号，如 1MO、-1FR）。
+// 展开在 DTSTART 所在时区按钟面时间进行，跨越夏令时切换时保持时刻不变。
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // 生产镜像不带时区数据库，TZID 依赖内嵌数据
+)
+
+// ErrInvalid 规则无法解析或超出支持的子集
+var ErrInvalid = errors.New("invalid recurrence rule")
+
+// Freq 重复频率
+type Freq string
+
+const (
+	Daily   Freq = "DAILY"
+	Weekly  Freq = "WEEKLY"
+	Monthly Freq = "MONTHLY"
+)
+
+// WeekdayNum BYDAY 中的一项，N 为 0 表示每个该星期几，否则为月内第 N 个（负数从月末倒数）
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Day]
+	}
+	return strconv.Itoa(w.N) + weekdayNames[w.Day]
+}
+
+// Rule 解析后的重复规则
+type Rule struct {
+	Freq     Freq
+	Interval int // 未设置时为 1
+	Count    int // 包含 DTSTART 在内的实例总数，0 表示不限
+	ByDay    []WeekdayNum
+
+	// Until 为最后一个实例的上限（含）。UntilFloating 表示 UNTIL 不带 Z，
+	// 此时按 DTSTART 所在时区解释；UntilDate 表示只给出日期，包含当天全天
+	Until         *time.Time
+	UntilFloating bool
+	UntilDate     bool
+}
+
+const (
+	untilDateLayout     = "20060102"
+	untilDateTimeLayout = "20060102T150405"
+)
+
+func invalid(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...))
+}
+
+// Parse 解析 RRULE，可带 "RRULE:" 前缀，键名不区分大小写
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	if s == "" {
+		return nil, invalid("empty rule")
+	}
+	r := &Rule{}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key, value = strings.ToUpper(strings.TrimSpace(key)), strings.ToUpper(strings.TrimSpace(value))
+		if !ok || key == "" || value == "" {
+			return nil, invalid("malformed part %q", part)
+		}
+		if seen[key] {
+			return nil, invalid("duplicate %s", key)
+		}
+		seen[key] = true
+		var err error
+		switch key {
+		case "FREQ":
+			switch f := Freq(value); f {
+			case Daily, Weekly, Monthly:
+				r.Freq = f
+			default:
+				return nil, invalid("unsupported FREQ %s", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = positive(key, value)
+		case "COUNT":
+			r.Count, err = positive(key, value)
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		default:
+			return nil, invalid("unsupported %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.Freq == "" {
+		return nil, invalid("FREQ is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, invalid("COUNT and UNTIL cannot both be set")
+	}
+	if r.Freq != Monthly {
+		for _, d := range r.ByDay {
+			if d.N != 0 {
+				return nil, invalid("BYDAY ordinal %s requires FREQ=MONTHLY", d)
+			}
+		}
+	}
+	return r, nil
+}
+
+func positive(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, invalid("%s must be a positive integer", key)
+	}
+	return n, nil
+}
+
+func (r *Rule) parseUntil(value string) error {
+	var t time.Time
+	var err error
+	switch {
+	case len(value) == len(untilDateLayout):
+		t, err = time.Parse(untilDateLayout, value)
+		r.UntilFloating, r.UntilDate = true, true
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(untilDateTimeLayout, strings.TrimSuffix(value, "Z"))
+	default:
+		t, err = time.Parse(untilDateTimeLayout, value)
+		r.UntilFloating = true
+	}
+	if err != nil {
+		return invalid("malformed UNTIL %s", value)
+	}
+	r.Until = &t
+	return nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var out []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) < 2 {
+			return nil, invalid("malformed BYDAY %q", item)
+		}
+		day := -1
+		for i, name := range weekdayNames {
+			if item[len(item)-2:] == name {
+				day = i
+			}
+		}
+		if day < 0 {
+			return nil, invalid("malformed BYDAY %q", item)
+		}
+		w := WeekdayNum{Day: time.Weekday(day)}
+		if num := item[:len(item)-2]; num != "" {
+			n, err := strconv.Atoi(num)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, invalid("BYDAY ordinal must be between -5 and 5 in %q", item)
+			}
+			w.N = n
+		}
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+// String 以规范形式输出规则，可再次被 Parse 解析
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			days = append(days, d.String())
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		switch {
+		case r.UntilDate:
+			parts = append(parts, "UNTIL="+r.Until.Format(untilDateLayout))
+		case r.UntilFloating:
+			parts = append(parts, "UNTIL="+r.Until.Format(untilDateTimeLayout))
+		default:
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilDateTimeLayout)+"Z")
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// until 返回 loc 下的上限时间
+func (r *Rule) until(loc *time.Location) *time.Time {
+	if r.Until == nil {
+		return nil
+	}
+	if !r.UntilFloating {
+		return r.Until
+	}
+	u := r.Until
+	t := time.Date(u.Year(), u.Month(), u.Day(), u.Hour(), u.Minute(), u.Second(), 0, loc)
+	if r.UntilDate {
+		t = time.Date(u.Year(), u.Month(), u.Day()+1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+	}
+	return &t
+}
+
+// maxEmptyPeriods 连续这么多个周期没有实例时停止展开，防止规则永远无法命中时死循环
+const maxEmptyPeriods = 1000
+
+// each 按时间顺序依次产出实例，fn 返回 false 时停止；DTSTART 总是第一个实例
+func (r *Rule) each(dtstart time.Time, fn func(time.Time) bool) {
+	loc := dtstart.Location()
+	until := r.until(loc)
+	count := 0
+	emit := func(t time.Time) bool {
+		if until != nil && t.After(*until) {
+			return false
+		}
+		count++
+		return fn(t) && (r.Count == 0 || count < r.Count)
+	}
+	if !emit(dtstart) {
+		return
+	}
+
+	y, m, d := dtstart.Date()
+	hour, min, sec := dtstart.Clock()
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	for k, empty := 0, 0; empty < maxEmptyPeriods; k++ {
+		found := false
+		for _, day := range r.period(y, m, d, dtstart.Weekday(), k*interval) {
+			t := localTime(day, hour, min, sec, dtstart.Nanosecond(), loc)
+			if !t.After(dtstart) {
+				continue
+			}
+			found = true
+			if !emit(t) {
+				return
+			}
+		}
+		if found {
+			empty = 0
+		} else if k > 0 {
+			empty++
+		}
+	}
+}
+
+// localTime 按钟面时间构造 loc 下的时刻。time.Date 对不存在或重复的钟面时间不保证结果，
+// 这里按 RFC 5545 处理：落在夏令时跳过的时段时使用切换前的偏移（即顺延跳过的时长），
+// 重复的钟面时间取较早的一次
+func localTime(day time.Time, hour, min, sec, nsec int, loc *time.Location) time.Time {
+	wall := time.Date(day.Year(), day.Month(), day.Day(), hour, min, sec, nsec, time.UTC)
+	// 假定前后 12 小时内至多一次偏移切换
+	_, before := wall.Add(-12 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(12 * time.Hour).In(loc).Zone()
+	valid := func(offset int) (time.Time, bool) {
+		t := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		_, got := t.Zone()
+		return t, got == offset
+	}
+	early, okEarly := valid(before)
+	late, okLate := valid(after)
+	switch {
+	case okEarly && okLate && late.Before(early):
+		return late
+	case okEarly:
+		return early
+	case okLate:
+		return late
+	}
+	return early
+}
+
+// civil 构造不受时区影响的日历日期，用于日期运算
+func civil(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// period 返回从 DTSTART 所在周期起第 offset 个周期内的候选日期（按日期升序）
+func (r *Rule) period(y int, m time.Month, d int, wd time.Weekday, offset int) []time.Time {
+	var out []time.Time
+	switch r.Freq {
+	case Daily:
+		day := civil(y, m, d+offset)
+		if r.matchWeekday(day.Weekday(), wd) {
+			out = append(out, day)
+		}
+	case Weekly:
+		start := civil(y, m, d-(int(wd)+6)%7+7*offset) // WKST=MO
+		for i := 0; i < 7; i++ {
+			if day := start.AddDate(0, 0, i); r.matchWeekday(day.Weekday(), wd) {
+				out = append(out, day)
+			}
+		}
+	case Monthly:
+		first := civil(y, m+time.Month(offset), 1)
+		days := first.AddDate(0, 1, -1).Day()
+		if len(r.ByDay) == 0 {
+			if d <= days { // 没有该日的月份跳过
+				out = append(out, first.AddDate(0, 0, d-1))
+			}
+			break
+		}
+		for i := 1; i <= days; i++ {
+			day := first.AddDate(0, 0, i-1)
+			for _, w := range r.ByDay {
+				if w.Day == day.Weekday() && (w.N == 0 || w.N == (i-1)/7+1 || w.N == -((days-i)/7+1)) {
+					out = append(out, day)
+					break
+				}
+			}
+		}
+	}
+	return out
+}
+
+// matchWeekday 没有 BYDAY 时 WEEKLY 取 DTSTART 的星期几，DAILY 不限
+func (r *Rule) matchWeekday(day, dtstart time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return r.Freq != Weekly || day == dtstart
+	}
+	for _, w := range r.ByDay {
+		if w.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Occurrences 返回前 n 个实例，第一个为 dtstart
+func (r *Rule) Occurrences(dtstart time.Time, n int) []time.Time {
+	var out []time.Time
+	if n <= 0 {
+		return out
+	}
+	r.each(dtstart, func(t time.Time) bool {
+		out = append(out, t)
+		return len(out) < n
+	})
+	return out
+}
+
+// After 返回晚于 after 的前 n 个实例
+func (r *Rule) After(dtstart, after time.Time, n int) []time.Time {
+	var out []time.Time
+	if n <= 0 {
+		return out
+	}
+	r.each(dtstart, func(t time.Time) bool {
+		if t.After(after) {
+			out = append(out, t)
+		}
+		return len(out) < n
+	})
+	return out
+}
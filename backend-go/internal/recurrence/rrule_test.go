@@ -0,0 +1,157 @@
+package recurrence
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%s): %v", name, err)
+	}
+	return loc
+}
+
+// formatAll 以 RFC3339 输出实例，便于整体比较
+func formatAll(ts []time.Time) string {
+	out := make([]string, 0, len(ts))
+	for _, t := range ts {
+		out = append(out, t.Format(time.RFC3339))
+	}
+	return strings.Join(out, " ")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // 规范形式；为空表示应解析失败
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:freq=weekly;byday=mo,we,fr;interval=2", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,FR"},
+		{"FREQ=WEEKLY;INTERVAL=1;COUNT=3", "FREQ=WEEKLY;COUNT=3"},
+		{"FREQ=MONTHLY;BYDAY=-1FR,+2MO", "FREQ=MONTHLY;BYDAY=-1FR,2MO"},
+		{"FREQ=DAILY;UNTIL=20240131", "FREQ=DAILY;UNTIL=20240131"},
+		{"FREQ=DAILY;UNTIL=20240131T090000Z", "FREQ=DAILY;UNTIL=20240131T090000Z"},
+		{"FREQ=DAILY;UNTIL=20240131T090000", "FREQ=DAILY;UNTIL=20240131T090000"},
+		{"", ""},
+		{"INTERVAL=2", ""},
+		{"FREQ=YEARLY", ""},
+		{"FREQ=DAILY;FREQ=WEEKLY", ""},
+		{"FREQ=DAILY;INTERVAL=0", ""},
+		{"FREQ=DAILY;COUNT=-1", ""},
+		{"FREQ=DAILY;COUNT=2;UNTIL=20240101", ""},
+		{"FREQ=DAILY;UNTIL=2024-01-01", ""},
+		{"FREQ=WEEKLY;BYDAY=1MO", ""},
+		{"FREQ=MONTHLY;BYDAY=6MO", ""},
+		{"FREQ=MONTHLY;BYDAY=XX", ""},
+		{"FREQ=DAILY;BYMONTH=1", ""},
+		{"FREQ=DAILY;COUNT", ""},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.in)
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Parse(%q): expected ErrInvalid, got %v", tt.in, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got := r.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	berlin := mustLoad(t, "Europe/Berlin")
+	utc := func(s string) time.Time {
+		ts, _ := time.Parse(time.RFC3339, s)
+		return ts
+	}
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		n       int
+		want    string
+	}{
+		{"daily", "FREQ=DAILY", utc("2024-01-30T09:00:00Z"), 4,
+			"2024-01-30T09:00:00Z 2024-01-31T09:00:00Z 2024-02-01T09:00:00Z 2024-02-02T09:00:00Z"},
+		{"daily interval leap day", "FREQ=DAILY;INTERVAL=2", utc("2024-02-27T00:00:00Z"), 3,
+			"2024-02-27T00:00:00Z 2024-02-29T00:00:00Z 2024-03-02T00:00:00Z"},
+		{"daily weekdays only", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", utc("2024-01-05T08:00:00Z"), 3,
+			"2024-01-05T08:00:00Z 2024-01-08T08:00:00Z 2024-01-09T08:00:00Z"},
+		{"count stops", "FREQ=DAILY;COUNT=2", utc("2024-01-01T00:00:00Z"), 5,
+			"2024-01-01T00:00:00Z 2024-01-02T00:00:00Z"},
+		{"until inclusive", "FREQ=DAILY;UNTIL=20240103T000000Z", utc("2024-01-01T00:00:00Z"), 5,
+			"2024-01-01T00:00:00Z 2024-01-02T00:00:00Z 2024-01-03T00:00:00Z"},
+		{"until date covers whole day", "FREQ=DAILY;UNTIL=20240102", utc("2024-01-01T23:00:00Z"), 5,
+			"2024-01-01T23:00:00Z 2024-01-02T23:00:00Z"},
+		{"until before start", "FREQ=DAILY;UNTIL=20231231", utc("2024-01-01T00:00:00Z"), 5, ""},
+		{"weekly same weekday", "FREQ=WEEKLY", utc("2024-01-03T10:00:00Z"), 3,
+			"2024-01-03T10:00:00Z 2024-01-10T10:00:00Z 2024-01-17T10:00:00Z"},
+		{"weekly byday from midweek", "FREQ=WEEKLY;BYDAY=MO,FR", utc("2024-01-03T10:00:00Z"), 4,
+			"2024-01-03T10:00:00Z 2024-01-05T10:00:00Z 2024-01-08T10:00:00Z 2024-01-12T10:00:00Z"},
+		{"biweekly keeps week parity", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU", utc("2024-01-01T10:00:00Z"), 4,
+			"2024-01-01T10:00:00Z 2024-01-07T10:00:00Z 2024-01-15T10:00:00Z 2024-01-21T10:00:00Z"},
+		{"weekly count includes dtstart", "FREQ=WEEKLY;BYDAY=TU;COUNT=3", utc("2024-01-03T10:00:00Z"), 5,
+			"2024-01-03T10:00:00Z 2024-01-09T10:00:00Z 2024-01-16T10:00:00Z"},
+		{"monthly skips short months", "FREQ=MONTHLY", utc("2024-01-31T12:00:00Z"), 4,
+			"2024-01-31T12:00:00Z 2024-03-31T12:00:00Z 2024-05-31T12:00:00Z 2024-07-31T12:00:00Z"},
+		{"monthly last friday", "FREQ=MONTHLY;BYDAY=-1FR", utc("2024-01-26T17:00:00Z"), 3,
+			"2024-01-26T17:00:00Z 2024-02-23T17:00:00Z 2024-03-29T17:00:00Z"},
+		{"monthly first and third monday", "FREQ=MONTHLY;BYDAY=1MO,3MO", utc("2024-01-01T09:00:00Z"), 4,
+			"2024-01-01T09:00:00Z 2024-01-15T09:00:00Z 2024-02-05T09:00:00Z 2024-02-19T09:00:00Z"},
+		{"monthly fifth thursday is rare", "FREQ=MONTHLY;BYDAY=5TH", utc("2024-02-29T09:00:00Z"), 3,
+			"2024-02-29T09:00:00Z 2024-05-30T09:00:00Z 2024-08-29T09:00:00Z"},
+		{"quarterly every saturday", "FREQ=MONTHLY;INTERVAL=3;BYDAY=SA;COUNT=3", utc("2024-01-06T00:00:00Z"), 5,
+			"2024-01-06T00:00:00Z 2024-01-13T00:00:00Z 2024-01-20T00:00:00Z"},
+
+		// 夏令时：按钟面时间重复，UTC 偏移随之变化
+		{"dst spring forward keeps wall clock", "FREQ=DAILY", time.Date(2024, 3, 9, 9, 0, 0, 0, ny), 3,
+			"2024-03-09T09:00:00-05:00 2024-03-10T09:00:00-04:00 2024-03-11T09:00:00-04:00"},
+		{"dst fall back keeps wall clock", "FREQ=WEEKLY", time.Date(2024, 10, 27, 9, 0, 0, 0, berlin), 2,
+			"2024-10-27T09:00:00+01:00 2024-11-03T09:00:00+01:00"},
+		{"dst gap shifts forward in new york", "FREQ=DAILY", time.Date(2024, 3, 9, 2, 30, 0, 0, ny), 3,
+			"2024-03-09T02:30:00-05:00 2024-03-10T03:30:00-04:00 2024-03-11T02:30:00-04:00"},
+		{"dst gap shifts forward in berlin", "FREQ=DAILY", time.Date(2024, 3, 30, 2, 30, 0, 0, berlin), 3,
+			"2024-03-30T02:30:00+01:00 2024-03-31T03:30:00+02:00 2024-04-01T02:30:00+02:00"},
+		{"dst overlap picks first in new york", "FREQ=DAILY", time.Date(2024, 11, 2, 1, 30, 0, 0, ny), 3,
+			"2024-11-02T01:30:00-04:00 2024-11-03T01:30:00-04:00 2024-11-04T01:30:00-05:00"},
+		{"dst overlap picks first in berlin", "FREQ=DAILY", time.Date(2024, 10, 26, 2, 30, 0, 0, berlin), 3,
+			"2024-10-26T02:30:00+02:00 2024-10-27T02:30:00+02:00 2024-10-28T02:30:00+01:00"},
+		{"until floating uses local zone", "FREQ=DAILY;UNTIL=20240311T090000", time.Date(2024, 3, 9, 9, 0, 0, 0, ny), 5,
+			"2024-03-09T09:00:00-05:00 2024-03-10T09:00:00-04:00 2024-03-11T09:00:00-04:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.rule, err)
+			}
+			if got := formatAll(r.Occurrences(tt.dtstart, tt.n)); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;BYDAY=MO,TH;COUNT=4")
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	got := r.After(start, time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC), 5)
+	if want := "2024-01-08T09:00:00Z 2024-01-11T09:00:00Z"; formatAll(got) != want {
+		t.Errorf("got %s, want %s", formatAll(got), want)
+	}
+	if got := r.After(start, start, 0); len(got) != 0 {
+		t.Errorf("Expected no occurrences for n=0, got %v", got)
+	}
+}
@@ -0,0 +1,129 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+)
+
+// ErrNoAnchor 重复任务既没有计划日期也没有截止日期，无法推算下一次
+var ErrNoAnchor = errors.New("recurring task needs a scheduledDate or deadline")
+
+// Occurrence 重复任务某一次实例的日期
+type Occurrence struct {
+	ScheduledDate *time.Time `json:"scheduledDate"`
+	Deadline      *time.Time `json:"deadline"`
+}
+
+// Validate 校验任务的重复规则与时区，并将规则改写为规范形式
+func Validate(task *models.Task) error {
+	rule, _, err := series(task)
+	if err != nil {
+		return err
+	}
+	task.Recurrence.Rule = rule.String()
+	return nil
+}
+
+// series 解析任务的规则并返回 DTSTART：优先取计划日期，其次截止日期，换算到规则时区
+func series(task *models.Task) (*Rule, time.Time, error) {
+	rule, err := Parse(task.Recurrence.Rule)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	loc, err := time.LoadLocation(task.Recurrence.TZID)
+	if err != nil || task.Recurrence.TZID == "Local" {
+		return nil, time.Time{}, fmt.Errorf("%w: unknown TZID %q", ErrInvalid, task.Recurrence.TZID)
+	}
+	anchor := task.ScheduledDate
+	if anchor == nil {
+		anchor = task.Deadline
+	}
+	if anchor == nil {
+		return nil, time.Time{}, ErrNoAnchor
+	}
+	return rule, anchor.In(loc), nil
+}
+
+// shift 将 t 按钟面时间平移 dtstart 到 next 之间相差的天数
+func shift(t *time.Time, dtstart, next time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	days := int(civil(next.Date()).Sub(civil(dtstart.Date())).Hours() / 24)
+	local := t.In(dtstart.Location())
+	y, m, d := local.Date()
+	hour, min, sec := local.Clock()
+	moved := localTime(civil(y, m, d+days), hour, min, sec, local.Nanosecond(), dtstart.Location()).UTC()
+	return &moved
+}
+
+// occurrence 由下一次 DTSTART 推算实例的两个日期
+func occurrence(task *models.Task, dtstart, next time.Time) Occurrence {
+	if task.ScheduledDate != nil {
+		scheduled := next.UTC()
+		return Occurrence{ScheduledDate: &scheduled, Deadline: shift(task.Deadline, dtstart, next)}
+	}
+	deadline := next.UTC()
+	return Occurrence{Deadline: &deadline}
+}
+
+// Upcoming 返回当前实例之后至多 n 次实例的日期
+func Upcoming(task *models.Task, n int) ([]Occurrence, error) {
+	if task.Recurrence == nil {
+		return []Occurrence{}, nil
+	}
+	rule, dtstart, err := series(task)
+	if err != nil {
+		return nil, err
+	}
+	out := []Occurrence{}
+	for _, next := range rule.After(dtstart, dtstart, n) {
+		out = append(out, occurrence(task, dtstart, next))
+	}
+	return out, nil
+}
+
+// Next 由刚完成的 task 生成下一次实例，系列已结束时返回 nil。
+// 重复规则转移到新实例且 COUNT 减一，检查项重置为未完成，评论与依赖不复制
+func Next(task *models.Task, now time.Time) (*models.Task, error) {
+	if task.Recurrence == nil {
+		return nil, nil
+	}
+	rule, dtstart, err := series(task)
+	if err != nil {
+		return nil, err
+	}
+	after := rule.After(dtstart, dtstart, 1)
+	if len(after) == 0 {
+		return nil, nil
+	}
+	if rule.Count > 0 {
+		rule.Count--
+	}
+	occ := occurrence(task, dtstart, after[0])
+	next := &models.Task{
+		Title:         task.Title,
+		Description:   task.Description,
+		Status:        "To Do",
+		Priority:      task.Priority,
+		Assignee:      task.Assignee,
+		Deadline:      occ.Deadline,
+		ScheduledDate: occ.ScheduledDate,
+		Comments:      []models.Comment{},
+		ParentID:      task.ParentID,
+		Recurrence:    &models.Recurrence{Rule: rule.String(), TZID: task.Recurrence.TZID},
+		Labels:        append([]string(nil), task.Labels...),
+		ProjectID:     task.ProjectID,
+		CreatedBy:     task.CreatedBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	for _, item := range task.Checklist {
+		item.Done = false
+		next.Checklist = append(next.Checklist, item)
+	}
+	return next, nil
+}
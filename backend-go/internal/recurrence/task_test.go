@@ -0,0 +1,79 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+)
+
+func TestNext(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	// 计划于纽约时间周五 09:00，截止日期晚两天；跨越 3 月 10 日夏令时后仍为当地 09:00
+	scheduled := time.Date(2024, 3, 8, 14, 0, 0, 0, time.UTC)
+	deadline := time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC)
+	parent := "p1"
+	task := &models.Task{
+		Title: "周报", Priority: "High", Status: "Done", CreatedBy: "u1", ParentID: &parent,
+		ScheduledDate: &scheduled, Deadline: &deadline,
+		Checklist:  []models.ChecklistItem{{ID: "1", Text: "汇总", Done: true}},
+		Comments:   []models.Comment{{Text: "done"}},
+		BlockedBy:  []string{"x"},
+		Labels:     []string{"工作"},
+		Recurrence: &models.Recurrence{Rule: "freq=weekly;count=2", TZID: "America/New_York"},
+	}
+	if err := Validate(task); err != nil || task.Recurrence.Rule != "FREQ=WEEKLY;COUNT=2" {
+		t.Fatalf("Validate: %v, rule %s", err, task.Recurrence.Rule)
+	}
+
+	next, err := Next(task, now)
+	if err != nil || next == nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if got := next.ScheduledDate.Format(time.RFC3339); got != "2024-03-15T13:00:00Z" {
+		t.Errorf("Expected scheduledDate shifted to 09:00 EDT, got %s", got)
+	}
+	if got := next.Deadline.Format(time.RFC3339); got != "2024-03-17T13:00:00Z" {
+		t.Errorf("Expected deadline shifted by a week, got %s", got)
+	}
+	if next.Status != "To Do" || next.Checklist[0].Done || len(next.Comments) != 0 || len(next.BlockedBy) != 0 || len(next.Labels) != 1 ||
+		*next.ParentID != parent || next.Recurrence.Rule != "FREQ=WEEKLY;COUNT=1" || !next.CreatedAt.Equal(now) {
+		t.Errorf("Unexpected next instance %+v", next)
+	}
+	if task.Checklist[0].Done != true {
+		t.Error("Next must not modify the completed task")
+	}
+
+	// COUNT 用尽后系列结束
+	if last, err := Next(next, now); err != nil || last != nil {
+		t.Errorf("Expected series to end, got %+v (%v)", last, err)
+	}
+}
+
+func TestUpcomingAndValidate(t *testing.T) {
+	deadline := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	task := &models.Task{Deadline: &deadline, Recurrence: &models.Recurrence{Rule: "FREQ=MONTHLY"}}
+	occ, err := Upcoming(task, 3)
+	if err != nil || len(occ) != 3 || occ[0].ScheduledDate != nil || occ[0].Deadline.Format("2006-01-02") != "2024-03-31" {
+		t.Fatalf("Unexpected occurrences %+v (%v)", occ, err)
+	}
+	if occ, _ := Upcoming(&models.Task{}, 3); len(occ) != 0 {
+		t.Errorf("Expected no occurrences without recurrence, got %v", occ)
+	}
+
+	tests := []struct {
+		task *models.Task
+		want error
+	}{
+		{&models.Task{Recurrence: &models.Recurrence{Rule: "FREQ=DAILY"}}, ErrNoAnchor},
+		{&models.Task{Deadline: &deadline, Recurrence: &models.Recurrence{Rule: "FREQ=HOURLY"}}, ErrInvalid},
+		{&models.Task{Deadline: &deadline, Recurrence: &models.Recurrence{Rule: "FREQ=DAILY", TZID: "Mars/Base"}}, ErrInvalid},
+		{&models.Task{Deadline: &deadline, Recurrence: &models.Recurrence{Rule: "FREQ=DAILY", TZID: "Local"}}, ErrInvalid},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.task); !errors.Is(err, tt.want) {
+			t.Errorf("Validate(%+v): expected %v, got %v", tt.task.Recurrence, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package report
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+// ValidType 判断报表类型是否受支持
+func ValidType(reportType string) bool {
+	return reportType == "daily" || reportType == "weekly" || reportType == "monthly"
+}
+
+// Title 根据报表类型和周期生成默认标题
+func Title(reportType, period string) string {
+	titles := map[string]string{"daily": "日报 - " + period, "weekly": "周报 - " + period, "monthly": "月报 - " + period}
+	return titles[reportType]
+}
+
+// Stats 统计任务完成情况，按任务所在工作流的状态类别区分已完成与进行中，now 用于判断是否过期
+func Stats(tasks []models.Task, now time.Time, flows *workflow.Set) models.Statistics {
+	stats := models.Statistics{TotalTasks: len(tasks)}
+	for i := range tasks {
+		t := &tasks[i]
+		category := flows.Category(t)
+		switch category {
+		case models.CategoryDone:
+			stats.CompletedTasks++
+		case models.CategoryActive:
+			stats.InProgressTasks++
+		}
+		if t.Deadline != nil && t.Deadline.Before(now) && category != models.CategoryDone {
+			stats.OverdueTasks++
+		}
+	}
+	if stats.TotalTasks > 0 {
+		stats.CompletionRate = int(float64(stats.CompletedTasks) / float64(stats.TotalTasks) * 100)
+	}
+	return stats
+}
+
+// LabelStats 按标签分组统计，一个任务计入它的每个标签；按标签名升序，
+// 未打标签的任务归入 Label 为空的分组并排在最后
+func LabelStats(tasks []models.Task, now time.Time, flows *workflow.Set) []models.LabelStatistics {
+	groups := map[string][]models.Task{}
+	for _, t := range tasks {
+		if len(t.Labels) == 0 {
+			groups[""] = append(groups[""], t)
+		}
+		for _, label := range t.Labels {
+			groups[label] = append(groups[label], t)
+		}
+	}
+	out := make([]models.LabelStatistics, 0, len(groups))
+	for label, group := range groups {
+		out = append(out, models.LabelStatistics{Label: label, Statistics: Stats(group, now, flows)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if (out[i].Label == "") != (out[j].Label == "") {
+			return out[j].Label == ""
+		}
+		return out[i].Label < out[j].Label
+	})
+	return out
+}
+
+// Markdown 生成报表的 Markdown 正文，events 为周期内任务的历史记录，用于活动时间线
+func Markdown(title string, start, end time.Time, tasks []models.Task, events []models.TaskEvent, stats models.Statistics, labelStats []models.LabelStatistics) string {
+	var sb strings.Builder
+	sb.WriteString("# " + title + "\n\n")
+	sb.WriteString("报告周期: " + start.Format("2006/01/02") + " - " + end.Format("2006/01/02") + "\n\n")
+	sb.WriteString("## 统计信息\n")
+	sb.WriteString("- 总任务数: " + strconv.Itoa(stats.TotalTasks) + "\n")
+	sb.WriteString("- 已完成任务: " + strconv.Itoa(stats.CompletedTasks) + "\n")
+	sb.WriteString("- 进行中任务: " + strconv.Itoa(stats.InProgressTasks) + "\n")
+	sb.WriteString("- 过期任务: " + strconv.Itoa(stats.OverdueTasks) + "\n")
+	sb.WriteString("- 完成率: " + strconv.Itoa(stats.CompletionRate) + "%\n\n")
+	if len(labelStats) > 0 {
+		sb.WriteString("## 标签统计\n")
+		sb.WriteString("| 标签 | 总数 | 已完成 | 进行中 | 过期 | 完成率 |\n")
+		sb.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, ls := range labelStats {
+			label := ls.Label
+			if label == "" {
+				label = "未打标签"
+			}
+			sb.WriteString("| " + label + " | " + strconv.Itoa(ls.TotalTasks) + " | " + strconv.Itoa(ls.CompletedTasks) + " | " +
+				strconv.Itoa(ls.InProgressTasks) + " | " + strconv.Itoa(ls.OverdueTasks) + " | " + strconv.Itoa(ls.CompletionRate) + "% |\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("## 任务详情\n")
+	if len(tasks) == 0 {
+		sb.WriteString("此周期内未找到任务。\n")
+		return sb.String()
+	}
+	titles := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		titles[t.ID] = t.Title
+	}
+	timeline := make(map[string][]models.TaskEvent)
+	for _, e := range events {
+		if e.Action != models.ActionCreated {
+			timeline[e.TaskID] = append(timeline[e.TaskID], e)
+		}
+	}
+	for _, t := range tasks {
+		sb.WriteString("### 任务: " + t.Title + "\n")
+		if t.ParentID != nil {
+			if parent, ok := titles[*t.ParentID]; ok {
+				sb.WriteString("- **父任务**: " + parent + "\n")
+			}
+		}
+		sb.WriteString("- **任务状态**: " + t.Status + "\n")
+		sb.WriteString("- **任务优先级**: " + t.Priority + "\n")
+		if len(t.Labels) > 0 {
+			sb.WriteString("- **标签**: " + strings.Join(t.Labels, ", ") + "\n")
+		}
+		if !t.CreatedAt.IsZero() {
+			sb.WriteString("- **创建时间**: " + t.CreatedAt.Format("2006-01-02 15:04:05") + "\n")
+		}
+		if !t.UpdatedAt.IsZero() {
+			sb.WriteString("- **更新时间**: " + t.UpdatedAt.Format("2006-01-02 15:04:05") + "\n")
+		}
+		if t.Deadline != nil && !t.Deadline.IsZero() {
+			sb.WriteString("- **截止日期**: " + t.Deadline.Format("2006-01-02 15:04:05") + "\n")
+		}
+		if t.ScheduledDate != nil && !t.ScheduledDate.IsZero() {
+			sb.WriteString("- **计划日期**: " + t.ScheduledDate.Format("2006-01-02 15:04:05") + "\n")
+		}
+		desc := t.Description
+		if desc == "" {
+			desc = "无"
+		}
+		sb.WriteString("- **任务描述**: " + desc + "\n")
+		for _, item := range t.Checklist {
+			mark := "[ ]"
+			if item.Done {
+				mark = "[x]"
+			}
+			sb.WriteString("  - " + mark + " " + item.Text + "\n")
+		}
+		sb.WriteString("\n")
+		sb.WriteString("#### 任务活动时间线\n")
+		sb.WriteString("- " + t.CreatedAt.Format("2006-01-02 15:04:05") + ": 任务已创建\n")
+		for _, e := range timeline[t.ID] {
+			sb.WriteString("- " + e.CreatedAt.Format("2006-01-02 15:04:05") + ": " + describe(e) + "\n")
+		}
+		sb.WriteString("\n---\n\n")
+	}
+	return sb.String()
+}
+
+// describe 将一条历史记录转换为时间线中的描述，状态变化单独列出
+func describe(e models.TaskEvent) string {
+	if e.Action == models.ActionDeleted {
+		return "任务已删除"
+	}
+	var parts, fields []string
+	comments := 0
+	for _, c := range e.Changes {
+		switch c.Field {
+		case "status":
+			parts = append(parts, "状态 "+value(c.Old)+" → "+value(c.New))
+		case "comment":
+			if c.Old == nil && c.New != nil {
+				comments++
+			}
+		default:
+			if len(fields) == 0 || fields[len(fields)-1] != c.Field {
+				fields = append(fields, c.Field)
+			}
+		}
+	}
+	if len(fields) > 0 {
+		parts = append(parts, "更新 "+strings.Join(fields, ", "))
+	}
+	if comments > 0 {
+		parts = append(parts, "新增 "+strconv.Itoa(comments)+" 条评论")
+	}
+	if len(parts) == 0 {
+		return "评论已修改"
+	}
+	return strings.Join(parts, "；")
+}
+
+func value(s *string) string {
+	if s == nil {
+		return "无"
+	}
+	return *s
+}
+
+// Generate 根据周期内的任务生成报表（未持久化），events 为这些任务在周期内的历史记录，flows 为用户的工作流
+func Generate(userID, reportType, period string, start, end time.Time, tasks []models.Task, events []models.TaskEvent, flows *workflow.Set) *models.Report {
+	return GenerateForProject(userID, reportType, period, start, end, tasks, events, nil, flows)
+}
+
+// GenerateForProject 生成限定在项目内的报表，标题附带项目名称；project 为 nil 时与 Generate 相同
+func GenerateForProject(userID, reportType, period string, start, end time.Time, tasks []models.Task, events []models.TaskEvent, project *models.Project, flows *workflow.Set) *models.Report {
+	now := time.Now()
+	title := Title(reportType, period)
+	var projectID *string
+	if project != nil {
+		title += " - " + project.Name
+		id := project.ID
+		projectID = &id
+	}
+	stats := Stats(tasks, now, flows)
+	labelStats := LabelStats(tasks, now, flows)
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return &models.Report{
+		UserID:          userID,
+		Type:            reportType,
+		Period:          period,
+		Title:           title,
+		Content:         Markdown(title, start, end, tasks, events, stats, labelStats),
+		Tasks:           ids,
+		Statistics:      stats,
+		LabelStatistics: labelStats,
+		ProjectID:       projectID,
+		StartDate:       start,
+		EndDate:         end,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// Export 导出报表正文，优先使用润色后的内容
+// format 为 "md"/"markdown" 时导出 Markdown，其余导出纯文本
+func Export(rep *models.Report, format string) (data []byte, filename, contentType string) {
+	content := rep.Content
+	if rep.PolishedContent != nil && *rep.PolishedContent != "" {
+		content = *rep.PolishedContent
+	}
+	fileExt := "txt"
+	contentType = "text/plain"
+	format = strings.ToLower(format)
+	if format == "md" || format == "markdown" {
+		fileExt = "md"
+		contentType = "text/markdown"
+	}
+	return []byte(content), "report-" + rep.Period + "." + fileExt, contentType
+}
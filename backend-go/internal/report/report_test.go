@@ -0,0 +1,166 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+func TestStats(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	tasks := []models.Task{
+		{Status: "Done", Deadline: &past},
+		{Status: "In Progress", Deadline: &past},
+		{Status: "To Do", Deadline: &future},
+		{Status: "To Do"},
+	}
+
+	stats := Stats(tasks, now, nil)
+	if stats.TotalTasks != 4 {
+		t.Errorf("Expected 4 total tasks, got %d", stats.TotalTasks)
+	}
+	if stats.CompletedTasks != 1 {
+		t.Errorf("Expected 1 completed task, got %d", stats.CompletedTasks)
+	}
+	if stats.InProgressTasks != 1 {
+		t.Errorf("Expected 1 in progress task, got %d", stats.InProgressTasks)
+	}
+	if stats.OverdueTasks != 1 {
+		t.Errorf("Expected 1 overdue task, got %d", stats.OverdueTasks)
+	}
+	if stats.CompletionRate != 25 {
+		t.Errorf("Expected completion rate 25, got %d", stats.CompletionRate)
+	}
+}
+
+func TestStatsByCategory(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	project := "p1"
+	flows := workflow.NewSet([]models.Workflow{{
+		ProjectID: &project,
+		Statuses: []models.WorkflowStatus{
+			{Name: "Backlog", Category: models.CategoryOpen},
+			{Name: "Review", Category: models.CategoryActive},
+			{Name: "Shipped", Category: models.CategoryDone},
+		},
+	}})
+	tasks := []models.Task{
+		{Status: "Shipped", ProjectID: &project, Deadline: &past},
+		{Status: "Review", ProjectID: &project, Deadline: &past},
+		{Status: "Done"},
+		{Status: "Backlog"},
+	}
+	stats := Stats(tasks, now, flows)
+	if stats.CompletedTasks != 2 || stats.InProgressTasks != 1 || stats.OverdueTasks != 1 || stats.CompletionRate != 50 {
+		t.Errorf("Unexpected statistics %+v", stats)
+	}
+}
+
+func TestLabelStats(t *testing.T) {
+	now := time.Now()
+	tasks := []models.Task{
+		{Status: "Done", Labels: []string{"工作", "Q1"}},
+		{Status: "In Progress", Labels: []string{"工作"}},
+		{Status: "To Do"},
+	}
+	got := LabelStats(tasks, now, nil)
+	if len(got) != 3 || got[0].Label != "Q1" || got[1].Label != "工作" || got[2].Label != "" {
+		t.Fatalf("Expected groups [Q1 工作 \"\"], got %+v", got)
+	}
+	if got[1].TotalTasks != 2 || got[1].CompletedTasks != 1 || got[1].InProgressTasks != 1 || got[1].CompletionRate != 50 {
+		t.Errorf("Unexpected stats for 工作: %+v", got[1])
+	}
+	if got[2].TotalTasks != 1 || got[0].CompletionRate != 100 {
+		t.Errorf("Unexpected stats %+v", got)
+	}
+	if got := LabelStats(nil, now, nil); len(got) != 0 {
+		t.Errorf("Expected no groups without tasks, got %v", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(7 * 24 * time.Hour)
+	parent := "t1"
+	tasks := []models.Task{
+		{ID: "t1", Title: "写周报", Status: "Done", Priority: "High", CreatedAt: start, Labels: []string{"工作"}},
+		{ID: "t2", Title: "汇总数据", Status: "Done", Priority: "Low", CreatedAt: start, ParentID: &parent,
+			Checklist: []models.ChecklistItem{{Text: "导出", Done: true}, {Text: "核对"}}},
+	}
+
+	rep := Generate("u1", "weekly", "2024-W01", start, end, tasks, nil, nil)
+	if rep.Title != "周报 - 2024-W01" {
+		t.Errorf("Unexpected title %q", rep.Title)
+	}
+	if len(rep.Tasks) != 2 || rep.Tasks[0] != "t1" {
+		t.Errorf("Expected task IDs [t1 t2], got %v", rep.Tasks)
+	}
+	if len(rep.LabelStatistics) != 2 || rep.LabelStatistics[0].Label != "工作" {
+		t.Errorf("Expected label statistics for 工作 and unlabeled tasks, got %v", rep.LabelStatistics)
+	}
+	for _, line := range []string{"- **父任务**: 写周报", "  - [x] 导出", "  - [ ] 核对", "- **标签**: 工作", "| 工作 | 1 | 1 | 0 | 0 | 100% |", "| 未打标签 | 1 |"} {
+		if !strings.Contains(rep.Content, line) {
+			t.Errorf("Expected %q in content, got %q", line, rep.Content)
+		}
+	}
+	if !strings.Contains(rep.Content, "### 任务: 写周报") {
+		t.Errorf("Expected task section in content, got %q", rep.Content)
+	}
+	if !strings.Contains(rep.Content, "报告周期: 2024/01/01 - 2024/01/08") {
+		t.Errorf("Expected period line in content, got %q", rep.Content)
+	}
+
+	empty := Generate("u1", "daily", "2024-01-01", start, start, nil, nil, nil)
+	if !strings.Contains(empty.Content, "此周期内未找到任务。") {
+		t.Errorf("Expected empty notice, got %q", empty.Content)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+	tasks := []models.Task{{ID: "t1", Title: "写周报", Status: "Done", CreatedAt: start, UpdatedAt: start.Add(time.Hour)}}
+	events := []models.TaskEvent{
+		{TaskID: "t1", Action: models.ActionCreated, CreatedAt: start},
+		{TaskID: "t1", Action: models.ActionUpdated, CreatedAt: start.Add(time.Hour), Changes: []models.FieldChange{
+			{Field: "status", Old: str("To Do"), New: str("Done")}, {Field: "checklist", ItemID: "i1", New: str("导出")},
+			{Field: "checklist", ItemID: "i2", New: str("核对")}}},
+		{TaskID: "t1", Action: models.ActionCommented, CreatedAt: start.Add(2 * time.Hour), Changes: []models.FieldChange{
+			{Field: "comment", ItemID: "c1", New: str("已发")}}},
+	}
+
+	rep := Generate("u1", "daily", "2024-01-01", start, start.Add(24*time.Hour), tasks, events, nil)
+	for _, line := range []string{
+		"- 2024-01-01 00:00:00: 任务已创建\n- 2024-01-01 01:00:00: 状态 To Do → Done；更新 checklist\n",
+		"- 2024-01-01 02:00:00: 新增 1 条评论\n",
+	} {
+		if !strings.Contains(rep.Content, line) {
+			t.Errorf("Expected %q in content, got %q", line, rep.Content)
+		}
+	}
+	if plain := Generate("u1", "daily", "2024-01-01", start, start, tasks, nil, nil); !strings.Contains(plain.Content, "任务已创建\n\n---") {
+		t.Errorf("Expected only the creation entry without history, got %q", plain.Content)
+	}
+}
+
+func TestExport(t *testing.T) {
+	polished := "polished"
+	rep := &models.Report{Period: "2024-01-01", Content: "raw"}
+
+	data, filename, contentType := Export(rep, "MD")
+	if string(data) != "raw" || filename != "report-2024-01-01.md" || contentType != "text/markdown" {
+		t.Errorf("Unexpected markdown export: %q %q %q", data, filename, contentType)
+	}
+
+	rep.PolishedContent = &polished
+	data, filename, contentType = Export(rep, "txt")
+	if string(data) != "polished" || filename != "report-2024-01-01.txt" || contentType != "text/plain" {
+		t.Errorf("Unexpected text export: %q %q %q", data, filename, contentType)
+	}
+}
@@ -0,0 +1,105 @@
+// Package reportgen 生成报表并读取报表关联的任务：按周期、查询与项目选出任务，连同子任务、
+// 工作流与活动时间线交给 report 统计后保存。/api 处理器与 gRPC 服务共用这些逻辑
+package reportgen
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+	"github.com/axfinn/todoIng/backend-go/internal/report"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/taskhistory"
+	"github.com/axfinn/todoIng/backend-go/internal/taskproject"
+	"github.com/axfinn/todoIng/backend-go/internal/tasktree"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+// ErrProject 限定的项目不存在
+var ErrProject = errors.New("project not found")
+
+// Service 报表的生成与关联任务的读取
+type Service struct {
+	Reports   repository.ReportRepository
+	Tasks     repository.TaskRepository
+	Projects  repository.ProjectRepository
+	Workflows repository.WorkflowRepository
+	History   repository.HistoryRepository
+}
+
+// Input 生成报表的参数，Type 须通过 report.ValidType
+type Input struct {
+	Type       string
+	Period     string
+	Title      string // 为空时按类型与周期生成
+	Start, End time.Time
+	Query      query.Cond // 仅统计匹配的任务
+	ProjectID  string     // 仅统计该项目内的任务
+}
+
+// Generate 统计周期内创建的任务并保存报表，父任务计入时其子任务一并计入；
+// 返回报表与其统计的任务，项目不存在时返回 ErrProject
+func (s *Service) Generate(ctx context.Context, userID string, in Input) (*models.Report, []models.Task, error) {
+	filter := repository.TaskFilter{UserID: userID, CreatedFrom: &in.Start, CreatedTo: &in.End, Query: in.Query}
+	var project *models.Project
+	if in.ProjectID != "" {
+		var err error
+		if project, err = taskproject.Get(ctx, s.Projects, userID, in.ProjectID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, nil, ErrProject
+			}
+			return nil, nil, err
+		}
+		filter.ProjectIDs = []string{project.ID}
+	}
+	tasks, err := s.Tasks.List(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tasks, err = tasktree.WithDescendants(ctx, s.Tasks, userID, tasks); err != nil {
+		return nil, nil, err
+	}
+	if project != nil {
+		tasks = taskproject.InProject(tasks, project.ID)
+	}
+	flows, err := workflow.Load(ctx, s.Workflows, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := taskhistory.ForTasks(ctx, s.History, userID, tasks, in.Start, in.End)
+	if err != nil {
+		return nil, nil, err
+	}
+	rep := report.GenerateForProject(userID, in.Type, in.Period, in.Start, in.End, tasks, events, project, flows)
+	if in.Title != "" {
+		rep.Title = in.Title
+	}
+	if err := s.Reports.Create(ctx, rep); err != nil {
+		return nil, nil, err
+	}
+	return rep, tasks, nil
+}
+
+// Populate 按报表中保存的顺序返回其关联的任务；回收站中的任务带有 DeletedAt，永久删除的任务被跳过
+func (s *Service) Populate(ctx context.Context, userID string, rep *models.Report) ([]models.Task, error) {
+	if len(rep.Tasks) == 0 {
+		return nil, nil
+	}
+	tasks, err := s.Tasks.List(ctx, repository.TaskFilter{UserID: userID, IDs: rep.Tasks, Trash: repository.WithTrashed})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Task, len(tasks))
+	for i := range tasks {
+		byID[tasks[i].ID] = &tasks[i]
+	}
+	out := make([]models.Task, 0, len(rep.Tasks))
+	for _, id := range rep.Tasks {
+		if t, ok := byID[id]; ok {
+			out = append(out, *t)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// HistoryRepository 内存任务历史存储，按写入顺序保存
+type HistoryRepository struct {
+	mu     sync.RWMutex
+	events []models.TaskEvent
+}
+
+// NewHistoryRepository 创建内存任务历史存储
+func NewHistoryRepository() *HistoryRepository {
+	return &HistoryRepository{}
+}
+
+var _ repository.HistoryRepository = (*HistoryRepository)(nil)
+
+// Create 保存一条历史记录并回填 ID
+func (r *HistoryRepository) Create(ctx context.Context, event *models.TaskEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = repository.NewID()
+	r.events = append(r.events, cloneEvent(event))
+	return nil
+}
+
+// List 按条件查询历史记录，按时间正序，时间相同时保持写入顺序
+func (r *HistoryRepository) List(ctx context.Context, filter repository.HistoryFilter) ([]models.TaskEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var ids map[string]bool
+	if len(filter.TaskIDs) > 0 {
+		ids = make(map[string]bool, len(filter.TaskIDs))
+		for _, id := range filter.TaskIDs {
+			ids[id] = true
+		}
+	}
+	out := []models.TaskEvent{}
+	for i := range r.events {
+		e := &r.events[i]
+		if (filter.UserID != "" && e.UserID != filter.UserID) ||
+			(ids != nil && !ids[e.TaskID]) ||
+			!inRange(&e.CreatedAt, filter.From, filter.To) {
+			continue
+		}
+		out = append(out, cloneEvent(e))
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// LabelRepository 内存标签存储
+type LabelRepository struct {
+	mu     sync.RWMutex
+	labels map[string]models.Label
+}
+
+// NewLabelRepository 创建内存标签存储
+func NewLabelRepository() *LabelRepository {
+	return &LabelRepository{labels: map[string]models.Label{}}
+}
+
+var _ repository.LabelRepository = (*LabelRepository)(nil)
+
+// taken 判断同一用户下是否已有同名（不区分大小写）的其他标签，调用方需持有锁
+func (r *LabelRepository) taken(label *models.Label) bool {
+	for _, l := range r.labels {
+		if l.UserID == label.UserID && l.ID != label.ID && strings.EqualFold(l.Name, label.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Create 保存新标签并回填 ID
+func (r *LabelRepository) Create(ctx context.Context, label *models.Label) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.taken(label) {
+		return repository.ErrConflict
+	}
+	label.ID = repository.NewID()
+	r.labels[label.ID] = *label
+	return nil
+}
+
+// Get 获取属于 userID 的标签
+func (r *LabelRepository) Get(ctx context.Context, userID, id string) (*models.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	l, ok := r.labels[id]
+	if !ok || l.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &l, nil
+}
+
+// List 返回用户的全部标签，按名称升序
+func (r *LabelRepository) List(ctx context.Context, userID string) ([]models.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []models.Label{}
+	for _, l := range r.labels {
+		if l.UserID == userID {
+			out = append(out, l)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Update 按 ID 与 UserID 整体覆盖标签
+func (r *LabelRepository) Update(ctx context.Context, label *models.Label) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.labels[label.ID]
+	if !ok || old.UserID != label.UserID {
+		return repository.ErrNotFound
+	}
+	if r.taken(label) {
+		return repository.ErrConflict
+	}
+	r.labels[label.ID] = *label
+	return nil
+}
+
+// Delete 删除属于 userID 的标签
+func (r *LabelRepository) Delete(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.labels[id]
+	if !ok || l.UserID != userID {
+		return repository.ErrNotFound
+	}
+	delete(r.labels, id)
+	return nil
+}
@@ -0,0 +1,115 @@
+// Package memory 基于内存的 repository 实现，供测试和单用户本地模式使用。
+// 数据只保存在进程内，重启后丢失；所有方法可并发调用。
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+)
+
+// page 对已排序的结果应用 skip/limit
+func page[T any](items []T, skip, limit int) []T {
+	if skip >= len(items) {
+		return []T{}
+	}
+	items = items[skip:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// sortByCreatedAtDesc 按创建时间倒序排列，时间相同时按 ID 保证顺序稳定
+func sortByCreatedAtDesc[T any](items []T, key func(T) (time.Time, string)) {
+	sort.Slice(items, func(i, j int) bool {
+		ti, idi := key(items[i])
+		tj, idj := key(items[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return idi > idj
+	})
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := *s
+	return &v
+}
+
+// cloneTask 深拷贝任务，避免调用方修改存储中的数据
+func cloneTask(t *models.Task) models.Task {
+	c := *t
+	c.Assignee = cloneString(t.Assignee)
+	c.Deadline = cloneTime(t.Deadline)
+	c.ScheduledDate = cloneTime(t.ScheduledDate)
+	c.ParentID = cloneString(t.ParentID)
+	c.ProjectID = cloneString(t.ProjectID)
+	c.DeletedAt = cloneTime(t.DeletedAt)
+	if t.Comments != nil {
+		c.Comments = append([]models.Comment{}, t.Comments...)
+	}
+	if t.Checklist != nil {
+		c.Checklist = append([]models.ChecklistItem{}, t.Checklist...)
+	}
+	if t.BlockedBy != nil {
+		c.BlockedBy = append([]string{}, t.BlockedBy...)
+	}
+	if t.Recurrence != nil {
+		r := *t.Recurrence
+		c.Recurrence = &r
+	}
+	if t.Labels != nil {
+		c.Labels = append([]string{}, t.Labels...)
+	}
+	return c
+}
+
+// cloneReport 深拷贝报表
+func cloneReport(r *models.Report) models.Report {
+	c := *r
+	c.PolishedContent = cloneString(r.PolishedContent)
+	c.ProjectID = cloneString(r.ProjectID)
+	c.DeletedAt = cloneTime(r.DeletedAt)
+	if r.Tasks != nil {
+		c.Tasks = append([]string{}, r.Tasks...)
+	}
+	if r.LabelStatistics != nil {
+		c.LabelStatistics = append([]models.LabelStatistics{}, r.LabelStatistics...)
+	}
+	return c
+}
+
+// cloneEvent 深拷贝任务历史记录
+func cloneEvent(e *models.TaskEvent) models.TaskEvent {
+	c := *e
+	c.Changes = make([]models.FieldChange, len(e.Changes))
+	for i, ch := range e.Changes {
+		c.Changes[i] = ch
+		c.Changes[i].Old = cloneString(ch.Old)
+		c.Changes[i].New = cloneString(ch.New)
+	}
+	return c
+}
+
+// cloneWorkflow 深拷贝工作流
+func cloneWorkflow(w *models.Workflow) models.Workflow {
+	c := *w
+	c.ProjectID = cloneString(w.ProjectID)
+	c.Statuses = append([]models.WorkflowStatus{}, w.Statuses...)
+	c.Transitions = append([]models.WorkflowTransition{}, w.Transitions...)
+	c.Priorities = append([]string{}, w.Priorities...)
+	return c
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/repotest"
+)
+
+func TestTaskRepository(t *testing.T) {
+	repotest.TaskRepository(t, NewTaskRepository())
+}
+
+func TestTaskQuery(t *testing.T) {
+	repotest.TaskQuery(t, NewTaskRepository())
+}
+
+func TestTaskTree(t *testing.T) {
+	repotest.TaskTree(t, NewTaskRepository())
+}
+
+func TestTaskDependencies(t *testing.T) {
+	repotest.TaskDependencies(t, NewTaskRepository())
+}
+
+func TestTaskRecurrence(t *testing.T) {
+	repotest.TaskRecurrence(t, NewTaskRepository())
+}
+
+func TestTaskLabels(t *testing.T) {
+	repotest.TaskLabels(t, NewTaskRepository())
+}
+
+func TestTaskProjects(t *testing.T) {
+	repotest.TaskProjects(t, NewTaskRepository())
+}
+
+func TestTaskTrash(t *testing.T) {
+	repotest.TaskTrash(t, NewTaskRepository())
+}
+
+func TestTaskBulkUpdate(t *testing.T) {
+	repotest.TaskBulkUpdate(t, NewTaskRepository())
+}
+
+func TestTaskVersion(t *testing.T) {
+	repotest.TaskVersion(t, NewTaskRepository())
+}
+
+func TestTaskQueryLanguage(t *testing.T) {
+	repotest.TaskQueryLanguage(t, NewTaskRepository())
+}
+
+func TestReportRepository(t *testing.T) {
+	repotest.ReportRepository(t, NewReportRepository())
+}
+
+func TestReportTrash(t *testing.T) {
+	repotest.ReportTrash(t, NewReportRepository())
+}
+
+func TestReportVersion(t *testing.T) {
+	repotest.ReportVersion(t, NewReportRepository())
+}
+
+func TestLabelRepository(t *testing.T) {
+	repotest.LabelRepository(t, NewLabelRepository())
+}
+
+func TestProjectRepository(t *testing.T) {
+	repotest.ProjectRepository(t, NewProjectRepository())
+}
+
+func TestWorkflowRepository(t *testing.T) {
+	repotest.WorkflowRepository(t, NewWorkflowRepository())
+}
+
+func TestHistoryRepository(t *testing.T) {
+	repotest.HistoryRepository(t, NewHistoryRepository())
+}
+
+func TestSearchRepository(t *testing.T) {
+	repotest.SearchRepository(t, NewSearchRepository())
+}
+
+func TestUserRepository(t *testing.T) {
+	repotest.UserRepository(t, NewUserRepository())
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	tasks := NewTaskRepository()
+	reports := NewReportRepository()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task := &models.Task{Title: "t", CreatedBy: "u1"}
+			_ = tasks.Create(ctx, task)
+			task.Status = "Done"
+			_ = tasks.Update(ctx, task)
+			_, _ = tasks.List(ctx, repository.TaskFilter{UserID: "u1"})
+			_ = reports.Create(ctx, &models.Report{UserID: "u1"})
+			_, _ = reports.List(ctx, repository.ReportFilter{UserID: "u1"})
+		}()
+	}
+	wg.Wait()
+	if n, _ := tasks.Count(ctx, repository.TaskFilter{UserID: "u1", Status: "Done"}); n != 50 {
+		t.Errorf("Expected 50 tasks, got %d", n)
+	}
+	if n, _ := reports.Count(ctx, repository.ReportFilter{UserID: "u1"}); n != 50 {
+		t.Errorf("Expected 50 reports, got %d", n)
+	}
+}
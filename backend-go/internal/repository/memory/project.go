@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// ProjectRepository 内存项目存储
+type ProjectRepository struct {
+	mu       sync.RWMutex
+	projects map[string]models.Project
+}
+
+// NewProjectRepository 创建内存项目存储
+func NewProjectRepository() *ProjectRepository {
+	return &ProjectRepository{projects: map[string]models.Project{}}
+}
+
+var _ repository.ProjectRepository = (*ProjectRepository)(nil)
+
+// Create 保存新项目并回填 ID
+func (r *ProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	project.ID = repository.NewID()
+	r.projects[project.ID] = *project
+	return nil
+}
+
+// Get 获取属于 userID 的项目
+func (r *ProjectRepository) Get(ctx context.Context, userID, id string) (*models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.projects[id]
+	if !ok || p.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &p, nil
+}
+
+// List 返回用户的全部项目，按 Position 升序，相同时按创建时间升序
+func (r *ProjectRepository) List(ctx context.Context, userID string) ([]models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []models.Project{}
+	for _, p := range r.projects {
+		if p.UserID == userID {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Position != out[j].Position {
+			return out[i].Position < out[j].Position
+		}
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// Update 按 ID 与 UserID 整体覆盖项目
+func (r *ProjectRepository) Update(ctx context.Context, project *models.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.projects[project.ID]
+	if !ok || old.UserID != project.UserID {
+		return repository.ErrNotFound
+	}
+	r.projects[project.ID] = *project
+	return nil
+}
+
+// Delete 删除属于 userID 的项目
+func (r *ProjectRepository) Delete(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.projects[id]
+	if !ok || p.UserID != userID {
+		return repository.ErrNotFound
+	}
+	delete(r.projects, id)
+	return nil
+}
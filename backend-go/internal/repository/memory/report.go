@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// ReportRepository 内存报表存储
+type ReportRepository struct {
+	mu      sync.RWMutex
+	reports map[string]models.Report
+}
+
+// NewReportRepository 创建内存报表存储
+func NewReportRepository() *ReportRepository {
+	return &ReportRepository{reports: map[string]models.Report{}}
+}
+
+var _ repository.ReportRepository = (*ReportRepository)(nil)
+
+// Create 保存新报表并回填 ID 与版本
+func (r *ReportRepository) Create(ctx context.Context, report *models.Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report.ID = repository.NewID()
+	report.Version = 1
+	r.reports[report.ID] = cloneReport(report)
+	return nil
+}
+
+// Get 获取属于 userID 且未删除的报表
+func (r *ReportRepository) Get(ctx context.Context, userID, id string) (*models.Report, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rep, ok := r.reports[id]
+	if !ok || rep.UserID != userID || rep.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+	c := cloneReport(&rep)
+	return &c, nil
+}
+
+// List 按条件查询报表，按创建时间倒序
+func (r *ReportRepository) List(ctx context.Context, filter repository.ReportFilter) ([]models.Report, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	reports := r.match(filter)
+	sortByCreatedAtDesc(reports, func(rep models.Report) (time.Time, string) { return rep.CreatedAt, rep.ID })
+	return page(reports, filter.Skip, filter.Limit), nil
+}
+
+// Count 统计满足条件的报表数
+func (r *ReportRepository) Count(ctx context.Context, filter repository.ReportFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return int64(len(r.match(filter))), nil
+}
+
+// Update 按 ID、UserID 与版本整体覆盖报表
+func (r *ReportRepository) Update(ctx context.Context, report *models.Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.reports[report.ID]
+	if !ok || old.UserID != report.UserID {
+		return repository.ErrNotFound
+	}
+	if old.Version != report.Version {
+		return repository.ErrVersion
+	}
+	report.Version++
+	r.reports[report.ID] = cloneReport(report)
+	return nil
+}
+
+// Delete 永久删除属于 userID 的报表
+func (r *ReportRepository) Delete(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep, ok := r.reports[id]
+	if !ok || rep.UserID != userID {
+		return repository.ErrNotFound
+	}
+	delete(r.reports, id)
+	return nil
+}
+
+// match 返回满足条件的报表副本，调用方需持有锁
+func (r *ReportRepository) match(f repository.ReportFilter) []models.Report {
+	var ids map[string]bool
+	if f.IDs != nil {
+		ids = make(map[string]bool, len(f.IDs))
+		for _, id := range f.IDs {
+			ids[id] = true
+		}
+	}
+	out := []models.Report{}
+	for _, rep := range r.reports {
+		if f.UserID != "" && rep.UserID != f.UserID {
+			continue
+		}
+		if ids != nil && !ids[rep.ID] {
+			continue
+		}
+		if !inTrash(rep.DeletedAt, f.Trash, f.DeletedTo) {
+			continue
+		}
+		if f.Type != "" && rep.Type != f.Type {
+			continue
+		}
+		if f.ProjectID != "" && (rep.ProjectID == nil || *rep.ProjectID != f.ProjectID) {
+			continue
+		}
+		out = append(out, cloneReport(&rep))
+	}
+	return out
+}
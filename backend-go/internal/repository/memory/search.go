@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+type searchKey struct {
+	kind, id string
+}
+
+// SearchRepository 内存索引词存储，按用户分组
+type SearchRepository struct {
+	mu     sync.RWMutex
+	users  map[string]map[searchKey]repository.SearchDoc
+	owners map[searchKey]string // 文档所属用户，按 kind 与 id 删除时定位分组
+}
+
+// NewSearchRepository 创建内存索引词存储
+func NewSearchRepository() *SearchRepository {
+	return &SearchRepository{users: map[string]map[searchKey]repository.SearchDoc{}, owners: map[searchKey]string{}}
+}
+
+var _ repository.SearchRepository = (*SearchRepository)(nil)
+
+// Put 写入文档，替换其原有的索引词
+func (r *SearchRepository) Put(ctx context.Context, doc *repository.SearchDoc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.put(doc)
+	return nil
+}
+
+// Insert 只在文档尚未索引时写入
+func (r *SearchRepository) Insert(ctx context.Context, doc *repository.SearchDoc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.owners[searchKey{doc.Kind, doc.ID}]; !ok {
+		r.put(doc)
+	}
+	return nil
+}
+
+// put 调用方需持有写锁
+func (r *SearchRepository) put(doc *repository.SearchDoc) {
+	key := searchKey{doc.Kind, doc.ID}
+	r.remove(key)
+	docs := r.users[doc.UserID]
+	if docs == nil {
+		docs = map[searchKey]repository.SearchDoc{}
+		r.users[doc.UserID] = docs
+	}
+	c := *doc
+	c.Terms = maps.Clone(doc.Terms)
+	docs[key] = c
+	r.owners[key] = doc.UserID
+}
+
+// Remove 删除文档的索引词，不存在时忽略
+func (r *SearchRepository) Remove(ctx context.Context, kind, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(searchKey{kind, id})
+	return nil
+}
+
+// remove 调用方需持有写锁
+func (r *SearchRepository) remove(key searchKey) {
+	owner, ok := r.owners[key]
+	if !ok {
+		return
+	}
+	delete(r.users[owner], key)
+	if len(r.users[owner]) == 0 {
+		delete(r.users, owner)
+	}
+	delete(r.owners, key)
+}
+
+// Postings 返回 userID 的文档中 terms 各词的词频与该用户全部文档的统计
+func (r *SearchRepository) Postings(ctx context.Context, userID string, terms []string) ([]repository.SearchPosting, repository.SearchStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var stats repository.SearchStats
+	var out []repository.SearchPosting
+	for key, doc := range r.users[userID] {
+		stats.Docs++
+		stats.TotalLength += doc.Length
+		for _, term := range terms {
+			if tf, ok := doc.Terms[term]; ok {
+				out = append(out, repository.SearchPosting{Kind: key.kind, ID: key.id, Term: term, TF: tf, Length: doc.Length})
+			}
+		}
+	}
+	return out, stats, nil
+}
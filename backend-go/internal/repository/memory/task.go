@@ -0,0 +1,237 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// TaskRepository 内存任务存储
+type TaskRepository struct {
+	mu    sync.RWMutex
+	tasks map[string]models.Task
+}
+
+// NewTaskRepository 创建内存任务存储
+func NewTaskRepository() *TaskRepository {
+	return &TaskRepository{tasks: map[string]models.Task{}}
+}
+
+var _ repository.TaskRepository = (*TaskRepository)(nil)
+
+// Create 保存新任务并回填 ID 与版本
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.insert(task)
+	return nil
+}
+
+// insert 保存新任务并回填 ID 与版本，调用方需持有写锁
+func (r *TaskRepository) insert(task *models.Task) {
+	task.ID = repository.NewID()
+	task.Version = 1
+	r.tasks[task.ID] = cloneTask(task)
+}
+
+// Get 获取属于 userID 且未删除的任务
+func (r *TaskRepository) Get(ctx context.Context, userID, id string) (*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tasks[id]
+	if !ok || t.CreatedBy != userID || t.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+	c := cloneTask(&t)
+	return &c, nil
+}
+
+// List 按条件查询任务，按 filter.Sort 排序
+func (r *TaskRepository) List(ctx context.Context, filter repository.TaskFilter) ([]models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tasks := r.match(filter)
+	sort.Slice(tasks, func(i, j int) bool { return filter.Sort.Less(&tasks[i], &tasks[j]) })
+	if filter.After != nil {
+		i := sort.Search(len(tasks), func(i int) bool { return filter.Sort.After(&tasks[i], filter.After) })
+		tasks = tasks[i:]
+	}
+	return page(tasks, filter.Skip, filter.Limit), nil
+}
+
+// Count 统计满足条件的任务数
+func (r *TaskRepository) Count(ctx context.Context, filter repository.TaskFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return int64(len(r.match(filter))), nil
+}
+
+// Update 按 ID、CreatedBy 与版本整体覆盖任务
+func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.check(task); err != nil {
+		return err
+	}
+	task.Version++
+	r.tasks[task.ID] = cloneTask(task)
+	return nil
+}
+
+// BulkUpdate 先确认全部任务存在且版本一致，再一次性覆盖并创建新任务
+func (r *TaskRepository) BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range tasks {
+		if err := r.check(&tasks[i]); err != nil {
+			return err
+		}
+	}
+	for i := range tasks {
+		tasks[i].Version++
+		r.tasks[tasks[i].ID] = cloneTask(&tasks[i])
+	}
+	for _, task := range created {
+		r.insert(task)
+	}
+	return nil
+}
+
+// check 确认任务存在且存储中的版本与 task.Version 一致，调用方需持有写锁
+func (r *TaskRepository) check(task *models.Task) error {
+	old, ok := r.tasks[task.ID]
+	if !ok || old.CreatedBy != task.CreatedBy {
+		return repository.ErrNotFound
+	}
+	if old.Version != task.Version {
+		return repository.ErrVersion
+	}
+	return nil
+}
+
+// Delete 永久删除属于 userID 的任务
+func (r *TaskRepository) Delete(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tasks[id]
+	if !ok || t.CreatedBy != userID {
+		return repository.ErrNotFound
+	}
+	delete(r.tasks, id)
+	return nil
+}
+
+// inRange 判断时间是否落在 [from, to] 内；设置了范围时空值不匹配
+func inRange(t, from, to *time.Time) bool {
+	if from == nil && to == nil {
+		return true
+	}
+	if t == nil {
+		return false
+	}
+	return (from == nil || !t.Before(*from)) && (to == nil || !t.After(*to))
+}
+
+// inTrash 判断记录是否符合回收站过滤条件
+func inTrash(deletedAt *time.Time, scope repository.TrashScope, to *time.Time) bool {
+	if deletedAt == nil {
+		return scope != repository.OnlyTrashed && to == nil
+	}
+	return scope != repository.WithoutTrashed && inRange(deletedAt, nil, to)
+}
+
+// containsAny 判断 values 中是否有元素在 set 内
+func containsAny(values []string, set map[string]bool) bool {
+	for _, id := range values {
+		if set[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// match 返回满足条件的任务副本，调用方需持有锁
+func (r *TaskRepository) match(f repository.TaskFilter) []models.Task {
+	var ids map[string]bool
+	if f.IDs != nil {
+		ids = make(map[string]bool, len(f.IDs))
+		for _, id := range f.IDs {
+			ids[id] = true
+		}
+	}
+	var parents map[string]bool
+	if f.ParentIDs != nil {
+		parents = make(map[string]bool, len(f.ParentIDs))
+		for _, id := range f.ParentIDs {
+			parents[id] = true
+		}
+	}
+	var blockers map[string]bool
+	if f.BlockedBy != nil {
+		blockers = make(map[string]bool, len(f.BlockedBy))
+		for _, id := range f.BlockedBy {
+			blockers[id] = true
+		}
+	}
+	var labels map[string]bool
+	if f.Labels != nil {
+		labels = make(map[string]bool, len(f.Labels))
+		for _, name := range f.Labels {
+			labels[name] = true
+		}
+	}
+	var projects map[string]bool
+	if f.ProjectIDs != nil {
+		projects = make(map[string]bool, len(f.ProjectIDs))
+		for _, id := range f.ProjectIDs {
+			projects[id] = true
+		}
+	}
+	out := []models.Task{}
+	for _, t := range r.tasks {
+		if f.UserID != "" && t.CreatedBy != f.UserID {
+			continue
+		}
+		if !inTrash(t.DeletedAt, f.Trash, f.DeletedTo) {
+			continue
+		}
+		if ids != nil && !ids[t.ID] {
+			continue
+		}
+		if parents != nil && (t.ParentID == nil || !parents[*t.ParentID]) {
+			continue
+		}
+		if blockers != nil && !containsAny(t.BlockedBy, blockers) {
+			continue
+		}
+		if labels != nil && !containsAny(t.Labels, labels) {
+			continue
+		}
+		if projects != nil && (t.ProjectID == nil || !projects[*t.ProjectID]) {
+			continue
+		}
+		if f.Status != "" && t.Status != f.Status {
+			continue
+		}
+		if f.Priority != "" && t.Priority != f.Priority {
+			continue
+		}
+		if f.Assignee != "" && (t.Assignee == nil || *t.Assignee != f.Assignee) {
+			continue
+		}
+		if f.Query != nil && !query.Eval(f.Query, &t) {
+			continue
+		}
+		if !inRange(&t.CreatedAt, f.CreatedFrom, f.CreatedTo) || !inRange(&t.UpdatedAt, f.UpdatedFrom, f.UpdatedTo) ||
+			!inRange(t.Deadline, f.DeadlineFrom, f.DeadlineTo) || !inRange(t.ScheduledDate, f.ScheduledFrom, f.ScheduledTo) {
+			continue
+		}
+		out = append(out, cloneTask(&t))
+	}
+	return out
+}
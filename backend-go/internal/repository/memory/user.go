@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// UserRepository 内存用户存储
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[string]models.User
+}
+
+// NewUserRepository 创建内存用户存储
+func NewUserRepository() *UserRepository {
+	return &UserRepository{users: map[string]models.User{}}
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+// Create 保存新用户并回填 ID
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user.ID = repository.NewID()
+	user.Email = strings.ToLower(user.Email)
+	r.users[user.ID] = *user
+	return nil
+}
+
+// GetByID 按 ID 查找用户
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	u, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &u, nil
+}
+
+// GetByEmail 按邮箱查找用户
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.find(func(u models.User) bool { return u.Email == strings.ToLower(email) })
+}
+
+// FindByEmailOrUsername 查找邮箱或用户名已被占用的用户
+func (r *UserRepository) FindByEmailOrUsername(ctx context.Context, email, username string) (*models.User, error) {
+	return r.find(func(u models.User) bool {
+		return u.Email == strings.ToLower(email) || u.Username == username
+	})
+}
+
+func (r *UserRepository) find(match func(models.User) bool) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, u := range r.users {
+		if match(u) {
+			return &u, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
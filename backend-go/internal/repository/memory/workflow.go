@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// WorkflowRepository 内存工作流存储
+type WorkflowRepository struct {
+	mu        sync.RWMutex
+	workflows map[string]models.Workflow
+}
+
+// NewWorkflowRepository 创建内存工作流存储
+func NewWorkflowRepository() *WorkflowRepository {
+	return &WorkflowRepository{workflows: map[string]models.Workflow{}}
+}
+
+var _ repository.WorkflowRepository = (*WorkflowRepository)(nil)
+
+// taken 判断同一用户下是否已有作用于同一范围的其他工作流，调用方需持有锁
+func (r *WorkflowRepository) taken(workflow *models.Workflow) bool {
+	for _, w := range r.workflows {
+		if w.UserID == workflow.UserID && w.ID != workflow.ID && scope(&w) == scope(workflow) {
+			return true
+		}
+	}
+	return false
+}
+
+// scope 工作流的作用范围，默认工作流为空字符串
+func scope(w *models.Workflow) string {
+	if w.ProjectID == nil {
+		return ""
+	}
+	return *w.ProjectID
+}
+
+// Create 保存新工作流并回填 ID
+func (r *WorkflowRepository) Create(ctx context.Context, workflow *models.Workflow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.taken(workflow) {
+		return repository.ErrConflict
+	}
+	workflow.ID = repository.NewID()
+	r.workflows[workflow.ID] = cloneWorkflow(workflow)
+	return nil
+}
+
+// Get 获取属于 userID 的工作流
+func (r *WorkflowRepository) Get(ctx context.Context, userID, id string) (*models.Workflow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	w, ok := r.workflows[id]
+	if !ok || w.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	c := cloneWorkflow(&w)
+	return &c, nil
+}
+
+// List 返回用户的全部工作流，默认工作流在前，其余按创建时间升序
+func (r *WorkflowRepository) List(ctx context.Context, userID string) ([]models.Workflow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := []models.Workflow{}
+	for _, w := range r.workflows {
+		if w.UserID == userID {
+			out = append(out, cloneWorkflow(&w))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if (out[i].ProjectID == nil) != (out[j].ProjectID == nil) {
+			return out[i].ProjectID == nil
+		}
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// Update 按 ID 与 UserID 整体覆盖工作流
+func (r *WorkflowRepository) Update(ctx context.Context, workflow *models.Workflow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.workflows[workflow.ID]
+	if !ok || old.UserID != workflow.UserID {
+		return repository.ErrNotFound
+	}
+	if r.taken(workflow) {
+		return repository.ErrConflict
+	}
+	r.workflows[workflow.ID] = cloneWorkflow(workflow)
+	return nil
+}
+
+// Delete 删除属于 userID 的工作流
+func (r *WorkflowRepository) Delete(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w, ok := r.workflows[id]
+	if !ok || w.UserID != userID {
+		return repository.ErrNotFound
+	}
+	delete(r.workflows, id)
+	return nil
+}
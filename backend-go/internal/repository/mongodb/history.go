@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// historyCollection 任务历史所在的集合，任务存储在事务中直接写入
+const historyCollection = "task_history"
+
+// HistoryRepository task_history 集合
+type HistoryRepository struct {
+	col *mongo.Collection
+}
+
+// NewHistoryRepository 创建任务历史存储
+func NewHistoryRepository(db *mongo.Database) *HistoryRepository {
+	return &HistoryRepository{col: db.Collection(historyCollection)}
+}
+
+var _ repository.HistoryRepository = (*HistoryRepository)(nil)
+
+// Create 保存一条历史记录并回填 ID
+func (r *HistoryRepository) Create(ctx context.Context, event *models.TaskEvent) error {
+	return insertEvent(ctx, r.col, event)
+}
+
+// insertEvent 向 col 保存一条历史记录并回填 ID，任务存储在事务中写入历史时同样使用
+func insertEvent(ctx context.Context, col *mongo.Collection, event *models.TaskEvent) error {
+	event.ID = ""
+	res, err := col.InsertOne(ctx, event)
+	if err != nil {
+		return err
+	}
+	event.ID = insertedID(res)
+	return nil
+}
+
+// List 按条件查询历史记录，按时间正序
+func (r *HistoryRepository) List(ctx context.Context, filter repository.HistoryFilter) ([]models.TaskEvent, error) {
+	q := bson.M{}
+	if filter.UserID != "" {
+		q["userId"] = filter.UserID
+	}
+	if len(filter.TaskIDs) > 0 {
+		q["taskId"] = bson.M{"$in": filter.TaskIDs}
+	}
+	timeRange(q, "createdAt", filter.From, filter.To)
+	order := bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}
+	cur, err := r.col.Find(ctx, q, options.Find().SetSort(order))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	events := []models.TaskEvent{}
+	if err := cur.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,110 @@
+package mongodb
+
+import (
+	"context"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// LabelRepository labels 集合
+type LabelRepository struct {
+	col *mongo.Collection
+}
+
+// NewLabelRepository 创建标签存储
+func NewLabelRepository(db *mongo.Database) *LabelRepository {
+	return &LabelRepository{col: db.Collection("labels")}
+}
+
+var _ repository.LabelRepository = (*LabelRepository)(nil)
+
+// taken 判断同一用户下是否已有同名（不区分大小写）的其他标签
+func (r *LabelRepository) taken(ctx context.Context, label *models.Label) error {
+	q := bson.M{
+		"userId": label.UserID,
+		"name":   bson.M{"$regex": "^" + regexp.QuoteMeta(label.Name) + "$", "$options": "i"},
+	}
+	if label.ID != "" {
+		objID, err := objectID(label.ID)
+		if err != nil {
+			return err
+		}
+		q["_id"] = bson.M{"$ne": objID}
+	}
+	n, err := r.col.CountDocuments(ctx, q)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+// Create 保存新标签并回填 ID
+func (r *LabelRepository) Create(ctx context.Context, label *models.Label) error {
+	label.ID = ""
+	if err := r.taken(ctx, label); err != nil {
+		return err
+	}
+	res, err := r.col.InsertOne(ctx, label)
+	if err != nil {
+		return err
+	}
+	label.ID = insertedID(res)
+	return nil
+}
+
+// Get 获取属于 userID 的标签
+func (r *LabelRepository) Get(ctx context.Context, userID, id string) (*models.Label, error) {
+	objID, err := objectID(id)
+	if err != nil {
+		return nil, err
+	}
+	var label models.Label
+	if err := r.col.FindOne(ctx, bson.M{"_id": objID, "userId": userID}).Decode(&label); err != nil {
+		return nil, notFound(err)
+	}
+	return &label, nil
+}
+
+// List 返回用户的全部标签，按名称升序
+func (r *LabelRepository) List(ctx context.Context, userID string) ([]models.Label, error) {
+	cur, err := r.col.Find(ctx, bson.M{"userId": userID}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	labels := []models.Label{}
+	if err := cur.All(ctx, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// Update 按 ID 与 UserID 整体覆盖标签
+func (r *LabelRepository) Update(ctx context.Context, label *models.Label) error {
+	objID, err := objectID(label.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.taken(ctx, label); err != nil {
+		return err
+	}
+	return update(ctx, r.col, bson.M{"_id": objID, "userId": label.UserID}, label)
+}
+
+// Delete 删除属于 userID 的标签
+func (r *LabelRepository) Delete(ctx context.Context, userID, id string) error {
+	objID, err := objectID(id)
+	if err != nil {
+		return err
+	}
+	return remove(ctx, r.col, bson.M{"_id": objID, "userId": userID})
+}
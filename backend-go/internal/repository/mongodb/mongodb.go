@@ -0,0 +1,165 @@
+// Package mongodb 基于 MongoDB 的 repository 实现
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// objectID 解析十六进制 ID，格式错误视为记录不存在
+func objectID(id string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, repository.ErrNotFound
+	}
+	return objID, nil
+}
+
+// notFound 将 mongo.ErrNoDocuments 转换为 repository.ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// insertedID 返回插入文档生成的十六进制 ID
+func insertedID(res *mongo.InsertOneResult) string {
+	if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		return id.Hex()
+	}
+	return ""
+}
+
+// replaceDoc 将模型编码为不含 _id 的文档，用于整体覆盖
+func replaceDoc(v interface{}) (bson.M, error) {
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	delete(doc, "_id")
+	return doc, nil
+}
+
+// createdAtDesc 默认排序：按创建时间倒序
+var createdAtDesc = bson.D{{Key: "createdAt", Value: -1}}
+
+// findOptions 按 sort 排序并应用分页
+func findOptions(sort bson.D, skip, limit int) *options.FindOptions {
+	opts := options.Find().SetSort(sort)
+	if skip > 0 {
+		opts.SetSkip(int64(skip))
+	}
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	return opts
+}
+
+// update 覆盖 filter 命中的文档，未命中时返回 ErrNotFound
+func update(ctx context.Context, col *mongo.Collection, filter bson.M, v interface{}) error {
+	doc, err := replaceDoc(v)
+	if err != nil {
+		return err
+	}
+	res, err := col.UpdateOne(ctx, filter, bson.M{"$set": doc})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// updateVersion 覆盖 filter 命中且版本为 version 的文档并递增版本；
+// 未命中时按 filter 区分 ErrNotFound 与 ErrVersion
+func updateVersion(ctx context.Context, col *mongo.Collection, filter bson.M, version int64, v interface{}) error {
+	doc, err := replaceDoc(v)
+	if err != nil {
+		return err
+	}
+	doc["version"] = version + 1
+	res, err := col.UpdateOne(ctx, versionFilter(filter, version), bson.M{"$set": doc})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+	n, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return repository.ErrVersion
+	}
+	return repository.ErrNotFound
+}
+
+// versionFilter 在 filter 上追加版本条件；没有 version 字段的历史文档视为版本 0
+func versionFilter(filter bson.M, version int64) bson.M {
+	f := bson.M{"version": version}
+	if version == 0 {
+		f["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	for k, v := range filter {
+		f[k] = v
+	}
+	return f
+}
+
+// remove 删除 filter 命中的文档，未命中时返回 ErrNotFound
+func remove(ctx context.Context, col *mongo.Collection, filter bson.M) error {
+	res, err := col.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// partial 将批量写入中途的错误包装为 PartialError，尚未写入任何记录时原样返回
+func partial(written int, err error) error {
+	if written == 0 {
+		return err
+	}
+	return &repository.PartialError{Written: written, Err: err}
+}
+
+// txSupport 缓存部署是否支持多文档事务：只有副本集与分片集群支持，单机部署不支持。
+// 首次使用时以 hello 命令探测，探测失败时下次重试
+type txSupport struct {
+	mu     sync.Mutex
+	known  bool
+	result bool
+}
+
+func (s *txSupport) supported(ctx context.Context, db *mongo.Database) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.known {
+		return s.result, nil
+	}
+	var hello bson.M
+	if err := db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	_, replicaSet := hello["setName"]
+	s.known, s.result = true, replicaSet || hello["msg"] == "isdbgrid"
+	return s.result, nil
+}
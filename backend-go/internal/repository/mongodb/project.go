@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// ProjectRepository projects 集合
+type ProjectRepository struct {
+	col *mongo.Collection
+}
+
+// NewProjectRepository 创建项目存储
+func NewProjectRepository(db *mongo.Database) *ProjectRepository {
+	return &ProjectRepository{col: db.Collection("projects")}
+}
+
+var _ repository.ProjectRepository = (*ProjectRepository)(nil)
+
+// Create 保存新项目并回填 ID
+func (r *ProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	project.ID = ""
+	res, err := r.col.InsertOne(ctx, project)
+	if err != nil {
+		return err
+	}
+	project.ID = insertedID(res)
+	return nil
+}
+
+// Get 获取属于 userID 的项目
+func (r *ProjectRepository) Get(ctx context.Context, userID, id string) (*models.Project, error) {
+	objID, err := objectID(id)
+	if err != nil {
+		return nil, err
+	}
+	var project models.Project
+	if err := r.col.FindOne(ctx, bson.M{"_id": objID, "userId": userID}).Decode(&project); err != nil {
+		return nil, notFound(err)
+	}
+	return &project, nil
+}
+
+// List 返回用户的全部项目，按 Position 升序，相同时按创建时间升序
+func (r *ProjectRepository) List(ctx context.Context, userID string) ([]models.Project, error) {
+	sort := bson.D{{Key: "position", Value: 1}, {Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}
+	cur, err := r.col.Find(ctx, bson.M{"userId": userID}, options.Find().SetSort(sort))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	projects := []models.Project{}
+	if err := cur.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// Update 按 ID 与 UserID 整体覆盖项目
+func (r *ProjectRepository) Update(ctx context.Context, project *models.Project) error {
+	objID, err := objectID(project.ID)
+	if err != nil {
+		return err
+	}
+	return update(ctx, r.col, bson.M{"_id": objID, "userId": project.UserID}, project)
+}
+
+// Delete 删除属于 userID 的项目
+func (r *ProjectRepository) Delete(ctx context.Context, userID, id string) error {
+	objID, err := objectID(id)
+	if err != nil {
+		return err
+	}
+	return remove(ctx, r.col, bson.M{"_id": objID, "userId": userID})
+}
@@ -0,0 +1,62 @@
+package mongodb
+
+import (
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+)
+
+// queryDoc 将查询语言的条件树翻译为查询文档；字段名与 models.Task 的 bson 标签一致。
+// MongoDB 中比较运算不匹配缺失或为 null 的字段，$nor 取反后匹配，与其他存储实现一致
+func queryDoc(c query.Cond) bson.M {
+	switch c := c.(type) {
+	case query.And:
+		if len(c) == 0 {
+			return bson.M{}
+		}
+		return bson.M{"$and": queryDocs(c)}
+	case query.Or:
+		if len(c) == 0 {
+			return matchNone()
+		}
+		return bson.M{"$or": queryDocs(c)}
+	case query.Not:
+		return bson.M{"$nor": []bson.M{queryDoc(c.Cond)}}
+	case query.Match:
+		field := string(c.Field)
+		switch c.Op {
+		case query.OpNull:
+			if c.Field == query.FieldLabels {
+				// 没有标签的任务可能缺少该字段、为 null 或为空数组
+				return bson.M{field: bson.M{"$in": bson.A{nil, bson.A{}}}}
+			}
+			return bson.M{field: nil}
+		case query.OpEq:
+			return bson.M{field: c.Values[0]}
+		case query.OpIn:
+			return bson.M{field: bson.M{"$in": c.Values}}
+		case query.OpContains:
+			return bson.M{field: bson.M{"$regex": regexp.QuoteMeta(c.Values[0]), "$options": "i"}}
+		case query.OpLt:
+			return bson.M{field: bson.M{"$lt": c.Time}}
+		case query.OpGte:
+			return bson.M{field: bson.M{"$gte": c.Time}}
+		}
+	}
+	return matchNone()
+}
+
+func queryDocs(cs []query.Cond) []bson.M {
+	out := make([]bson.M, 0, len(cs))
+	for _, c := range cs {
+		out = append(out, queryDoc(c))
+	}
+	return out
+}
+
+// matchNone 不匹配任何文档的条件
+func matchNone() bson.M {
+	return bson.M{"_id": bson.M{"$in": bson.A{}}}
+}
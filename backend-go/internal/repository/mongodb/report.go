@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// ReportRepository reports 集合
+type ReportRepository struct {
+	col *mongo.Collection
+}
+
+// NewReportRepository 创建报表存储
+func NewReportRepository(db *mongo.Database) *ReportRepository {
+	return &ReportRepository{col: db.Collection("reports")}
+}
+
+var _ repository.ReportRepository = (*ReportRepository)(nil)
+
+// Create 保存新报表并回填 ID 与版本
+func (r *ReportRepository) Create(ctx context.Context, report *models.Report) error {
+	report.ID = ""
+	report.Version = 1
+	res, err := r.col.InsertOne(ctx, report)
+	if err != nil {
+		return err
+	}
+	report.ID = insertedID(res)
+	return nil
+}
+
+// Get 获取属于 userID 且未删除的报表
+func (r *ReportRepository) Get(ctx context.Context, userID, id string) (*models.Report, error) {
+	objID, err := objectID(id)
+	if err != nil {
+		return nil, err
+	}
+	var rep models.Report
+	if err := r.col.FindOne(ctx, bson.M{"_id": objID, "userId": userID, "deletedAt": nil}).Decode(&rep); err != nil {
+		return nil, notFound(err)
+	}
+	return &rep, nil
+}
+
+// List 按条件查询报表
+func (r *ReportRepository) List(ctx context.Context, filter repository.ReportFilter) ([]models.Report, error) {
+	cur, err := r.col.Find(ctx, reportQuery(filter), findOptions(createdAtDesc, filter.Skip, filter.Limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	reports := []models.Report{}
+	if err := cur.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Count 统计满足条件的报表数
+func (r *ReportRepository) Count(ctx context.Context, filter repository.ReportFilter) (int64, error) {
+	return r.col.CountDocuments(ctx, reportQuery(filter))
+}
+
+// Update 按 ID、UserID 与版本整体覆盖报表
+func (r *ReportRepository) Update(ctx context.Context, report *models.Report) error {
+	objID, err := objectID(report.ID)
+	if err != nil {
+		return err
+	}
+	if err := updateVersion(ctx, r.col, bson.M{"_id": objID, "userId": report.UserID}, report.Version, report); err != nil {
+		return err
+	}
+	report.Version++
+	return nil
+}
+
+// Delete 永久删除属于 userID 的报表
+func (r *ReportRepository) Delete(ctx context.Context, userID, id string) error {
+	objID, err := objectID(id)
+	if err != nil {
+		return err
+	}
+	return remove(ctx, r.col, bson.M{"_id": objID, "userId": userID})
+}
+
+// reportQuery 将过滤条件转换为查询文档
+func reportQuery(f repository.ReportFilter) bson.M {
+	q := bson.M{}
+	if f.UserID != "" {
+		q["userId"] = f.UserID
+	}
+	if f.IDs != nil {
+		ids := make([]primitive.ObjectID, 0, len(f.IDs))
+		for _, id := range f.IDs {
+			if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+				ids = append(ids, objID)
+			}
+		}
+		q["_id"] = bson.M{"$in": ids}
+	}
+	trashQuery(q, f.Trash, f.DeletedTo)
+	if f.Type != "" {
+		q["type"] = f.Type
+	}
+	if f.ProjectID != "" {
+		q["projectId"] = f.ProjectID
+	}
+	return q
+}
@@ -0,0 +1,146 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// searchTerm 文档中一个索引词的词频
+type searchTerm struct {
+	Term string  `bson:"term"`
+	TF   float64 `bson:"tf"`
+}
+
+// searchDoc search_index 集合中的文档，_id 为 kind/id
+type searchDoc struct {
+	ID     string       `bson:"_id"`
+	Kind   string       `bson:"kind"`
+	DocID  string       `bson:"docId"`
+	UserID string       `bson:"userId"`
+	Length float64      `bson:"length"`
+	Terms  []searchTerm `bson:"terms"`
+}
+
+// SearchRepository search_index 集合，每个文档的索引词保存为数组，以 userId 与 terms.term 的多键索引查询。
+// 不使用 $text 文本索引：它按空白与标点分词，无法切分中文，也不能按用户统计 BM25 所需的文档频率
+type SearchRepository struct {
+	col *mongo.Collection
+
+	mu      sync.Mutex
+	indexed bool // 多键索引已创建，创建失败时下次重试
+}
+
+// NewSearchRepository 创建索引词存储
+func NewSearchRepository(db *mongo.Database) *SearchRepository {
+	return &SearchRepository{col: db.Collection("search_index")}
+}
+
+var _ repository.SearchRepository = (*SearchRepository)(nil)
+
+// ensureIndex 首次使用时创建 userId 与 terms.term 的多键索引，索引已存在时为空操作
+func (r *SearchRepository) ensureIndex(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.indexed {
+		return nil
+	}
+	_, err := r.col.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "terms.term", Value: 1}}})
+	r.indexed = err == nil
+	return err
+}
+
+func searchID(kind, id string) string {
+	return kind + "/" + id
+}
+
+func newSearchDoc(doc *repository.SearchDoc) searchDoc {
+	terms := make([]searchTerm, 0, len(doc.Terms))
+	for term, tf := range doc.Terms {
+		terms = append(terms, searchTerm{Term: term, TF: tf})
+	}
+	return searchDoc{ID: searchID(doc.Kind, doc.ID), Kind: doc.Kind, DocID: doc.ID, UserID: doc.UserID, Length: doc.Length, Terms: terms}
+}
+
+// Put 写入文档，整体替换其原有的索引词
+func (r *SearchRepository) Put(ctx context.Context, doc *repository.SearchDoc) error {
+	if err := r.ensureIndex(ctx); err != nil {
+		return err
+	}
+	d := newSearchDoc(doc)
+	_, err := r.col.ReplaceOne(ctx, bson.M{"_id": d.ID}, d, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Insert 只在文档尚未索引时写入，_id 已存在时忽略
+func (r *SearchRepository) Insert(ctx context.Context, doc *repository.SearchDoc) error {
+	if err := r.ensureIndex(ctx); err != nil {
+		return err
+	}
+	_, err := r.col.InsertOne(ctx, newSearchDoc(doc))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// Remove 删除文档的索引词，不存在时忽略
+func (r *SearchRepository) Remove(ctx context.Context, kind, id string) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": searchID(kind, id)})
+	return err
+}
+
+// Postings 返回 userID 的文档中 terms 各词的词频与该用户全部文档的统计；只投影出查询词，不读取整个索引词数组
+func (r *SearchRepository) Postings(ctx context.Context, userID string, terms []string) ([]repository.SearchPosting, repository.SearchStats, error) {
+	var stats repository.SearchStats
+	if err := r.ensureIndex(ctx); err != nil {
+		return nil, stats, err
+	}
+	cur, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "docs": bson.M{"$sum": 1}, "length": bson.M{"$sum": "$length"}}}},
+	})
+	if err != nil {
+		return nil, stats, err
+	}
+	var totals []struct {
+		Docs   int     `bson:"docs"`
+		Length float64 `bson:"length"`
+	}
+	if err := cur.All(ctx, &totals); err != nil {
+		return nil, stats, err
+	}
+	if len(totals) == 0 {
+		return nil, stats, nil
+	}
+	stats = repository.SearchStats{Docs: totals[0].Docs, TotalLength: totals[0].Length}
+	if len(terms) == 0 {
+		return nil, stats, nil
+	}
+
+	cur, err = r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID, "terms.term": bson.M{"$in": terms}}}},
+		{{Key: "$project", Value: bson.M{"kind": 1, "docId": 1, "length": 1, "terms": bson.M{
+			"$filter": bson.M{"input": "$terms", "cond": bson.M{"$in": bson.A{"$$this.term", terms}}},
+		}}}},
+	})
+	if err != nil {
+		return nil, stats, err
+	}
+	var docs []searchDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, stats, err
+	}
+	var out []repository.SearchPosting
+	for _, d := range docs {
+		for _, t := range d.Terms {
+			out = append(out, repository.SearchPosting{Kind: d.Kind, ID: d.DocID, Term: t.Term, TF: t.TF, Length: d.Length})
+		}
+	}
+	return out, stats, nil
+}
@@ -0,0 +1,368 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/axfinn/todoIng/backend-go/internal/convert"
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// TaskRepository tasks 集合，BulkUpdateWithHistory 同时写入 task_history 集合
+type TaskRepository struct {
+	col     *mongo.Collection
+	history *mongo.Collection
+	tx      txSupport
+}
+
+// NewTaskRepository 创建任务存储
+func NewTaskRepository(db *mongo.Database) *TaskRepository {
+	return &TaskRepository{col: db.Collection("tasks"), history: db.Collection(historyCollection)}
+}
+
+var _ repository.HistoryTaskRepository = (*TaskRepository)(nil)
+
+// Create 保存新任务并回填 ID 与版本
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	task.ID = ""
+	task.Version = 1
+	res, err := r.col.InsertOne(ctx, task)
+	if err != nil {
+		return err
+	}
+	task.ID = insertedID(res)
+	return nil
+}
+
+// Get 获取属于 userID 且未删除的任务
+func (r *TaskRepository) Get(ctx context.Context, userID, id string) (*models.Task, error) {
+	objID, err := objectID(id)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := r.col.FindOne(ctx, bson.M{"_id": objID, "createdBy": userID, "deletedAt": nil}).Decode(&doc); err != nil {
+		return nil, notFound(err)
+	}
+	task := convert.DocToTask(doc)
+	return &task, nil
+}
+
+// List 按条件查询任务，历史数据中的字符串日期经 DocToTask 兼容处理
+func (r *TaskRepository) List(ctx context.Context, filter repository.TaskFilter) ([]models.Task, error) {
+	q := taskQuery(filter)
+	if filter.After != nil {
+		after, err := afterQuery(filter.Sort, filter.After)
+		if err != nil {
+			return nil, err
+		}
+		q["$or"] = after
+	}
+	cur, err := r.col.Find(ctx, q, findOptions(taskSort(filter.Sort), filter.Skip, filter.Limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	tasks := []models.Task{}
+	for cur.Next(ctx) {
+		var doc bson.M
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, convert.DocToTask(doc))
+	}
+	return tasks, cur.Err()
+}
+
+// Count 统计满足条件的任务数
+func (r *TaskRepository) Count(ctx context.Context, filter repository.TaskFilter) (int64, error) {
+	return r.col.CountDocuments(ctx, taskQuery(filter))
+}
+
+// Update 按 ID、CreatedBy 与版本整体覆盖任务
+func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
+	objID, err := objectID(task.ID)
+	if err != nil {
+		return err
+	}
+	if err := updateVersion(ctx, r.col, bson.M{"_id": objID, "createdBy": task.CreatedBy}, task.Version, task); err != nil {
+		return err
+	}
+	task.Version++
+	return nil
+}
+
+// BulkUpdate 覆盖多个任务并创建新任务。副本集与分片集群上在一个事务中以一次 BulkWrite 写入，
+// 出错时回滚；单机部署不支持事务，先确认全部版本一致再逐条写入，之间被并发修改时返回 *repository.PartialError
+func (r *TaskRepository) BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error {
+	return r.BulkUpdateWithHistory(ctx, tasks, created, nil)
+}
+
+// BulkUpdateWithHistory 与 BulkUpdate 相同，并保存 events 返回的历史记录：副本集与分片集群上与任务在同一事务中写入，
+// 单机部署在任务写入后为已生效的写入逐条保存，失败只记日志
+func (r *TaskRepository) BulkUpdateWithHistory(ctx context.Context, tasks []models.Task, created []*models.Task, events repository.TaskEvents) error {
+	if len(tasks) == 0 && len(created) == 0 {
+		return nil
+	}
+	ok, err := r.tx.supported(ctx, r.col.Database())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err := r.bulkSequential(ctx, tasks, created)
+		if events != nil {
+			for _, event := range events(repository.Written(err, len(tasks)+len(created))) {
+				if err := insertEvent(ctx, r.history, event); err != nil {
+					observability.LogError("Failed to record history for task %s: %v", event.TaskID, err)
+				}
+			}
+		}
+		return err
+	}
+	ids := make([]primitive.ObjectID, len(created))
+	old := make([]string, len(created))
+	for i, task := range created {
+		old[i] = task.ID
+	}
+	sess, err := r.col.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		if err := r.bulkWrite(sc, tasks, created, ids); err != nil || events == nil {
+			return nil, err
+		}
+		// 历史记录需要新任务的 ID，事务失败时恢复
+		for i, task := range created {
+			task.ID = ids[i].Hex()
+		}
+		for _, event := range events(len(tasks) + len(created)) {
+			if err := insertEvent(sc, r.history, event); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		for i, task := range created {
+			task.ID = old[i]
+		}
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Version++
+	}
+	for i, task := range created {
+		task.ID, task.Version = ids[i].Hex(), 1
+	}
+	return nil
+}
+
+// bulkWrite 以一次 BulkWrite 按版本覆盖任务并插入新任务，新任务的 ID 写入 ids；有任务未命中时返回对应错误，由事务回滚
+func (r *TaskRepository) bulkWrite(ctx context.Context, tasks []models.Task, created []*models.Task, ids []primitive.ObjectID) error {
+	writes := make([]mongo.WriteModel, 0, len(tasks)+len(created))
+	for i := range tasks {
+		objID, err := objectID(tasks[i].ID)
+		if err != nil {
+			return err
+		}
+		doc, err := replaceDoc(&tasks[i])
+		if err != nil {
+			return err
+		}
+		doc["version"] = tasks[i].Version + 1
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(versionFilter(bson.M{"_id": objID, "createdBy": tasks[i].CreatedBy}, tasks[i].Version)).
+			SetUpdate(bson.M{"$set": doc}))
+	}
+	for i, task := range created {
+		doc, err := replaceDoc(task)
+		if err != nil {
+			return err
+		}
+		ids[i] = primitive.NewObjectID()
+		doc["_id"], doc["version"] = ids[i], 1
+		writes = append(writes, mongo.NewInsertOneModel().SetDocument(doc))
+	}
+	res, err := r.col.BulkWrite(ctx, writes)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount < int64(len(tasks)) {
+		return r.mismatch(ctx, tasks)
+	}
+	return nil
+}
+
+// bulkSequential 不支持事务时的批量写入：先确认全部任务存在且版本一致，再逐条写入，在首个错误处停止
+func (r *TaskRepository) bulkSequential(ctx context.Context, tasks []models.Task, created []*models.Task) error {
+	if len(tasks) > 0 {
+		or := make(bson.A, 0, len(tasks))
+		for i := range tasks {
+			objID, err := objectID(tasks[i].ID)
+			if err != nil {
+				return err
+			}
+			or = append(or, versionFilter(bson.M{"_id": objID, "createdBy": tasks[i].CreatedBy}, tasks[i].Version))
+		}
+		n, err := r.col.CountDocuments(ctx, bson.M{"$or": or})
+		if err != nil {
+			return err
+		}
+		if n < int64(len(tasks)) {
+			return r.mismatch(ctx, tasks)
+		}
+	}
+	for i := range tasks {
+		if err := r.Update(ctx, &tasks[i]); err != nil {
+			return partial(i, err)
+		}
+	}
+	for i, task := range created {
+		if err := r.Create(ctx, task); err != nil {
+			return partial(len(tasks)+i, err)
+		}
+	}
+	return nil
+}
+
+// mismatch 在有任务未命中版本条件时区分 ErrNotFound 与 ErrVersion
+func (r *TaskRepository) mismatch(ctx context.Context, tasks []models.Task) error {
+	ids := make(bson.A, 0, len(tasks))
+	for i := range tasks {
+		objID, err := objectID(tasks[i].ID)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, objID)
+	}
+	n, err := r.col.CountDocuments(ctx, bson.M{"_id": bson.M{"$in": ids}, "createdBy": tasks[0].CreatedBy})
+	if err != nil {
+		return err
+	}
+	if n < int64(len(tasks)) {
+		return repository.ErrNotFound
+	}
+	return repository.ErrVersion
+}
+
+// Delete 永久删除属于 userID 的任务
+func (r *TaskRepository) Delete(ctx context.Context, userID, id string) error {
+	objID, err := objectID(id)
+	if err != nil {
+		return err
+	}
+	return remove(ctx, r.col, bson.M{"_id": objID, "createdBy": userID})
+}
+
+// taskQuery 将过滤条件转换为查询文档
+func taskQuery(f repository.TaskFilter) bson.M {
+	q := bson.M{}
+	if f.UserID != "" {
+		q["createdBy"] = f.UserID
+	}
+	trashQuery(q, f.Trash, f.DeletedTo)
+	if f.IDs != nil {
+		ids := make([]primitive.ObjectID, 0, len(f.IDs))
+		for _, id := range f.IDs {
+			if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+				ids = append(ids, objID)
+			}
+		}
+		q["_id"] = bson.M{"$in": ids}
+	}
+	if f.ParentIDs != nil {
+		q["parentId"] = bson.M{"$in": f.ParentIDs}
+	}
+	if f.BlockedBy != nil {
+		q["blockedBy"] = bson.M{"$in": f.BlockedBy}
+	}
+	if f.Labels != nil {
+		q["labels"] = bson.M{"$in": f.Labels}
+	}
+	if f.ProjectIDs != nil {
+		q["projectId"] = bson.M{"$in": f.ProjectIDs}
+	}
+	if f.Status != "" {
+		q["status"] = f.Status
+	}
+	if f.Priority != "" {
+		q["priority"] = f.Priority
+	}
+	if f.Assignee != "" {
+		q["assignee"] = f.Assignee
+	}
+	timeRange(q, "createdAt", f.CreatedFrom, f.CreatedTo)
+	timeRange(q, "updatedAt", f.UpdatedFrom, f.UpdatedTo)
+	timeRange(q, "deadline", f.DeadlineFrom, f.DeadlineTo)
+	timeRange(q, "scheduledDate", f.ScheduledFrom, f.ScheduledTo)
+	if f.Query != nil {
+		q["$and"] = []bson.M{queryDoc(f.Query)}
+	}
+	return q
+}
+
+// trashQuery 添加回收站过滤条件；deletedAt 为 null 或不存在均视为未删除
+func trashQuery(q bson.M, scope repository.TrashScope, to *time.Time) {
+	r := bson.M{}
+	switch scope {
+	case repository.WithoutTrashed:
+		r["$eq"] = nil
+	case repository.OnlyTrashed:
+		r["$ne"] = nil
+	}
+	if to != nil {
+		r["$lte"] = *to
+	}
+	if len(r) > 0 {
+		q["deletedAt"] = r
+	}
+}
+
+// timeRange 为字段添加含边界的时间范围条件
+func timeRange(q bson.M, field string, from, to *time.Time) {
+	r := bson.M{}
+	if from != nil {
+		r["$gte"] = *from
+	}
+	if to != nil {
+		r["$lte"] = *to
+	}
+	if len(r) > 0 {
+		q[field] = r
+	}
+}
+
+// taskSort 排序文档；MongoDB 中 null 本就小于其他值，与 TaskSort 的约定一致
+func taskSort(s repository.TaskSort) bson.D {
+	dir := -1
+	if s.Asc {
+		dir = 1
+	}
+	return bson.D{{Key: string(s.Key()), Value: dir}, {Key: "_id", Value: dir}}
+}
+
+// afterQuery 生成游标之后的键集条件（$or 分支），与 taskSort 的顺序一致
+func afterQuery(s repository.TaskSort, c *repository.TaskCursor) ([]bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil {
+		return nil, repository.ErrInvalidCursor
+	}
+	field := string(s.Key())
+	switch {
+	case c.Value == nil && s.Asc:
+		return []bson.M{{field: nil, "_id": bson.M{"$gt": id}}, {field: bson.M{"$ne": nil}}}, nil
+	case c.Value == nil:
+		return []bson.M{{field: nil, "_id": bson.M{"$lt": id}}}, nil
+	case s.Asc:
+		return []bson.M{{field: bson.M{"$gt": c.Value}}, {field: c.Value, "_id": bson.M{"$gt": id}}}, nil
+	}
+	return []bson.M{{field: bson.M{"$lt": c.Value}}, {field: c.Value, "_id": bson.M{"$lt": id}}, {field: nil}}, nil
+}
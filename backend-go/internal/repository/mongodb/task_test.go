@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+func TestTaskQuery(t *testing.T) {
+	if q := taskQuery(repository.TaskFilter{Trash: repository.WithTrashed}); len(q) != 0 {
+		t.Errorf("Expected empty query, got %v", q)
+	}
+	// 默认排除回收站中的任务，deletedAt 不存在的旧文档视为未删除
+	if q := taskQuery(repository.TaskFilter{}); len(q) != 1 || len(q["deletedAt"].(bson.M)) != 1 {
+		t.Errorf("Expected only the trash condition, got %v", q)
+	}
+	cutoff := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if q := taskQuery(repository.TaskFilter{Trash: repository.OnlyTrashed, DeletedTo: &cutoff}); q["deletedAt"].(bson.M)["$lte"] != cutoff {
+		t.Errorf("Unexpected trash query %v", q)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := primitive.NewObjectID()
+	q := taskQuery(repository.TaskFilter{
+		UserID:      "u1",
+		IDs:         []string{id.Hex(), "not-an-id"},
+		Status:      "Done",
+		Labels:      []string{"工作"},
+		CreatedFrom: &from,
+	})
+	if q["createdBy"] != "u1" || q["status"] != "Done" || q["labels"].(bson.M)["$in"].([]string)[0] != "工作" {
+		t.Errorf("Unexpected query %v", q)
+	}
+	ids := q["_id"].(bson.M)["$in"].([]primitive.ObjectID)
+	if len(ids) != 1 || ids[0] != id {
+		t.Errorf("Expected invalid IDs to be skipped, got %v", ids)
+	}
+	created := q["createdAt"].(bson.M)
+	if created["$gte"] != from || created["$lte"] != nil {
+		t.Errorf("Unexpected createdAt range %v", created)
+	}
+
+	// 显式传入空 ID 列表时不应匹配任何任务
+	q = taskQuery(repository.TaskFilter{IDs: []string{}})
+	if ids := q["_id"].(bson.M)["$in"].([]primitive.ObjectID); len(ids) != 0 {
+		t.Errorf("Expected empty $in, got %v", ids)
+	}
+}
+
+func TestAfterQuery(t *testing.T) {
+	id := primitive.NewObjectID()
+	v := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	or, err := afterQuery(repository.TaskSort{Field: repository.SortByDeadline}, &repository.TaskCursor{Value: v, ID: id.Hex()})
+	if err != nil || len(or) != 3 {
+		t.Fatalf("Expected 3 branches for descending cursor, got %v (%v)", or, err)
+	}
+	if or[0]["deadline"].(bson.M)["$lt"] != v || or[2]["deadline"] != nil {
+		t.Errorf("Unexpected descending cursor query %v", or)
+	}
+
+	or, _ = afterQuery(repository.TaskSort{Field: repository.SortByDeadline, Asc: true}, &repository.TaskCursor{ID: id.Hex()})
+	if len(or) != 2 || or[0]["_id"].(bson.M)["$gt"] != id {
+		t.Errorf("Unexpected ascending null cursor query %v", or)
+	}
+
+	if _, err := afterQuery(repository.TaskSort{}, &repository.TaskCursor{ID: "bad"}); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+	if s := taskSort(repository.TaskSort{}); s[0].Key != "createdAt" || s[0].Value != -1 || s[1].Key != "_id" {
+		t.Errorf("Unexpected default sort %v", s)
+	}
+}
+
+func TestQueryDoc(t *testing.T) {
+	now := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	cond, err := query.ParseAndCompile(`title:"a.b" -assignee:bob priority<low`, now)
+	if err != nil {
+		t.Fatalf("ParseAndCompile failed: %v", err)
+	}
+	doc := queryDoc(cond)
+	and, ok := doc["$and"].([]bson.M)
+	if !ok || len(and) != 3 {
+		t.Fatalf("Expected 3 conjuncts, got %v", doc)
+	}
+	if re := and[0]["title"].(bson.M); re["$regex"] != `a\.b` || re["$options"] != "i" {
+		t.Errorf("Expected escaped regex, got %v", re)
+	}
+	if nor := and[1]["$nor"].([]bson.M); len(nor) != 1 || nor[0]["assignee"] != "bob" {
+		t.Errorf("Expected $nor on assignee, got %v", and[1])
+	}
+	if in := and[2]["priority"].(bson.M)["$in"].([]string); len(in) != 0 {
+		t.Errorf("Expected empty $in, got %v", in)
+	}
+
+	doc = queryDoc(mustCompile(t, "due:none", now))
+	if v, ok := doc["deadline"]; !ok || v != nil {
+		t.Errorf("Expected null match on deadline, got %v", doc)
+	}
+	doc = queryDoc(mustCompile(t, "due:2024-06-11", now))
+	start := time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC)
+	if and := doc["$and"].([]bson.M); len(and) != 2 || and[0]["deadline"].(bson.M)["$gte"] != start {
+		t.Errorf("Unexpected date range %v", doc)
+	}
+}
+
+func mustCompile(t *testing.T, q string, now time.Time) query.Cond {
+	t.Helper()
+	cond, err := query.ParseAndCompile(q, now)
+	if err != nil {
+		t.Fatalf("ParseAndCompile(%q) failed: %v", q, err)
+	}
+	return cond
+}
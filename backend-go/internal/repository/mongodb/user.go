@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// UserRepository users 集合
+type UserRepository struct {
+	col *mongo.Collection
+}
+
+// NewUserRepository 创建用户存储
+func NewUserRepository(db *mongo.Database) *UserRepository {
+	return &UserRepository{col: db.Collection("users")}
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+// Create 保存新用户并回填 ID
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	user.ID = ""
+	user.Email = strings.ToLower(user.Email)
+	res, err := r.col.InsertOne(ctx, user)
+	if err != nil {
+		return err
+	}
+	user.ID = insertedID(res)
+	return nil
+}
+
+// GetByID 按 ID 查找用户
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	objID, err := objectID(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+// GetByEmail 按邮箱查找用户
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"email": strings.ToLower(email)})
+}
+
+// FindByEmailOrUsername 查找邮箱或用户名已被占用的用户
+func (r *UserRepository) FindByEmailOrUsername(ctx context.Context, email, username string) (*models.User, error) {
+	return r.findOne(ctx, bson.M{"$or": []bson.M{
+		{"email": strings.ToLower(email)},
+		{"username": username},
+	}})
+}
+
+func (r *UserRepository) findOne(ctx context.Context, filter bson.M) (*models.User, error) {
+	var user models.User
+	if err := r.col.FindOne(ctx, filter).Decode(&user); err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
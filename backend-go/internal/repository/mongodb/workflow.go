@@ -0,0 +1,114 @@
+package mongodb
+
+import (
+	"context"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// WorkflowRepository workflows 集合
+type WorkflowRepository struct {
+	col *mongo.Collection
+}
+
+// NewWorkflowRepository 创建工作流存储
+func NewWorkflowRepository(db *mongo.Database) *WorkflowRepository {
+	return &WorkflowRepository{col: db.Collection("workflows")}
+}
+
+var _ repository.WorkflowRepository = (*WorkflowRepository)(nil)
+
+// taken 判断同一用户下是否已有作用于同一范围的其他工作流
+func (r *WorkflowRepository) taken(ctx context.Context, workflow *models.Workflow) error {
+	q := bson.M{"userId": workflow.UserID, "projectId": nil}
+	if workflow.ProjectID != nil {
+		q["projectId"] = *workflow.ProjectID
+	}
+	if workflow.ID != "" {
+		objID, err := objectID(workflow.ID)
+		if err != nil {
+			return err
+		}
+		q["_id"] = bson.M{"$ne": objID}
+	}
+	n, err := r.col.CountDocuments(ctx, q)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+// Create 保存新工作流并回填 ID
+func (r *WorkflowRepository) Create(ctx context.Context, workflow *models.Workflow) error {
+	workflow.ID = ""
+	if err := r.taken(ctx, workflow); err != nil {
+		return err
+	}
+	res, err := r.col.InsertOne(ctx, workflow)
+	if err != nil {
+		return err
+	}
+	workflow.ID = insertedID(res)
+	return nil
+}
+
+// Get 获取属于 userID 的工作流
+func (r *WorkflowRepository) Get(ctx context.Context, userID, id string) (*models.Workflow, error) {
+	objID, err := objectID(id)
+	if err != nil {
+		return nil, err
+	}
+	var workflow models.Workflow
+	if err := r.col.FindOne(ctx, bson.M{"_id": objID, "userId": userID}).Decode(&workflow); err != nil {
+		return nil, notFound(err)
+	}
+	return &workflow, nil
+}
+
+// List 返回用户的全部工作流，默认工作流在前，其余按创建时间升序
+func (r *WorkflowRepository) List(ctx context.Context, userID string) ([]models.Workflow, error) {
+	order := bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}
+	cur, err := r.col.Find(ctx, bson.M{"userId": userID}, options.Find().SetSort(order))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	workflows := []models.Workflow{}
+	if err := cur.All(ctx, &workflows); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(workflows, func(i, j int) bool {
+		return workflows[i].ProjectID == nil && workflows[j].ProjectID != nil
+	})
+	return workflows, nil
+}
+
+// Update 按 ID 与 UserID 整体覆盖工作流
+func (r *WorkflowRepository) Update(ctx context.Context, workflow *models.Workflow) error {
+	objID, err := objectID(workflow.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.taken(ctx, workflow); err != nil {
+		return err
+	}
+	return update(ctx, r.col, bson.M{"_id": objID, "userId": workflow.UserID}, workflow)
+}
+
+// Delete 删除属于 userID 的工作流
+func (r *WorkflowRepository) Delete(ctx context.Context, userID, id string) error {
+	objID, err := objectID(id)
+	if err != nil {
+		return err
+	}
+	return remove(ctx, r.col, bson.M{"_id": objID, "userId": userID})
+}
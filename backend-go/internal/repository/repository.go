@@ -0,0 +1,258 @@
+// Package repository 定义任务、报表和用户的数据访问接口，
+// HTTP 处理器与 gRPC 服务通过这些接口访问存储，不直接依赖具体数据库。
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+)
+
+// ErrNotFound 记录不存在，或不属于指定用户
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict 违反唯一约束，如同一用户下的标签重名
+var ErrConflict = errors.New("conflict")
+
+// ErrVersion 写入时记录的版本已不是调用方读取到的版本，记录已被其他请求修改
+var ErrVersion = errors.New("version mismatch")
+
+// PartialError 不支持事务的存储在批量写入中途失败：按写入顺序（先覆盖的任务，再新建的任务）前 Written 条已生效，
+// 覆盖的任务版本已递增、新建的任务已回填 ID，其余未写入；Err 为导致中止的错误
+type PartialError struct {
+	Written int
+	Err     error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("partial write after %d item(s): %v", e.Written, e.Err)
+}
+
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// Written 返回批量写入中已生效的条数：成功时为 n，PartialError 时为其 Written，其他错误时为 0
+func Written(err error, n int) int {
+	var partial *PartialError
+	switch {
+	case err == nil:
+		return n
+	case errors.As(err, &partial):
+		return partial.Written
+	}
+	return 0
+}
+
+// NewID 生成与 Mongo ObjectID 同样格式的 24 位十六进制 ID，供非 Mongo 实现使用
+func NewID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// TrashScope 按是否已移入回收站过滤，零值只返回未删除的记录
+type TrashScope int
+
+const (
+	WithoutTrashed TrashScope = iota // 只返回未删除的记录
+	OnlyTrashed                      // 只返回回收站中的记录
+	WithTrashed                      // 不区分是否已删除
+)
+
+// TaskFilter 任务查询条件，零值字段不参与过滤；时间范围均含边界，
+// 按截止/计划日期过滤时不匹配未设置该日期的任务
+type TaskFilter struct {
+	UserID        string
+	IDs           []string
+	ParentIDs     []string // 只返回这些任务的直接子任务
+	BlockedBy     []string // 只返回被其中任一任务阻塞的任务
+	Labels        []string // 只返回带有其中任一标签的任务，按名称精确匹配
+	ProjectIDs    []string // 只返回属于其中任一项目的任务
+	Status        string
+	Priority      string
+	Assignee      string
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	UpdatedFrom   *time.Time
+	UpdatedTo     *time.Time
+	DeadlineFrom  *time.Time
+	DeadlineTo    *time.Time
+	ScheduledFrom *time.Time
+	ScheduledTo   *time.Time
+	Query         query.Cond // 查询语言编译得到的附加条件，nil 表示不限制
+	Trash         TrashScope
+	DeletedTo     *time.Time // 只返回在此之前移入回收站的任务，不匹配未删除的任务
+
+	Sort  TaskSort
+	After *TaskCursor // 只返回排在游标之后的任务
+	Skip  int
+	Limit int // 0 表示不限制
+}
+
+// ReportFilter 报表查询条件，零值字段不参与过滤
+type ReportFilter struct {
+	UserID    string
+	IDs       []string
+	Type      string
+	ProjectID string // 只返回限定在该项目的报表
+	Trash     TrashScope
+	DeletedTo *time.Time // 只返回在此之前移入回收站的报表，不匹配未删除的报表
+	Skip      int
+	Limit     int // 0 表示不限制
+}
+
+// HistoryFilter 任务历史查询条件，零值字段不参与过滤；时间范围含边界
+type HistoryFilter struct {
+	UserID  string
+	TaskIDs []string // 只返回这些任务的历史
+	From    *time.Time
+	To      *time.Time
+}
+
+// TaskRepository 任务存储；List 按 filter.Sort 排序，默认按创建时间倒序。
+// DeletedAt 非空的任务位于回收站，Get 不返回，List 与 Count 按 filter.Trash 过滤。
+// Version 由存储维护：Create 置为 1，每次成功写入加 1 并回填到传入的任务
+type TaskRepository interface {
+	// Create 保存新任务并回填 ID 与版本
+	Create(ctx context.Context, task *models.Task) error
+	// Get 获取属于 userID 且未删除的任务
+	Get(ctx context.Context, userID, id string) (*models.Task, error)
+	List(ctx context.Context, filter TaskFilter) ([]models.Task, error)
+	// Count 统计满足条件的任务数，忽略 Sort/After/Skip/Limit
+	Count(ctx context.Context, filter TaskFilter) (int64, error)
+	// Update 按 ID 与 CreatedBy 整体覆盖任务，回收站中的任务同样适用；
+	// 存储中的版本与 task.Version 不同时返回 ErrVersion
+	Update(ctx context.Context, task *models.Task) error
+	// BulkUpdate 在一次批量写入中按 ID、CreatedBy 与 Version 整体覆盖多个任务，并创建 created 中的新任务；
+	// 任一任务不存在时返回 ErrNotFound，版本不同时返回 ErrVersion。内存、SQL 与副本集或分片集群上的 MongoDB
+	// 在一个事务中写入，出错时不写入任何任务；单机 MongoDB 不支持事务，按顺序写入并在首个错误处停止，返回 *PartialError
+	BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error
+	// Delete 永久删除属于 userID 的任务，回收站中的任务同样适用
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// TaskEvents 生成随任务写入一并保存的历史记录；written 为已生效的写入数（先覆盖的任务，再新建的任务），
+// 调用时覆盖的任务尚未递增版本，新建的任务已回填 ID
+type TaskEvents func(written int) []*models.TaskEvent
+
+// HistoryTaskRepository 能随任务写入一并保存任务历史的存储，taskhistory 优先使用
+type HistoryTaskRepository interface {
+	TaskRepository
+	// BulkUpdateWithHistory 与 BulkUpdate 相同，并保存 events 返回的历史记录。支持事务时历史与任务在同一事务中提交，
+	// 历史写入失败时任务一并回滚；单机 MongoDB 在任务写入后为已生效的写入保存历史，历史写入失败只记日志
+	BulkUpdateWithHistory(ctx context.Context, tasks []models.Task, created []*models.Task, events TaskEvents) error
+}
+
+// ReportRepository 报表存储；List 按创建时间倒序返回。
+// DeletedAt 非空的报表位于回收站，Get 不返回，List 与 Count 按 filter.Trash 过滤。
+// Version 的维护方式与任务相同
+type ReportRepository interface {
+	// Create 保存新报表并回填 ID 与版本
+	Create(ctx context.Context, report *models.Report) error
+	// Get 获取属于 userID 且未删除的报表
+	Get(ctx context.Context, userID, id string) (*models.Report, error)
+	List(ctx context.Context, filter ReportFilter) ([]models.Report, error)
+	// Count 统计满足条件的报表数，忽略 Skip/Limit
+	Count(ctx context.Context, filter ReportFilter) (int64, error)
+	// Update 按 ID 与 UserID 整体覆盖报表，回收站中的报表同样适用；
+	// 存储中的版本与 report.Version 不同时返回 ErrVersion
+	Update(ctx context.Context, report *models.Report) error
+	// Delete 永久删除属于 userID 的报表，回收站中的报表同样适用
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// LabelRepository 标签存储；List 按名称升序返回。
+// 名称在同一用户内不区分大小写唯一，Create 与 Update 遇到重名时返回 ErrConflict
+type LabelRepository interface {
+	// Create 保存新标签并回填 ID
+	Create(ctx context.Context, label *models.Label) error
+	// Get 获取属于 userID 的标签
+	Get(ctx context.Context, userID, id string) (*models.Label, error)
+	List(ctx context.Context, userID string) ([]models.Label, error)
+	// Update 按 ID 与 UserID 整体覆盖标签
+	Update(ctx context.Context, label *models.Label) error
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// ProjectRepository 项目存储；List 按 Position 升序返回，相同时按创建时间升序
+type ProjectRepository interface {
+	// Create 保存新项目并回填 ID
+	Create(ctx context.Context, project *models.Project) error
+	// Get 获取属于 userID 的项目
+	Get(ctx context.Context, userID, id string) (*models.Project, error)
+	List(ctx context.Context, userID string) ([]models.Project, error)
+	// Update 按 ID 与 UserID 整体覆盖项目
+	Update(ctx context.Context, project *models.Project) error
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// WorkflowRepository 工作流存储；List 先返回用户默认工作流，其余按创建时间升序。
+// 每个用户至多一个默认工作流，每个项目至多一个工作流，Create 与 Update 违反时返回 ErrConflict
+type WorkflowRepository interface {
+	// Create 保存新工作流并回填 ID
+	Create(ctx context.Context, workflow *models.Workflow) error
+	// Get 获取属于 userID 的工作流
+	Get(ctx context.Context, userID, id string) (*models.Workflow, error)
+	List(ctx context.Context, userID string) ([]models.Workflow, error)
+	// Update 按 ID 与 UserID 整体覆盖工作流
+	Update(ctx context.Context, workflow *models.Workflow) error
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// HistoryRepository 任务历史存储，只追加不修改；List 按时间正序返回
+type HistoryRepository interface {
+	// Create 保存一条历史记录并回填 ID
+	Create(ctx context.Context, event *models.TaskEvent) error
+	List(ctx context.Context, filter HistoryFilter) ([]models.TaskEvent, error)
+}
+
+// SearchDoc 全文检索中的一个文档，按 Kind 与 ID 唯一；Terms 为按字段权重累加的词频，Length 为按字段权重累加的词数
+type SearchDoc struct {
+	Kind   string
+	ID     string
+	UserID string
+	Terms  map[string]float64
+	Length float64
+}
+
+// SearchPosting 文档中一个索引词的词频，Length 为文档长度
+type SearchPosting struct {
+	Kind   string
+	ID     string
+	Term   string
+	TF     float64
+	Length float64
+}
+
+// SearchStats 一个用户已索引的文档数与总长度
+type SearchStats struct {
+	Docs        int
+	TotalLength float64
+}
+
+// SearchRepository 全文检索的索引词存储；索引保存在数据库中，共用同一数据库的各进程立即看到彼此的写入
+type SearchRepository interface {
+	// Put 写入文档，替换其原有的索引词
+	Put(ctx context.Context, doc *SearchDoc) error
+	// Insert 只在文档尚未索引时写入，已索引时忽略，补建索引时不会覆盖并发写入的较新内容
+	Insert(ctx context.Context, doc *SearchDoc) error
+	// Remove 删除文档的索引词，不存在时忽略
+	Remove(ctx context.Context, kind, id string) error
+	// Postings 返回 userID 的文档中 terms 各词的词频，以及该用户全部已索引文档的统计
+	Postings(ctx context.Context, userID string, terms []string) ([]SearchPosting, SearchStats, error)
+}
+
+// UserRepository 用户存储，邮箱统一以小写保存和查询
+type UserRepository interface {
+	// Create 保存新用户并回填 ID
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	// FindByEmailOrUsername 查找邮箱或用户名已被占用的用户
+	FindByEmailOrUsername(ctx context.Context, email, username string) (*models.User, error)
+}
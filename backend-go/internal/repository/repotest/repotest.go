@@ -0,0 +1,1217 @@
+// Package repotest 提供各 repository 实现共用的一致性测试
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// TaskRepository 校验任务存储的增删改查、过滤、排序与分页
+func TaskRepository(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ids []string
+	for i := 0; i < 5; i++ {
+		task := &models.Task{
+			Title:     fmt.Sprintf("t%d", i),
+			Status:    "To Do",
+			Priority:  "Medium",
+			CreatedBy: "u1",
+			CreatedAt: base.Add(time.Duration(i) * time.Hour),
+			UpdatedAt: base.Add(time.Duration(i) * time.Hour),
+			Comments:  []models.Comment{},
+		}
+		if i%2 == 0 {
+			task.Status = "Done"
+		}
+		if err := repo.Create(ctx, task); err != nil || task.ID == "" {
+			t.Fatalf("Create failed: %v, id=%q", err, task.ID)
+		}
+		ids = append(ids, task.ID)
+	}
+	if err := repo.Create(ctx, &models.Task{Title: "other", CreatedBy: "u2", CreatedAt: base}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	list, err := repo.List(ctx, repository.TaskFilter{UserID: "u1"})
+	if err != nil || len(list) != 5 || list[0].Title != "t4" || list[4].Title != "t0" {
+		t.Errorf("Expected 5 tasks newest first, got %v (%v)", list, err)
+	}
+	list, _ = repo.List(ctx, repository.TaskFilter{UserID: "u1", Skip: 1, Limit: 2})
+	if len(list) != 2 || list[0].Title != "t3" || list[1].Title != "t2" {
+		t.Errorf("Unexpected page %v", list)
+	}
+	list, _ = repo.List(ctx, repository.TaskFilter{UserID: "u1", Skip: 4})
+	if len(list) != 1 || list[0].Title != "t0" {
+		t.Errorf("Unexpected skip-only page %v", list)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "u1", Status: "Done", Limit: 1}); n != 3 {
+		t.Errorf("Expected 3 done tasks, got %d", n)
+	}
+	from, to := base.Add(time.Hour), base.Add(3*time.Hour)
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "u1", CreatedFrom: &from, CreatedTo: &to}); n != 3 {
+		t.Errorf("Expected 3 tasks in range, got %d", n)
+	}
+	list, _ = repo.List(ctx, repository.TaskFilter{UserID: "u1", IDs: []string{ids[1], ids[3], "missing"}})
+	if len(list) != 2 {
+		t.Errorf("Expected 2 tasks by ID, got %v", list)
+	}
+	if list, _ = repo.List(ctx, repository.TaskFilter{UserID: "u1", IDs: []string{}}); len(list) != 0 {
+		t.Errorf("Expected no tasks for empty ID list, got %v", list)
+	}
+
+	// 可选字段与评论的读写
+	got, err := repo.Get(ctx, "u1", ids[1])
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Assignee != nil || got.Deadline != nil || got.ScheduledDate != nil || len(got.Comments) != 0 {
+		t.Errorf("Expected empty optional fields, got %+v", got)
+	}
+	assignee := "bob"
+	deadline := base.Add(48 * time.Hour)
+	got.Title = "changed"
+	got.Assignee = &assignee
+	got.Deadline = &deadline
+	first, edited := "c1", base.Add(2*time.Minute)
+	got.Comments = []models.Comment{
+		{ID: first, Text: "first", CreatedBy: "u1", CreatedAt: base, UpdatedAt: &edited},
+		{ID: "c2", ParentID: &first, Text: "second", CreatedBy: "u1", CreatedAt: base.Add(time.Minute)},
+	}
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	again, _ := repo.Get(ctx, "u1", ids[1])
+	if again.Title != "changed" || again.Assignee == nil || *again.Assignee != "bob" ||
+		again.Deadline == nil || !again.Deadline.Equal(deadline) || !again.CreatedAt.Equal(got.CreatedAt) {
+		t.Errorf("Unexpected task after update: %+v", again)
+	}
+	if len(again.Comments) != 2 || again.Comments[0].Text != "first" || again.Comments[1].Text != "second" ||
+		!again.Comments[1].CreatedAt.Equal(base.Add(time.Minute)) || again.Comments[1].ID != "c2" ||
+		again.Comments[1].ParentID == nil || *again.Comments[1].ParentID != first || again.Comments[1].UpdatedAt != nil ||
+		again.Comments[0].ParentID != nil || again.Comments[0].UpdatedAt == nil || !again.Comments[0].UpdatedAt.Equal(edited) {
+		t.Errorf("Unexpected comments after update: %+v", again.Comments)
+	}
+	again.Title = "not saved"
+	if stored, _ := repo.Get(ctx, "u1", ids[1]); stored.Title != "changed" {
+		t.Errorf("Expected stored task to be unchanged, got %q", stored.Title)
+	}
+
+	if _, err := repo.Get(ctx, "u2", ids[1]); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for other user, got %v", err)
+	}
+	if _, err := repo.Get(ctx, "u1", "not-an-id"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for malformed ID, got %v", err)
+	}
+	got.CreatedBy = "u2"
+	if err := repo.Update(ctx, got); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when updating as other user, got %v", err)
+	}
+	if err := repo.Delete(ctx, "u1", ids[1]); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if err := repo.Delete(ctx, "u1", ids[1]); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound on second delete, got %v", err)
+	}
+}
+
+// TaskQuery 校验任务的组合过滤、各排序字段（含空值与相同值）以及游标分页
+func TaskQuery(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) *time.Time {
+		v := base.Add(time.Duration(n) * 24 * time.Hour)
+		return &v
+	}
+	alice, bob := "alice", "bob"
+	specs := []struct {
+		title     string
+		assignee  *string
+		deadline  *time.Time
+		scheduled *time.Time
+		updated   int
+	}{
+		{"b", &alice, day(3), nil, 5},
+		{"a", nil, nil, day(1), 1},
+		{"d", &bob, day(1), day(2), 4},
+		{"c", &alice, day(3), nil, 2},
+		{"b", nil, nil, nil, 3},
+		{"e", &bob, day(2), day(1), 0},
+	}
+	for i, sp := range specs {
+		task := &models.Task{
+			Title:         sp.title,
+			Status:        "To Do",
+			Priority:      "Low",
+			Assignee:      sp.assignee,
+			Deadline:      sp.deadline,
+			ScheduledDate: sp.scheduled,
+			CreatedBy:     "q1",
+			CreatedAt:     base.Add(time.Duration(i) * time.Minute),
+			UpdatedAt:     *day(sp.updated),
+			Comments:      []models.Comment{},
+		}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	count := func(f repository.TaskFilter) int64 {
+		f.UserID = "q1"
+		n, err := repo.Count(ctx, f)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		return n
+	}
+	filters := []struct {
+		name   string
+		filter repository.TaskFilter
+		want   int64
+	}{
+		{"assignee", repository.TaskFilter{Assignee: "alice"}, 2},
+		{"deadline from", repository.TaskFilter{DeadlineFrom: day(2)}, 3},
+		{"deadline range", repository.TaskFilter{DeadlineFrom: day(1), DeadlineTo: day(2)}, 2},
+		{"scheduled to", repository.TaskFilter{ScheduledTo: day(1)}, 2},
+		{"updated range", repository.TaskFilter{UpdatedFrom: day(2), UpdatedTo: day(4)}, 3},
+		{"combined", repository.TaskFilter{Assignee: "bob", ScheduledFrom: day(2)}, 1},
+	}
+	for _, tt := range filters {
+		if n := count(tt.filter); n != tt.want {
+			t.Errorf("%s: expected %d tasks, got %d", tt.name, tt.want, n)
+		}
+	}
+
+	for _, field := range []repository.TaskSortField{
+		repository.SortByCreatedAt, repository.SortByUpdatedAt, repository.SortByDeadline,
+		repository.SortByScheduledDate, repository.SortByTitle,
+	} {
+		for _, asc := range []bool{true, false} {
+			sort := repository.TaskSort{Field: field, Asc: asc}
+			all, err := repo.List(ctx, repository.TaskFilter{UserID: "q1", Sort: sort})
+			if err != nil || len(all) != len(specs) {
+				t.Fatalf("%s: List failed: %v (%d tasks)", sort, err, len(all))
+			}
+			for i := 1; i < len(all); i++ {
+				if !sort.Less(&all[i-1], &all[i]) {
+					t.Errorf("%s: tasks %d and %d out of order: %+v, %+v", sort, i-1, i, all[i-1], all[i])
+				}
+			}
+
+			// 逐页读取应与一次性读取的顺序完全一致
+			var paged []models.Task
+			filter := repository.TaskFilter{UserID: "q1", Sort: sort, Limit: 2}
+			for pages := 0; ; pages++ {
+				if pages > len(specs) {
+					t.Fatalf("%s: cursor pagination does not terminate", sort)
+				}
+				page, next, err := repository.ListTaskPage(ctx, repo, filter)
+				if err != nil {
+					t.Fatalf("%s: ListTaskPage failed: %v", sort, err)
+				}
+				paged = append(paged, page...)
+				if next == nil {
+					break
+				}
+				if filter.After, err = sort.DecodeCursor(sort.EncodeCursor(next)); err != nil {
+					t.Fatalf("%s: cursor round trip failed: %v", sort, err)
+				}
+			}
+			if len(paged) != len(all) {
+				t.Fatalf("%s: expected %d paged tasks, got %d", sort, len(all), len(paged))
+			}
+			for i := range all {
+				if paged[i].ID != all[i].ID {
+					t.Errorf("%s: page order differs at %d: %s vs %s", sort, i, paged[i].ID, all[i].ID)
+				}
+			}
+		}
+	}
+
+	// 降序时未设置截止日期的任务排在最后
+	list, _ := repo.List(ctx, repository.TaskFilter{UserID: "q1", Sort: repository.TaskSort{Field: repository.SortByDeadline}})
+	if list[0].Deadline == nil || list[len(list)-1].Deadline != nil || list[len(list)-2].Deadline != nil {
+		t.Errorf("Expected tasks without deadline last, got %+v", list)
+	}
+}
+
+// TaskTree 校验父任务、按父任务过滤以及检查项的保存与整体替换
+func TaskTree(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	newTask := func(title string, parent *string, checklist ...models.ChecklistItem) *models.Task {
+		task := &models.Task{Title: title, Status: "To Do", Priority: "Medium", CreatedBy: "tree",
+			CreatedAt: now, UpdatedAt: now, Comments: []models.Comment{}, ParentID: parent, Checklist: checklist}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task
+	}
+	root := newTask("root", nil)
+	a := newTask("a", &root.ID,
+		models.ChecklistItem{ID: "1", Text: "one", Done: true, CreatedAt: now},
+		models.ChecklistItem{ID: "2", Text: "two", CreatedAt: now})
+	b := newTask("b", &root.ID)
+	newTask("a1", &a.ID)
+
+	got, err := repo.Get(ctx, "tree", a.ID)
+	if err != nil || got.ParentID == nil || *got.ParentID != root.ID {
+		t.Fatalf("Expected parent %s, got %+v (%v)", root.ID, got, err)
+	}
+	if len(got.Checklist) != 2 || got.Checklist[0].ID != "1" || !got.Checklist[0].Done || got.Checklist[1].Text != "two" ||
+		!got.Checklist[1].CreatedAt.Equal(now) {
+		t.Errorf("Unexpected checklist %+v", got.Checklist)
+	}
+	if got, _ := repo.Get(ctx, "tree", root.ID); got.ParentID != nil || len(got.Checklist) != 0 {
+		t.Errorf("Expected top-level task without checklist, got %+v", got)
+	}
+
+	children, err := repo.List(ctx, repository.TaskFilter{UserID: "tree", ParentIDs: []string{root.ID}, Sort: repository.TaskSort{Field: repository.SortByTitle, Asc: true}})
+	if err != nil || len(children) != 2 || children[0].ID != a.ID || children[1].ID != b.ID {
+		t.Errorf("Expected children [a b], got %v (%v)", children, err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "tree", ParentIDs: []string{root.ID, a.ID}}); n != 3 {
+		t.Errorf("Expected 3 tasks under root and a, got %d", n)
+	}
+	if list, _ := repo.List(ctx, repository.TaskFilter{UserID: "tree", ParentIDs: []string{}}); len(list) != 0 {
+		t.Errorf("Expected no tasks for empty ParentIDs, got %v", list)
+	}
+
+	// 移动到新父任务并替换检查项
+	got.ParentID = &b.ID
+	got.Checklist = []models.ChecklistItem{{ID: "3", Text: "three", CreatedAt: now}}
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, _ = repo.Get(ctx, "tree", a.ID)
+	if *got.ParentID != b.ID || len(got.Checklist) != 1 || got.Checklist[0].ID != "3" {
+		t.Errorf("Unexpected task after update %+v", got)
+	}
+	got.ParentID = nil
+	_ = repo.Update(ctx, got)
+	if got, _ := repo.Get(ctx, "tree", a.ID); got.ParentID != nil {
+		t.Errorf("Expected a to become top-level, got parent %v", *got.ParentID)
+	}
+}
+
+// TaskDependencies 校验阻塞任务列表的保存顺序、整体替换与按阻塞任务过滤
+func TaskDependencies(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	newTask := func(title string, blockedBy ...string) *models.Task {
+		task := &models.Task{Title: title, Status: "To Do", Priority: "Medium", CreatedBy: "deps",
+			CreatedAt: now, UpdatedAt: now, Comments: []models.Comment{}, BlockedBy: blockedBy}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task
+	}
+	a := newTask("a")
+	b := newTask("b")
+	c := newTask("c", b.ID, a.ID)
+	d := newTask("d", a.ID)
+
+	got, err := repo.Get(ctx, "deps", c.ID)
+	if err != nil || len(got.BlockedBy) != 2 || got.BlockedBy[0] != b.ID || got.BlockedBy[1] != a.ID {
+		t.Fatalf("Expected blockedBy [b a], got %+v (%v)", got, err)
+	}
+	list, err := repo.List(ctx, repository.TaskFilter{UserID: "deps", BlockedBy: []string{a.ID}, Sort: repository.TaskSort{Field: repository.SortByTitle, Asc: true}})
+	if err != nil || len(list) != 2 || list[0].ID != c.ID || list[1].ID != d.ID {
+		t.Errorf("Expected [c d] blocked by a, got %v (%v)", list, err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "deps", BlockedBy: []string{b.ID, d.ID}}); n != 1 {
+		t.Errorf("Expected 1 task blocked by b or d, got %d", n)
+	}
+
+	got.BlockedBy = []string{d.ID}
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got, _ := repo.Get(ctx, "deps", c.ID); len(got.BlockedBy) != 1 || got.BlockedBy[0] != d.ID {
+		t.Errorf("Expected blockedBy [d], got %v", got.BlockedBy)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "deps", BlockedBy: []string{b.ID}}); n != 0 {
+		t.Errorf("Expected no task blocked by b after update, got %d", n)
+	}
+}
+
+// TaskRecurrence 校验重复规则的保存、修改与清除
+func TaskRecurrence(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	task := &models.Task{Title: "weekly", Status: "To Do", Priority: "Medium", CreatedBy: "rec",
+		CreatedAt: now, UpdatedAt: now, Comments: []models.Comment{},
+		Recurrence: &models.Recurrence{Rule: "FREQ=WEEKLY;BYDAY=MO", TZID: "Asia/Shanghai"}}
+	plain := &models.Task{Title: "once", Status: "To Do", Priority: "Medium", CreatedBy: "rec",
+		CreatedAt: now, UpdatedAt: now, Comments: []models.Comment{}}
+	for _, x := range []*models.Task{task, plain} {
+		if err := repo.Create(ctx, x); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	got, err := repo.Get(ctx, "rec", task.ID)
+	if err != nil || got.Recurrence == nil || *got.Recurrence != *task.Recurrence {
+		t.Fatalf("Expected recurrence %+v, got %+v (%v)", task.Recurrence, got, err)
+	}
+	if got, _ := repo.Get(ctx, "rec", plain.ID); got.Recurrence != nil {
+		t.Errorf("Expected no recurrence, got %+v", got.Recurrence)
+	}
+
+	got.Recurrence = &models.Recurrence{Rule: "FREQ=DAILY;COUNT=3"}
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got, _ := repo.Get(ctx, "rec", task.ID); got.Recurrence == nil || got.Recurrence.Rule != "FREQ=DAILY;COUNT=3" || got.Recurrence.TZID != "" {
+		t.Errorf("Expected updated recurrence, got %+v", got.Recurrence)
+	}
+	got.Recurrence = nil
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got, _ := repo.Get(ctx, "rec", task.ID); got.Recurrence != nil {
+		t.Errorf("Expected recurrence to be cleared, got %+v", got.Recurrence)
+	}
+}
+
+// TaskLabels 校验任务标签的保存顺序、整体替换与按标签过滤
+func TaskLabels(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	newTask := func(title string, labels ...string) *models.Task {
+		task := &models.Task{Title: title, Status: "To Do", Priority: "Medium", CreatedBy: "lbl",
+			CreatedAt: now, UpdatedAt: now, Comments: []models.Comment{}, Labels: labels}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task
+	}
+	a := newTask("a", "工作", "Q1")
+	b := newTask("b", "Q1")
+	c := newTask("c")
+
+	got, err := repo.Get(ctx, "lbl", a.ID)
+	if err != nil || len(got.Labels) != 2 || got.Labels[0] != "工作" || got.Labels[1] != "Q1" {
+		t.Fatalf("Expected labels [工作 Q1], got %+v (%v)", got, err)
+	}
+	if got, _ := repo.Get(ctx, "lbl", c.ID); len(got.Labels) != 0 {
+		t.Errorf("Expected no labels, got %v", got.Labels)
+	}
+	list, err := repo.List(ctx, repository.TaskFilter{UserID: "lbl", Labels: []string{"Q1"}, Sort: repository.TaskSort{Field: repository.SortByTitle, Asc: true}})
+	if err != nil || len(list) != 2 || list[0].ID != a.ID || list[1].ID != b.ID {
+		t.Errorf("Expected [a b] labeled Q1, got %v (%v)", list, err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "lbl", Labels: []string{"q1"}}); n != 0 {
+		t.Errorf("Expected label filter to be case-sensitive, got %d", n)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "lbl", Labels: []string{}}); n != 0 {
+		t.Errorf("Expected no tasks for empty label list, got %d", n)
+	}
+
+	got.Labels = []string{"生活"}
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "lbl", Labels: []string{"工作", "生活"}}); n != 1 {
+		t.Errorf("Expected 1 task labeled 工作 or 生活, got %d", n)
+	}
+	if got, _ := repo.Get(ctx, "lbl", a.ID); len(got.Labels) != 1 || got.Labels[0] != "生活" {
+		t.Errorf("Expected labels [生活], got %v", got.Labels)
+	}
+}
+
+// TaskTrash 校验回收站：已删除的任务 Get 不返回，List 与 Count 按 Trash 与 DeletedTo 过滤，可恢复或永久删除
+func TaskTrash(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	var ids []string
+	for _, title := range []string{"保留", "早删", "晚删"} {
+		task := &models.Task{Title: title, CreatedBy: "trash", CreatedAt: now, UpdatedAt: now}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		ids = append(ids, task.ID)
+	}
+	for i, at := range map[int]time.Time{1: now.Add(time.Hour), 2: now.Add(48 * time.Hour)} {
+		task, err := repo.Get(ctx, "trash", ids[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		deleted := at
+		task.DeletedAt = &deleted
+		if err := repo.Update(ctx, task); err != nil {
+			t.Fatalf("Trash failed: %v", err)
+		}
+	}
+
+	if _, err := repo.Get(ctx, "trash", ids[1]); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for trashed task, got %v", err)
+	}
+	if list, _ := repo.List(ctx, repository.TaskFilter{UserID: "trash"}); len(list) != 1 || list[0].ID != ids[0] {
+		t.Errorf("Expected only the kept task by default, got %v", list)
+	}
+	trashed, err := repo.List(ctx, repository.TaskFilter{UserID: "trash", Trash: repository.OnlyTrashed, Sort: repository.TaskSort{Field: repository.SortByTitle, Asc: true}})
+	if err != nil || len(trashed) != 2 || trashed[0].DeletedAt == nil || !trashed[0].DeletedAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("Expected 2 trashed tasks with deletedAt, got %+v (%v)", trashed, err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "trash", Trash: repository.WithTrashed}); n != 3 {
+		t.Errorf("Expected 3 tasks including trash, got %d", n)
+	}
+	cutoff := now.Add(24 * time.Hour)
+	if list, _ := repo.List(ctx, repository.TaskFilter{Trash: repository.OnlyTrashed, DeletedTo: &cutoff}); len(list) != 1 || list[0].ID != ids[1] {
+		t.Errorf("Expected only the early trashed task before cutoff, got %v", list)
+	}
+
+	// 恢复即清空 DeletedAt；永久删除对回收站中的任务同样有效
+	restored := trashed[0]
+	restored.DeletedAt = nil
+	if err := repo.Update(ctx, &restored); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if got, err := repo.Get(ctx, "trash", restored.ID); err != nil || got.DeletedAt != nil {
+		t.Errorf("Expected restored task, got %+v (%v)", got, err)
+	}
+	if err := repo.Delete(ctx, "trash", ids[2]); err != nil {
+		t.Errorf("Delete trashed task failed: %v", err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "trash", Trash: repository.OnlyTrashed}); n != 0 {
+		t.Errorf("Expected empty trash, got %d", n)
+	}
+}
+
+// TaskBulkUpdate 校验批量覆盖与创建：全部写入成功，或有任务不属于该用户时返回 ErrNotFound 且不写入任何任务
+func TaskBulkUpdate(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	var tasks []models.Task
+	for _, owner := range []string{"bulk", "bulk", "other"} {
+		task := &models.Task{Title: "批量", Status: "To Do", CreatedBy: owner, CreatedAt: now, UpdatedAt: now}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	batch := []models.Task{tasks[0], tasks[1]}
+	for i := range batch {
+		batch[i].Status = "Done"
+		batch[i].Comments = []models.Comment{{ID: "c1", Text: "批量完成", CreatedBy: "bulk", CreatedAt: now}}
+	}
+	next := &models.Task{Title: "下一次", Status: "To Do", CreatedBy: "bulk", CreatedAt: now, UpdatedAt: now}
+	if err := repo.BulkUpdate(ctx, batch, next); err != nil {
+		t.Fatalf("BulkUpdate failed: %v", err)
+	}
+	if got, err := repo.Get(ctx, "bulk", next.ID); err != nil || next.Version != 1 || got.Title != "下一次" {
+		t.Errorf("Expected created task, got %+v (%v)", got, err)
+	}
+	for _, task := range batch {
+		if got, err := repo.Get(ctx, "bulk", task.ID); err != nil || got.Status != "Done" || len(got.Comments) != 1 {
+			t.Errorf("Expected bulk update to be written, got %+v (%v)", got, err)
+		}
+	}
+
+	// 冒充其他用户的任务时整批失败
+	foreign := tasks[2]
+	foreign.CreatedBy = "bulk"
+	first := batch[0]
+	first.Status = "In Progress"
+	if err := repo.BulkUpdate(ctx, []models.Task{first, foreign}, &models.Task{Title: "不应创建", CreatedBy: "bulk", CreatedAt: now, UpdatedAt: now}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "bulk"}); n != 3 {
+		t.Errorf("Expected failed batch to create nothing, got %d tasks", n)
+	}
+	if got, _ := repo.Get(ctx, "bulk", first.ID); got.Status != "Done" {
+		t.Errorf("Expected failed batch to write nothing, got status %q", got.Status)
+	}
+	if got, _ := repo.Get(ctx, "other", foreign.ID); got.CreatedBy != "other" {
+		t.Errorf("Expected other user's task to be untouched, got %+v", got)
+	}
+	if err := repo.BulkUpdate(ctx, nil); err != nil {
+		t.Errorf("Empty batch: %v", err)
+	}
+}
+
+// TaskHistoryWrite 校验历史随任务一并写入：新建的任务在生成历史时已有 ID，任务写入失败时不保存历史
+func TaskHistoryWrite(t *testing.T, repo repository.HistoryTaskRepository, history repository.HistoryRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+	task := &models.Task{Title: "历史", Status: "To Do", CreatedBy: "hist", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Create(ctx, task); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	events := func(written int) []*models.TaskEvent {
+		var out []*models.TaskEvent
+		for i := 0; i < written; i++ {
+			out = append(out, &models.TaskEvent{UserID: "hist", Actor: "hist", Action: models.ActionUpdated, CreatedAt: now, Changes: []models.FieldChange{}})
+		}
+		return out
+	}
+
+	update := *task
+	update.Status = "Done"
+	next := &models.Task{Title: "下一次", Status: "To Do", CreatedBy: "hist", CreatedAt: now, UpdatedAt: now}
+	var ids []string
+	err := repo.BulkUpdateWithHistory(ctx, []models.Task{update}, []*models.Task{next}, func(written int) []*models.TaskEvent {
+		out := events(written)
+		out[0].TaskID, out[1].TaskID = update.ID, next.ID
+		ids = []string{update.ID, next.ID}
+		return out
+	})
+	if err != nil || next.ID == "" || ids[1] != next.ID {
+		t.Fatalf("BulkUpdateWithHistory failed: %v (ids %v, next %q)", err, ids, next.ID)
+	}
+	if got, err := history.List(ctx, repository.HistoryFilter{UserID: "hist"}); err != nil || len(got) != 2 {
+		t.Errorf("Expected 2 events, got %+v (%v)", got, err)
+	}
+
+	// 版本冲突时任务与历史都不写入
+	stale := update
+	stale.Version = task.Version
+	if err := repo.BulkUpdateWithHistory(ctx, []models.Task{stale}, nil, events); !errors.Is(err, repository.ErrVersion) {
+		t.Errorf("Expected ErrVersion, got %v", err)
+	}
+	if got, _ := history.List(ctx, repository.HistoryFilter{UserID: "hist"}); len(got) != 2 {
+		t.Errorf("Expected failed write to record nothing, got %d events", len(got))
+	}
+}
+
+// ReportTrash 校验报表的回收站过滤与按 ID 查询
+func ReportTrash(t *testing.T, repo repository.ReportRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	kept := &models.Report{UserID: "trash", Type: "daily", Title: "保留", CreatedAt: now, UpdatedAt: now, StartDate: now, EndDate: now}
+	gone := &models.Report{UserID: "trash", Type: "daily", Title: "删除", CreatedAt: now.Add(time.Hour), UpdatedAt: now, StartDate: now, EndDate: now}
+	for _, rep := range []*models.Report{kept, gone} {
+		if err := repo.Create(ctx, rep); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	deleted := now.Add(2 * time.Hour)
+	gone.DeletedAt = &deleted
+	if err := repo.Update(ctx, gone); err != nil {
+		t.Fatalf("Trash failed: %v", err)
+	}
+
+	if _, err := repo.Get(ctx, "trash", gone.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for trashed report, got %v", err)
+	}
+	if list, _ := repo.List(ctx, repository.ReportFilter{UserID: "trash"}); len(list) != 1 || list[0].ID != kept.ID {
+		t.Errorf("Expected only the kept report by default, got %v", list)
+	}
+	list, err := repo.List(ctx, repository.ReportFilter{UserID: "trash", IDs: []string{gone.ID}, Trash: repository.OnlyTrashed})
+	if err != nil || len(list) != 1 || list[0].DeletedAt == nil || !list[0].DeletedAt.Equal(deleted) {
+		t.Errorf("Expected the trashed report by ID, got %+v (%v)", list, err)
+	}
+	if n, _ := repo.Count(ctx, repository.ReportFilter{UserID: "trash", Trash: repository.WithTrashed}); n != 2 {
+		t.Errorf("Expected 2 reports including trash, got %d", n)
+	}
+	if list, _ := repo.List(ctx, repository.ReportFilter{UserID: "trash", IDs: []string{}, Trash: repository.WithTrashed}); len(list) != 0 {
+		t.Errorf("Expected no reports for empty IDs, got %v", list)
+	}
+	early := now.Add(time.Hour)
+	if n, _ := repo.Count(ctx, repository.ReportFilter{Trash: repository.OnlyTrashed, DeletedTo: &early}); n != 0 {
+		t.Errorf("Expected no reports trashed before %v, got %d", early, n)
+	}
+}
+
+// TaskVersion 校验任务版本：创建为 1，写入后递增，基于旧版本的写入返回 ErrVersion 且不生效
+func TaskVersion(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	task := &models.Task{Title: "版本", Status: "To Do", CreatedBy: "version", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Create(ctx, task); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if task.Version != 1 {
+		t.Fatalf("Expected version 1 after create, got %d", task.Version)
+	}
+	stale := *task
+	task.Title = "第一次修改"
+	if err := repo.Update(ctx, task); err != nil || task.Version != 2 {
+		t.Fatalf("Update: version %d (%v)", task.Version, err)
+	}
+	if got, err := repo.Get(ctx, "version", task.ID); err != nil || got.Version != 2 {
+		t.Errorf("Expected stored version 2, got %+v (%v)", got, err)
+	}
+
+	stale.Title = "覆盖"
+	if err := repo.Update(ctx, &stale); !errors.Is(err, repository.ErrVersion) {
+		t.Errorf("Stale update: expected ErrVersion, got %v", err)
+	}
+	if stale.Version != 1 {
+		t.Errorf("Expected failed update to keep version 1, got %d", stale.Version)
+	}
+	if err := repo.BulkUpdate(ctx, []models.Task{stale}); !errors.Is(err, repository.ErrVersion) {
+		t.Errorf("Stale bulk update: expected ErrVersion, got %v", err)
+	}
+	if got, _ := repo.Get(ctx, "version", task.ID); got.Title != "第一次修改" {
+		t.Errorf("Expected stale writes to be rejected, got title %q", got.Title)
+	}
+
+	other := stale
+	other.CreatedBy = "other"
+	if err := repo.Update(ctx, &other); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Other user: expected ErrNotFound, got %v", err)
+	}
+	batch := []models.Task{*task}
+	if err := repo.BulkUpdate(ctx, batch); err != nil || batch[0].Version != 3 {
+		t.Errorf("BulkUpdate: version %d (%v)", batch[0].Version, err)
+	}
+}
+
+// ReportVersion 校验报表版本，规则与任务相同
+func ReportVersion(t *testing.T, repo repository.ReportRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	rep := &models.Report{UserID: "version", Type: "daily", Title: "版本", CreatedAt: now, UpdatedAt: now, StartDate: now, EndDate: now}
+	if err := repo.Create(ctx, rep); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if rep.Version != 1 {
+		t.Fatalf("Expected version 1 after create, got %d", rep.Version)
+	}
+	stale := *rep
+	rep.Content = "已润色"
+	if err := repo.Update(ctx, rep); err != nil || rep.Version != 2 {
+		t.Fatalf("Update: version %d (%v)", rep.Version, err)
+	}
+	stale.Content = "覆盖"
+	if err := repo.Update(ctx, &stale); !errors.Is(err, repository.ErrVersion) {
+		t.Errorf("Stale update: expected ErrVersion, got %v", err)
+	}
+	if got, err := repo.Get(ctx, "version", rep.ID); err != nil || got.Version != 2 || got.Content != "已润色" {
+		t.Errorf("Expected stale write to be rejected, got %+v (%v)", got, err)
+	}
+	stale.UserID = "other"
+	if err := repo.Update(ctx, &stale); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Other user: expected ErrNotFound, got %v", err)
+	}
+}
+
+// LabelRepository 校验标签存储，包括不区分大小写的重名检查
+func LabelRepository(t *testing.T, repo repository.LabelRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	bug := &models.Label{UserID: "u1", Name: "bug", Color: "#d73a4a", Description: "缺陷", CreatedAt: now, UpdatedAt: now}
+	if err := repo.Create(ctx, bug); err != nil || bug.ID == "" {
+		t.Fatalf("Create failed: %v", err)
+	}
+	for _, l := range []*models.Label{
+		{UserID: "u1", Name: "Docs", CreatedAt: now, UpdatedAt: now},
+		{UserID: "u2", Name: "bug", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if err := repo.Create(ctx, &models.Label{UserID: "u1", Name: "BUG", CreatedAt: now, UpdatedAt: now}); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Expected ErrConflict for duplicate name, got %v", err)
+	}
+
+	got, err := repo.Get(ctx, "u1", bug.ID)
+	if err != nil || *got != *bug {
+		t.Fatalf("Expected %+v, got %+v (%v)", bug, got, err)
+	}
+	list, err := repo.List(ctx, "u1")
+	if err != nil || len(list) != 2 || list[0].Name != "Docs" || list[1].Name != "bug" {
+		t.Errorf("Expected [Docs bug], got %v (%v)", list, err)
+	}
+
+	got.Name = "Bug"
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Changing case of own name: %v", err)
+	}
+	got.Name = "docs"
+	if err := repo.Update(ctx, got); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Expected ErrConflict when renaming onto another label, got %v", err)
+	}
+	if _, err := repo.Get(ctx, "u2", bug.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for other user, got %v", err)
+	}
+	if err := repo.Delete(ctx, "u1", bug.ID); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if _, err := repo.Get(ctx, "u1", bug.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := repo.Create(ctx, &models.Label{UserID: "u1", Name: "BUG", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Errorf("Expected name to be free after delete, got %v", err)
+	}
+}
+
+// TaskProjects 校验任务所属项目的保存、修改、清除与按项目过滤
+func TaskProjects(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	newTask := func(title string, project *string) *models.Task {
+		task := &models.Task{Title: title, Status: "To Do", Priority: "Medium", CreatedBy: "prj",
+			CreatedAt: now, UpdatedAt: now, Comments: []models.Comment{}, ProjectID: project}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task
+	}
+	p1, p2 := "p1", "p2"
+	a := newTask("a", &p1)
+	b := newTask("b", &p2)
+	c := newTask("c", nil)
+
+	got, err := repo.Get(ctx, "prj", a.ID)
+	if err != nil || got.ProjectID == nil || *got.ProjectID != "p1" {
+		t.Fatalf("Expected project p1, got %+v (%v)", got, err)
+	}
+	if got, _ := repo.Get(ctx, "prj", c.ID); got.ProjectID != nil {
+		t.Errorf("Expected no project, got %v", *got.ProjectID)
+	}
+	list, err := repo.List(ctx, repository.TaskFilter{UserID: "prj", ProjectIDs: []string{"p1", "p2"}, Sort: repository.TaskSort{Field: repository.SortByTitle, Asc: true}})
+	if err != nil || len(list) != 2 || list[0].ID != a.ID || list[1].ID != b.ID {
+		t.Errorf("Expected [a b] in p1 or p2, got %v (%v)", list, err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "prj", ProjectIDs: []string{}}); n != 0 {
+		t.Errorf("Expected no tasks for empty project list, got %d", n)
+	}
+
+	got.ProjectID = &p2
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "prj", ProjectIDs: []string{"p2"}}); n != 2 {
+		t.Errorf("Expected 2 tasks in p2 after move, got %d", n)
+	}
+	got.ProjectID = nil
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got, _ := repo.Get(ctx, "prj", a.ID); got.ProjectID != nil {
+		t.Errorf("Expected project to be cleared, got %v", *got.ProjectID)
+	}
+}
+
+// ProjectRepository 校验项目存储，包括按 Position 排序
+func ProjectRepository(t *testing.T, repo repository.ProjectRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	work := &models.Project{UserID: "u1", Name: "工作", Description: "日常工作", Position: 1, CreatedAt: now, UpdatedAt: now}
+	if err := repo.Create(ctx, work); err != nil || work.ID == "" {
+		t.Fatalf("Create failed: %v", err)
+	}
+	home := &models.Project{UserID: "u1", Name: "家务", Position: 0, CreatedAt: now.Add(time.Hour), UpdatedAt: now}
+	study := &models.Project{UserID: "u1", Name: "学习", Position: 1, CreatedAt: now.Add(2 * time.Hour), UpdatedAt: now}
+	for _, p := range []*models.Project{home, study, {UserID: "u2", Name: "工作", CreatedAt: now, UpdatedAt: now}} {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	got, err := repo.Get(ctx, "u1", work.ID)
+	if err != nil || *got != *work {
+		t.Fatalf("Expected %+v, got %+v (%v)", work, got, err)
+	}
+	list, err := repo.List(ctx, "u1")
+	if err != nil || len(list) != 3 || list[0].ID != home.ID || list[1].ID != work.ID || list[2].ID != study.ID {
+		t.Errorf("Expected [家务 工作 学习], got %v (%v)", list, err)
+	}
+
+	got.Archived = true
+	got.Position = 5
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if again, _ := repo.Get(ctx, "u1", work.ID); !again.Archived || again.Position != 5 {
+		t.Errorf("Unexpected project after update: %+v", again)
+	}
+	got.UserID = "u2"
+	if err := repo.Update(ctx, got); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when updating as other user, got %v", err)
+	}
+	if _, err := repo.Get(ctx, "u2", work.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for other user, got %v", err)
+	}
+	if err := repo.Delete(ctx, "u1", work.ID); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if _, err := repo.Get(ctx, "u1", work.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+// WorkflowRepository 校验工作流存储的增删改查、子项顺序与每个范围至多一个工作流
+func WorkflowRepository(t *testing.T, repo repository.WorkflowRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	project := "p1"
+	board := &models.Workflow{UserID: "u1", ProjectID: &project, Name: "看板", CreatedAt: now, UpdatedAt: now,
+		Statuses: []models.WorkflowStatus{
+			{Name: "Backlog", Category: models.CategoryOpen},
+			{Name: "Doing", Category: models.CategoryActive, WIPLimit: 2},
+			{Name: "Shipped", Category: models.CategoryDone},
+		},
+		Transitions: []models.WorkflowTransition{{From: "Backlog", To: "Doing"}, {From: "Doing", To: "Shipped"}},
+		Priorities:  []string{"P3", "P2", "P1"},
+	}
+	if err := repo.Create(ctx, board); err != nil || board.ID == "" {
+		t.Fatalf("Create failed: %v", err)
+	}
+	def := &models.Workflow{UserID: "u1", Name: "默认", CreatedAt: now.Add(time.Hour), UpdatedAt: now,
+		Statuses:    []models.WorkflowStatus{{Name: "Open", Category: models.CategoryOpen}, {Name: "Closed", Category: models.CategoryDone}},
+		Transitions: []models.WorkflowTransition{}, Priorities: []string{"Normal"}}
+	for _, w := range []*models.Workflow{def, {UserID: "u2", Name: "默认", CreatedAt: now, UpdatedAt: now}} {
+		if err := repo.Create(ctx, w); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	for _, w := range []*models.Workflow{
+		{UserID: "u1", Name: "重复默认", CreatedAt: now, UpdatedAt: now},
+		{UserID: "u1", ProjectID: &project, Name: "重复项目", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := repo.Create(ctx, w); !errors.Is(err, repository.ErrConflict) {
+			t.Errorf("Expected ErrConflict for %s, got %v", w.Name, err)
+		}
+	}
+
+	got, err := repo.Get(ctx, "u1", board.ID)
+	if err != nil || got.Name != "看板" || got.ProjectID == nil || *got.ProjectID != project ||
+		fmt.Sprint(got.Statuses) != fmt.Sprint(board.Statuses) || fmt.Sprint(got.Transitions) != fmt.Sprint(board.Transitions) ||
+		fmt.Sprint(got.Priorities) != fmt.Sprint(board.Priorities) || !got.CreatedAt.Equal(now) {
+		t.Fatalf("Expected %+v, got %+v (%v)", board, got, err)
+	}
+	list, err := repo.List(ctx, "u1")
+	if err != nil || len(list) != 2 || list[0].ID != def.ID || list[1].ID != board.ID {
+		t.Errorf("Expected [默认 看板], got %v (%v)", list, err)
+	}
+
+	got.Statuses = got.Statuses[1:]
+	got.Transitions = nil
+	got.ProjectID = nil
+	if err := repo.Update(ctx, got); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Expected ErrConflict when moving onto the default scope, got %v", err)
+	}
+	other := "p2"
+	got.ProjectID = &other
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if again, _ := repo.Get(ctx, "u1", board.ID); len(again.Statuses) != 2 || again.Statuses[0].WIPLimit != 2 || len(again.Transitions) != 0 || *again.ProjectID != other {
+		t.Errorf("Unexpected workflow after update: %+v", again)
+	}
+	got.UserID = "u2"
+	if err := repo.Update(ctx, got); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when updating as other user, got %v", err)
+	}
+	if _, err := repo.Get(ctx, "u2", board.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for other user, got %v", err)
+	}
+	if err := repo.Delete(ctx, "u1", board.ID); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if _, err := repo.Get(ctx, "u1", board.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+// HistoryRepository 校验任务历史的写入、按任务与时间过滤及排序
+func HistoryRepository(t *testing.T, repo repository.HistoryRepository) {
+	ctx := context.Background()
+	base := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+	events := []*models.TaskEvent{
+		{UserID: "u1", TaskID: "t1", Actor: "u1", Action: models.ActionCreated, CreatedAt: base,
+			Changes: []models.FieldChange{{Field: "title", New: str("写周报")}, {Field: "status", New: str("To Do")}}},
+		{UserID: "u1", TaskID: "t2", Actor: "u1", Action: models.ActionCreated, CreatedAt: base.Add(time.Minute),
+			Changes: []models.FieldChange{{Field: "title", New: str("整理文档")}}},
+		{UserID: "u1", TaskID: "t1", Actor: "u3", Action: models.ActionCommented, CreatedAt: base.Add(3 * time.Hour),
+			Changes: []models.FieldChange{{Field: "comment", ItemID: "c1", New: str("已完成初稿")}}},
+		{UserID: "u1", TaskID: "t1", Actor: "u1", Action: models.ActionUpdated, CreatedAt: base.Add(2 * time.Hour),
+			Changes: []models.FieldChange{{Field: "status", Old: str("To Do"), New: str("Done")}}},
+		{UserID: "u2", TaskID: "t9", Actor: "u2", Action: models.ActionDeleted, CreatedAt: base, Changes: []models.FieldChange{}},
+	}
+	for _, e := range events {
+		if err := repo.Create(ctx, e); err != nil || e.ID == "" {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	got, err := repo.List(ctx, repository.HistoryFilter{UserID: "u1", TaskIDs: []string{"t1"}})
+	if err != nil || len(got) != 3 {
+		t.Fatalf("Expected 3 events for t1, got %d (%v)", len(got), err)
+	}
+	if got[0].Action != models.ActionCreated || got[1].Action != models.ActionUpdated || got[2].Action != models.ActionCommented {
+		t.Errorf("Expected events ordered by time, got %s %s %s", got[0].Action, got[1].Action, got[2].Action)
+	}
+	if c := got[0].Changes; len(c) != 2 || c[0].Field != "title" || c[0].Old != nil || *c[0].New != "写周报" || !got[0].CreatedAt.Equal(base) {
+		t.Errorf("Unexpected created event %+v", got[0])
+	}
+	if c := got[1].Changes[0]; c.Old == nil || *c.Old != "To Do" || *c.New != "Done" {
+		t.Errorf("Unexpected status change %+v", c)
+	}
+	if c := got[2].Changes[0]; got[2].Actor != "u3" || c.ItemID != "c1" {
+		t.Errorf("Unexpected comment event %+v", got[2])
+	}
+
+	from, to := base.Add(time.Minute), base.Add(2*time.Hour)
+	ranged, err := repo.List(ctx, repository.HistoryFilter{UserID: "u1", From: &from, To: &to})
+	if err != nil || len(ranged) != 2 || ranged[0].TaskID != "t2" || ranged[1].Action != models.ActionUpdated {
+		t.Errorf("Expected 2 events in range, got %+v (%v)", ranged, err)
+	}
+	if other, _ := repo.List(ctx, repository.HistoryFilter{UserID: "u2"}); len(other) != 1 || other[0].Changes == nil {
+		t.Errorf("Expected 1 event with empty changes for u2, got %+v", other)
+	}
+	if none, err := repo.List(ctx, repository.HistoryFilter{UserID: "u1", TaskIDs: []string{"t9"}}); err != nil || len(none) != 0 {
+		t.Errorf("Expected no events for other user's task, got %v (%v)", none, err)
+	}
+}
+
+// TaskQueryLanguage 校验查询语言条件的结果与 query.Eval 一致，包括空值与取反
+func TaskQueryLanguage(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	day := func(n int) *time.Time {
+		v := time.Date(2024, 6, 10+n, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	alice, project := "alice", "p1"
+	fixtures := []models.Task{
+		{Title: "写周报", Description: "整理 Weekly 进展", Status: "In Progress", Priority: "High", Assignee: &alice, Deadline: day(2), Labels: []string{"工作", "Q1"}, ProjectID: &project},
+		{Title: "买菜", Description: "100%_有机", Status: "To Do", Priority: "Low", Deadline: day(-1), Labels: []string{}},
+		{Title: "Review PR", Status: "Done", Priority: "Medium", ScheduledDate: day(0)},
+		{Title: "周会纪要", Description: "weekly sync", Status: "To Do", Priority: "High", Deadline: day(10)},
+	}
+	var all []models.Task
+	for i := range fixtures {
+		task := fixtures[i]
+		task.CreatedBy = "ql"
+		task.CreatedAt = now.Add(-time.Duration(i) * 24 * time.Hour)
+		task.UpdatedAt = task.CreatedAt
+		task.Comments = []models.Comment{}
+		if err := repo.Create(ctx, &task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		all = append(all, task)
+	}
+
+	for _, q := range []string{
+		`weekly`,
+		`周`,
+		`"100%_"`,
+		`status:todo`,
+		`status!=done`,
+		`priority>=medium -status:done`,
+		`due<7d`,
+		`-due<7d`,
+		`due:none OR scheduled:today`,
+		`assignee:none`,
+		`-assignee:alice`,
+		`created>=-1d`,
+		`priority<low`,
+		`(title:周 OR title:review) AND NOT due>today`,
+		`label:Q1`,
+		`tag:none`,
+		`-label:工作 status:todo`,
+		`project:p1`,
+		`project:none`,
+		`-project:p1`,
+	} {
+		cond, err := query.ParseAndCompile(q, now)
+		if err != nil {
+			t.Fatalf("ParseAndCompile(%q) failed: %v", q, err)
+		}
+		want := map[string]bool{}
+		for i := range all {
+			if query.Eval(cond, &all[i]) {
+				want[all[i].ID] = true
+			}
+		}
+		list, err := repo.List(ctx, repository.TaskFilter{UserID: "ql", Query: cond})
+		if err != nil {
+			t.Fatalf("%s: List failed: %v", q, err)
+		}
+		got := map[string]bool{}
+		for _, task := range list {
+			got[task.ID] = true
+		}
+		if len(got) != len(want) {
+			t.Errorf("%s: expected %d tasks, got %d", q, len(want), len(got))
+		}
+		for id := range want {
+			if !got[id] {
+				t.Errorf("%s: missing task %s", q, id)
+			}
+		}
+		if n, err := repo.Count(ctx, repository.TaskFilter{UserID: "ql", Query: cond}); err != nil || n != int64(len(want)) {
+			t.Errorf("%s: expected count %d, got %d (%v)", q, len(want), n, err)
+		}
+	}
+}
+
+// ReportRepository 校验报表存储，包括关联任务的顺序
+func ReportRepository(t *testing.T, repo repository.ReportRepository) {
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	projectID := "p1"
+	rep := &models.Report{
+		UserID:     "u1",
+		Type:       "weekly",
+		Period:     "2024-W01",
+		Title:      "周报",
+		Content:    "content",
+		Tasks:      []string{"c", "a", "b"},
+		Statistics: models.Statistics{TotalTasks: 3, CompletedTasks: 1, CompletionRate: 33},
+		LabelStatistics: []models.LabelStatistics{
+			{Label: "工作", Statistics: models.Statistics{TotalTasks: 2, CompletedTasks: 1, CompletionRate: 50}},
+			{Label: "", Statistics: models.Statistics{TotalTasks: 1, InProgressTasks: 1}},
+		},
+		ProjectID: &projectID,
+		StartDate: base,
+		EndDate:   base.Add(7 * 24 * time.Hour),
+		CreatedAt: base,
+		UpdatedAt: base,
+	}
+	if err := repo.Create(ctx, rep); err != nil || rep.ID == "" {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Report{UserID: "u1", Type: "daily", CreatedAt: base.Add(time.Hour)}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	got, err := repo.Get(ctx, "u1", rep.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Title != "周报" || got.Statistics != rep.Statistics || !got.EndDate.Equal(rep.EndDate) || got.PolishedContent != nil {
+		t.Errorf("Unexpected report: %+v", got)
+	}
+	if got.ProjectID == nil || *got.ProjectID != "p1" {
+		t.Errorf("Expected project p1, got %v", got.ProjectID)
+	}
+	if len(got.Tasks) != 3 || got.Tasks[0] != "c" || got.Tasks[1] != "a" || got.Tasks[2] != "b" {
+		t.Errorf("Expected task order [c a b], got %v", got.Tasks)
+	}
+	if len(got.LabelStatistics) != 2 || got.LabelStatistics[0] != rep.LabelStatistics[0] || got.LabelStatistics[1] != rep.LabelStatistics[1] {
+		t.Errorf("Expected label statistics %v, got %v", rep.LabelStatistics, got.LabelStatistics)
+	}
+
+	polished := "polished"
+	got.PolishedContent = &polished
+	got.Tasks = []string{"a"}
+	got.LabelStatistics = nil
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	again, _ := repo.Get(ctx, "u1", rep.ID)
+	if again.PolishedContent == nil || *again.PolishedContent != "polished" || len(again.Tasks) != 1 || len(again.LabelStatistics) != 0 {
+		t.Errorf("Unexpected report after update: %+v", again)
+	}
+
+	list, _ := repo.List(ctx, repository.ReportFilter{UserID: "u1"})
+	if len(list) != 2 || list[0].Type != "daily" {
+		t.Errorf("Expected 2 reports newest first, got %v", list)
+	}
+	if n, _ := repo.Count(ctx, repository.ReportFilter{UserID: "u1", Type: "weekly"}); n != 1 {
+		t.Errorf("Expected 1 weekly report, got %d", n)
+	}
+	if list, _ := repo.List(ctx, repository.ReportFilter{UserID: "u1", ProjectID: "p1"}); len(list) != 1 || list[0].ID != rep.ID {
+		t.Errorf("Expected only the p1 report, got %v", list)
+	}
+	if _, err := repo.Get(ctx, "u2", rep.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for other user, got %v", err)
+	}
+	if err := repo.Delete(ctx, "u1", rep.ID); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if _, err := repo.Get(ctx, "u1", rep.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+// SearchRepository 校验索引词的写入、替换、补建、删除与按用户统计
+func SearchRepository(t *testing.T, repo repository.SearchRepository) {
+	ctx := context.Background()
+	put := func(doc *repository.SearchDoc) {
+		t.Helper()
+		if err := repo.Put(ctx, doc); err != nil {
+			t.Fatalf("Put %s failed: %v", doc.ID, err)
+		}
+	}
+	put(&repository.SearchDoc{Kind: "task", ID: "t1", UserID: "u1", Terms: map[string]float64{"项目": 3, "文档": 4}, Length: 7})
+	put(&repository.SearchDoc{Kind: "report", ID: "t1", UserID: "u1", Terms: map[string]float64{"文档": 1}, Length: 2})
+	put(&repository.SearchDoc{Kind: "task", ID: "t2", UserID: "u2", Terms: map[string]float64{"文档": 1}, Length: 1})
+
+	postings := func(userID string, terms ...string) map[string]repository.SearchPosting {
+		t.Helper()
+		list, _, err := repo.Postings(ctx, userID, terms)
+		if err != nil {
+			t.Fatalf("Postings failed: %v", err)
+		}
+		out := make(map[string]repository.SearchPosting, len(list))
+		for _, p := range list {
+			out[p.Kind+"/"+p.ID+"/"+p.Term] = p
+		}
+		return out
+	}
+	got := postings("u1", "文档", "项目", "排期")
+	if len(got) != 3 || got["task/t1/文档"].TF != 4 || got["task/t1/项目"].Length != 7 || got["report/t1/文档"].TF != 1 {
+		t.Errorf("Unexpected postings for u1: %+v", got)
+	}
+	if _, stats, _ := repo.Postings(ctx, "u1", []string{"文档"}); stats.Docs != 2 || stats.TotalLength != 9 {
+		t.Errorf("Expected 2 documents of total length 9, got %+v", stats)
+	}
+
+	// Put 替换全部索引词，Insert 不覆盖已索引的文档
+	put(&repository.SearchDoc{Kind: "task", ID: "t1", UserID: "u1", Terms: map[string]float64{"排期": 3}, Length: 3})
+	if err := repo.Insert(ctx, &repository.SearchDoc{Kind: "task", ID: "t1", UserID: "u1", Terms: map[string]float64{"文档": 1}, Length: 1}); err != nil {
+		t.Fatalf("Insert indexed document failed: %v", err)
+	}
+	if err := repo.Insert(ctx, &repository.SearchDoc{Kind: "task", ID: "t3", UserID: "u1", Terms: map[string]float64{"排期": 1}, Length: 1}); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if got := postings("u1", "文档", "项目", "排期"); len(got) != 3 || got["task/t1/排期"].TF != 3 || got["task/t3/排期"].TF != 1 {
+		t.Errorf("Unexpected postings after Put and Insert: %+v", got)
+	}
+
+	if err := repo.Remove(ctx, "task", "t1"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := repo.Remove(ctx, "task", "missing"); err != nil {
+		t.Errorf("Remove missing document failed: %v", err)
+	}
+	if got := postings("u1", "排期"); len(got) != 1 || got["task/t3/排期"].TF != 1 {
+		t.Errorf("Unexpected postings after Remove: %+v", got)
+	}
+	if _, stats, _ := repo.Postings(ctx, "u1", []string{"排期"}); stats.Docs != 2 || stats.TotalLength != 3 {
+		t.Errorf("Expected 2 documents of total length 3 after Remove, got %+v", stats)
+	}
+	if got := postings("u3", "文档"); len(got) != 0 {
+		t.Errorf("Expected no postings for unknown user, got %+v", got)
+	}
+}
+
+// UserRepository 校验用户存储的查找逻辑
+func UserRepository(t *testing.T, repo repository.UserRepository) {
+	ctx := context.Background()
+	user := &models.User{Username: "alice", Email: "Alice@Example.com", Password: "hash", CreatedAt: time.Now()}
+	if err := repo.Create(ctx, user); err != nil || user.ID == "" {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if u, err := repo.GetByEmail(ctx, "ALICE@example.com"); err != nil || u.ID != user.ID || u.Password != "hash" {
+		t.Errorf("GetByEmail failed: %+v %v", u, err)
+	}
+	if u, err := repo.GetByID(ctx, user.ID); err != nil || u.Username != "alice" {
+		t.Errorf("GetByID failed: %+v %v", u, err)
+	}
+	if _, err := repo.FindByEmailOrUsername(ctx, "bob@example.com", "alice"); err != nil {
+		t.Errorf("Expected username match, got %v", err)
+	}
+	if _, err := repo.FindByEmailOrUsername(ctx, "bob@example.com", "bob"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
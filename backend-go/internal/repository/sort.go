@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+)
+
+// ErrInvalidCursor 游标无法解析，或与当前排序方式不匹配
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TaskSortField 任务排序字段，取值与 JSON 字段名一致
+type TaskSortField string
+
+const (
+	SortByCreatedAt     TaskSortField = "createdAt"
+	SortByUpdatedAt     TaskSortField = "updatedAt"
+	SortByDeadline      TaskSortField = "deadline"
+	SortByScheduledDate TaskSortField = "scheduledDate"
+	SortByTitle         TaskSortField = "title"
+)
+
+var taskSortFields = map[TaskSortField]bool{
+	SortByCreatedAt: true, SortByUpdatedAt: true, SortByDeadline: true, SortByScheduledDate: true, SortByTitle: true,
+}
+
+// TaskSort 任务排序方式，零值为按创建时间倒序。
+// 排序值相同时按 ID 同向排序；空值（未设置的截止/计划日期）视为最小值，
+// 即升序时排在最前、降序时排在最后，各存储实现都遵循这一约定。
+type TaskSort struct {
+	Field TaskSortField
+	Asc   bool
+}
+
+// ParseTaskSort 解析 "deadline"、"-createdAt" 形式的排序参数，"-" 前缀表示降序；空串为默认排序
+func ParseTaskSort(s string) (TaskSort, error) {
+	if s == "" {
+		return TaskSort{Field: SortByCreatedAt}, nil
+	}
+	sort := TaskSort{Field: TaskSortField(strings.TrimPrefix(s, "-")), Asc: !strings.HasPrefix(s, "-")}
+	if !taskSortFields[sort.Field] {
+		return TaskSort{}, errors.New("unknown sort field " + string(sort.Field))
+	}
+	return sort, nil
+}
+
+// String 返回 ParseTaskSort 可解析的形式
+func (s TaskSort) String() string {
+	field := s.Key()
+	if s.Asc {
+		return string(field)
+	}
+	return "-" + string(field)
+}
+
+// Key 返回排序字段，零值时为 createdAt
+func (s TaskSort) Key() TaskSortField {
+	if s.Field == "" {
+		return SortByCreatedAt
+	}
+	return s.Field
+}
+
+// Nullable 排序字段是否可能为空
+func (s TaskSort) Nullable() bool {
+	f := s.Key()
+	return f == SortByDeadline || f == SortByScheduledDate
+}
+
+// Value 返回任务在排序字段上的取值：title 为 string，时间字段为 UTC time.Time，空值为 nil
+func (s TaskSort) Value(t *models.Task) interface{} {
+	switch s.Key() {
+	case SortByUpdatedAt:
+		return t.UpdatedAt.UTC()
+	case SortByDeadline:
+		return timeValue(t.Deadline)
+	case SortByScheduledDate:
+		return timeValue(t.ScheduledDate)
+	case SortByTitle:
+		return t.Title
+	default:
+		return t.CreatedAt.UTC()
+	}
+}
+
+func timeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// Less 判断任务 a 是否排在 b 之前，供不支持排序查询的存储实现使用
+func (s TaskSort) Less(a, b *models.Task) bool {
+	return s.compare(s.Value(a), a.ID, s.Value(b), b.ID) < 0
+}
+
+// After 判断任务是否排在游标之后
+func (s TaskSort) After(t *models.Task, c *TaskCursor) bool {
+	return s.compare(c.Value, c.ID, s.Value(t), t.ID) < 0
+}
+
+// compare 按排序方向比较 (value, id)，返回负数表示前者排在前面
+func (s TaskSort) compare(va interface{}, ida string, vb interface{}, idb string) int {
+	c := compareValues(va, vb)
+	if c == 0 {
+		c = strings.Compare(ida, idb)
+	}
+	if !s.Asc {
+		c = -c
+	}
+	return c
+}
+
+// compareValues 升序比较两个排序值，nil 最小
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	switch av := a.(type) {
+	case time.Time:
+		return av.Compare(b.(time.Time))
+	case string:
+		return strings.Compare(av, b.(string))
+	}
+	return 0
+}
+
+// TaskCursor 指向某条任务的分页游标，List 只返回排在它之后的任务
+type TaskCursor struct {
+	Value interface{} // 与 TaskSort.Value 的取值类型一致
+	ID    string
+}
+
+// Cursor 返回指向任务 t 的游标
+func (s TaskSort) Cursor(t *models.Task) *TaskCursor {
+	return &TaskCursor{Value: s.Value(t), ID: t.ID}
+}
+
+type cursorToken struct {
+	Sort  string  `json:"s"`
+	Value *string `json:"v"`
+	ID    string  `json:"id"`
+}
+
+// EncodeCursor 将游标编码为不透明字符串，其中记录了排序方式
+func (s TaskSort) EncodeCursor(c *TaskCursor) string {
+	tok := cursorToken{Sort: s.String(), ID: c.ID}
+	switch v := c.Value.(type) {
+	case time.Time:
+		str := v.Format(time.RFC3339Nano)
+		tok.Value = &str
+	case string:
+		tok.Value = &v
+	}
+	data, _ := json.Marshal(tok)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor 解析 EncodeCursor 生成的游标，排序方式不一致时返回 ErrInvalidCursor
+func (s TaskSort) DecodeCursor(token string) (*TaskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var tok cursorToken
+	if err := json.Unmarshal(data, &tok); err != nil || tok.Sort != s.String() || tok.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	c := &TaskCursor{ID: tok.ID}
+	switch {
+	case tok.Value == nil:
+		if !s.Nullable() {
+			return nil, ErrInvalidCursor
+		}
+	case s.Key() == SortByTitle:
+		c.Value = *tok.Value
+	default:
+		t, err := time.Parse(time.RFC3339Nano, *tok.Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		c.Value = t.UTC()
+	}
+	return c, nil
+}
+
+// ListTaskPage 查询一页任务；filter.Limit > 0 且后面还有数据时，返回指向本页最后一条的游标
+func ListTaskPage(ctx context.Context, repo TaskRepository, filter TaskFilter) ([]models.Task, *TaskCursor, error) {
+	if filter.Limit <= 0 {
+		tasks, err := repo.List(ctx, filter)
+		return tasks, nil, err
+	}
+	limit := filter.Limit
+	filter.Limit++
+	tasks, err := repo.List(ctx, filter)
+	if err != nil || len(tasks) <= limit {
+		return tasks, nil, err
+	}
+	tasks = tasks[:limit]
+	return tasks, filter.Sort.Cursor(&tasks[limit-1]), nil
+}
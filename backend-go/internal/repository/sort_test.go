@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+)
+
+func TestParseTaskSort(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    TaskSort
+		wantErr bool
+	}{
+		{"", TaskSort{Field: SortByCreatedAt}, false},
+		{"deadline", TaskSort{Field: SortByDeadline, Asc: true}, false},
+		{"-updatedAt", TaskSort{Field: SortByUpdatedAt}, false},
+		{"priority", TaskSort{}, true},
+		{"-", TaskSort{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTaskSort(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseTaskSort(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+	if s := (TaskSort{}).String(); s != "-createdAt" {
+		t.Errorf("Expected zero sort to be -createdAt, got %q", s)
+	}
+}
+
+func TestTaskCursor(t *testing.T) {
+	deadline := time.Date(2024, 5, 1, 8, 30, 0, 123456789, time.FixedZone("CST", 8*3600))
+	task := &models.Task{ID: "t1", Title: "x", Deadline: &deadline}
+
+	byDeadline := TaskSort{Field: SortByDeadline, Asc: true}
+	c, err := byDeadline.DecodeCursor(byDeadline.EncodeCursor(byDeadline.Cursor(task)))
+	if err != nil || c.ID != "t1" || !c.Value.(time.Time).Equal(deadline) {
+		t.Errorf("Unexpected cursor %+v (%v)", c, err)
+	}
+
+	// 空值游标只对可为空的字段有效
+	c, err = byDeadline.DecodeCursor(byDeadline.EncodeCursor(&TaskCursor{ID: "t2"}))
+	if err != nil || c.Value != nil {
+		t.Errorf("Expected null cursor value, got %+v (%v)", c, err)
+	}
+	byTitle := TaskSort{Field: SortByTitle}
+	c, err = byTitle.DecodeCursor(byTitle.EncodeCursor(byTitle.Cursor(task)))
+	if err != nil || c.Value != "x" {
+		t.Errorf("Unexpected title cursor %+v (%v)", c, err)
+	}
+
+	for _, token := range []string{"", "!!", byTitle.EncodeCursor(byTitle.Cursor(task)), TaskSort{}.EncodeCursor(&TaskCursor{ID: "t1"})} {
+		if _, err := byDeadline.DecodeCursor(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", token, err)
+		}
+	}
+}
+
+func TestTaskSortLess(t *testing.T) {
+	d1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := &models.Task{ID: "a", Deadline: &d1}
+	b := &models.Task{ID: "b"}
+	asc := TaskSort{Field: SortByDeadline, Asc: true}
+	if !asc.Less(b, a) || asc.Less(a, b) {
+		t.Errorf("Expected null deadline first when ascending")
+	}
+	desc := TaskSort{Field: SortByDeadline}
+	if !desc.Less(a, b) {
+		t.Errorf("Expected null deadline last when descending")
+	}
+	if !desc.After(b, desc.Cursor(a)) || desc.After(a, desc.Cursor(b)) {
+		t.Errorf("Unexpected After result")
+	}
+}
@@ -0,0 +1,123 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// HistoryRepository task_events 表与 task_event_changes 子表
+type HistoryRepository struct {
+	db *DB
+}
+
+// NewHistoryRepository 创建任务历史存储
+func NewHistoryRepository(db *DB) *HistoryRepository {
+	return &HistoryRepository{db: db}
+}
+
+var _ repository.HistoryRepository = (*HistoryRepository)(nil)
+
+// Create 保存一条历史记录及其字段变化并回填 ID
+func (r *HistoryRepository) Create(ctx context.Context, event *models.TaskEvent) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		return r.db.insertEvent(ctx, tx, event)
+	})
+}
+
+// insertEvent 在事务中保存一条历史记录及其字段变化并回填 ID，事务回滚时 ID 不可用
+func (db *DB) insertEvent(ctx context.Context, tx *sql.Tx, event *models.TaskEvent) error {
+	id := repository.NewID()
+	_, err := tx.ExecContext(ctx, db.rebind("INSERT INTO task_events (id, user_id, task_id, actor, action, created_at) VALUES (?, ?, ?, ?, ?, ?)"),
+		id, event.UserID, event.TaskID, event.Actor, event.Action, utc(event.CreatedAt))
+	if err != nil {
+		return err
+	}
+	for i, c := range event.Changes {
+		_, err := tx.ExecContext(ctx, db.rebind(`INSERT INTO task_event_changes (event_id, position, field, item_id, old_value, new_value)
+			VALUES (?, ?, ?, ?, ?, ?)`),
+			id, i, c.Field, c.ItemID, nullString(c.Old), nullString(c.New))
+		if err != nil {
+			return err
+		}
+	}
+	event.ID = id
+	return nil
+}
+
+// List 按条件查询历史记录，按时间正序
+func (r *HistoryRepository) List(ctx context.Context, filter repository.HistoryFilter) ([]models.TaskEvent, error) {
+	var conds []string
+	var args []interface{}
+	if filter.UserID != "" {
+		conds = append(conds, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if len(filter.TaskIDs) > 0 {
+		conds = append(conds, "task_id IN ("+placeholders(len(filter.TaskIDs))+")")
+		for _, id := range filter.TaskIDs {
+			args = append(args, id)
+		}
+	}
+	if filter.From != nil {
+		conds = append(conds, "created_at >= ?")
+		args = append(args, utc(*filter.From))
+	}
+	if filter.To != nil {
+		conds = append(conds, "created_at <= ?")
+		args = append(args, utc(*filter.To))
+	}
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT id, user_id, task_id, actor, action, created_at FROM task_events "+
+		where(conds)+" ORDER BY created_at, id"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := []models.TaskEvent{}
+	for rows.Next() {
+		var e models.TaskEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.TaskID, &e.Actor, &e.Action, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.CreatedAt = e.CreatedAt.UTC()
+		e.Changes = []models.FieldChange{}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return events, nil
+	}
+	return events, r.loadChanges(ctx, events)
+}
+
+// loadChanges 按 position 顺序填充历史记录的字段变化
+func (r *HistoryRepository) loadChanges(ctx context.Context, events []models.TaskEvent) error {
+	index := make(map[string]int, len(events))
+	ids := make([]interface{}, 0, len(events))
+	for i, e := range events {
+		index[e.ID] = i
+		ids = append(ids, e.ID)
+	}
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT event_id, field, item_id, old_value, new_value FROM task_event_changes WHERE event_id IN ("+
+		placeholders(len(ids))+") ORDER BY event_id, position"), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var eventID string
+		var c models.FieldChange
+		var oldValue, newValue sql.NullString
+		if err := rows.Scan(&eventID, &c.Field, &c.ItemID, &oldValue, &newValue); err != nil {
+			return err
+		}
+		c.Old, c.New = stringPtr(oldValue), stringPtr(newValue)
+		i := index[eventID]
+		events[i].Changes = append(events[i].Changes, c)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,122 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+const labelColumns = "id, user_id, name, color, description, created_at, updated_at"
+
+// LabelRepository labels 表
+type LabelRepository struct {
+	db *DB
+}
+
+// NewLabelRepository 创建标签存储
+func NewLabelRepository(db *DB) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+var _ repository.LabelRepository = (*LabelRepository)(nil)
+
+// taken 判断同一用户下是否已有同名的其他标签；唯一索引兜底并发写入
+func (r *LabelRepository) taken(ctx context.Context, tx *sql.Tx, label *models.Label) error {
+	var n int
+	err := tx.QueryRowContext(ctx, r.db.rebind("SELECT COUNT(*) FROM labels WHERE user_id = ? AND name_key = ? AND id <> ?"),
+		label.UserID, strings.ToLower(label.Name), label.ID).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+// Create 保存新标签并回填 ID
+func (r *LabelRepository) Create(ctx context.Context, label *models.Label) error {
+	id := repository.NewID()
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if err := r.taken(ctx, tx, label); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, r.db.rebind(`INSERT INTO labels (id, user_id, name, name_key, color, description, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+			id, label.UserID, label.Name, strings.ToLower(label.Name), label.Color, label.Description, utc(label.CreatedAt), utc(label.UpdatedAt))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	label.ID = id
+	return nil
+}
+
+// Get 获取属于 userID 的标签
+func (r *LabelRepository) Get(ctx context.Context, userID, id string) (*models.Label, error) {
+	labels, err := r.query(ctx, "WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &labels[0], nil
+}
+
+// List 返回用户的全部标签，按名称升序
+func (r *LabelRepository) List(ctx context.Context, userID string) ([]models.Label, error) {
+	order := " ORDER BY name"
+	if r.db.dialect == postgres {
+		order = ` ORDER BY name COLLATE "C"`
+	}
+	return r.query(ctx, "WHERE user_id = ?"+order, userID)
+}
+
+// Update 按 ID 与 UserID 整体覆盖标签
+func (r *LabelRepository) Update(ctx context.Context, label *models.Label) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if err := r.taken(ctx, tx, label); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, r.db.rebind(`UPDATE labels SET name = ?, name_key = ?, color = ?, description = ?,
+			created_at = ?, updated_at = ? WHERE id = ? AND user_id = ?`),
+			label.Name, strings.ToLower(label.Name), label.Color, label.Description,
+			utc(label.CreatedAt), utc(label.UpdatedAt), label.ID, label.UserID)
+		if err != nil {
+			return err
+		}
+		return affected(res)
+	})
+}
+
+// Delete 删除属于 userID 的标签
+func (r *LabelRepository) Delete(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, r.db.rebind("DELETE FROM labels WHERE id = ? AND user_id = ?"), id, userID)
+	if err != nil {
+		return err
+	}
+	return affected(res)
+}
+
+func (r *LabelRepository) query(ctx context.Context, clause string, args ...interface{}) ([]models.Label, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT "+labelColumns+" FROM labels "+clause), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	labels := []models.Label{}
+	for rows.Next() {
+		var l models.Label
+		if err := rows.Scan(&l.ID, &l.UserID, &l.Name, &l.Color, &l.Description, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, err
+		}
+		l.CreatedAt, l.UpdatedAt = l.CreatedAt.UTC(), l.UpdatedAt.UTC()
+		labels = append(labels, l)
+	}
+	return labels, rows.Err()
+}
@@ -0,0 +1,273 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// migrations 按版本顺序执行的建表语句，已发布的版本不可修改，只能追加。
+// {{time}} 按方言替换为时间列类型
+var migrations = []string{
+	// 1: 初始表结构
+	`CREATE TABLE users (
+		id TEXT PRIMARY KEY,
+		username TEXT NOT NULL UNIQUE,
+		email TEXT NOT NULL UNIQUE,
+		password TEXT NOT NULL,
+		created_at {{time}} NOT NULL
+	);
+	CREATE TABLE tasks (
+		id TEXT PRIMARY KEY,
+		created_by TEXT NOT NULL,
+		title TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT '',
+		priority TEXT NOT NULL DEFAULT '',
+		assignee TEXT,
+		deadline {{time}},
+		scheduled_date {{time}},
+		created_at {{time}} NOT NULL,
+		updated_at {{time}} NOT NULL
+	);
+	CREATE INDEX idx_tasks_created_by_created_at ON tasks (created_by, created_at);
+	CREATE TABLE task_comments (
+		task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		text TEXT NOT NULL,
+		created_by TEXT NOT NULL,
+		created_at {{time}} NOT NULL,
+		PRIMARY KEY (task_id, position)
+	);
+	CREATE TABLE reports (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		type TEXT NOT NULL,
+		period TEXT NOT NULL DEFAULT '',
+		title TEXT NOT NULL DEFAULT '',
+		content TEXT NOT NULL DEFAULT '',
+		polished_content TEXT,
+		total_tasks INTEGER NOT NULL DEFAULT 0,
+		completed_tasks INTEGER NOT NULL DEFAULT 0,
+		in_progress_tasks INTEGER NOT NULL DEFAULT 0,
+		overdue_tasks INTEGER NOT NULL DEFAULT 0,
+		completion_rate INTEGER NOT NULL DEFAULT 0,
+		start_date {{time}} NOT NULL,
+		end_date {{time}} NOT NULL,
+		created_at {{time}} NOT NULL,
+		updated_at {{time}} NOT NULL
+	);
+	CREATE INDEX idx_reports_user_id_created_at ON reports (user_id, created_at);
+	CREATE TABLE report_tasks (
+		report_id TEXT NOT NULL REFERENCES reports (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		task_id TEXT NOT NULL,
+		PRIMARY KEY (report_id, position)
+	);`,
+	// 2: 任务列表的排序与日期范围过滤
+	`CREATE INDEX idx_tasks_created_by_updated_at ON tasks (created_by, updated_at);
+	CREATE INDEX idx_tasks_created_by_deadline ON tasks (created_by, deadline);
+	CREATE INDEX idx_tasks_created_by_scheduled_date ON tasks (created_by, scheduled_date);`,
+	// 3: 子任务与检查项
+	`ALTER TABLE tasks ADD COLUMN parent_id TEXT;
+	CREATE INDEX idx_tasks_parent_id ON tasks (parent_id);
+	CREATE TABLE task_checklist (
+		task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		id TEXT NOT NULL,
+		text TEXT NOT NULL,
+		done BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at {{time}} NOT NULL,
+		PRIMARY KEY (task_id, position)
+	);`,
+	// 4: 任务依赖；blocked_by 不设外键，被删除任务的引用由上层清理
+	`CREATE TABLE task_dependencies (
+		task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		blocked_by TEXT NOT NULL,
+		PRIMARY KEY (task_id, position)
+	);
+	CREATE INDEX idx_task_dependencies_blocked_by ON task_dependencies (blocked_by);`,
+	// 5: 重复任务
+	`ALTER TABLE tasks ADD COLUMN recurrence_rule TEXT;
+	ALTER TABLE tasks ADD COLUMN recurrence_tzid TEXT NOT NULL DEFAULT '';`,
+	// 6: 标签；name_key 为小写名称，保证同一用户内不区分大小写唯一
+	`CREATE TABLE labels (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		name_key TEXT NOT NULL,
+		color TEXT NOT NULL DEFAULT '',
+		description TEXT NOT NULL DEFAULT '',
+		created_at {{time}} NOT NULL,
+		updated_at {{time}} NOT NULL,
+		UNIQUE (user_id, name_key)
+	);
+	CREATE TABLE task_labels (
+		task_id TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		label TEXT NOT NULL,
+		PRIMARY KEY (task_id, position)
+	);
+	CREATE INDEX idx_task_labels_label ON task_labels (label);
+	CREATE TABLE report_label_stats (
+		report_id TEXT NOT NULL REFERENCES reports (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		label TEXT NOT NULL,
+		total_tasks INTEGER NOT NULL DEFAULT 0,
+		completed_tasks INTEGER NOT NULL DEFAULT 0,
+		in_progress_tasks INTEGER NOT NULL DEFAULT 0,
+		overdue_tasks INTEGER NOT NULL DEFAULT 0,
+		completion_rate INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (report_id, position)
+	);`,
+	// 7: 项目；任务与报表的 project_id 不设外键，删除项目时由上层移出任务
+	`CREATE TABLE projects (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		archived BOOLEAN NOT NULL DEFAULT FALSE,
+		position INTEGER NOT NULL DEFAULT 0,
+		created_at {{time}} NOT NULL,
+		updated_at {{time}} NOT NULL
+	);
+	CREATE INDEX idx_projects_user_id ON projects (user_id);
+	ALTER TABLE tasks ADD COLUMN project_id TEXT;
+	CREATE INDEX idx_tasks_project_id ON tasks (project_id);
+	ALTER TABLE reports ADD COLUMN project_id TEXT;`,
+	// 8: 工作流；scope_key 为 project_id，默认工作流为空字符串，保证每个范围至多一个工作流
+	`CREATE TABLE workflows (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		project_id TEXT,
+		scope_key TEXT NOT NULL,
+		name TEXT NOT NULL DEFAULT '',
+		created_at {{time}} NOT NULL,
+		updated_at {{time}} NOT NULL,
+		UNIQUE (user_id, scope_key)
+	);
+	CREATE TABLE workflow_statuses (
+		workflow_id TEXT NOT NULL REFERENCES workflows (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		category TEXT NOT NULL,
+		wip_limit INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (workflow_id, position)
+	);
+	CREATE TABLE workflow_transitions (
+		workflow_id TEXT NOT NULL REFERENCES workflows (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		from_status TEXT NOT NULL,
+		to_status TEXT NOT NULL,
+		PRIMARY KEY (workflow_id, position)
+	);
+	CREATE TABLE workflow_priorities (
+		workflow_id TEXT NOT NULL REFERENCES workflows (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		PRIMARY KEY (workflow_id, position)
+	);`,
+	// 9: 评论的 ID、回复与编辑时间；已有评论按位置回填 ID，与 Mongo 读取旧数据时的规则一致
+	`ALTER TABLE task_comments ADD COLUMN id TEXT NOT NULL DEFAULT '';
+	UPDATE task_comments SET id = 'legacy-' || CAST(position AS TEXT);
+	ALTER TABLE task_comments ADD COLUMN parent_id TEXT;
+	ALTER TABLE task_comments ADD COLUMN updated_at {{time}};`,
+	// 10: 任务历史；不引用 tasks，任务删除后历史仍然保留
+	`CREATE TABLE task_events (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		task_id TEXT NOT NULL,
+		actor TEXT NOT NULL,
+		action TEXT NOT NULL,
+		created_at {{time}} NOT NULL
+	);
+	CREATE INDEX idx_task_events_task_id_created_at ON task_events (task_id, created_at);
+	CREATE INDEX idx_task_events_user_id_created_at ON task_events (user_id, created_at);
+	CREATE TABLE task_event_changes (
+		event_id TEXT NOT NULL REFERENCES task_events (id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		field TEXT NOT NULL,
+		item_id TEXT NOT NULL DEFAULT '',
+		old_value TEXT,
+		new_value TEXT,
+		PRIMARY KEY (event_id, position)
+	);`,
+	// 11: 回收站；deleted_at 非空表示已移入回收站
+	`ALTER TABLE tasks ADD COLUMN deleted_at {{time}};
+	CREATE INDEX idx_tasks_deleted_at ON tasks (deleted_at);
+	ALTER TABLE reports ADD COLUMN deleted_at {{time}};
+	CREATE INDEX idx_reports_deleted_at ON reports (deleted_at);`,
+	// 12: 乐观并发控制的版本号，已有记录从 1 开始
+	`ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+	ALTER TABLE reports ADD COLUMN version INTEGER NOT NULL DEFAULT 1;`,
+	// 13: 跨进程的租约锁
+	`CREATE TABLE locks (
+		name TEXT PRIMARY KEY,
+		holder TEXT NOT NULL,
+		expires_at {{time}} NOT NULL
+	);`,
+	// 14: 全文检索的索引词保存在数据库中，各进程共用；不再需要租约锁
+	`DROP TABLE locks;
+	CREATE TABLE search_docs (
+		kind TEXT NOT NULL,
+		id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		length DOUBLE PRECISION NOT NULL,
+		PRIMARY KEY (kind, id)
+	);
+	CREATE INDEX idx_search_docs_user_id ON search_docs (user_id);
+	CREATE TABLE search_terms (
+		kind TEXT NOT NULL,
+		id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		term TEXT NOT NULL,
+		tf DOUBLE PRECISION NOT NULL,
+		PRIMARY KEY (kind, id, term),
+		FOREIGN KEY (kind, id) REFERENCES search_docs (kind, id) ON DELETE CASCADE
+	);
+	CREATE INDEX idx_search_terms_user_id_term ON search_terms (user_id, term);`,
+}
+
+// migrate 执行尚未应用的迁移，已应用的版本记录在 schema_migrations 表
+func (db *DB) migrate(ctx context.Context) error {
+	if _, err := db.ExecContext(ctx, db.schema(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at {{time}} NOT NULL
+	)`)); err != nil {
+		return err
+	}
+	var current sql.NullInt64
+	if err := db.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_migrations").Scan(&current); err != nil {
+		return err
+	}
+	for i := int(current.Int64); i < len(migrations); i++ {
+		version := i + 1
+		err := db.withTx(ctx, func(tx *sql.Tx) error {
+			for _, stmt := range strings.Split(db.schema(migrations[i]), ";") {
+				if strings.TrimSpace(stmt) == "" {
+					continue
+				}
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			_, err := tx.ExecContext(ctx, db.rebind("INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)"), version, utc(time.Now()))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schema 替换方言相关的列类型
+func (db *DB) schema(stmt string) string {
+	timeType := "TIMESTAMP"
+	if db.dialect == postgres {
+		timeType = "TIMESTAMPTZ"
+	}
+	return strings.ReplaceAll(stmt, "{{time}}", timeType)
+}
@@ -0,0 +1,91 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+const projectColumns = "id, user_id, name, description, archived, position, created_at, updated_at"
+
+// ProjectRepository projects 表
+type ProjectRepository struct {
+	db *DB
+}
+
+// NewProjectRepository 创建项目存储
+func NewProjectRepository(db *DB) *ProjectRepository {
+	return &ProjectRepository{db: db}
+}
+
+var _ repository.ProjectRepository = (*ProjectRepository)(nil)
+
+// Create 保存新项目并回填 ID
+func (r *ProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	id := repository.NewID()
+	_, err := r.db.ExecContext(ctx, r.db.rebind(`INSERT INTO projects (`+projectColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+		id, project.UserID, project.Name, project.Description, project.Archived, project.Position,
+		utc(project.CreatedAt), utc(project.UpdatedAt))
+	if err != nil {
+		return err
+	}
+	project.ID = id
+	return nil
+}
+
+// Get 获取属于 userID 的项目
+func (r *ProjectRepository) Get(ctx context.Context, userID, id string) (*models.Project, error) {
+	projects, err := r.query(ctx, "WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &projects[0], nil
+}
+
+// List 返回用户的全部项目，按 Position 升序，相同时按创建时间升序
+func (r *ProjectRepository) List(ctx context.Context, userID string) ([]models.Project, error) {
+	return r.query(ctx, "WHERE user_id = ? ORDER BY position, created_at, id", userID)
+}
+
+// Update 按 ID 与 UserID 整体覆盖项目
+func (r *ProjectRepository) Update(ctx context.Context, project *models.Project) error {
+	res, err := r.db.ExecContext(ctx, r.db.rebind(`UPDATE projects SET name = ?, description = ?, archived = ?, position = ?,
+		created_at = ?, updated_at = ? WHERE id = ? AND user_id = ?`),
+		project.Name, project.Description, project.Archived, project.Position,
+		utc(project.CreatedAt), utc(project.UpdatedAt), project.ID, project.UserID)
+	if err != nil {
+		return err
+	}
+	return affected(res)
+}
+
+// Delete 删除属于 userID 的项目
+func (r *ProjectRepository) Delete(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, r.db.rebind("DELETE FROM projects WHERE id = ? AND user_id = ?"), id, userID)
+	if err != nil {
+		return err
+	}
+	return affected(res)
+}
+
+func (r *ProjectRepository) query(ctx context.Context, clause string, args ...interface{}) ([]models.Project, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT "+projectColumns+" FROM projects "+clause), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	projects := []models.Project{}
+	for rows.Next() {
+		var p models.Project
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Name, &p.Description, &p.Archived, &p.Position, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		p.CreatedAt, p.UpdatedAt = p.CreatedAt.UTC(), p.UpdatedAt.UTC()
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
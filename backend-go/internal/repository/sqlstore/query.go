@@ -0,0 +1,93 @@
+package sqlstore
+
+import (
+	"strings"
+
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+)
+
+var queryColumns = map[query.Field]string{
+	query.FieldTitle:         "title",
+	query.FieldDescription:   "description",
+	query.FieldStatus:        "status",
+	query.FieldPriority:      "priority",
+	query.FieldAssignee:      "assignee",
+	query.FieldCreatedAt:     "created_at",
+	query.FieldUpdatedAt:     "updated_at",
+	query.FieldDeadline:      "deadline",
+	query.FieldScheduledDate: "scheduled_date",
+	query.FieldProject:       "project_id",
+}
+
+// queryCond 将查询语言的条件树翻译为 WHERE 条件。可为空的列先判断非空，
+// 避免 NULL 参与比较得到 UNKNOWN，使取反后的结果与其他存储实现一致
+func queryCond(c query.Cond) (string, []interface{}) {
+	switch c := c.(type) {
+	case query.And:
+		return joinConds(c, " AND ", "1=1")
+	case query.Or:
+		return joinConds(c, " OR ", "1=0")
+	case query.Not:
+		cond, args := queryCond(c.Cond)
+		return "NOT " + cond, args
+	case query.Match:
+		if c.Field == query.FieldLabels {
+			return labelCond(c)
+		}
+		col := queryColumns[c.Field]
+		var cond string
+		var args []interface{}
+		switch c.Op {
+		case query.OpNull:
+			return col + " IS NULL", nil
+		case query.OpEq:
+			cond, args = col+" = ?", []interface{}{c.Values[0]}
+		case query.OpIn:
+			if len(c.Values) == 0 {
+				return "1=0", nil
+			}
+			cond = col + " IN (" + placeholders(len(c.Values)) + ")"
+			for _, v := range c.Values {
+				args = append(args, v)
+			}
+		case query.OpContains:
+			cond, args = "LOWER("+col+`) LIKE ? ESCAPE '\'`, []interface{}{"%" + escapeLike(strings.ToLower(c.Values[0])) + "%"}
+		case query.OpLt:
+			cond, args = col+" < ?", []interface{}{utc(c.Time)}
+		case query.OpGte:
+			cond, args = col+" >= ?", []interface{}{utc(c.Time)}
+		}
+		if c.Field.Nullable() {
+			cond = "(" + col + " IS NOT NULL AND " + cond + ")"
+		}
+		return cond, args
+	}
+	return "1=0", nil
+}
+
+// labelCond 标签保存在 task_labels 表，按子查询匹配
+func labelCond(c query.Match) (string, []interface{}) {
+	if c.Op == query.OpNull {
+		return "id NOT IN (SELECT task_id FROM task_labels)", nil
+	}
+	return "id IN (SELECT task_id FROM task_labels WHERE label = ?)", []interface{}{c.Values[0]}
+}
+
+func joinConds(cs []query.Cond, sep, empty string) (string, []interface{}) {
+	if len(cs) == 0 {
+		return empty, nil
+	}
+	parts := make([]string, 0, len(cs))
+	var args []interface{}
+	for _, c := range cs {
+		cond, a := queryCond(c)
+		parts = append(parts, cond)
+		args = append(args, a...)
+	}
+	return "(" + strings.Join(parts, sep) + ")", args
+}
+
+// escapeLike 转义 LIKE 中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
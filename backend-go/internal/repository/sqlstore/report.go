@@ -0,0 +1,259 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+const reportColumns = `id, user_id, type, period, title, content, polished_content,
+	total_tasks, completed_tasks, in_progress_tasks, overdue_tasks, completion_rate,
+	start_date, end_date, created_at, updated_at, project_id, deleted_at, version`
+
+// ReportRepository reports 表与 report_tasks 关联表、report_label_stats 子表
+type ReportRepository struct {
+	db *DB
+}
+
+// NewReportRepository 创建报表存储
+func NewReportRepository(db *DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+var _ repository.ReportRepository = (*ReportRepository)(nil)
+
+// Create 保存新报表及关联任务并回填 ID 与版本
+func (r *ReportRepository) Create(ctx context.Context, report *models.Report) error {
+	id := repository.NewID()
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		st := report.Statistics
+		_, err := tx.ExecContext(ctx, r.db.rebind(`INSERT INTO reports (`+reportColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)`),
+			id, report.UserID, report.Type, report.Period, report.Title, report.Content, nullString(report.PolishedContent),
+			st.TotalTasks, st.CompletedTasks, st.InProgressTasks, st.OverdueTasks, st.CompletionRate,
+			utc(report.StartDate), utc(report.EndDate), utc(report.CreatedAt), utc(report.UpdatedAt), nullString(report.ProjectID),
+			nullTime(report.DeletedAt))
+		if err != nil {
+			return err
+		}
+		return r.insertChildren(ctx, tx, id, report)
+	})
+	if err != nil {
+		return err
+	}
+	report.ID = id
+	report.Version = 1
+	return nil
+}
+
+// Get 获取属于 userID 且未删除的报表
+func (r *ReportRepository) Get(ctx context.Context, userID, id string) (*models.Report, error) {
+	reports, err := r.query(ctx, "WHERE id = ? AND user_id = ? AND deleted_at IS NULL", []interface{}{id, userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &reports[0], nil
+}
+
+// List 按条件查询报表，按创建时间倒序
+func (r *ReportRepository) List(ctx context.Context, filter repository.ReportFilter) ([]models.Report, error) {
+	if filter.IDs != nil && len(filter.IDs) == 0 {
+		return []models.Report{}, nil
+	}
+	where, args := reportWhere(filter)
+	page, pageArgs := r.db.limitOffset(filter.Skip, filter.Limit)
+	return r.query(ctx, where+" ORDER BY created_at DESC, id DESC"+page, append(args, pageArgs...))
+}
+
+// Count 统计满足条件的报表数
+func (r *ReportRepository) Count(ctx context.Context, filter repository.ReportFilter) (int64, error) {
+	if filter.IDs != nil && len(filter.IDs) == 0 {
+		return 0, nil
+	}
+	where, args := reportWhere(filter)
+	var n int64
+	err := r.db.QueryRowContext(ctx, r.db.rebind("SELECT COUNT(*) FROM reports "+where), args...).Scan(&n)
+	return n, err
+}
+
+// Update 按 ID、UserID 与版本整体覆盖报表，关联任务与标签统计整体替换
+func (r *ReportRepository) Update(ctx context.Context, report *models.Report) error {
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		st := report.Statistics
+		res, err := tx.ExecContext(ctx, r.db.rebind(`UPDATE reports SET type = ?, period = ?, title = ?, content = ?, polished_content = ?,
+			total_tasks = ?, completed_tasks = ?, in_progress_tasks = ?, overdue_tasks = ?, completion_rate = ?,
+			start_date = ?, end_date = ?, created_at = ?, updated_at = ?, project_id = ?, deleted_at = ?, version = version + 1
+			WHERE id = ? AND user_id = ? AND version = ?`),
+			report.Type, report.Period, report.Title, report.Content, nullString(report.PolishedContent),
+			st.TotalTasks, st.CompletedTasks, st.InProgressTasks, st.OverdueTasks, st.CompletionRate,
+			utc(report.StartDate), utc(report.EndDate), utc(report.CreatedAt), utc(report.UpdatedAt),
+			nullString(report.ProjectID), nullTime(report.DeletedAt), report.ID, report.UserID, report.Version)
+		if err != nil {
+			return err
+		}
+		if err := r.db.versioned(ctx, tx, res, "SELECT COUNT(*) FROM reports WHERE id = ? AND user_id = ?", report.ID, report.UserID); err != nil {
+			return err
+		}
+		for _, table := range []string{"report_tasks", "report_label_stats"} {
+			if _, err := tx.ExecContext(ctx, r.db.rebind("DELETE FROM "+table+" WHERE report_id = ?"), report.ID); err != nil {
+				return err
+			}
+		}
+		return r.insertChildren(ctx, tx, report.ID, report)
+	})
+	if err != nil {
+		return err
+	}
+	report.Version++
+	return nil
+}
+
+// Delete 永久删除属于 userID 的报表，关联记录随外键级联删除
+func (r *ReportRepository) Delete(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, r.db.rebind("DELETE FROM reports WHERE id = ? AND user_id = ?"), id, userID)
+	if err != nil {
+		return err
+	}
+	return affected(res)
+}
+
+// insertChildren 按顺序写入关联任务与标签统计
+func (r *ReportRepository) insertChildren(ctx context.Context, tx *sql.Tx, reportID string, report *models.Report) error {
+	for i, taskID := range report.Tasks {
+		if _, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO report_tasks (report_id, position, task_id) VALUES (?, ?, ?)"), reportID, i, taskID); err != nil {
+			return err
+		}
+	}
+	for i, ls := range report.LabelStatistics {
+		_, err := tx.ExecContext(ctx, r.db.rebind(`INSERT INTO report_label_stats (report_id, position, label,
+			total_tasks, completed_tasks, in_progress_tasks, overdue_tasks, completion_rate) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+			reportID, i, ls.Label, ls.TotalTasks, ls.CompletedTasks, ls.InProgressTasks, ls.OverdueTasks, ls.CompletionRate)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// query 查询报表并批量加载关联任务 ID 与标签统计
+func (r *ReportRepository) query(ctx context.Context, clause string, args []interface{}) ([]models.Report, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT "+reportColumns+" FROM reports "+clause), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []models.Report{}
+	for rows.Next() {
+		var rep models.Report
+		var polished, project sql.NullString
+		var deleted sql.NullTime
+		st := &rep.Statistics
+		if err := rows.Scan(&rep.ID, &rep.UserID, &rep.Type, &rep.Period, &rep.Title, &rep.Content, &polished,
+			&st.TotalTasks, &st.CompletedTasks, &st.InProgressTasks, &st.OverdueTasks, &st.CompletionRate,
+			&rep.StartDate, &rep.EndDate, &rep.CreatedAt, &rep.UpdatedAt, &project, &deleted, &rep.Version); err != nil {
+			return nil, err
+		}
+		rep.PolishedContent = stringPtr(polished)
+		rep.ProjectID = stringPtr(project)
+		rep.DeletedAt = timePtr(deleted)
+		rep.StartDate, rep.EndDate = rep.StartDate.UTC(), rep.EndDate.UTC()
+		rep.CreatedAt, rep.UpdatedAt = rep.CreatedAt.UTC(), rep.UpdatedAt.UTC()
+		rep.Tasks = []string{}
+		reports = append(reports, rep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return reports, nil
+	}
+	index := make(map[string]int, len(reports))
+	ids := make([]interface{}, 0, len(reports))
+	for i, rep := range reports {
+		index[rep.ID] = i
+		ids = append(ids, rep.ID)
+	}
+	if err := r.loadTasks(ctx, reports, index, ids); err != nil {
+		return nil, err
+	}
+	if err := r.loadLabelStats(ctx, reports, index, ids); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// loadTasks 按 position 顺序填充报表关联的任务 ID
+func (r *ReportRepository) loadTasks(ctx context.Context, reports []models.Report, index map[string]int, ids []interface{}) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT report_id, task_id FROM report_tasks WHERE report_id IN ("+
+		placeholders(len(ids))+") ORDER BY report_id, position"), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var reportID, taskID string
+		if err := rows.Scan(&reportID, &taskID); err != nil {
+			return err
+		}
+		i := index[reportID]
+		reports[i].Tasks = append(reports[i].Tasks, taskID)
+	}
+	return rows.Err()
+}
+
+// loadLabelStats 按 position 顺序填充报表的标签统计
+func (r *ReportRepository) loadLabelStats(ctx context.Context, reports []models.Report, index map[string]int, ids []interface{}) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind(`SELECT report_id, label, total_tasks, completed_tasks, in_progress_tasks, overdue_tasks, completion_rate
+		FROM report_label_stats WHERE report_id IN (`+placeholders(len(ids))+`) ORDER BY report_id, position`), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var reportID string
+		var ls models.LabelStatistics
+		if err := rows.Scan(&reportID, &ls.Label, &ls.TotalTasks, &ls.CompletedTasks, &ls.InProgressTasks, &ls.OverdueTasks, &ls.CompletionRate); err != nil {
+			return err
+		}
+		i := index[reportID]
+		reports[i].LabelStatistics = append(reports[i].LabelStatistics, ls)
+	}
+	return rows.Err()
+}
+
+// reportWhere 将过滤条件转换为 WHERE 子句
+func reportWhere(f repository.ReportFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if f.UserID != "" {
+		conds = append(conds, "user_id = ?")
+		args = append(args, f.UserID)
+	}
+	if len(f.IDs) > 0 {
+		conds = append(conds, "id IN ("+placeholders(len(f.IDs))+")")
+		for _, id := range f.IDs {
+			args = append(args, id)
+		}
+	}
+	if cond := trashCond(f.Trash); cond != "" {
+		conds = append(conds, cond)
+	}
+	if f.DeletedTo != nil {
+		conds = append(conds, "deleted_at <= ?")
+		args = append(args, utc(*f.DeletedTo))
+	}
+	if f.Type != "" {
+		conds = append(conds, "type = ?")
+		args = append(args, f.Type)
+	}
+	if f.ProjectID != "" {
+		conds = append(conds, "project_id = ?")
+		args = append(args, f.ProjectID)
+	}
+	return where(conds), args
+}
@@ -0,0 +1,117 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// searchBatch 每条 INSERT 写入的索引词数，保持在两种方言的参数个数上限之内
+const searchBatch = 200
+
+// SearchRepository search_docs 表与 search_terms 子表，每个文档的每个索引词一行
+type SearchRepository struct {
+	db *DB
+}
+
+// NewSearchRepository 创建索引词存储
+func NewSearchRepository(db *DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+var _ repository.SearchRepository = (*SearchRepository)(nil)
+
+// Put 写入文档并替换其索引词。先写 search_docs 行以锁定文档，并发写入同一文档时依次替换，不会互相插入重复的索引词
+func (r *SearchRepository) Put(ctx context.Context, doc *repository.SearchDoc) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, r.db.rebind(`INSERT INTO search_docs (kind, id, user_id, length) VALUES (?, ?, ?, ?)
+			ON CONFLICT (kind, id) DO UPDATE SET user_id = excluded.user_id, length = excluded.length`),
+			doc.Kind, doc.ID, doc.UserID, doc.Length)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, r.db.rebind("DELETE FROM search_terms WHERE kind = ? AND id = ?"), doc.Kind, doc.ID); err != nil {
+			return err
+		}
+		return r.insertTerms(ctx, tx, doc)
+	})
+}
+
+// Insert 只在文档尚未索引时写入
+func (r *SearchRepository) Insert(ctx context.Context, doc *repository.SearchDoc) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, r.db.rebind(`INSERT INTO search_docs (kind, id, user_id, length) VALUES (?, ?, ?, ?)
+			ON CONFLICT (kind, id) DO NOTHING`),
+			doc.Kind, doc.ID, doc.UserID, doc.Length)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		return r.insertTerms(ctx, tx, doc)
+	})
+}
+
+// insertTerms 按词序分批写入文档的索引词
+func (r *SearchRepository) insertTerms(ctx context.Context, tx *sql.Tx, doc *repository.SearchDoc) error {
+	terms := make([]string, 0, len(doc.Terms))
+	for term := range doc.Terms {
+		terms = append(terms, term)
+	}
+	slices.Sort(terms)
+	for len(terms) > 0 {
+		batch := terms[:min(searchBatch, len(terms))]
+		terms = terms[len(batch):]
+		rows := make([]string, len(batch))
+		args := make([]interface{}, 0, 5*len(batch))
+		for i, term := range batch {
+			rows[i] = "(?, ?, ?, ?, ?)"
+			args = append(args, doc.Kind, doc.ID, doc.UserID, term, doc.Terms[term])
+		}
+		_, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO search_terms (kind, id, user_id, term, tf) VALUES "+strings.Join(rows, ", ")), args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove 删除文档及其索引词，不存在时忽略
+func (r *SearchRepository) Remove(ctx context.Context, kind, id string) error {
+	_, err := r.db.ExecContext(ctx, r.db.rebind("DELETE FROM search_docs WHERE kind = ? AND id = ?"), kind, id)
+	return err
+}
+
+// Postings 返回 userID 的文档中 terms 各词的词频与该用户全部文档的统计
+func (r *SearchRepository) Postings(ctx context.Context, userID string, terms []string) ([]repository.SearchPosting, repository.SearchStats, error) {
+	var stats repository.SearchStats
+	err := r.db.QueryRowContext(ctx, r.db.rebind("SELECT COUNT(*), COALESCE(SUM(length), 0) FROM search_docs WHERE user_id = ?"), userID).
+		Scan(&stats.Docs, &stats.TotalLength)
+	if err != nil || len(terms) == 0 {
+		return nil, stats, err
+	}
+	args := []interface{}{userID}
+	for _, term := range terms {
+		args = append(args, term)
+	}
+	rows, err := r.db.QueryContext(ctx, r.db.rebind(`SELECT t.kind, t.id, t.term, t.tf, d.length FROM search_terms t
+		JOIN search_docs d ON d.kind = t.kind AND d.id = t.id
+		WHERE t.user_id = ? AND t.term IN (`+placeholders(len(terms))+")"), args...)
+	if err != nil {
+		return nil, stats, err
+	}
+	defer rows.Close()
+	var out []repository.SearchPosting
+	for rows.Next() {
+		var p repository.SearchPosting
+		if err := rows.Scan(&p.Kind, &p.ID, &p.Term, &p.TF, &p.Length); err != nil {
+			return nil, stats, err
+		}
+		out = append(out, p)
+	}
+	return out, stats, rows.Err()
+}
@@ -0,0 +1,191 @@
+package sqlstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/axfinn/todoIng/backend-go/internal/repository/repotest"
+)
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(context.Background(), "sqlite://"+filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestTaskRepository(t *testing.T) {
+	repotest.TaskRepository(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskQuery(t *testing.T) {
+	repotest.TaskQuery(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskTree(t *testing.T) {
+	repotest.TaskTree(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskDependencies(t *testing.T) {
+	repotest.TaskDependencies(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskRecurrence(t *testing.T) {
+	repotest.TaskRecurrence(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskLabels(t *testing.T) {
+	repotest.TaskLabels(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskProjects(t *testing.T) {
+	repotest.TaskProjects(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskTrash(t *testing.T) {
+	repotest.TaskTrash(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskBulkUpdate(t *testing.T) {
+	repotest.TaskBulkUpdate(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskHistoryWrite(t *testing.T) {
+	db := openTestDB(t)
+	repotest.TaskHistoryWrite(t, NewTaskRepository(db), NewHistoryRepository(db))
+}
+
+func TestTaskVersion(t *testing.T) {
+	repotest.TaskVersion(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestTaskQueryLanguage(t *testing.T) {
+	repotest.TaskQueryLanguage(t, NewTaskRepository(openTestDB(t)))
+}
+
+func TestReportRepository(t *testing.T) {
+	repotest.ReportRepository(t, NewReportRepository(openTestDB(t)))
+}
+
+func TestReportTrash(t *testing.T) {
+	repotest.ReportTrash(t, NewReportRepository(openTestDB(t)))
+}
+
+func TestReportVersion(t *testing.T) {
+	repotest.ReportVersion(t, NewReportRepository(openTestDB(t)))
+}
+
+func TestLabelRepository(t *testing.T) {
+	repotest.LabelRepository(t, NewLabelRepository(openTestDB(t)))
+}
+
+func TestProjectRepository(t *testing.T) {
+	repotest.ProjectRepository(t, NewProjectRepository(openTestDB(t)))
+}
+
+func TestWorkflowRepository(t *testing.T) {
+	repotest.WorkflowRepository(t, NewWorkflowRepository(openTestDB(t)))
+}
+
+func TestHistoryRepository(t *testing.T) {
+	repotest.HistoryRepository(t, NewHistoryRepository(openTestDB(t)))
+}
+
+func TestSearchRepository(t *testing.T) {
+	repotest.SearchRepository(t, NewSearchRepository(openTestDB(t)))
+}
+
+func TestUserRepository(t *testing.T) {
+	repotest.UserRepository(t, NewUserRepository(openTestDB(t)))
+}
+
+func TestCommentsCascadeOnDelete(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "INSERT INTO tasks (id, created_by, title, created_at, updated_at) VALUES ('t1', 'u1', 't', '2024-01-01', '2024-01-01')"); err != nil {
+		t.Fatalf("Insert task failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO task_comments (task_id, position, text, created_by, created_at) VALUES ('t1', 0, 'c', 'u1', '2024-01-01')"); err != nil {
+		t.Fatalf("Insert comment failed: %v", err)
+	}
+	if err := NewTaskRepository(db).Delete(ctx, "u1", "t1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	var n int
+	_ = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM task_comments").Scan(&n)
+	if n != 0 {
+		t.Errorf("Expected comments to be deleted with task, got %d", n)
+	}
+}
+
+func TestMigrateBackfillsCommentIDs(t *testing.T) {
+	ctx := context.Background()
+	all := migrations
+	migrations = all[:8]
+	db := openTestDB(t)
+	migrations = all
+	if _, err := db.ExecContext(ctx, "INSERT INTO tasks (id, created_by, title, created_at, updated_at) VALUES ('t1', 'u1', 't', '2024-01-01', '2024-01-01')"); err != nil {
+		t.Fatalf("Insert task failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO task_comments (task_id, position, text, created_by, created_at) VALUES ('t1', 0, 'a', 'u1', '2024-01-01'), ('t1', 1, 'b', 'u1', '2024-01-01')"); err != nil {
+		t.Fatalf("Insert comments failed: %v", err)
+	}
+	if err := db.migrate(ctx); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	task, err := NewTaskRepository(db).Get(ctx, "u1", "t1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(task.Comments) != 2 || task.Comments[0].ID != "legacy-0" || task.Comments[1].ID != "legacy-1" {
+		t.Errorf("Unexpected backfilled comments %+v", task.Comments)
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	db := openTestDB(t)
+	if err := db.migrate(context.Background()); err != nil {
+		t.Fatalf("Second migrate failed: %v", err)
+	}
+	var version int
+	_ = db.QueryRowContext(context.Background(), "SELECT MAX(version) FROM schema_migrations").Scan(&version)
+	if version != len(migrations) {
+		t.Errorf("Expected schema version %d, got %d", len(migrations), version)
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		uri     string
+		dialect dialect
+		dsn     string
+		wantErr bool
+	}{
+		{"sqlite://todoing.db", sqlite, "file:todoing.db?_foreign_keys=1&_busy_timeout=5000", false},
+		{"sqlite:///var/lib/todoing.db?cache=shared", sqlite, "file:/var/lib/todoing.db?cache=shared&_foreign_keys=1&_busy_timeout=5000", false},
+		{"postgres://u:p@localhost/todoing", postgres, "postgres://u:p@localhost/todoing", false},
+		{"sqlite://", 0, "", true},
+		{"mongodb://localhost:27017", 0, "", true},
+	}
+	for _, tt := range tests {
+		d, dsn, err := parseURI(tt.uri)
+		if (err != nil) != tt.wantErr || d != tt.dialect || dsn != tt.dsn {
+			t.Errorf("parseURI(%q) = %v, %q, %v", tt.uri, d, dsn, err)
+		}
+	}
+}
+
+func TestRebind(t *testing.T) {
+	pg := &DB{dialect: postgres}
+	if got := pg.rebind("a = ? AND b IN (?, ?)"); got != "a = $1 AND b IN ($2, $3)" {
+		t.Errorf("Unexpected postgres query %q", got)
+	}
+	lite := &DB{dialect: sqlite}
+	if got := lite.rebind("a = ?"); got != "a = ?" {
+		t.Errorf("Unexpected sqlite query %q", got)
+	}
+}
@@ -0,0 +1,555 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+const taskColumns = "id, created_by, title, description, status, priority, assignee, deadline, scheduled_date, created_at, updated_at, parent_id, recurrence_rule, recurrence_tzid, project_id, deleted_at, version"
+
+// TaskRepository tasks 表与 task_comments、task_checklist、task_dependencies、task_labels 子表
+type TaskRepository struct {
+	db *DB
+}
+
+// NewTaskRepository 创建任务存储
+func NewTaskRepository(db *DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+var _ repository.HistoryTaskRepository = (*TaskRepository)(nil)
+
+// Create 保存新任务及其评论、检查项并回填 ID 与版本
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	var id string
+	err := r.db.withTx(ctx, func(tx *sql.Tx) (err error) {
+		id, err = r.insert(ctx, tx, task)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	task.ID = id
+	task.Version = 1
+	return nil
+}
+
+// insert 在事务中插入任务及其评论、检查项、依赖与标签，返回新任务的 ID
+func (r *TaskRepository) insert(ctx context.Context, tx *sql.Tx, task *models.Task) (string, error) {
+	id := repository.NewID()
+	rule, tzid := recurrenceColumns(task.Recurrence)
+	_, err := tx.ExecContext(ctx, r.db.rebind(`INSERT INTO tasks (`+taskColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)`),
+		id, task.CreatedBy, task.Title, task.Description, task.Status, task.Priority,
+		nullString(task.Assignee), nullTime(task.Deadline), nullTime(task.ScheduledDate),
+		utc(task.CreatedAt), utc(task.UpdatedAt), nullString(task.ParentID), rule, tzid, nullString(task.ProjectID), nullTime(task.DeletedAt))
+	if err != nil {
+		return "", err
+	}
+	return id, r.insertChildren(ctx, tx, id, task)
+}
+
+// Get 获取属于 userID 且未删除的任务
+func (r *TaskRepository) Get(ctx context.Context, userID, id string) (*models.Task, error) {
+	tasks, err := r.query(ctx, "WHERE id = ? AND created_by = ? AND deleted_at IS NULL", []interface{}{id, userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &tasks[0], nil
+}
+
+// List 按条件查询任务，按创建时间倒序
+func (r *TaskRepository) List(ctx context.Context, filter repository.TaskFilter) ([]models.Task, error) {
+	if emptyIn(filter) {
+		return []models.Task{}, nil
+	}
+	conds, args := taskConds(filter)
+	if filter.After != nil {
+		cond, cursorArgs := r.afterCond(filter.Sort, filter.After)
+		conds = append(conds, cond)
+		args = append(args, cursorArgs...)
+	}
+	page, pageArgs := r.db.limitOffset(filter.Skip, filter.Limit)
+	return r.query(ctx, where(conds)+r.orderBy(filter.Sort)+page, append(args, pageArgs...))
+}
+
+// sortColumn 返回排序字段对应的列；PostgreSQL 按字节序比较标题，与其他存储实现保持一致
+func (r *TaskRepository) sortColumn(s repository.TaskSort) string {
+	switch s.Key() {
+	case repository.SortByUpdatedAt:
+		return "updated_at"
+	case repository.SortByDeadline:
+		return "deadline"
+	case repository.SortByScheduledDate:
+		return "scheduled_date"
+	case repository.SortByTitle:
+		if r.db.dialect == postgres {
+			return `title COLLATE "C"`
+		}
+		return "title"
+	}
+	return "created_at"
+}
+
+// orderBy 生成排序子句，空值视为最小值
+func (r *TaskRepository) orderBy(s repository.TaskSort) string {
+	col := r.sortColumn(s)
+	if s.Asc {
+		return " ORDER BY " + col + " IS NULL DESC, " + col + " ASC, id ASC"
+	}
+	return " ORDER BY " + col + " IS NULL ASC, " + col + " DESC, id DESC"
+}
+
+// afterCond 生成游标之后的键集条件，与 orderBy 的顺序一致
+func (r *TaskRepository) afterCond(s repository.TaskSort, c *repository.TaskCursor) (string, []interface{}) {
+	col := r.sortColumn(s)
+	switch {
+	case c.Value == nil && s.Asc:
+		return "((" + col + " IS NULL AND id > ?) OR " + col + " IS NOT NULL)", []interface{}{c.ID}
+	case c.Value == nil:
+		return "(" + col + " IS NULL AND id < ?)", []interface{}{c.ID}
+	case s.Asc:
+		return "(" + col + " > ? OR (" + col + " = ? AND id > ?))", []interface{}{c.Value, c.Value, c.ID}
+	}
+	return "(" + col + " < ? OR (" + col + " = ? AND id < ?) OR " + col + " IS NULL)", []interface{}{c.Value, c.Value, c.ID}
+}
+
+// Count 统计满足条件的任务数
+func (r *TaskRepository) Count(ctx context.Context, filter repository.TaskFilter) (int64, error) {
+	if emptyIn(filter) {
+		return 0, nil
+	}
+	conds, args := taskConds(filter)
+	var n int64
+	err := r.db.QueryRowContext(ctx, r.db.rebind("SELECT COUNT(*) FROM tasks "+where(conds)), args...).Scan(&n)
+	return n, err
+}
+
+// Update 按 ID、CreatedBy 与版本整体覆盖任务，评论与检查项整体替换
+func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		return r.update(ctx, tx, task)
+	})
+	if err != nil {
+		return err
+	}
+	task.Version++
+	return nil
+}
+
+// BulkUpdate 在同一事务中覆盖全部任务并创建新任务，任一任务不存在或版本不一致时回滚
+func (r *TaskRepository) BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error {
+	return r.BulkUpdateWithHistory(ctx, tasks, created, nil)
+}
+
+// BulkUpdateWithHistory 与 BulkUpdate 相同，并在同一事务中保存 events 返回的历史记录
+func (r *TaskRepository) BulkUpdateWithHistory(ctx context.Context, tasks []models.Task, created []*models.Task, events repository.TaskEvents) error {
+	ids := make([]string, len(created))
+	old := make([]string, len(created))
+	for i, task := range created {
+		old[i] = task.ID
+	}
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		for i := range tasks {
+			if err := r.update(ctx, tx, &tasks[i]); err != nil {
+				return err
+			}
+		}
+		for i, task := range created {
+			id, err := r.insert(ctx, tx, task)
+			if err != nil {
+				return err
+			}
+			ids[i] = id
+		}
+		if events == nil {
+			return nil
+		}
+		// 历史记录需要新任务的 ID，事务回滚时恢复
+		for i, task := range created {
+			task.ID = ids[i]
+		}
+		for _, event := range events(len(tasks) + len(created)) {
+			if err := r.db.insertEvent(ctx, tx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i, task := range created {
+			task.ID = old[i]
+		}
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Version++
+	}
+	for i, task := range created {
+		task.ID, task.Version = ids[i], 1
+	}
+	return nil
+}
+
+// update 在事务中覆盖任务本身并递增存储中的版本，再重写评论、检查项、依赖与标签
+func (r *TaskRepository) update(ctx context.Context, tx *sql.Tx, task *models.Task) error {
+	rule, tzid := recurrenceColumns(task.Recurrence)
+	res, err := tx.ExecContext(ctx, r.db.rebind(`UPDATE tasks SET title = ?, description = ?, status = ?, priority = ?,
+		assignee = ?, deadline = ?, scheduled_date = ?, created_at = ?, updated_at = ?, parent_id = ?,
+		recurrence_rule = ?, recurrence_tzid = ?, project_id = ?, deleted_at = ?, version = version + 1
+		WHERE id = ? AND created_by = ? AND version = ?`),
+		task.Title, task.Description, task.Status, task.Priority,
+		nullString(task.Assignee), nullTime(task.Deadline), nullTime(task.ScheduledDate),
+		utc(task.CreatedAt), utc(task.UpdatedAt), nullString(task.ParentID), rule, tzid, nullString(task.ProjectID),
+		nullTime(task.DeletedAt), task.ID, task.CreatedBy, task.Version)
+	if err != nil {
+		return err
+	}
+	if err := r.db.versioned(ctx, tx, res, "SELECT COUNT(*) FROM tasks WHERE id = ? AND created_by = ?", task.ID, task.CreatedBy); err != nil {
+		return err
+	}
+	for _, table := range []string{"task_comments", "task_checklist", "task_dependencies", "task_labels"} {
+		if _, err := tx.ExecContext(ctx, r.db.rebind("DELETE FROM "+table+" WHERE task_id = ?"), task.ID); err != nil {
+			return err
+		}
+	}
+	return r.insertChildren(ctx, tx, task.ID, task)
+}
+
+// Delete 永久删除属于 userID 的任务，评论、检查项、依赖与标签随外键级联删除
+func (r *TaskRepository) Delete(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, r.db.rebind("DELETE FROM tasks WHERE id = ? AND created_by = ?"), id, userID)
+	if err != nil {
+		return err
+	}
+	return affected(res)
+}
+
+// insertChildren 按顺序写入评论、检查项、依赖与标签
+func (r *TaskRepository) insertChildren(ctx context.Context, tx *sql.Tx, taskID string, task *models.Task) error {
+	for i, c := range task.Comments {
+		_, err := tx.ExecContext(ctx, r.db.rebind(`INSERT INTO task_comments (task_id, position, id, parent_id, text, created_by, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+			taskID, i, c.ID, nullString(c.ParentID), c.Text, c.CreatedBy, utc(c.CreatedAt), nullTime(c.UpdatedAt))
+		if err != nil {
+			return err
+		}
+	}
+	for i, item := range task.Checklist {
+		_, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO task_checklist (task_id, position, id, text, done, created_at) VALUES (?, ?, ?, ?, ?, ?)"),
+			taskID, i, item.ID, item.Text, item.Done, utc(item.CreatedAt))
+		if err != nil {
+			return err
+		}
+	}
+	for i, id := range task.BlockedBy {
+		_, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO task_dependencies (task_id, position, blocked_by) VALUES (?, ?, ?)"), taskID, i, id)
+		if err != nil {
+			return err
+		}
+	}
+	for i, label := range task.Labels {
+		_, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO task_labels (task_id, position, label) VALUES (?, ?, ?)"), taskID, i, label)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recurrenceColumns 拆分重复规则，nil 时规则列为 NULL
+func recurrenceColumns(r *models.Recurrence) (sql.NullString, string) {
+	if r == nil {
+		return sql.NullString{}, ""
+	}
+	return sql.NullString{String: r.Rule, Valid: true}, r.TZID
+}
+
+// query 查询任务并批量加载评论
+func (r *TaskRepository) query(ctx context.Context, clause string, args []interface{}) ([]models.Task, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT "+taskColumns+" FROM tasks "+clause), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		var t models.Task
+		var assignee, parent, rule, project sql.NullString
+		var tzid string
+		var deadline, scheduled, deleted sql.NullTime
+		if err := rows.Scan(&t.ID, &t.CreatedBy, &t.Title, &t.Description, &t.Status, &t.Priority,
+			&assignee, &deadline, &scheduled, &t.CreatedAt, &t.UpdatedAt, &parent, &rule, &tzid, &project, &deleted, &t.Version); err != nil {
+			return nil, err
+		}
+		t.Assignee = stringPtr(assignee)
+		t.ParentID = stringPtr(parent)
+		t.ProjectID = stringPtr(project)
+		if rule.Valid {
+			t.Recurrence = &models.Recurrence{Rule: rule.String, TZID: tzid}
+		}
+		t.Deadline = timePtr(deadline)
+		t.ScheduledDate = timePtr(scheduled)
+		t.DeletedAt = timePtr(deleted)
+		t.CreatedAt, t.UpdatedAt = t.CreatedAt.UTC(), t.UpdatedAt.UTC()
+		t.Comments = []models.Comment{}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+	index := make(map[string]int, len(tasks))
+	ids := make([]interface{}, 0, len(tasks))
+	for i, t := range tasks {
+		index[t.ID] = i
+		ids = append(ids, t.ID)
+	}
+	if err := r.loadComments(ctx, tasks, index, ids); err != nil {
+		return nil, err
+	}
+	if err := r.loadChecklist(ctx, tasks, index, ids); err != nil {
+		return nil, err
+	}
+	if err := r.loadDependencies(ctx, tasks, index, ids); err != nil {
+		return nil, err
+	}
+	if err := r.loadLabels(ctx, tasks, index, ids); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// loadComments 按 position 顺序填充任务评论
+func (r *TaskRepository) loadComments(ctx context.Context, tasks []models.Task, index map[string]int, ids []interface{}) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT task_id, id, parent_id, text, created_by, created_at, updated_at FROM task_comments WHERE task_id IN ("+
+		placeholders(len(ids))+") ORDER BY task_id, position"), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var taskID string
+		var c models.Comment
+		var parent sql.NullString
+		var updated sql.NullTime
+		if err := rows.Scan(&taskID, &c.ID, &parent, &c.Text, &c.CreatedBy, &c.CreatedAt, &updated); err != nil {
+			return err
+		}
+		c.ParentID = stringPtr(parent)
+		c.CreatedAt, c.UpdatedAt = c.CreatedAt.UTC(), timePtr(updated)
+		i := index[taskID]
+		tasks[i].Comments = append(tasks[i].Comments, c)
+	}
+	return rows.Err()
+}
+
+// loadChecklist 按 position 顺序填充任务检查项
+func (r *TaskRepository) loadChecklist(ctx context.Context, tasks []models.Task, index map[string]int, ids []interface{}) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT task_id, id, text, done, created_at FROM task_checklist WHERE task_id IN ("+
+		placeholders(len(ids))+") ORDER BY task_id, position"), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var taskID string
+		var item models.ChecklistItem
+		if err := rows.Scan(&taskID, &item.ID, &item.Text, &item.Done, &item.CreatedAt); err != nil {
+			return err
+		}
+		item.CreatedAt = item.CreatedAt.UTC()
+		i := index[taskID]
+		tasks[i].Checklist = append(tasks[i].Checklist, item)
+	}
+	return rows.Err()
+}
+
+// loadDependencies 按 position 顺序填充阻塞任务 ID
+func (r *TaskRepository) loadDependencies(ctx context.Context, tasks []models.Task, index map[string]int, ids []interface{}) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT task_id, blocked_by FROM task_dependencies WHERE task_id IN ("+
+		placeholders(len(ids))+") ORDER BY task_id, position"), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var taskID, blockedBy string
+		if err := rows.Scan(&taskID, &blockedBy); err != nil {
+			return err
+		}
+		i := index[taskID]
+		tasks[i].BlockedBy = append(tasks[i].BlockedBy, blockedBy)
+	}
+	return rows.Err()
+}
+
+// loadLabels 按 position 顺序填充任务标签
+func (r *TaskRepository) loadLabels(ctx context.Context, tasks []models.Task, index map[string]int, ids []interface{}) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT task_id, label FROM task_labels WHERE task_id IN ("+
+		placeholders(len(ids))+") ORDER BY task_id, position"), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var taskID, label string
+		if err := rows.Scan(&taskID, &label); err != nil {
+			return err
+		}
+		i := index[taskID]
+		tasks[i].Labels = append(tasks[i].Labels, label)
+	}
+	return rows.Err()
+}
+
+// taskConds 将过滤条件转换为 WHERE 条件列表
+func taskConds(f repository.TaskFilter) ([]string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		conds = append(conds, cond)
+		args = append(args, arg)
+	}
+	if f.UserID != "" {
+		add("created_by = ?", f.UserID)
+	}
+	if cond := trashCond(f.Trash); cond != "" {
+		conds = append(conds, cond)
+	}
+	if f.DeletedTo != nil {
+		add("deleted_at <= ?", utc(*f.DeletedTo))
+	}
+	if len(f.IDs) > 0 {
+		conds = append(conds, "id IN ("+placeholders(len(f.IDs))+")")
+		for _, id := range f.IDs {
+			args = append(args, id)
+		}
+	}
+	if len(f.ParentIDs) > 0 {
+		conds = append(conds, "parent_id IN ("+placeholders(len(f.ParentIDs))+")")
+		for _, id := range f.ParentIDs {
+			args = append(args, id)
+		}
+	}
+	if len(f.BlockedBy) > 0 {
+		conds = append(conds, "id IN (SELECT task_id FROM task_dependencies WHERE blocked_by IN ("+placeholders(len(f.BlockedBy))+"))")
+		for _, id := range f.BlockedBy {
+			args = append(args, id)
+		}
+	}
+	if len(f.Labels) > 0 {
+		conds = append(conds, "id IN (SELECT task_id FROM task_labels WHERE label IN ("+placeholders(len(f.Labels))+"))")
+		for _, label := range f.Labels {
+			args = append(args, label)
+		}
+	}
+	if len(f.ProjectIDs) > 0 {
+		conds = append(conds, "project_id IN ("+placeholders(len(f.ProjectIDs))+")")
+		for _, id := range f.ProjectIDs {
+			args = append(args, id)
+		}
+	}
+	if f.Status != "" {
+		add("status = ?", f.Status)
+	}
+	if f.Priority != "" {
+		add("priority = ?", f.Priority)
+	}
+	if f.Assignee != "" {
+		add("assignee = ?", f.Assignee)
+	}
+	ranges := []struct {
+		col      string
+		from, to *time.Time
+	}{
+		{"created_at", f.CreatedFrom, f.CreatedTo},
+		{"updated_at", f.UpdatedFrom, f.UpdatedTo},
+		{"deadline", f.DeadlineFrom, f.DeadlineTo},
+		{"scheduled_date", f.ScheduledFrom, f.ScheduledTo},
+	}
+	for _, rg := range ranges {
+		if rg.from != nil {
+			add(rg.col+" >= ?", utc(*rg.from))
+		}
+		if rg.to != nil {
+			add(rg.col+" <= ?", utc(*rg.to))
+		}
+	}
+	if f.Query != nil {
+		cond, queryArgs := queryCond(f.Query)
+		conds = append(conds, cond)
+		args = append(args, queryArgs...)
+	}
+	return conds, args
+}
+
+// trashCond 回收站过滤条件，不区分时返回空串
+func trashCond(scope repository.TrashScope) string {
+	switch scope {
+	case repository.WithoutTrashed:
+		return "deleted_at IS NULL"
+	case repository.OnlyTrashed:
+		return "deleted_at IS NOT NULL"
+	}
+	return ""
+}
+
+// emptyIn 以空集合过滤 ID、父任务、阻塞任务、标签或项目时不可能有结果
+func emptyIn(f repository.TaskFilter) bool {
+	return (f.IDs != nil && len(f.IDs) == 0) || (f.ParentIDs != nil && len(f.ParentIDs) == 0) ||
+		(f.BlockedBy != nil && len(f.BlockedBy) == 0) || (f.Labels != nil && len(f.Labels) == 0) ||
+		(f.ProjectIDs != nil && len(f.ProjectIDs) == 0)
+}
+
+// where 拼接 WHERE 子句，无条件时返回空串
+func where(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conds, " AND ")
+}
+
+// affected 未影响任何行时返回 ErrNotFound
+func affected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// versioned 带版本条件的更新未影响任何行时，按 exists 查询区分记录不存在与版本不一致
+func (db *DB) versioned(ctx context.Context, tx *sql.Tx, res sql.Result, exists string, args ...interface{}) error {
+	err := affected(res)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	var n int64
+	if err := tx.QueryRowContext(ctx, db.rebind(exists), args...).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return repository.ErrVersion
+	}
+	return repository.ErrNotFound
+}
+
+// isNoRows 判断查询是否无结果
+func isNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
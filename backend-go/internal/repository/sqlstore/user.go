@@ -0,0 +1,63 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// UserRepository users 表
+type UserRepository struct {
+	db *DB
+}
+
+// NewUserRepository 创建用户存储
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+// Create 保存新用户并回填 ID
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	id := repository.NewID()
+	email := strings.ToLower(user.Email)
+	_, err := r.db.ExecContext(ctx, r.db.rebind("INSERT INTO users (id, username, email, password, created_at) VALUES (?, ?, ?, ?, ?)"),
+		id, user.Username, email, user.Password, utc(user.CreatedAt))
+	if err != nil {
+		return err
+	}
+	user.ID, user.Email = id, email
+	return nil
+}
+
+// GetByID 按 ID 查找用户
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// GetByEmail 按邮箱查找用户
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.findOne(ctx, "email = ?", strings.ToLower(email))
+}
+
+// FindByEmailOrUsername 查找邮箱或用户名已被占用的用户
+func (r *UserRepository) FindByEmailOrUsername(ctx context.Context, email, username string) (*models.User, error) {
+	return r.findOne(ctx, "email = ? OR username = ?", strings.ToLower(email), username)
+}
+
+func (r *UserRepository) findOne(ctx context.Context, where string, args ...interface{}) (*models.User, error) {
+	var u models.User
+	err := r.db.QueryRowContext(ctx, r.db.rebind("SELECT id, username, email, password, created_at FROM users WHERE "+where+" LIMIT 1"), args...).
+		Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.CreatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	u.CreatedAt = u.CreatedAt.UTC()
+	return &u, nil
+}
@@ -0,0 +1,245 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+const workflowColumns = "id, user_id, project_id, name, created_at, updated_at"
+
+// WorkflowRepository workflows 表与 workflow_statuses、workflow_transitions、workflow_priorities 子表
+type WorkflowRepository struct {
+	db *DB
+}
+
+// NewWorkflowRepository 创建工作流存储
+func NewWorkflowRepository(db *DB) *WorkflowRepository {
+	return &WorkflowRepository{db: db}
+}
+
+var _ repository.WorkflowRepository = (*WorkflowRepository)(nil)
+
+// scopeKey 工作流的作用范围，默认工作流为空字符串
+func scopeKey(w *models.Workflow) string {
+	if w.ProjectID == nil {
+		return ""
+	}
+	return *w.ProjectID
+}
+
+// taken 判断同一用户下是否已有作用于同一范围的其他工作流；唯一索引兜底并发写入
+func (r *WorkflowRepository) taken(ctx context.Context, tx *sql.Tx, workflow *models.Workflow) error {
+	var n int
+	err := tx.QueryRowContext(ctx, r.db.rebind("SELECT COUNT(*) FROM workflows WHERE user_id = ? AND scope_key = ? AND id <> ?"),
+		workflow.UserID, scopeKey(workflow), workflow.ID).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+// Create 保存新工作流并回填 ID
+func (r *WorkflowRepository) Create(ctx context.Context, workflow *models.Workflow) error {
+	id := repository.NewID()
+	err := r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if err := r.taken(ctx, tx, workflow); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, r.db.rebind(`INSERT INTO workflows (id, user_id, project_id, scope_key, name, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`),
+			id, workflow.UserID, nullString(workflow.ProjectID), scopeKey(workflow), workflow.Name,
+			utc(workflow.CreatedAt), utc(workflow.UpdatedAt))
+		if err != nil {
+			return err
+		}
+		return r.insertChildren(ctx, tx, id, workflow)
+	})
+	if err != nil {
+		return err
+	}
+	workflow.ID = id
+	return nil
+}
+
+// Get 获取属于 userID 的工作流
+func (r *WorkflowRepository) Get(ctx context.Context, userID, id string) (*models.Workflow, error) {
+	workflows, err := r.query(ctx, "WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(workflows) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &workflows[0], nil
+}
+
+// List 返回用户的全部工作流，默认工作流在前，其余按创建时间升序
+func (r *WorkflowRepository) List(ctx context.Context, userID string) ([]models.Workflow, error) {
+	return r.query(ctx, "WHERE user_id = ? ORDER BY CASE WHEN project_id IS NULL THEN 0 ELSE 1 END, created_at, id", userID)
+}
+
+// Update 按 ID 与 UserID 整体覆盖工作流，状态、流转与优先级整体替换
+func (r *WorkflowRepository) Update(ctx context.Context, workflow *models.Workflow) error {
+	return r.db.withTx(ctx, func(tx *sql.Tx) error {
+		if err := r.taken(ctx, tx, workflow); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, r.db.rebind(`UPDATE workflows SET project_id = ?, scope_key = ?, name = ?,
+			created_at = ?, updated_at = ? WHERE id = ? AND user_id = ?`),
+			nullString(workflow.ProjectID), scopeKey(workflow), workflow.Name,
+			utc(workflow.CreatedAt), utc(workflow.UpdatedAt), workflow.ID, workflow.UserID)
+		if err != nil {
+			return err
+		}
+		if err := affected(res); err != nil {
+			return err
+		}
+		for _, table := range []string{"workflow_statuses", "workflow_transitions", "workflow_priorities"} {
+			if _, err := tx.ExecContext(ctx, r.db.rebind("DELETE FROM "+table+" WHERE workflow_id = ?"), workflow.ID); err != nil {
+				return err
+			}
+		}
+		return r.insertChildren(ctx, tx, workflow.ID, workflow)
+	})
+}
+
+// Delete 删除属于 userID 的工作流，子表记录随外键级联删除
+func (r *WorkflowRepository) Delete(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, r.db.rebind("DELETE FROM workflows WHERE id = ? AND user_id = ?"), id, userID)
+	if err != nil {
+		return err
+	}
+	return affected(res)
+}
+
+// insertChildren 按顺序写入状态、流转与优先级
+func (r *WorkflowRepository) insertChildren(ctx context.Context, tx *sql.Tx, workflowID string, workflow *models.Workflow) error {
+	for i, s := range workflow.Statuses {
+		_, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO workflow_statuses (workflow_id, position, name, category, wip_limit) VALUES (?, ?, ?, ?, ?)"),
+			workflowID, i, s.Name, s.Category, s.WIPLimit)
+		if err != nil {
+			return err
+		}
+	}
+	for i, t := range workflow.Transitions {
+		_, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO workflow_transitions (workflow_id, position, from_status, to_status) VALUES (?, ?, ?, ?)"),
+			workflowID, i, t.From, t.To)
+		if err != nil {
+			return err
+		}
+	}
+	for i, p := range workflow.Priorities {
+		if _, err := tx.ExecContext(ctx, r.db.rebind("INSERT INTO workflow_priorities (workflow_id, position, name) VALUES (?, ?, ?)"), workflowID, i, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// query 查询工作流并批量加载状态、流转与优先级
+func (r *WorkflowRepository) query(ctx context.Context, clause string, args ...interface{}) ([]models.Workflow, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT "+workflowColumns+" FROM workflows "+clause), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	workflows := []models.Workflow{}
+	for rows.Next() {
+		var w models.Workflow
+		var project sql.NullString
+		if err := rows.Scan(&w.ID, &w.UserID, &project, &w.Name, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		w.ProjectID = stringPtr(project)
+		w.CreatedAt, w.UpdatedAt = w.CreatedAt.UTC(), w.UpdatedAt.UTC()
+		w.Statuses, w.Transitions, w.Priorities = []models.WorkflowStatus{}, []models.WorkflowTransition{}, []string{}
+		workflows = append(workflows, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(workflows) == 0 {
+		return workflows, nil
+	}
+	index := make(map[string]int, len(workflows))
+	ids := make([]interface{}, 0, len(workflows))
+	for i, w := range workflows {
+		index[w.ID] = i
+		ids = append(ids, w.ID)
+	}
+	if err := r.loadStatuses(ctx, workflows, index, ids); err != nil {
+		return nil, err
+	}
+	if err := r.loadTransitions(ctx, workflows, index, ids); err != nil {
+		return nil, err
+	}
+	if err := r.loadPriorities(ctx, workflows, index, ids); err != nil {
+		return nil, err
+	}
+	return workflows, nil
+}
+
+// loadStatuses 按 position 顺序填充工作流的状态
+func (r *WorkflowRepository) loadStatuses(ctx context.Context, workflows []models.Workflow, index map[string]int, ids []interface{}) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT workflow_id, name, category, wip_limit FROM workflow_statuses WHERE workflow_id IN ("+
+		placeholders(len(ids))+") ORDER BY workflow_id, position"), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var workflowID string
+		var s models.WorkflowStatus
+		if err := rows.Scan(&workflowID, &s.Name, &s.Category, &s.WIPLimit); err != nil {
+			return err
+		}
+		i := index[workflowID]
+		workflows[i].Statuses = append(workflows[i].Statuses, s)
+	}
+	return rows.Err()
+}
+
+// loadTransitions 按 position 顺序填充工作流允许的流转
+func (r *WorkflowRepository) loadTransitions(ctx context.Context, workflows []models.Workflow, index map[string]int, ids []interface{}) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT workflow_id, from_status, to_status FROM workflow_transitions WHERE workflow_id IN ("+
+		placeholders(len(ids))+") ORDER BY workflow_id, position"), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var workflowID string
+		var t models.WorkflowTransition
+		if err := rows.Scan(&workflowID, &t.From, &t.To); err != nil {
+			return err
+		}
+		i := index[workflowID]
+		workflows[i].Transitions = append(workflows[i].Transitions, t)
+	}
+	return rows.Err()
+}
+
+// loadPriorities 按 position 顺序填充工作流的优先级
+func (r *WorkflowRepository) loadPriorities(ctx context.Context, workflows []models.Workflow, index map[string]int, ids []interface{}) error {
+	rows, err := r.db.QueryContext(ctx, r.db.rebind("SELECT workflow_id, name FROM workflow_priorities WHERE workflow_id IN ("+
+		placeholders(len(ids))+") ORDER BY workflow_id, position"), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var workflowID, name string
+		if err := rows.Scan(&workflowID, &name); err != nil {
+			return err
+		}
+		i := index[workflowID]
+		workflows[i].Priorities = append(workflows[i].Priorities, name)
+	}
+	return rows.Err()
+}
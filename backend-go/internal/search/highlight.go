@@ -0,0 +1,85 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	snippetRunes  = 80 // 摘要最大字符数
+	snippetBefore = 20 // 首个命中之前保留的字符数
+)
+
+// highlight 在 text 中标记查询词，返回以首个命中为中心、HTML 转义后的摘要，
+// 命中部分以 <mark> 包裹；没有命中时返回空串
+func highlight(text string, terms []string) string {
+	runes := []rune(text)
+	low := lower(runes)
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		t := []rune(term)
+		word := isWord(t[0])
+		for i := 0; i+len(t) <= len(low); i++ {
+			if !equalRunes(low[i:i+len(t)], t) {
+				continue
+			}
+			// 拉丁词只匹配完整的词，避免 "go" 命中 "good"
+			if word && ((i > 0 && isWord(low[i-1])) || (i+len(t) < len(low) && isWord(low[i+len(t)]))) {
+				continue
+			}
+			for k := i; k < i+len(t); k++ {
+				marked[k] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start := first - snippetBefore
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetRunes
+	if end > len(runes) {
+		end = len(runes)
+		if start = end - snippetRunes; start < 0 {
+			start = 0
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		chunk := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			b.WriteString("<mark>" + chunk + "</mark>")
+		} else {
+			b.WriteString(chunk)
+		}
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
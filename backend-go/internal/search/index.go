@@ -0,0 +1,268 @@
+// Package search 提供任务与报表的全文检索：倒排索引、BM25 排序与高亮摘要。
+// 索引词保存在 repository.SearchRepository 中，与任务和报表位于同一数据库，通过包装 repository 的写操作保持同步，
+// 因此共用同一数据库的各进程（如 cmd/api 与 cmd/grpc，或同一服务的多个副本）立即看到彼此的写入；
+// 升级前已有的数据由 Backfill 补建索引
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// Kind 文档类型
+type Kind string
+
+const (
+	KindTask   Kind = "task"
+	KindReport Kind = "report"
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// field 参与检索的字段，name 与 JSON 字段名一致；boost 为字段权重
+type field struct {
+	name  string
+	boost float64
+	text  string
+}
+
+type docKey struct {
+	kind Kind
+	id   string
+}
+
+// taskFields 任务参与检索的字段，评论内容并入 comments 字段
+func taskFields(t *models.Task) []field {
+	comments := make([]string, 0, len(t.Comments))
+	for _, c := range t.Comments {
+		comments = append(comments, c.Text)
+	}
+	return []field{
+		{"title", 3, t.Title},
+		{"description", 1, t.Description},
+		{"comments", 1, strings.Join(comments, "\n")},
+	}
+}
+
+// reportFields 报表参与检索的字段
+func reportFields(r *models.Report) []field {
+	polished := ""
+	if r.PolishedContent != nil {
+		polished = *r.PolishedContent
+	}
+	return []field{
+		{"title", 2, r.Title},
+		{"content", 1, r.Content},
+		{"polishedContent", 1, polished},
+	}
+}
+
+// document 切分字段并按字段权重累加词频与长度
+func document(kind Kind, id, owner string, fields []field) *repository.SearchDoc {
+	doc := &repository.SearchDoc{Kind: string(kind), ID: id, UserID: owner, Terms: map[string]float64{}}
+	for _, f := range fields {
+		for _, term := range Tokenize(f.text) {
+			doc.Terms[term] += f.boost
+			doc.Length += f.boost
+		}
+	}
+	return doc
+}
+
+// TaskDoc 任务的索引文档
+func TaskDoc(t *models.Task) *repository.SearchDoc {
+	return document(KindTask, t.ID, t.CreatedBy, taskFields(t))
+}
+
+// ReportDoc 报表的索引文档
+func ReportDoc(r *models.Report) *repository.SearchDoc {
+	return document(KindReport, r.ID, r.UserID, reportFields(r))
+}
+
+// Service 在存储的索引上检索，命中的任务与报表从 Tasks 与 Reports 读取标题与高亮内容
+type Service struct {
+	Store   repository.SearchRepository
+	Tasks   repository.TaskRepository
+	Reports repository.ReportRepository
+}
+
+// Query 检索条件
+type Query struct {
+	UserID string
+	Text   string
+	Kind   Kind // 为空时同时检索任务与报表
+	Limit  int  // 0 表示不限制
+}
+
+// Hit 检索结果，Highlights 为各命中字段的高亮摘要
+type Hit struct {
+	Kind       Kind              `json:"type"`
+	ID         string            `json:"id"`
+	Title      string            `json:"title"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// Search 返回包含全部查询词的文档，按 BM25 得分降序；total 为命中总数。BM25 的文档数、平均长度
+// 与文档频率只在用户自己的文档中统计，其他用户的数据不影响排序。索引与文档之间有短暂的不一致时，
+// 已不存在或已移入回收站的文档不出现在结果中，也不计入 total
+func (s *Service) Search(ctx context.Context, q Query) (hits []Hit, total int, err error) {
+	terms := queryTerms(q.Text)
+	if len(terms) == 0 {
+		return []Hit{}, 0, nil
+	}
+	postings, stats, err := s.Store.Postings(ctx, q.UserID, terms)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	type scored struct {
+		key    docKey
+		tf     map[string]float64
+		length float64
+		score  float64
+	}
+	df := map[string]float64{}
+	docs := map[docKey]*scored{}
+	for _, p := range postings {
+		df[p.Term]++
+		key := docKey{Kind(p.Kind), p.ID}
+		m := docs[key]
+		if m == nil {
+			m = &scored{key: key, tf: map[string]float64{}, length: p.Length}
+			docs[key] = m
+		}
+		m.tf[p.Term] = p.TF
+	}
+	n := float64(max(stats.Docs, 1))
+	avg := stats.TotalLength / n
+	if avg <= 0 {
+		avg = 1
+	}
+	var matches []*scored
+	for _, m := range docs {
+		if len(m.tf) < len(terms) || (q.Kind != "" && m.key.kind != q.Kind) {
+			continue
+		}
+		for _, term := range terms {
+			tf := m.tf[term]
+			idf := math.Log(1 + (n-df[term]+0.5)/(df[term]+0.5))
+			m.score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*m.length/avg))
+		}
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if matches[i].key.kind != matches[j].key.kind {
+			return matches[i].key.kind > matches[j].key.kind
+		}
+		return matches[i].key.id < matches[j].key.id
+	})
+
+	total = len(matches)
+	if q.Limit > 0 && q.Limit < len(matches) {
+		matches = matches[:q.Limit]
+	}
+	keys := make([]docKey, len(matches))
+	for i, m := range matches {
+		keys[i] = m.key
+	}
+	found, err := s.load(ctx, q.UserID, keys)
+	if err != nil {
+		return nil, 0, err
+	}
+	hits = make([]Hit, 0, len(matches))
+	for _, m := range matches {
+		d, ok := found[m.key]
+		if !ok {
+			total--
+			continue
+		}
+		hl := map[string]string{}
+		for _, f := range d.fields {
+			if s := highlight(f.text, terms); s != "" {
+				hl[f.name] = s
+			}
+		}
+		hits = append(hits, Hit{
+			Kind:       m.key.kind,
+			ID:         m.key.id,
+			Title:      d.title,
+			Score:      math.Round(m.score*1000) / 1000,
+			Highlights: hl,
+		})
+	}
+	return hits, total, nil
+}
+
+type loaded struct {
+	title  string
+	fields []field
+}
+
+// load 读取命中的任务与报表的当前内容，已不存在或已移入回收站的文档不在结果中
+func (s *Service) load(ctx context.Context, userID string, keys []docKey) (map[docKey]loaded, error) {
+	var taskIDs, reportIDs []string
+	for _, k := range keys {
+		if k.kind == KindTask {
+			taskIDs = append(taskIDs, k.id)
+		} else {
+			reportIDs = append(reportIDs, k.id)
+		}
+	}
+	out := make(map[docKey]loaded, len(keys))
+	if len(taskIDs) > 0 {
+		tasks, err := s.Tasks.List(ctx, repository.TaskFilter{UserID: userID, IDs: taskIDs})
+		if err != nil {
+			return nil, err
+		}
+		for i := range tasks {
+			out[docKey{KindTask, tasks[i].ID}] = loaded{tasks[i].Title, taskFields(&tasks[i])}
+		}
+	}
+	if len(reportIDs) > 0 {
+		reports, err := s.Reports.List(ctx, repository.ReportFilter{UserID: userID, IDs: reportIDs})
+		if err != nil {
+			return nil, err
+		}
+		for i := range reports {
+			out[docKey{KindReport, reports[i].ID}] = loaded{reports[i].Title, reportFields(&reports[i])}
+		}
+	}
+	return out, nil
+}
+
+// Backfill 为尚未索引的任务与报表补建索引，如升级前已有的数据；已索引的文档不被覆盖，
+// 可与正常写入以及其他进程的 Backfill 同时运行。返回检查的文档数
+func (s *Service) Backfill(ctx context.Context) (int, error) {
+	tasks, err := s.Tasks.List(ctx, repository.TaskFilter{})
+	if err != nil {
+		return 0, err
+	}
+	for i := range tasks {
+		if err := s.Store.Insert(ctx, TaskDoc(&tasks[i])); err != nil {
+			return i, err
+		}
+	}
+	reports, err := s.Reports.List(ctx, repository.ReportFilter{})
+	if err != nil {
+		return len(tasks), err
+	}
+	for i := range reports {
+		if err := s.Store.Insert(ctx, ReportDoc(&reports[i])); err != nil {
+			return len(tasks) + i, err
+		}
+	}
+	return len(tasks) + len(reports), nil
+}
@@ -0,0 +1,179 @@
+package search
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+// search 检索并在出错时终止测试
+func search(t *testing.T, s *Service, q Query) ([]Hit, int) {
+	t.Helper()
+	hits, total, err := s.Search(context.Background(), q)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	return hits, total
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewSearchRepository()
+	rawTasks, rawReports := memory.NewTaskRepository(), memory.NewReportRepository()
+	tasks := NewTaskRepository(rawTasks, store)
+	reports := NewReportRepository(rawReports, store)
+	s := &Service{Store: store, Tasks: rawTasks, Reports: rawReports}
+
+	titleMatch := &models.Task{Title: "整理项目文档", CreatedBy: "u1"}
+	commentMatch := &models.Task{Title: "周会", CreatedBy: "u1", Comments: []models.Comment{{Text: "会后补充项目文档"}}}
+	partial := &models.Task{Title: "项目排期", CreatedBy: "u1"}
+	other := &models.Task{Title: "项目文档", CreatedBy: "u2"}
+	for _, task := range []*models.Task{titleMatch, commentMatch, partial, other} {
+		if err := tasks.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	polished := "本周完成了项目文档的评审"
+	rep := &models.Report{UserID: "u1", Title: "周报", Content: "草稿", PolishedContent: &polished}
+	if err := reports.Create(ctx, rep); err != nil {
+		t.Fatalf("Create report failed: %v", err)
+	}
+
+	hits, total := search(t, s, Query{UserID: "u1", Text: "项目文档"})
+	if total != 3 || len(hits) != 3 {
+		t.Fatalf("Expected 3 hits, got %d: %+v", total, hits)
+	}
+	if hits[0].ID != titleMatch.ID || hits[0].Highlights["title"] != "整理<mark>项目文档</mark>" {
+		t.Errorf("Expected title match ranked first, got %+v", hits[0])
+	}
+	for _, h := range hits {
+		if h.ID == commentMatch.ID && h.Highlights["comments"] == "" {
+			t.Errorf("Expected comment highlight, got %+v", h)
+		}
+		if h.ID == rep.ID && (h.Kind != KindReport || h.Highlights["polishedContent"] == "") {
+			t.Errorf("Expected polished content highlight, got %+v", h)
+		}
+	}
+
+	if hits, _ := search(t, s, Query{UserID: "u1", Text: "项目文档", Kind: KindReport}); len(hits) != 1 || hits[0].ID != rep.ID {
+		t.Errorf("Expected only the report, got %+v", hits)
+	}
+	if hits, total := search(t, s, Query{UserID: "u1", Text: "项目文档", Limit: 1}); len(hits) != 1 || total != 3 {
+		t.Errorf("Expected 1 of 3 hits, got %d of %d", len(hits), total)
+	}
+
+	// 另一个进程经同一存储写入，本进程立即可见
+	otherProcess := NewTaskRepository(rawTasks, store)
+	titleMatch.Title = "整理排期"
+	if err := otherProcess.Update(ctx, titleMatch); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := otherProcess.Delete(ctx, "u1", commentMatch.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if hits, _ := search(t, s, Query{UserID: "u1", Text: "项目文档", Kind: KindTask}); len(hits) != 0 {
+		t.Errorf("Expected no task hits after update and delete, got %+v", hits)
+	}
+	if hits, _ := search(t, s, Query{UserID: "u1", Text: "排期"}); len(hits) != 2 {
+		t.Errorf("Expected 2 hits for 排期, got %+v", hits)
+	}
+
+	// 索引中残留的已删除文档不出现在结果中，也不计入总数
+	if err := rawTasks.Delete(ctx, "u1", partial.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if hits, total := search(t, s, Query{UserID: "u1", Text: "排期"}); len(hits) != 1 || total != 1 || hits[0].ID != titleMatch.ID {
+		t.Errorf("Expected only the live task, got %d: %+v", total, hits)
+	}
+
+	if hits, total := search(t, s, Query{UserID: "u1", Text: "  ,. "}); len(hits) != 0 || total != 0 {
+		t.Errorf("Expected no hits for empty query, got %+v", hits)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewSearchRepository()
+	rawTasks, rawReports := memory.NewTaskRepository(), memory.NewReportRepository()
+	s := &Service{Store: store, Tasks: rawTasks, Reports: rawReports}
+
+	// 索引建立之前已有的数据
+	old := &models.Task{Title: "项目文档", CreatedBy: "u1"}
+	trashed := &models.Task{Title: "项目文档", CreatedBy: "u1"}
+	for _, task := range []*models.Task{old, trashed} {
+		if err := rawTasks.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	trashed.DeletedAt = &trashed.CreatedAt
+	if err := rawTasks.Update(ctx, trashed); err != nil {
+		t.Fatalf("Trash failed: %v", err)
+	}
+	if err := rawReports.Create(ctx, &models.Report{UserID: "u1", Title: "文档周报"}); err != nil {
+		t.Fatalf("Create report failed: %v", err)
+	}
+	// 已索引的文档保留较新的内容，不被补建覆盖
+	tasks := NewTaskRepository(rawTasks, store)
+	fresh := &models.Task{Title: "排期", CreatedBy: "u1"}
+	if err := tasks.Create(ctx, fresh); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := store.Put(ctx, TaskDoc(&models.Task{ID: fresh.ID, Title: "最新排期", CreatedBy: "u1"})); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if n, err := s.Backfill(ctx); err != nil || n != 3 {
+		t.Fatalf("Expected 3 documents checked, got %d (%v)", n, err)
+	}
+	if hits, total := search(t, s, Query{UserID: "u1", Text: "文档"}); total != 2 {
+		t.Errorf("Expected the old task and the report, got %+v", hits)
+	}
+	if hits, _ := search(t, s, Query{UserID: "u1", Text: "最新"}); len(hits) != 1 || hits[0].ID != fresh.ID {
+		t.Errorf("Expected the indexed task kept, got %+v", hits)
+	}
+}
+
+func TestSearchStatisticsPerOwner(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewSearchRepository()
+	rawTasks := memory.NewTaskRepository()
+	tasks := NewTaskRepository(rawTasks, store)
+	s := &Service{Store: store, Tasks: rawTasks, Reports: memory.NewReportRepository()}
+	create := func(task *models.Task) {
+		t.Helper()
+		if err := tasks.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	create(&models.Task{Title: "项目文档", CreatedBy: "u1"})
+	create(&models.Task{Title: "项目排期与文档整理", CreatedBy: "u1"})
+	before, _ := search(t, s, Query{UserID: "u1", Text: "文档"})
+
+	// 其他用户的文档不影响文档频率与平均长度，u1 的得分不变
+	var others []string
+	for i := 0; i < 20; i++ {
+		task := &models.Task{Title: "文档", Description: strings.Repeat("很长的描述", 50), CreatedBy: "u2"}
+		create(task)
+		others = append(others, task.ID)
+	}
+	after, _ := search(t, s, Query{UserID: "u1", Text: "文档"})
+	if len(before) != 2 || !reflect.DeepEqual(before, after) {
+		t.Errorf("Expected scores independent of other users, got %+v and %+v", before, after)
+	}
+
+	for _, id := range others {
+		if err := tasks.Delete(ctx, "u2", id); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+	}
+	if hits, _ := search(t, s, Query{UserID: "u2", Text: "文档"}); len(hits) != 0 {
+		t.Errorf("Expected u2 documents removed, got %+v", hits)
+	}
+	if _, stats, _ := store.Postings(ctx, "u2", nil); stats.Docs != 0 {
+		t.Errorf("Expected no u2 documents in the store, got %+v", stats)
+	}
+}
@@ -0,0 +1,124 @@
+package search
+
+import (
+	"context"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// TaskRepository 包装任务存储，写入成功后同步更新存储中的索引；任务已写入，
+// 索引写入失败只记日志：该任务在下次修改时重新索引，新建的任务由下次启动的 Backfill 补建
+type TaskRepository struct {
+	repository.TaskRepository
+	store repository.SearchRepository
+}
+
+// NewTaskRepository 创建带索引同步的任务存储
+func NewTaskRepository(tasks repository.TaskRepository, store repository.SearchRepository) *TaskRepository {
+	return &TaskRepository{TaskRepository: tasks, store: store}
+}
+
+// Create 保存任务并加入索引
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	if err := r.TaskRepository.Create(ctx, task); err != nil {
+		return err
+	}
+	put(ctx, r.store, TaskDoc(task))
+	return nil
+}
+
+// Update 覆盖任务并重新索引，移入回收站的任务移出索引
+func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
+	if err := r.TaskRepository.Update(ctx, task); err != nil {
+		return err
+	}
+	r.sync(ctx, task)
+	return nil
+}
+
+// BulkUpdate 批量覆盖与创建任务并逐个重新索引；部分写入时只索引已生效的任务
+func (r *TaskRepository) BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error {
+	err := r.TaskRepository.BulkUpdate(ctx, tasks, created...)
+	written := repository.Written(err, len(tasks)+len(created))
+	for i := 0; i < written && i < len(tasks); i++ {
+		r.sync(ctx, &tasks[i])
+	}
+	for i := len(tasks); i < written; i++ {
+		put(ctx, r.store, TaskDoc(created[i-len(tasks)]))
+	}
+	return err
+}
+
+// sync 按任务当前状态更新索引，回收站中的任务移出索引
+func (r *TaskRepository) sync(ctx context.Context, task *models.Task) {
+	if task.DeletedAt != nil {
+		remove(ctx, r.store, KindTask, task.ID)
+		return
+	}
+	put(ctx, r.store, TaskDoc(task))
+}
+
+// Delete 永久删除任务并移出索引
+func (r *TaskRepository) Delete(ctx context.Context, userID, id string) error {
+	if err := r.TaskRepository.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+	remove(ctx, r.store, KindTask, id)
+	return nil
+}
+
+// ReportRepository 包装报表存储，写入成功后同步更新存储中的索引，索引写入失败只记日志
+type ReportRepository struct {
+	repository.ReportRepository
+	store repository.SearchRepository
+}
+
+// NewReportRepository 创建带索引同步的报表存储
+func NewReportRepository(reports repository.ReportRepository, store repository.SearchRepository) *ReportRepository {
+	return &ReportRepository{ReportRepository: reports, store: store}
+}
+
+// Create 保存报表并加入索引
+func (r *ReportRepository) Create(ctx context.Context, report *models.Report) error {
+	if err := r.ReportRepository.Create(ctx, report); err != nil {
+		return err
+	}
+	put(ctx, r.store, ReportDoc(report))
+	return nil
+}
+
+// Update 覆盖报表并重新索引，移入回收站的报表移出索引
+func (r *ReportRepository) Update(ctx context.Context, report *models.Report) error {
+	if err := r.ReportRepository.Update(ctx, report); err != nil {
+		return err
+	}
+	if report.DeletedAt != nil {
+		remove(ctx, r.store, KindReport, report.ID)
+		return nil
+	}
+	put(ctx, r.store, ReportDoc(report))
+	return nil
+}
+
+// Delete 永久删除报表并移出索引
+func (r *ReportRepository) Delete(ctx context.Context, userID, id string) error {
+	if err := r.ReportRepository.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+	remove(ctx, r.store, KindReport, id)
+	return nil
+}
+
+func put(ctx context.Context, store repository.SearchRepository, doc *repository.SearchDoc) {
+	if err := store.Put(ctx, doc); err != nil {
+		observability.LogError("Failed to index %s %s: %v", doc.Kind, doc.ID, err)
+	}
+}
+
+func remove(ctx context.Context, store repository.SearchRepository, kind Kind, id string) {
+	if err := store.Remove(ctx, string(kind), id); err != nil {
+		observability.LogError("Failed to remove %s %s from search index: %v", kind, id, err)
+	}
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"unicode"
+)
+
+// isCJK 判断是否为需要按字切分的中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// isWord 判断是否为拉丁等以空白分词的文字或数字
+func isWord(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r)) && !isCJK(r)
+}
+
+// Tokenize 将文本切分为索引词：拉丁文字与数字按词切分并转为小写；
+// 中日韩文字没有空白分隔，连续片段同时产出单字与相邻二元组，
+// 这样无需词典即可匹配任意长度的中文查询
+func Tokenize(text string) []string {
+	return tokens(text, true)
+}
+
+// queryTerms 切分查询：长度不小于 2 的中文片段只取二元组，单字片段取单字
+func queryTerms(query string) []string {
+	return dedupe(tokens(query, false))
+}
+
+func tokens(text string, unigrams bool) []string {
+	runes := []rune(text)
+	var out []string
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case isCJK(r):
+			j := i
+			for j < len(runes) && isCJK(runes[j]) {
+				j++
+			}
+			run := runes[i:j]
+			if len(run) == 1 || unigrams {
+				for _, c := range run {
+					out = append(out, string(c))
+				}
+			}
+			for k := 0; k+1 < len(run); k++ {
+				out = append(out, string(run[k:k+2]))
+			}
+			i = j
+		case isWord(r):
+			j := i
+			for j < len(runes) && isWord(runes[j]) {
+				j++
+			}
+			out = append(out, string(lower(runes[i:j])))
+			i = j
+		default:
+			i++
+		}
+	}
+	return out
+}
+
+// lower 逐字符转小写，保证与原文的字符位置一一对应
+func lower(runes []rune) []rune {
+	out := make([]rune, len(runes))
+	for i, r := range runes {
+		out[i] = unicode.ToLower(r)
+	}
+	return out
+}
+
+func dedupe(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := terms[:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
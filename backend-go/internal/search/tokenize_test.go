@@ -0,0 +1,62 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Hello, World!", []string{"hello", "world"}},
+		{"周报", []string{"周", "报", "周报"}},
+		{"写Go周报v2", []string{"写", "go", "周", "报", "周报", "v2"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"项目文档", []string{"项目", "目文", "文档"}},
+		{"报 API api", []string{"报", "api"}},
+	}
+	for _, tt := range tests {
+		if got := queryTerms(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("queryTerms(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text  string
+		terms []string
+		want  string
+	}{
+		{"完成项目文档", []string{"项目", "目文", "文档"}, "完成<mark>项目文档</mark>"},
+		{"Go is good", []string{"go"}, "<mark>Go</mark> is good"},
+		{"<b>周报</b>", []string{"周报"}, "&lt;b&gt;<mark>周报</mark>&lt;/b&gt;"},
+		{"nothing here", []string{"周报"}, ""},
+	}
+	for _, tt := range tests {
+		if got := highlight(tt.text, tt.terms); got != tt.want {
+			t.Errorf("highlight(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	long := "这是一段很长的前言，用来把关键词推到后面。这是一段很长的前言，用来把关键词推到后面。这里提到周报。然后还有很长很长的结尾内容，一直写下去一直写下去一直写下去一直写下去一直写下去一直写下去一直写下去一直写下去一直写下去一直写下去。"
+	got := highlight(long, []string{"周报"})
+	if []rune(got)[0] != '…' || []rune(got)[len([]rune(got))-1] != '…' {
+		t.Errorf("Expected snippet trimmed on both sides, got %q", got)
+	}
+}
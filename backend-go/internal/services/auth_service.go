@@ -2,136 +2,136 @@ package services
 
 import (
 	"context"
-	"fmt"
+	"errors"
 
+	"github.com/axfinn/todoIng/backend-go/internal/account"
 	"github.com/axfinn/todoIng/backend-go/internal/convert"
-	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/email"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
 	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-// AuthService gRPC 认证服务实现
+// AuthService gRPC 认证服务实现，注册与登录逻辑与 HTTP 接口共用 account 包
 type AuthService struct {
 	pb.UnimplementedAuthServiceServer
-	db *mongo.Database
+	accounts *account.Service
 }
 
 // NewAuthService 创建新的认证服务
-func NewAuthService(db *mongo.Database) *AuthService {
+func NewAuthService(users repository.UserRepository, emailCodes *email.Store) *AuthService {
 	return &AuthService{
-		db: db,
+		accounts: &account.Service{Users: users, EmailCodes: emailCodes},
 	}
 }
 
 // Register 实现用户注册
 func (s *AuthService) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
-	// 验证请求参数
-	if req.Username == "" || req.Email == "" || len(req.Password) < 6 {
-		return nil, status.Error(codes.InvalidArgument, "Invalid username, email, or password")
-	}
-
-	// 检查用户是否已存在
-	users := s.db.Collection("users")
-	err := users.FindOne(ctx, bson.M{
-		"$or": []bson.M{
-			{"email": req.Email},
-			{"username": req.Username},
-		},
-	}).Err()
-
-	if err == nil {
-		return nil, status.Error(codes.AlreadyExists, "User already exists")
-	}
-
-	if err != mongo.ErrNoDocuments {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("Database error: %v", err))
-	}
-
-	// 这里应该添加密码哈希逻辑
-	// 为了示例，我们简化处理
-	user := &models.User{
-		Username: req.Username,
-		Email:    req.Email,
-		Password: req.Password, // 实际应用中需要哈希
-	}
-
-	// 插入用户到数据库
-	result, err := users.InsertOne(ctx, user)
+	session, err := s.accounts.Register(ctx, account.Registration{
+		Username:    req.Username,
+		Email:       req.Email,
+		Password:    req.Password,
+		EmailCode:   req.EmailCode,
+		EmailCodeID: req.EmailCodeId,
+	})
 	if err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("Failed to create user: %v", err))
+		return nil, accountError(err)
 	}
-
-	// 设置用户ID
-	user.ID = result.InsertedID.(string)
-
-	// 转换为 protobuf 响应
-	pbUser := convert.UserToProto(user)
-
 	return &pb.RegisterResponse{
 		Response: &pb.Response{
 			Code:    201,
 			Message: "User created successfully",
 		},
-		User: pbUser,
+		User:  convert.UserToProto(session.User),
+		Token: session.Token,
 	}, nil
 }
 
-// Login 实现用户登录
+// Login 实现用户密码登录
 func (s *AuthService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
-	if req.Email == "" || req.Password == "" {
-		return nil, status.Error(codes.InvalidArgument, "Email and password are required")
+	session, err := s.accounts.Login(ctx, req.Email, req.Password)
+	if err != nil {
+		return nil, accountError(err)
 	}
+	return loginResponse(session), nil
+}
 
-	// 查找用户
-	users := s.db.Collection("users")
-	var user models.User
-	err := users.FindOne(ctx, bson.M{"email": req.Email}).Decode(&user)
+// EmailCodeLogin 实现邮箱验证码登录
+func (s *AuthService) EmailCodeLogin(ctx context.Context, req *pb.EmailCodeLoginRequest) (*pb.LoginResponse, error) {
+	session, err := s.accounts.LoginWithCode(ctx, req.Email, req.CodeId, req.Code)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, status.Error(codes.Unauthenticated, "Invalid email or password")
-		}
-		return nil, status.Error(codes.Internal, fmt.Sprintf("Database error: %v", err))
+		return nil, accountError(err)
 	}
+	return loginResponse(session), nil
+}
 
-	// 这里应该验证密码哈希
-	// 为了示例，我们简化处理
-	if user.Password != req.Password {
-		return nil, status.Error(codes.Unauthenticated, "Invalid email or password")
+// SendLoginEmailCode 发送登录邮箱验证码
+func (s *AuthService) SendLoginEmailCode(ctx context.Context, req *pb.SendLoginEmailCodeRequest) (*pb.SendLoginEmailCodeResponse, error) {
+	id, err := s.accounts.SendLoginCode(ctx, req.Email)
+	if err != nil {
+		return nil, accountError(err)
 	}
-
-	// 生成 JWT token（这里简化处理）
-	token := "jwt_token_here"
-
-	// 转换为 protobuf 响应
-	pbUser := convert.UserToProto(&user)
-
-	return &pb.LoginResponse{
+	return &pb.SendLoginEmailCodeResponse{
 		Response: &pb.Response{
 			Code:    200,
-			Message: "Login successful",
+			Message: "Login verification code sent",
 		},
-		Token: token,
-		User:  pbUser,
+		CodeId: id,
 	}, nil
 }
 
-// EmailCodeLogin 实现邮箱验证码登录
-func (s *AuthService) EmailCodeLogin(ctx context.Context, req *pb.EmailCodeLoginRequest) (*pb.LoginResponse, error) {
-	// 实现邮箱验证码登录逻辑
-	return nil, status.Error(codes.Unimplemented, "Email code login not implemented yet")
+// VerifyToken 验证 JWT token 并返回对应用户
+func (s *AuthService) VerifyToken(ctx context.Context, req *pb.VerifyTokenRequest) (*pb.VerifyTokenResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "Token required")
+	}
+	user, err := s.accounts.Authenticate(ctx, req.Token)
+	if err != nil {
+		return nil, accountError(err)
+	}
+	return &pb.VerifyTokenResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "Token valid",
+		},
+		User: convert.UserToProto(user),
+	}, nil
 }
 
-// SendLoginEmailCode 发送登录邮箱验证码
-func (s *AuthService) SendLoginEmailCode(ctx context.Context, req *pb.SendLoginEmailCodeRequest) (*pb.Response, error) {
-	// 实现发送邮箱验证码逻辑
-	return nil, status.Error(codes.Unimplemented, "Send login email code not implemented yet")
+// loginResponse 将签发的令牌与用户转换为登录响应
+func loginResponse(session *account.Session) *pb.LoginResponse {
+	return &pb.LoginResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "Login successful",
+		},
+		Token: session.Token,
+		User:  convert.UserToProto(session.User),
+	}
 }
 
-// VerifyToken 验证 JWT token
-func (s *AuthService) VerifyToken(ctx context.Context, req *pb.VerifyTokenRequest) (*pb.VerifyTokenResponse, error) {
-	// 实现 token 验证逻辑
-	return nil, status.Error(codes.Unimplemented, "Token verification not implemented yet")
+// accountError 将 account 包的错误转换为 gRPC 状态，消息与 HTTP 接口一致，其余视为存储错误
+func accountError(err error) error {
+	switch {
+	case errors.Is(err, account.ErrRegistrationDisabled):
+		return status.Error(codes.PermissionDenied, "Registration is disabled")
+	case errors.Is(err, account.ErrFields):
+		return status.Error(codes.InvalidArgument, "Invalid fields")
+	case errors.Is(err, account.ErrEmailRequired):
+		return status.Error(codes.InvalidArgument, "Email required")
+	case errors.Is(err, account.ErrEmailCode):
+		return status.Error(codes.InvalidArgument, "Invalid verification code")
+	case errors.Is(err, account.ErrEmailDisabled):
+		return status.Error(codes.FailedPrecondition, "Email verification disabled")
+	case errors.Is(err, account.ErrExists):
+		return status.Error(codes.AlreadyExists, "User already exists")
+	case errors.Is(err, account.ErrNoUser):
+		return status.Error(codes.Unauthenticated, "User does not exist")
+	case errors.Is(err, account.ErrCredentials):
+		return status.Error(codes.Unauthenticated, "Invalid credentials")
+	case errors.Is(err, account.ErrToken):
+		return status.Error(codes.Unauthenticated, "Token invalid")
+	}
+	return storeError(err, "User does not exist")
 }
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/email"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 以下用例在访问数据库之前就会返回，无需 MongoDB
+func TestAuthServiceGuards(t *testing.T) {
+	svc := NewAuthService(nil, email.NewStore(10*time.Minute, 3))
+	ctx := context.Background()
+
+	t.Run("注册已关闭", func(t *testing.T) {
+		t.Setenv("DISABLE_REGISTRATION", "true")
+		_, err := svc.Register(ctx, &pb.RegisterRequest{Username: "u", Email: "u@example.com", Password: "password123"})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied, got %v", err)
+		}
+	})
+
+	t.Run("注册参数无效", func(t *testing.T) {
+		_, err := svc.Register(ctx, &pb.RegisterRequest{Username: "u", Email: "u@example.com", Password: "123"})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("邮箱验证码未启用", func(t *testing.T) {
+		t.Setenv("ENABLE_EMAIL_VERIFICATION", "false")
+		_, err := svc.EmailCodeLogin(ctx, &pb.EmailCodeLoginRequest{Email: "u@example.com", Code: "123456", CodeId: "id"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("Expected FailedPrecondition, got %v", err)
+		}
+		_, err = svc.SendLoginEmailCode(ctx, &pb.SendLoginEmailCodeRequest{Email: "u@example.com"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("Expected FailedPrecondition, got %v", err)
+		}
+	})
+
+	t.Run("无效令牌", func(t *testing.T) {
+		t.Setenv("JWT_SECRET", "test_secret")
+		_, err := svc.VerifyToken(ctx, &pb.VerifyTokenRequest{Token: "invalid_token"})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated, got %v", err)
+		}
+	})
+}
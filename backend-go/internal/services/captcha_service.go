@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/axfinn/todoIng/backend-go/internal/captcha"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CaptchaService gRPC 验证码服务实现
+type CaptchaService struct {
+	pb.UnimplementedCaptchaServiceServer
+	store *captcha.Store
+}
+
+// NewCaptchaService 创建新的验证码服务
+func NewCaptchaService(store *captcha.Store) *CaptchaService {
+	return &CaptchaService{
+		store: store,
+	}
+}
+
+// GetCaptcha 生成验证码图片，未启用验证码时返回占位图片
+func (s *CaptchaService) GetCaptcha(ctx context.Context, req *pb.GetCaptchaRequest) (*pb.GetCaptchaResponse, error) {
+	c := s.store.New()
+	msg := "OK"
+	if c.Disabled {
+		msg = "captcha disabled"
+	}
+	return &pb.GetCaptchaResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: msg,
+		},
+		Captcha: &pb.Captcha{
+			Id:        c.ID,
+			ImageData: c.Image,
+		},
+	}, nil
+}
+
+// VerifyCaptcha 校验验证码，未启用验证码时直接通过
+func (s *CaptchaService) VerifyCaptcha(ctx context.Context, req *pb.VerifyCaptchaRequest) (*pb.Response, error) {
+	bypassed, err := s.store.Check(req.CaptchaId, req.Captcha)
+	switch {
+	case errors.Is(err, captcha.ErrRequired):
+		return nil, status.Error(codes.InvalidArgument, "Captcha and CaptchaId required")
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, "Invalid or expired captcha")
+	case bypassed:
+		return &pb.Response{Code: 200, Message: "Captcha bypassed"}, nil
+	}
+	return &pb.Response{
+		Code:    200,
+		Message: "Captcha verified successfully",
+	}, nil
+}
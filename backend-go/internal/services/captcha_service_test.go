@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/captcha"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCaptchaServiceDisabled(t *testing.T) {
+	t.Setenv("ENABLE_CAPTCHA", "false")
+	svc := NewCaptchaService(captcha.NewStore(time.Minute))
+
+	resp, err := svc.GetCaptcha(context.Background(), &pb.GetCaptchaRequest{})
+	if err != nil {
+		t.Fatalf("GetCaptcha failed: %v", err)
+	}
+	if resp.Captcha.Id != captcha.DisabledID {
+		t.Errorf("Expected placeholder id, got %s", resp.Captcha.Id)
+	}
+
+	if _, err := svc.VerifyCaptcha(context.Background(), &pb.VerifyCaptchaRequest{}); err != nil {
+		t.Errorf("Expected captcha to be bypassed, got %v", err)
+	}
+}
+
+func TestCaptchaServiceVerify(t *testing.T) {
+	t.Setenv("ENABLE_CAPTCHA", "true")
+	store := captcha.NewStore(time.Minute)
+	svc := NewCaptchaService(store)
+
+	resp, err := svc.GetCaptcha(context.Background(), &pb.GetCaptchaRequest{})
+	if err != nil {
+		t.Fatalf("GetCaptcha failed: %v", err)
+	}
+	if resp.Captcha.Id == "" || resp.Captcha.ImageData == "" {
+		t.Fatal("Expected captcha id and image")
+	}
+
+	_, err = svc.VerifyCaptcha(context.Background(), &pb.VerifyCaptchaRequest{CaptchaId: resp.Captcha.Id, Captcha: "wrong"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+
+	id, text := store.Generate(6)
+	if _, err := svc.VerifyCaptcha(context.Background(), &pb.VerifyCaptchaRequest{CaptchaId: id, Captcha: text}); err != nil {
+		t.Errorf("Expected captcha to verify, got %v", err)
+	}
+	// 验证码只能使用一次
+	_, err = svc.VerifyCaptcha(context.Background(), &pb.VerifyCaptchaRequest{CaptchaId: id, Captcha: text})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected reused captcha to fail, got %v", err)
+	}
+}
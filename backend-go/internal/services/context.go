@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/axfinn/todoIng/backend-go/internal/auth"
+	"github.com/axfinn/todoIng/backend-go/internal/taskhistory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type contextKey string
+
+const userKey contextKey = "userId"
+
+// ContextWithUserID 将已认证的用户 ID 写入上下文，同时记为任务历史的操作者
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return taskhistory.WithActor(context.WithValue(ctx, userKey, userID), userID)
+}
+
+// UserIDFromContext 读取认证拦截器写入的用户 ID
+func UserIDFromContext(ctx context.Context) string {
+	if s, ok := ctx.Value(userKey).(string); ok {
+		return s
+	}
+	return ""
+}
+
+// userIDFromContext 获取当前用户 ID，未认证时返回 Unauthenticated
+func userIDFromContext(ctx context.Context) (string, error) {
+	uid := UserIDFromContext(ctx)
+	if uid == "" {
+		return "", status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+	return uid, nil
+}
+
+// authenticate 从 gRPC 元数据的 authorization 字段解析 Bearer 令牌
+func authenticate(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "No token")
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return "", status.Error(codes.Unauthenticated, "No token")
+	}
+	parts := strings.SplitN(values[0], " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return "", status.Error(codes.Unauthenticated, "Invalid auth header")
+	}
+	claims, err := auth.Parse(parts[1])
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, "Token invalid")
+	}
+	return claims.UserID, nil
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// storeError 将存储错误转换为 gRPC 状态，记录不存在时返回 NotFound(msg)，
+// 读取后记录已被其他请求修改时返回 Aborted，客户端应重新读取后重试；
+// 其余错误只记录日志，返回固定的 Internal 信息，不向客户端暴露驱动的错误文本
+func storeError(err error, msg string) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Error(codes.NotFound, msg)
+	case errors.Is(err, repository.ErrVersion):
+		return status.Error(codes.Aborted, "Record was modified concurrently, please retry")
+	}
+	observability.LogError("gRPC store error: %v", err)
+	return status.Error(codes.Internal, "Database error")
+}
+
+// compileQuery 编译查询语言表达式，语法错误返回带位置的 InvalidArgument
+func compileQuery(q string) (query.Cond, error) {
+	if q == "" {
+		return nil, nil
+	}
+	cond, err := query.ParseAndCompile(q, time.Now().UTC())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid query: "+err.Error())
+	}
+	return cond, nil
+}
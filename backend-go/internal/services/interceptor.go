@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"google.golang.org/grpc"
+)
+
+// PublicMethods 无需认证即可调用的 RPC
+var PublicMethods = map[string]bool{
+	pb.AuthService_Register_FullMethodName:           true,
+	pb.AuthService_Login_FullMethodName:              true,
+	pb.AuthService_EmailCodeLogin_FullMethodName:     true,
+	pb.AuthService_SendLoginEmailCode_FullMethodName: true,
+	pb.AuthService_VerifyToken_FullMethodName:        true,
+	pb.CaptchaService_GetCaptcha_FullMethodName:      true,
+	pb.CaptchaService_VerifyCaptcha_FullMethodName:   true,
+	// grpcurl 等工具使用的反射服务
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      true,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": true,
+}
+
+// UnaryAuthInterceptor 校验 Bearer 令牌并将用户 ID 写入上下文，public 中的方法跳过校验
+func UnaryAuthInterceptor(public map[string]bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if public[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		uid, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ContextWithUserID(ctx, uid), req)
+	}
+}
+
+// StreamAuthInterceptor 流式 RPC 版本的认证拦截器
+func StreamAuthInterceptor(public map[string]bool) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if public[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		uid, err := authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authedStream{ServerStream: ss, ctx: ContextWithUserID(ss.Context(), uid)})
+	}
+}
+
+// authedStream 替换流的上下文以携带用户 ID
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/axfinn/todoIng/backend-go/internal/auth"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test_secret")
+	token, err := auth.GenerateJWT("user-1")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	interceptor := UnaryAuthInterceptor(PublicMethods)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return UserIDFromContext(ctx), nil
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		authHeader string
+		wantCode   codes.Code
+		wantUser   string
+	}{
+		{"有效令牌", pb.TaskService_GetTasks_FullMethodName, "Bearer " + token, codes.OK, "user-1"},
+		{"缺少令牌", pb.TaskService_GetTasks_FullMethodName, "", codes.Unauthenticated, ""},
+		{"无效令牌", pb.TaskService_GetTasks_FullMethodName, "Bearer invalid_token", codes.Unauthenticated, ""},
+		{"错误的认证方式", pb.TaskService_GetTasks_FullMethodName, "Basic " + token, codes.Unauthenticated, ""},
+		{"公开方法", pb.AuthService_Login_FullMethodName, "", codes.OK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authHeader != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authHeader))
+			}
+			resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected code %v, got %v", tt.wantCode, err)
+			}
+			if err == nil && resp.(string) != tt.wantUser {
+				t.Errorf("Expected user %q, got %q", tt.wantUser, resp)
+			}
+		})
+	}
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test_secret")
+	token, _ := auth.GenerateJWT("user-2")
+	interceptor := StreamAuthInterceptor(PublicMethods)
+
+	var got string
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		got = UserIDFromContext(ss.Context())
+		return nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	if err := interceptor(nil, &fakeStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/todoing.api.v1.TaskService/Watch"}, handler); err != nil {
+		t.Fatalf("Expected stream to be authorized, got %v", err)
+	}
+	if got != "user-2" {
+		t.Errorf("Expected user-2, got %q", got)
+	}
+
+	err := interceptor(nil, &fakeStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/todoing.api.v1.TaskService/Watch"}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated, got %v", err)
+	}
+}
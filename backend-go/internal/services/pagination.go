@@ -0,0 +1,46 @@
+package services
+
+import (
+	"time"
+
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// pageParams 解析分页参数，limit 为 0 表示不分页
+func pageParams(p *pb.PaginationRequest) (page, limit int) {
+	page = 1
+	if p == nil {
+		return page, 0
+	}
+	if p.Page > 1 {
+		page = int(p.Page)
+	}
+	if p.Limit > 0 {
+		limit = int(p.Limit)
+	}
+	return page, limit
+}
+
+// paginationResponse 构造分页响应
+func paginationResponse(page, limit, total int) *pb.PaginationResponse {
+	totalPages := 1
+	if limit > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	return &pb.PaginationResponse{
+		Page:       int32(page),
+		Limit:      int32(limit),
+		Total:      int32(total),
+		TotalPages: int32(totalPages),
+	}
+}
+
+// timestampPtr 将可选的 Timestamp 转换为时间指针，未设置时返回 nil
+func timestampPtr(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/convert"
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/report"
+	"github.com/axfinn/todoIng/backend-go/internal/reportgen"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/trash"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReportService gRPC 报表服务实现，报表的生成与关联任务的读取由 reportgen 完成，与 /api 处理器共用
+type ReportService struct {
+	pb.UnimplementedReportServiceServer
+	reports repository.ReportRepository
+	gen     *reportgen.Service
+}
+
+// NewReportService 创建新的报表服务，projects 用于生成限定在项目内的报表，workflows 用于按状态类别统计，
+// history 用于生成任务活动时间线
+func NewReportService(reports repository.ReportRepository, tasks repository.TaskRepository, projects repository.ProjectRepository, workflows repository.WorkflowRepository, history repository.HistoryRepository) *ReportService {
+	return &ReportService{
+		reports: reports,
+		gen:     &reportgen.Service{Reports: reports, Tasks: tasks, Projects: projects, Workflows: workflows, History: history},
+	}
+}
+
+// GenerateReport 统计周期内创建的任务并生成报表
+func (s *ReportService) GenerateReport(ctx context.Context, req *pb.GenerateReportRequest) (*pb.GenerateReportResponse, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reportType := convert.ProtoToReportType(req.Type)
+	if req.Type == pb.ReportType_REPORT_TYPE_UNSPECIFIED || !report.ValidType(reportType) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid type")
+	}
+	if req.StartDate == nil || req.EndDate == nil {
+		return nil, status.Error(codes.InvalidArgument, "Please provide type, startDate, and endDate")
+	}
+	start, end := req.StartDate.AsTime(), req.EndDate.AsTime()
+	if end.Before(start) {
+		return nil, status.Error(codes.InvalidArgument, "endDate must not be before startDate")
+	}
+	period := req.Period
+	if period == "" {
+		period = start.Format("2006-01-02")
+		if reportType != "daily" {
+			period += " - " + end.Format("2006-01-02")
+		}
+	}
+
+	cond, err := compileQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	rep, tasks, err := s.gen.Generate(ctx, uid, reportgen.Input{
+		Type:      reportType,
+		Period:    period,
+		Title:     req.Title,
+		Start:     start,
+		End:       end,
+		Query:     cond,
+		ProjectID: req.ProjectId,
+	})
+	if errors.Is(err, reportgen.ErrProject) {
+		return nil, status.Error(codes.InvalidArgument, "Project not found")
+	}
+	if err != nil {
+		return nil, storeError(err, "Report not found")
+	}
+
+	pbReport := convert.ReportToProto(rep)
+	for i := range tasks {
+		pbReport.Tasks = append(pbReport.Tasks, convert.TaskToProto(&tasks[i]))
+	}
+	return &pb.GenerateReportResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "Report generated successfully",
+		},
+		Report: pbReport,
+	}, nil
+}
+
+// GetReports 获取当前用户的报表列表，按创建时间倒序排列
+func (s *ReportService) GetReports(ctx context.Context, req *pb.GetReportsRequest) (*pb.GetReportsResponse, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter := repository.ReportFilter{UserID: uid, ProjectID: req.ProjectId}
+	if req.Type != pb.ReportType_REPORT_TYPE_UNSPECIFIED {
+		reportType := convert.ProtoToReportType(req.Type)
+		if convert.ReportTypeToProto(reportType) != req.Type {
+			return nil, status.Error(codes.InvalidArgument, "Invalid type")
+		}
+		filter.Type = reportType
+	}
+
+	total, err := s.reports.Count(ctx, filter)
+	if err != nil {
+		return nil, storeError(err, "Report not found")
+	}
+	page, limit := pageParams(req.Pagination)
+	if limit > 0 {
+		filter.Skip, filter.Limit = (page-1)*limit, limit
+	}
+	list, err := s.reports.List(ctx, filter)
+	if err != nil {
+		return nil, storeError(err, "Report not found")
+	}
+	reports := make([]*pb.Report, 0, len(list))
+	for i := range list {
+		reports = append(reports, convert.ReportToProto(&list[i]))
+	}
+
+	return &pb.GetReportsResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "OK",
+		},
+		Reports:    reports,
+		Pagination: paginationResponse(page, limit, int(total)),
+	}, nil
+}
+
+// GetReport 获取报表详情，并填充关联的任务
+func (s *ReportService) GetReport(ctx context.Context, req *pb.GetReportRequest) (*pb.GetReportResponse, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep, err := s.findReport(ctx, uid, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.gen.Populate(ctx, uid, rep)
+	if err != nil {
+		return nil, storeError(err, "Task not found")
+	}
+	pbReport := convert.ReportToProto(rep)
+	for i := range tasks {
+		pbReport.Tasks = append(pbReport.Tasks, convert.TaskToProto(&tasks[i]))
+	}
+
+	return &pb.GetReportResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "OK",
+		},
+		Report: pbReport,
+	}, nil
+}
+
+// DeleteReport 将报表移入回收站
+func (s *ReportService) DeleteReport(ctx context.Context, req *pb.DeleteReportRequest) (*pb.Response, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep, err := s.reports.Get(ctx, uid, req.Id)
+	if err != nil {
+		return nil, storeError(err, "Report not found")
+	}
+	if err := trash.TrashReport(ctx, s.reports, rep, time.Now()); err != nil {
+		return nil, storeError(err, "Report not found")
+	}
+
+	return &pb.Response{
+		Code:    200,
+		Message: "Report removed",
+	}, nil
+}
+
+// ExportReport 导出报表正文
+func (s *ReportService) ExportReport(ctx context.Context, req *pb.ExportReportRequest) (*pb.ExportReportResponse, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep, err := s.findReport(ctx, uid, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	data, filename, contentType := report.Export(rep, req.Format)
+
+	return &pb.ExportReportResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "OK",
+		},
+		Data:        data,
+		Filename:    filename,
+		ContentType: contentType,
+	}, nil
+}
+
+// findReport 查找属于当前用户的报表
+func (s *ReportService) findReport(ctx context.Context, uid, id string) (*models.Report, error) {
+	rep, err := s.reports.Get(ctx, uid, id)
+	if err != nil {
+		return nil, storeError(err, "Report not found")
+	}
+	return rep, nil
+}
@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/convert"
+	"github.com/axfinn/todoIng/backend-go/internal/depgraph"
+	"github.com/axfinn/todoIng/backend-go/internal/query"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/taskedit"
+	"github.com/axfinn/todoIng/backend-go/internal/tasklabel"
+	"github.com/axfinn/todoIng/backend-go/internal/taskproject"
+	"github.com/axfinn/todoIng/backend-go/internal/tasktree"
+	"github.com/axfinn/todoIng/backend-go/internal/trash"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TaskService gRPC 任务服务实现，创建与修改由 taskedit 完成，与 /api 处理器共用同一套校验
+type TaskService struct {
+	pb.UnimplementedTaskServiceServer
+	tasks     repository.TaskRepository
+	workflows repository.WorkflowRepository
+	edit      *taskedit.Service
+}
+
+// NewTaskService 创建新的任务服务，labels 用于解析任务引用的标签，projects 用于校验任务所属项目，
+// workflows 提供任务可用的状态、流转与优先级
+func NewTaskService(tasks repository.TaskRepository, labels repository.LabelRepository, projects repository.ProjectRepository, workflows repository.WorkflowRepository) *TaskService {
+	return &TaskService{
+		tasks:     tasks,
+		workflows: workflows,
+		edit:      &taskedit.Service{Tasks: tasks, Labels: labels, Projects: projects, Workflows: workflows},
+	}
+}
+
+// resolveStatus 校验 protobuf 状态；name 非空时优先使用名称，由工作流校验，均未指定时返回 def
+func resolveStatus(s pb.TaskStatus, name, def string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	if s == pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		return def, nil
+	}
+	name = convert.TaskStatusName(s)
+	if name == "" {
+		return "", status.Error(codes.InvalidArgument, "Invalid status")
+	}
+	return name, nil
+}
+
+// resolvePriority 校验 protobuf 优先级；name 非空时优先使用名称，由工作流校验，均未指定时返回 def
+func resolvePriority(p pb.TaskPriority, name, def string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	if p == pb.TaskPriority_TASK_PRIORITY_UNSPECIFIED {
+		return def, nil
+	}
+	name = convert.TaskPriorityName(p)
+	if name == "" {
+		return "", status.Error(codes.InvalidArgument, "Invalid priority")
+	}
+	return name, nil
+}
+
+// CreateTask 创建任务
+func (s *TaskService) CreateTask(ctx context.Context, req *pb.CreateTaskRequest) (*pb.CreateTaskResponse, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskStatus, err := resolveStatus(req.Status, req.StatusName, "")
+	if err != nil {
+		return nil, err
+	}
+	priority, err := resolvePriority(req.Priority, req.PriorityName, "")
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.edit.Create(ctx, uid, taskedit.Input{
+		Title:         req.Title,
+		Description:   req.Description,
+		Status:        taskStatus,
+		Priority:      priority,
+		Assignee:      &req.Assignee,
+		Deadline:      timestampPtr(req.DueDate),
+		ScheduledDate: timestampPtr(req.ScheduledDate),
+		ParentID:      &req.ParentId,
+		BlockedBy:     req.BlockedBy,
+		Recurrence:    convert.ProtoToRecurrence(req.Recurrence),
+		Labels:        req.Labels,
+		ProjectID:     &req.ProjectId,
+		Comments:      req.Comments,
+	}, time.Now())
+	if err != nil {
+		return nil, editError(err)
+	}
+
+	return &pb.CreateTaskResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "Task created successfully",
+		},
+		Task: convert.TaskToProto(task),
+	}, nil
+}
+
+// GetTasks 获取当前用户的任务列表，支持过滤、排序以及页码或游标分页
+func (s *TaskService) GetTasks(ctx context.Context, req *pb.GetTasksRequest) (*pb.GetTasksResponse, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter := repository.TaskFilter{
+		UserID:        uid,
+		Assignee:      req.Assignee,
+		DeadlineFrom:  timestampPtr(req.DueDateFrom),
+		DeadlineTo:    timestampPtr(req.DueDateTo),
+		ScheduledFrom: timestampPtr(req.ScheduledFrom),
+		ScheduledTo:   timestampPtr(req.ScheduledTo),
+		CreatedFrom:   timestampPtr(req.CreatedFrom),
+		CreatedTo:     timestampPtr(req.CreatedTo),
+		UpdatedFrom:   timestampPtr(req.UpdatedFrom),
+		UpdatedTo:     timestampPtr(req.UpdatedTo),
+	}
+	if req.ParentId != "" {
+		filter.ParentIDs = []string{req.ParentId}
+	}
+	if len(req.Labels) > 0 {
+		filter.Labels = req.Labels
+	}
+	if filter.Status, err = resolveStatus(req.Status, "", ""); err != nil {
+		return nil, err
+	}
+	if filter.Priority, err = resolvePriority(req.Priority, "", ""); err != nil {
+		return nil, err
+	}
+	if filter.Sort, err = repository.ParseTaskSort(req.Sort); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid sort")
+	}
+	if filter.Query, err = compileQuery(req.Query); err != nil {
+		return nil, err
+	}
+	switch req.ProjectId {
+	case "":
+	case "none":
+		noProject := query.Match{Field: query.FieldProject, Op: query.OpNull}
+		if filter.Query == nil {
+			filter.Query = noProject
+		} else {
+			filter.Query = query.And{noProject, filter.Query}
+		}
+	default:
+		filter.ProjectIDs = []string{req.ProjectId}
+	}
+
+	total, err := s.tasks.Count(ctx, filter)
+	if err != nil {
+		return nil, storeError(err, "Task not found")
+	}
+	page, limit := pageParams(req.Pagination)
+	if cursor := req.Pagination.GetCursor(); cursor != "" {
+		if filter.After, err = filter.Sort.DecodeCursor(cursor); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid cursor")
+		}
+	} else if limit > 0 {
+		filter.Skip = (page - 1) * limit
+	}
+	filter.Limit = limit
+	list, next, err := repository.ListTaskPage(ctx, s.tasks, filter)
+	if err != nil {
+		return nil, storeError(err, "Task not found")
+	}
+	tasks := make([]*pb.Task, 0, len(list))
+	for i := range list {
+		tasks = append(tasks, convert.TaskToProto(&list[i]))
+	}
+	pagination := paginationResponse(page, limit, int(total))
+	if next != nil {
+		pagination.NextCursor = filter.Sort.EncodeCursor(next)
+	}
+
+	return &pb.GetTasksResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "OK",
+		},
+		Tasks:      tasks,
+		Pagination: pagination,
+	}, nil
+}
+
+// GetTask 获取任务详情，包含嵌套的子任务与汇总的完成度
+func (s *TaskService) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.GetTaskResponse, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.tasks.Get(ctx, uid, req.Id)
+	if err != nil {
+		return nil, storeError(err, "Task not found")
+	}
+	flows, err := workflow.Load(ctx, s.workflows, uid)
+	if err != nil {
+		return nil, storeError(err, "Workflow not found")
+	}
+	node, err := tasktree.Load(ctx, s.tasks, flows, task)
+	if err != nil {
+		return nil, storeError(err, "Task not found")
+	}
+
+	return &pb.GetTaskResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "OK",
+		},
+		Task: treeToProto(node),
+	}, nil
+}
+
+// treeToProto 转换子任务树
+func treeToProto(n *tasktree.Node) *pb.Task {
+	t := convert.TaskToProto(&n.Task)
+	t.Progress = int32(n.Progress)
+	for _, c := range n.Children {
+		t.Subtasks = append(t.Subtasks, treeToProto(c))
+	}
+	return t
+}
+
+// editError 将创建与修改任务的错误转换为 gRPC 状态：请求不合法返回 InvalidArgument，
+// 与父任务、依赖、项目或工作流冲突返回 FailedPrecondition，其余按 storeError 处理
+func editError(err error) error {
+	var (
+		flowErr    *taskedit.WorkflowError
+		blocked    *taskedit.BlockedError
+		invalidRec *taskedit.RecurrenceError
+		cycle      *depgraph.CycleError
+		unknown    *tasklabel.UnknownError
+	)
+	switch {
+	case errors.Is(err, taskedit.ErrTitle):
+		return status.Error(codes.InvalidArgument, "Title is required")
+	case errors.Is(err, taskedit.ErrParent):
+		return status.Error(codes.InvalidArgument, "Parent task not found")
+	case errors.Is(err, tasktree.ErrCycle):
+		return status.Error(codes.FailedPrecondition, "Task cannot be moved under itself or one of its subtasks")
+	case errors.Is(err, taskedit.ErrProject):
+		return status.Error(codes.InvalidArgument, "Project not found")
+	case errors.Is(err, taskproject.ErrArchived):
+		return status.Error(codes.FailedPrecondition, "Project is archived")
+	case errors.As(err, &flowErr):
+		return workflowError(flowErr)
+	case errors.As(err, &cycle):
+		return status.Error(codes.FailedPrecondition, cycle.Error())
+	case errors.Is(err, depgraph.ErrMissing):
+		return status.Error(codes.InvalidArgument, "Blocking task not found")
+	case errors.As(err, &blocked):
+		return status.Errorf(codes.FailedPrecondition, "Task is blocked by %d unfinished task(s)", len(blocked.Open))
+	case errors.As(err, &invalidRec):
+		return status.Error(codes.InvalidArgument, invalidRec.Error())
+	case errors.As(err, &unknown):
+		return status.Errorf(codes.InvalidArgument, "Label not found: %s", unknown.Name)
+	}
+	return storeError(err, "Task not found")
+}
+
+// workflowError 将状态或优先级不符合工作流的错误转换为 gRPC 状态，流转不被允许或超过 WIP 上限时返回 FailedPrecondition
+func workflowError(err *taskedit.WorkflowError) error {
+	switch {
+	case errors.Is(err, workflow.ErrStatus):
+		return status.Error(codes.InvalidArgument, "Invalid status")
+	case errors.Is(err, workflow.ErrPriority):
+		return status.Error(codes.InvalidArgument, "Invalid priority")
+	case errors.Is(err, workflow.ErrTransition):
+		return status.Errorf(codes.FailedPrecondition, "Status transition not allowed: %s -> %s", err.From, err.To)
+	}
+	return status.Errorf(codes.FailedPrecondition, "WIP limit reached: %s", err.To)
+}
+
+// maskFields UpdateTaskRequest 中可以列在 update_mask 中的字段
+var maskFields = map[string]bool{
+	"title": true, "description": true, "status": true, "status_name": true, "priority": true, "priority_name": true,
+	"due_date": true, "assignee": true, "scheduled_date": true, "blocked_by": true, "recurrence": true,
+	"labels": true, "project_id": true,
+}
+
+// updateInput 将请求中要修改的字段写入 in：设置了 update_mask 时写入其中列出的字段，为空的字段被清空，
+// 否则只写入非空的字段；clear_labels 与 clear_project 在未同时提供新值时清空对应字段
+func updateInput(in *taskedit.Input, req *pb.UpdateTaskRequest) error {
+	paths := map[string]bool{}
+	for _, p := range req.GetUpdateMask().GetPaths() {
+		if !maskFields[p] {
+			return status.Errorf(codes.InvalidArgument, "Invalid update_mask path: %s", p)
+		}
+		paths[p] = true
+	}
+	set := func(field string, present bool) bool {
+		if len(paths) == 0 {
+			return present
+		}
+		return paths[field]
+	}
+	taskStatus, err := resolveStatus(req.Status, req.StatusName, "")
+	if err != nil {
+		return err
+	}
+	priority, err := resolvePriority(req.Priority, req.PriorityName, "")
+	if err != nil {
+		return err
+	}
+	if set("title", req.Title != "") {
+		in.Title = req.Title
+	}
+	if set("description", req.Description != "") {
+		in.Description = req.Description
+	}
+	if set("status", taskStatus != "") || paths["status_name"] {
+		in.Status = taskStatus
+	}
+	if set("priority", priority != "") || paths["priority_name"] {
+		in.Priority = priority
+	}
+	if set("due_date", req.DueDate != nil) {
+		in.Deadline = timestampPtr(req.DueDate)
+	}
+	if set("assignee", req.Assignee != "") {
+		in.Assignee = &req.Assignee
+	}
+	if set("scheduled_date", req.ScheduledDate != nil) {
+		in.ScheduledDate = timestampPtr(req.ScheduledDate)
+	}
+	if set("blocked_by", len(req.BlockedBy) > 0) {
+		in.BlockedBy = req.BlockedBy
+	}
+	if set("recurrence", req.Recurrence != nil) {
+		in.Recurrence = convert.ProtoToRecurrence(req.Recurrence)
+	}
+	switch {
+	case set("labels", len(req.Labels) > 0):
+		in.Labels = req.Labels
+	case req.ClearLabels:
+		in.Labels = nil
+	}
+	switch {
+	case set("project_id", req.ProjectId != ""):
+		in.ProjectID = &req.ProjectId
+	case req.ClearProject:
+		in.ProjectID = nil
+	}
+	in.Comments = req.Comments
+	return nil
+}
+
+// UpdateTask 更新任务：设置了 update_mask 时只修改其中列出的字段，列出的空字段被清空；
+// 未设置时修改请求中所有非空的字段，其余字段保持不变。version 非 0 且与当前版本不符时返回 Aborted；
+// 仍有未完成的阻塞任务时除非 force 否则不能改为完成
+func (s *TaskService) UpdateTask(ctx context.Context, req *pb.UpdateTaskRequest) (*pb.UpdateTaskResponse, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.tasks.Get(ctx, uid, req.Id)
+	if err != nil {
+		return nil, storeError(err, "Task not found")
+	}
+	if req.Version != 0 && req.Version != task.Version {
+		return nil, storeError(repository.ErrVersion, "Task not found")
+	}
+	in := taskedit.Editable(task)
+	if err := updateInput(&in, req); err != nil {
+		return nil, err
+	}
+	next, err := s.edit.Replace(ctx, uid, task, in, taskedit.Options{Cascade: req.Cascade, Force: req.Force}, time.Now())
+	if err != nil {
+		return nil, editError(err)
+	}
+	resp := &pb.UpdateTaskResponse{
+		Response: &pb.Response{
+			Code:    200,
+			Message: "Task updated successfully",
+		},
+		Task: convert.TaskToProto(task),
+	}
+	if next != nil {
+		resp.Next = convert.TaskToProto(next)
+	}
+	return resp, nil
+}
+
+// DeleteTask 将任务及其全部子任务移入回收站，version 非 0 且与当前版本不符时返回 Aborted
+func (s *TaskService) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.Response, error) {
+	uid, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.tasks.Get(ctx, uid, req.Id)
+	if err != nil {
+		return nil, storeError(err, "Task not found")
+	}
+	if req.Version != 0 && req.Version != task.Version {
+		return nil, storeError(repository.ErrVersion, "Task not found")
+	}
+	if _, err := trash.TrashTask(ctx, s.tasks, task, time.Now()); err != nil {
+		return nil, storeError(err, "Task not found")
+	}
+
+	return &pb.Response{
+		Code:    200,
+		Message: "Task removed",
+	}, nil
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+	pb "github.com/axfinn/todoIng/backend-go/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGetTasksPagination(t *testing.T) {
+	tasks := memory.NewTaskRepository()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, title := range []string{"c", "a", "e", "b", "d"} {
+		created := base.Add(time.Duration(i) * time.Hour)
+		task := &models.Task{Title: title, Status: "To Do", Priority: "Medium", CreatedBy: "u1", CreatedAt: created, UpdatedAt: created}
+		if err := tasks.Create(context.Background(), task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	svc := NewTaskService(tasks, memory.NewLabelRepository(), memory.NewProjectRepository(), nil)
+	ctx := ContextWithUserID(context.Background(), "u1")
+
+	var titles []string
+	req := &pb.GetTasksRequest{Sort: "title", Pagination: &pb.PaginationRequest{Limit: 2}}
+	for {
+		resp, err := svc.GetTasks(ctx, req)
+		if err != nil {
+			t.Fatalf("GetTasks failed: %v", err)
+		}
+		if resp.Pagination.Total != 5 || resp.Pagination.TotalPages != 3 {
+			t.Errorf("Unexpected pagination %+v", resp.Pagination)
+		}
+		for _, task := range resp.Tasks {
+			titles = append(titles, task.Title)
+		}
+		if resp.Pagination.NextCursor == "" {
+			break
+		}
+		req.Pagination.Cursor = resp.Pagination.NextCursor
+	}
+	if got := len(titles); got != 5 || titles[0] != "a" || titles[4] != "e" {
+		t.Errorf("Expected titles a..e, got %v", titles)
+	}
+
+	// 查询语言
+	resp, err := svc.GetTasks(ctx, &pb.GetTasksRequest{Sort: "-title", Query: "title:a OR title:b"})
+	if err != nil || len(resp.Tasks) != 2 || resp.Tasks[0].Title != "b" || resp.Pagination.Total != 2 {
+		t.Errorf("Unexpected query result %v (%v)", resp, err)
+	}
+	if _, err := svc.GetTasks(ctx, &pb.GetTasksRequest{Query: "title:"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for bad query, got %v", err)
+	}
+
+	// 页码分页同样返回游标，最后一页不返回
+	resp, err = svc.GetTasks(ctx, &pb.GetTasksRequest{Pagination: &pb.PaginationRequest{Page: 3, Limit: 2}})
+	if err != nil || len(resp.Tasks) != 1 || resp.Tasks[0].Title != "c" || resp.Pagination.NextCursor != "" {
+		t.Errorf("Unexpected last page %v (%v)", resp, err)
+	}
+
+	resp, err = svc.GetTasks(ctx, &pb.GetTasksRequest{
+		CreatedFrom: timestamppb.New(base.Add(time.Hour)),
+		CreatedTo:   timestamppb.New(base.Add(2 * time.Hour)),
+	})
+	if err != nil || len(resp.Tasks) != 2 || resp.Pagination.Total != 2 {
+		t.Errorf("Expected 2 tasks in created range, got %v (%v)", resp, err)
+	}
+
+	invalid := []*pb.GetTasksRequest{
+		{Sort: "priority"},
+		{Pagination: &pb.PaginationRequest{Limit: 2, Cursor: "bogus"}},
+		// 游标与排序方式不一致
+		{Sort: "-title", Pagination: &pb.PaginationRequest{Limit: 2, Cursor: req.Pagination.Cursor}},
+	}
+	for _, r := range invalid {
+		if _, err := svc.GetTasks(ctx, r); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v, got %v", r, err)
+		}
+	}
+}
+
+func TestTaskServiceSubtasks(t *testing.T) {
+	svc := NewTaskService(memory.NewTaskRepository(), memory.NewLabelRepository(), memory.NewProjectRepository(), nil)
+	ctx := ContextWithUserID(context.Background(), "u1")
+
+	parent, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "parent"})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	child, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "child", ParentId: parent.Task.Id})
+	if err != nil || child.Task.ParentId != parent.Task.Id {
+		t.Fatalf("Expected child of %s, got %v (%v)", parent.Task.Id, child, err)
+	}
+	if _, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "orphan", ParentId: "missing"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for missing parent, got %v", err)
+	}
+	list, err := svc.GetTasks(ctx, &pb.GetTasksRequest{ParentId: parent.Task.Id})
+	if err != nil || len(list.Tasks) != 1 || list.Tasks[0].Id != child.Task.Id {
+		t.Errorf("Expected only the child for parent filter, got %v (%v)", list, err)
+	}
+
+	if _, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: parent.Task.Id, Status: pb.TaskStatus_TASK_STATUS_DONE, Cascade: true}); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	resp, err := svc.GetTask(ctx, &pb.GetTaskRequest{Id: parent.Task.Id})
+	if err != nil || len(resp.Task.Subtasks) != 1 || resp.Task.Progress != 100 ||
+		resp.Task.Subtasks[0].Status != pb.TaskStatus_TASK_STATUS_DONE {
+		t.Errorf("Unexpected tree %v (%v)", resp, err)
+	}
+
+	if _, err := svc.DeleteTask(ctx, &pb.DeleteTaskRequest{Id: parent.Task.Id}); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := svc.GetTask(ctx, &pb.GetTaskRequest{Id: child.Task.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected subtask to be deleted, got %v", err)
+	}
+}
+
+func TestTaskServiceDependencies(t *testing.T) {
+	svc := NewTaskService(memory.NewTaskRepository(), memory.NewLabelRepository(), memory.NewProjectRepository(), nil)
+	ctx := ContextWithUserID(context.Background(), "u1")
+
+	design, _ := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "design"})
+	build, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "build", BlockedBy: []string{design.Task.Id}})
+	if err != nil || len(build.Task.BlockedBy) != 1 {
+		t.Fatalf("Expected build blocked by design, got %v (%v)", build, err)
+	}
+	if _, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "x", BlockedBy: []string{"missing"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for missing blocker, got %v", err)
+	}
+	if _, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: design.Task.Id, BlockedBy: []string{build.Task.Id}}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for cycle, got %v", err)
+	}
+
+	done := pb.TaskStatus_TASK_STATUS_DONE
+	if _, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: build.Task.Id, Status: done}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for blocked task, got %v", err)
+	}
+	if _, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: build.Task.Id, Status: done, Force: true}); err != nil {
+		t.Errorf("Forced completion failed: %v", err)
+	}
+
+	if _, err := svc.DeleteTask(ctx, &pb.DeleteTaskRequest{Id: design.Task.Id}); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := svc.GetTask(ctx, &pb.GetTaskRequest{Id: design.Task.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected trashed task to be hidden, got %v", err)
+	}
+	// 回收站中的阻塞任务保留在依赖列表中，恢复后依赖随之恢复
+	resp, err := svc.GetTask(ctx, &pb.GetTaskRequest{Id: build.Task.Id})
+	if err != nil || len(resp.Task.BlockedBy) != 1 {
+		t.Errorf("Expected trashed blocker to stay linked, got %v (%v)", resp, err)
+	}
+}
+
+func TestTaskServiceRecurrence(t *testing.T) {
+	svc := NewTaskService(memory.NewTaskRepository(), memory.NewLabelRepository(), memory.NewProjectRepository(), nil)
+	ctx := ContextWithUserID(context.Background(), "u1")
+	due := timestamppb.New(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))
+
+	if _, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "x", Recurrence: &pb.Recurrence{Rule: "FREQ=MONTHLY"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without dates, got %v", err)
+	}
+	created, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "月结", DueDate: due, Recurrence: &pb.Recurrence{Rule: "freq=monthly"}})
+	if err != nil || created.Task.Recurrence.GetRule() != "FREQ=MONTHLY" {
+		t.Fatalf("CreateTask failed: %v (%v)", created, err)
+	}
+
+	resp, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: created.Task.Id, Status: pb.TaskStatus_TASK_STATUS_DONE})
+	if err != nil || resp.Next == nil || resp.Task.Recurrence != nil {
+		t.Fatalf("Expected next instance, got %v (%v)", resp, err)
+	}
+	if got := resp.Next.DueDate.AsTime(); !got.Equal(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected next due date 2024-03-31, got %v", got)
+	}
+
+	cleared, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: resp.Next.Id, Recurrence: &pb.Recurrence{}})
+	if err != nil || cleared.Task.Recurrence != nil {
+		t.Errorf("Expected recurrence to be cleared, got %v (%v)", cleared, err)
+	}
+}
+
+func TestTaskServiceLabels(t *testing.T) {
+	labels := memory.NewLabelRepository()
+	svc := NewTaskService(memory.NewTaskRepository(), labels, nil, nil)
+	ctx := ContextWithUserID(context.Background(), "u1")
+	if err := labels.Create(ctx, &models.Label{UserID: "u1", Name: "Bug"}); err != nil {
+		t.Fatalf("Create label failed: %v", err)
+	}
+
+	if _, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "x", Labels: []string{"docs"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for unknown label, got %v", err)
+	}
+	created, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "修复", Labels: []string{"bug"}})
+	if err != nil || len(created.Task.Labels) != 1 || created.Task.Labels[0] != "Bug" {
+		t.Fatalf("Expected canonical label Bug, got %v (%v)", created, err)
+	}
+	if _, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "其他"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	list, err := svc.GetTasks(ctx, &pb.GetTasksRequest{Labels: []string{"Bug"}})
+	if err != nil || len(list.Tasks) != 1 || list.Tasks[0].Id != created.Task.Id {
+		t.Errorf("Expected only the labeled task, got %v (%v)", list, err)
+	}
+
+	cleared, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: created.Task.Id, ClearLabels: true})
+	if err != nil || len(cleared.Task.Labels) != 0 {
+		t.Errorf("Expected labels to be cleared, got %v (%v)", cleared, err)
+	}
+}
+
+func TestTaskServiceProjects(t *testing.T) {
+	projects := memory.NewProjectRepository()
+	svc := NewTaskService(memory.NewTaskRepository(), nil, projects, nil)
+	ctx := ContextWithUserID(context.Background(), "u1")
+	work := &models.Project{UserID: "u1", Name: "工作"}
+	archived := &models.Project{UserID: "u1", Name: "旧项目", Archived: true}
+	for _, p := range []*models.Project{work, archived} {
+		if err := projects.Create(ctx, p); err != nil {
+			t.Fatalf("Create project failed: %v", err)
+		}
+	}
+
+	if _, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "x", ProjectId: "missing"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for unknown project, got %v", err)
+	}
+	if _, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "x", ProjectId: archived.ID}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for archived project, got %v", err)
+	}
+	parent, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "发布", ProjectId: work.ID})
+	if err != nil || parent.Task.ProjectId != work.ID {
+		t.Fatalf("Expected task in project, got %v (%v)", parent, err)
+	}
+	child, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "打包", ParentId: parent.Task.Id})
+	if err != nil || child.Task.ProjectId != work.ID {
+		t.Fatalf("Expected subtask to inherit project, got %v (%v)", child, err)
+	}
+	if _, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "买菜"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if list, err := svc.GetTasks(ctx, &pb.GetTasksRequest{ProjectId: work.ID}); err != nil || len(list.Tasks) != 2 {
+		t.Errorf("Expected 2 tasks in project, got %v (%v)", list, err)
+	}
+
+	cleared, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: child.Task.Id, ClearProject: true})
+	if err != nil || cleared.Task.ProjectId != "" {
+		t.Fatalf("Expected project to be cleared, got %v (%v)", cleared, err)
+	}
+	if list, err := svc.GetTasks(ctx, &pb.GetTasksRequest{ProjectId: "none"}); err != nil || len(list.Tasks) != 2 {
+		t.Errorf("Expected 2 tasks without project, got %v (%v)", list, err)
+	}
+}
+
+func TestTaskServiceWorkflow(t *testing.T) {
+	workflows := memory.NewWorkflowRepository()
+	svc := NewTaskService(memory.NewTaskRepository(), nil, nil, workflows)
+	ctx := ContextWithUserID(context.Background(), "u1")
+	flow := &models.Workflow{
+		UserID: "u1",
+		Name:   "看板",
+		Statuses: []models.WorkflowStatus{
+			{Name: "Backlog", Category: models.CategoryOpen},
+			{Name: "Review", Category: models.CategoryActive},
+			{Name: "Shipped", Category: models.CategoryDone},
+		},
+		Transitions: []models.WorkflowTransition{{From: "Backlog", To: "Review"}, {From: "Review", To: "Shipped"}},
+		Priorities:  []string{"P2", "P1"},
+	}
+	if err := workflows.Create(ctx, flow); err != nil {
+		t.Fatalf("Create workflow failed: %v", err)
+	}
+
+	created, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "x"})
+	if err != nil || created.Task.StatusName != "Backlog" || created.Task.PriorityName != "P2" {
+		t.Fatalf("Expected workflow defaults, got %v (%v)", created, err)
+	}
+	if created.Task.Status != pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		t.Errorf("Custom status should not map to an enum, got %v", created.Task.Status)
+	}
+	if _, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "x", Status: pb.TaskStatus_TASK_STATUS_TODO}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for status outside workflow, got %v", err)
+	}
+	id := created.Task.Id
+	if _, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: id, StatusName: "Shipped"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for disallowed transition, got %v", err)
+	}
+	if _, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: id, StatusName: "Review", PriorityName: "P1"}); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	done, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: id, StatusName: "Shipped"})
+	if err != nil || done.Task.StatusName != "Shipped" || done.Task.PriorityName != "P1" {
+		t.Errorf("Unexpected updated task %v (%v)", done, err)
+	}
+}
+
+func TestTaskServiceUpdateMask(t *testing.T) {
+	svc := NewTaskService(memory.NewTaskRepository(), memory.NewLabelRepository(), memory.NewProjectRepository(), nil)
+	ctx := ContextWithUserID(context.Background(), "u1")
+	due := timestamppb.New(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	created, err := svc.CreateTask(ctx, &pb.CreateTaskRequest{Title: "t", Description: "d", Assignee: "bob", DueDate: due})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	id := created.Task.Id
+
+	// 未设置 update_mask 时空请求不修改任何字段
+	resp, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: id})
+	if err != nil || resp.Task.Assignee != "bob" || resp.Task.DueDate == nil {
+		t.Fatalf("Empty update: %v (%v)", resp, err)
+	}
+
+	// update_mask 中列出的空字段被清空，其余字段保持不变
+	resp, err = svc.UpdateTask(ctx, &pb.UpdateTaskRequest{
+		Id:         id,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"assignee", "due_date", "status"}},
+	})
+	if err != nil || resp.Task.Assignee != "" || resp.Task.DueDate != nil || resp.Task.Description != "d" ||
+		resp.Task.Status != pb.TaskStatus_TASK_STATUS_TODO {
+		t.Fatalf("Masked update: %v (%v)", resp, err)
+	}
+	if _, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: id, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for cleared title, got %v", err)
+	}
+	if _, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: id, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"created_by"}}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for unknown path, got %v", err)
+	}
+
+	// version 与当前版本不符时不修改
+	stale := created.Task.Version
+	if _, err := svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: id, Title: "lost", Version: stale}); status.Code(err) != codes.Aborted {
+		t.Errorf("Expected Aborted for stale version, got %v", err)
+	}
+	if _, err := svc.DeleteTask(ctx, &pb.DeleteTaskRequest{Id: id, Version: stale}); status.Code(err) != codes.Aborted {
+		t.Errorf("Expected Aborted for stale delete, got %v", err)
+	}
+	resp, err = svc.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: id, Title: "renamed", Version: resp.Task.Version})
+	if err != nil || resp.Task.Title != "renamed" {
+		t.Errorf("Expected update with current version, got %v (%v)", resp, err)
+	}
+}
+
+func TestStoreErrorHidesDriverText(t *testing.T) {
+	err := storeError(errors.New("pq: relation \"tasks\" does not exist (dsn=postgres://secret@db)"), "Task not found")
+	if status.Code(err) != codes.Internal || status.Convert(err).Message() != "Database error" {
+		t.Errorf("Expected fixed Internal message, got %v", err)
+	}
+}
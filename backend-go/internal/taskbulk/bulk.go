@@ -0,0 +1,261 @@
+// Package taskbulk 对一批任务执行同一操作：逐个校验后以一次批量写入保存，完成重复任务时创建的下一次实例在同一次写入中创建。
+// 不存在或不属于当前用户的任务被跳过；atomic 时任一任务校验失败则不写入任何任务
+package taskbulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/depgraph"
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/recurrence"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/taskcomment"
+	"github.com/axfinn/todoIng/backend-go/internal/taskedit"
+	"github.com/axfinn/todoIng/backend-go/internal/tasktree"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+// Op 批量操作类型
+type Op string
+
+const (
+	OpSetStatus   Op = "setStatus"
+	OpSetPriority Op = "setPriority"
+	OpSetAssignee Op = "setAssignee"
+	OpSetDeadline Op = "setDeadline"
+	OpDelete      Op = "delete" // 移入回收站，后代一并移入
+	OpComment     Op = "comment"
+)
+
+// MaxTasks 单次批量操作的任务数上限
+const MaxTasks = 500
+
+var (
+	// ErrOp 不支持的操作类型
+	ErrOp = errors.New("unknown bulk operation")
+	// ErrBlocked 任务仍有未完成的阻塞任务，不能完成
+	ErrBlocked = errors.New("task is blocked")
+	// ErrRecurrence 修改截止日期或完成任务后重复规则无法推算
+	ErrRecurrence = errors.New("invalid recurrence")
+	// ErrAborted 存储不支持事务，批量写入在之前的任务处中止，该任务未写入
+	ErrAborted = errors.New("not written, bulk write stopped at an earlier task")
+)
+
+// Valid 判断是否为支持的操作类型
+func (op Op) Valid() bool {
+	switch op {
+	case OpSetStatus, OpSetPriority, OpSetAssignee, OpSetDeadline, OpDelete, OpComment:
+		return true
+	}
+	return false
+}
+
+// Operation 对每个任务执行的操作，只使用与 Op 对应的字段；Assignee 与 Deadline 为 nil 表示清空
+type Operation struct {
+	Op       Op
+	Status   string
+	Priority string
+	Assignee *string
+	Deadline *time.Time
+	Comment  string
+	Force    bool // 完成任务时忽略未完成的阻塞任务
+	Atomic   bool // 任一任务校验失败时不写入任何任务
+}
+
+// Result 单个任务的执行结果；Err 为 repository.ErrNotFound 时表示任务已跳过
+type Result struct {
+	ID   string
+	Err  error
+	Next *models.Task // 完成重复任务时创建的下一次实例
+}
+
+// Apply 对 userID 的任务 ids 执行 op，返回与去重后的 ids 一一对应的结果，以及是否已写入。
+// 状态与优先级按任务所在的工作流校验，同一批进入同一状态的任务合并计入 WIP 上限；
+// 完成重复任务时与单个更新一样创建下一次实例。返回的 error 只表示存储错误，此时没有写入任何任务；
+// 不支持事务的存储中途失败时返回 *repository.PartialError 与已写入为 true，results 中未写入的任务带有错误
+func Apply(ctx context.Context, tasks repository.TaskRepository, flows *workflow.Set, userID string, ids []string, op Operation, now time.Time) ([]Result, bool, error) {
+	if !op.Op.Valid() {
+		return nil, false, ErrOp
+	}
+	ids = depgraph.Normalize(ids)
+	results := make([]Result, len(ids))
+	if len(ids) == 0 {
+		return results, true, nil
+	}
+	found, err := tasks.List(ctx, repository.TaskFilter{UserID: userID, IDs: ids})
+	if err != nil {
+		return nil, false, err
+	}
+	byID := make(map[string]*models.Task, len(found))
+	for i := range found {
+		byID[found[i].ID] = &found[i]
+	}
+
+	afters := make([]*models.Task, len(ids))
+	for i, id := range ids {
+		results[i].ID = id
+		before := byID[id]
+		if before == nil {
+			results[i].Err = repository.ErrNotFound
+			continue
+		}
+		after := *before
+		after.Comments = append([]models.Comment(nil), before.Comments...)
+		results[i].Err = apply(&after, userID, op, now)
+		afters[i] = &after
+	}
+
+	if op.Op == OpSetStatus || op.Op == OpSetPriority {
+		if err := checkWorkflow(ctx, tasks, flows, byID, afters, results); err != nil {
+			return nil, false, err
+		}
+	}
+	if op.Op == OpSetStatus {
+		if err := complete(ctx, tasks, flows, userID, byID, afters, results, op.Force, now); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var writes []models.Task
+	var created []*models.Task
+	var owners []int // 每条写入（覆盖在前、新建在后）对应的结果下标，随父任务移入回收站的后代为 -1
+	var trashed []string
+	for i := range results {
+		switch {
+		case results[i].Err == nil:
+			writes = append(writes, *afters[i])
+			owners = append(owners, i)
+			if op.Op == OpDelete {
+				trashed = append(trashed, afters[i].ID)
+			}
+		case !errors.Is(results[i].Err, repository.ErrNotFound) && op.Atomic:
+			return results, false, nil
+		}
+	}
+	if len(trashed) > 0 {
+		descendants, err := tasktree.Descendants(ctx, tasks, userID, trashed)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, t := range descendants {
+			at := now
+			t.DeletedAt = &at
+			writes = append(writes, t)
+			owners = append(owners, -1)
+		}
+	}
+	for i := range results {
+		if results[i].Err == nil && results[i].Next != nil {
+			created = append(created, results[i].Next)
+			owners = append(owners, i)
+		}
+	}
+	err = tasks.BulkUpdate(ctx, writes, created...)
+	var partial *repository.PartialError
+	switch {
+	case errors.As(err, &partial):
+		unwritten(results, owners, len(writes), partial)
+		return results, true, err
+	case err != nil:
+		return nil, false, err
+	}
+	return results, true, nil
+}
+
+// unwritten 将部分写入后未生效的写入标记到结果：中止处的任务带有存储错误，之后的任务为 ErrAborted，
+// 未能创建的下一次实例从结果中移除
+func unwritten(results []Result, owners []int, updates int, partial *repository.PartialError) {
+	for k := partial.Written; k < len(owners); k++ {
+		i := owners[k]
+		switch {
+		case i < 0:
+		case k >= updates:
+			results[i].Next = nil
+		case k == partial.Written:
+			results[i].Err = partial.Err
+		default:
+			results[i].Err = ErrAborted
+		}
+	}
+}
+
+// apply 在任务副本上执行不依赖其他任务的修改
+func apply(task *models.Task, userID string, op Operation, now time.Time) error {
+	switch op.Op {
+	case OpSetStatus:
+		task.Status = op.Status
+	case OpSetPriority:
+		task.Priority = op.Priority
+	case OpSetAssignee:
+		task.Assignee = op.Assignee
+	case OpSetDeadline:
+		task.Deadline = op.Deadline
+		if task.Recurrence != nil {
+			if err := recurrence.Validate(task); err != nil {
+				return fmt.Errorf("%w: %v", ErrRecurrence, err)
+			}
+		}
+	case OpDelete:
+		at := now
+		task.DeletedAt = &at
+		return nil
+	case OpComment:
+		if _, err := taskcomment.Add(task, userID, op.Comment, nil, now); err != nil {
+			return err
+		}
+	}
+	task.UpdatedAt = now
+	return nil
+}
+
+// checkWorkflow 按工作流校验尚未出错的任务，结果写入 results
+func checkWorkflow(ctx context.Context, tasks repository.TaskRepository, flows *workflow.Set, before map[string]*models.Task, afters []*models.Task, results []Result) error {
+	var changes []workflow.Change
+	var index []int
+	for i, after := range afters {
+		if results[i].Err == nil {
+			changes = append(changes, workflow.Change{Task: after, Before: before[after.ID]})
+			index = append(index, i)
+		}
+	}
+	errs, err := workflow.CheckAll(ctx, tasks, flows, changes)
+	if err != nil {
+		return err
+	}
+	for j, i := range index {
+		results[i].Err = errs[j]
+	}
+	return nil
+}
+
+// complete 以 taskedit.Complete 处理尚未出错的任务中改为完成类状态的任务，与单个修改共用阻塞检查与重复任务的处理，
+// 结果写入 results
+func complete(ctx context.Context, tasks repository.TaskRepository, flows *workflow.Set, userID string, before map[string]*models.Task, afters []*models.Task, results []Result, force bool, now time.Time) error {
+	var changes []workflow.Change
+	var index []int
+	for i, after := range afters {
+		if results[i].Err == nil {
+			changes = append(changes, workflow.Change{Task: after, Before: before[after.ID]})
+			index = append(index, i)
+		}
+	}
+	done, err := taskedit.Complete(ctx, tasks, flows, userID, changes, taskedit.Options{Force: force}, now)
+	if err != nil {
+		return err
+	}
+	for j, i := range index {
+		results[i].Next = done[j].Next
+		var blocked *taskedit.BlockedError
+		var recur *taskedit.RecurrenceError
+		switch err := done[j].Err; {
+		case errors.As(err, &blocked):
+			results[i].Err = ErrBlocked
+		case errors.As(err, &recur):
+			results[i].Err = fmt.Errorf("%w: %v", ErrRecurrence, recur.Err)
+		}
+	}
+	return nil
+}
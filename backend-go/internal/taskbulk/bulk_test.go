@@ -0,0 +1,172 @@
+package taskbulk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	tasks := memory.NewTaskRepository()
+	flows := workflow.NewSet(nil)
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	create := func(task *models.Task) *models.Task {
+		task.CreatedBy, task.Status, task.Priority, task.CreatedAt, task.UpdatedAt = "u1", "To Do", "Medium", now, now
+		if err := tasks.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task
+	}
+	weekly := create(&models.Task{Title: "周会", Deadline: &deadline, Recurrence: &models.Recurrence{Rule: "FREQ=WEEKLY"}})
+	plain := create(&models.Task{Title: "普通"})
+
+	// 无效状态只影响对应任务；非 atomic 时其余任务照常写入
+	results, applied, err := Apply(ctx, tasks, flows, "u1", []string{plain.ID, "missing", plain.ID}, Operation{Op: OpSetStatus, Status: "Nope"}, now)
+	if err != nil || !applied || len(results) != 2 || !errors.Is(results[0].Err, workflow.ErrStatus) || !errors.Is(results[1].Err, repository.ErrNotFound) {
+		t.Fatalf("Unexpected results %+v (%v, %v)", results, applied, err)
+	}
+
+	// 完成重复任务时创建下一次实例
+	results, applied, err = Apply(ctx, tasks, flows, "u1", []string{weekly.ID, plain.ID}, Operation{Op: OpSetStatus, Status: "Done"}, now)
+	if err != nil || !applied || results[0].Err != nil || results[0].Next == nil {
+		t.Fatalf("Unexpected results %+v (%v, %v)", results, applied, err)
+	}
+	if next := results[0].Next; next.ID == "" || next.Status != "To Do" || !next.Deadline.Equal(deadline.AddDate(0, 0, 7)) {
+		t.Errorf("Unexpected next occurrence %+v", next)
+	}
+	if got, _ := tasks.Get(ctx, "u1", weekly.ID); got.Status != "Done" || got.Recurrence != nil || !got.UpdatedAt.Equal(now) {
+		t.Errorf("Expected completed task without recurrence, got %+v", got)
+	}
+
+	// atomic 时任一任务失败则不写入
+	results, applied, err = Apply(ctx, tasks, flows, "u1", []string{plain.ID, weekly.ID}, Operation{Op: OpSetPriority, Priority: "High", Atomic: true}, now)
+	if err != nil || !applied || results[0].Err != nil {
+		t.Fatalf("Unexpected results %+v (%v, %v)", results, applied, err)
+	}
+	results, applied, err = Apply(ctx, tasks, flows, "u1", []string{plain.ID, "missing", weekly.ID}, Operation{Op: OpSetStatus, Status: "Nope", Atomic: true}, now)
+	if err != nil || applied || len(results) != 3 {
+		t.Fatalf("Expected atomic batch to abort, got %+v (%v, %v)", results, applied, err)
+	}
+
+	if _, _, err := Apply(ctx, tasks, flows, "u1", []string{plain.ID}, Operation{Op: "archive"}, now); !errors.Is(err, ErrOp) {
+		t.Errorf("Expected ErrOp, got %v", err)
+	}
+}
+
+func TestApplyBlockersInBatch(t *testing.T) {
+	ctx := context.Background()
+	tasks := memory.NewTaskRepository()
+	flows := workflow.NewSet(nil)
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	create := func(title string, blockedBy ...string) *models.Task {
+		task := &models.Task{Title: title, CreatedBy: "u1", Status: "To Do", Priority: "Medium", BlockedBy: blockedBy, CreatedAt: now, UpdatedAt: now}
+		if err := tasks.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task
+	}
+	design := create("设计")
+	build := create("开发", design.ID)
+	release := create("发布", build.ID)
+	outside := create("外部")
+	docs := create("文档", outside.ID)
+	review := create("评审", docs.ID)
+
+	// 被阻塞的任务排在阻塞它的任务之前也能一起完成；阻塞链上有任务未完成时其后的任务都失败
+	results, applied, err := Apply(ctx, tasks, flows, "u1", []string{release.ID, build.ID, review.ID, design.ID, docs.ID}, Operation{Op: OpSetStatus, Status: "Done"}, now)
+	if err != nil || !applied {
+		t.Fatalf("Apply failed: %v (%v)", err, applied)
+	}
+	for i, want := range []error{nil, nil, ErrBlocked, nil, ErrBlocked} {
+		if !errors.Is(results[i].Err, want) {
+			t.Errorf("Result %d: expected %v, got %v", i, want, results[i].Err)
+		}
+	}
+}
+
+// partialTasks 模拟不支持事务的存储：只写入第一个任务后以 ErrVersion 中止
+type partialTasks struct {
+	*memory.TaskRepository
+}
+
+func (r partialTasks) BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error {
+	if err := r.Update(ctx, &tasks[0]); err != nil {
+		return err
+	}
+	return &repository.PartialError{Written: 1, Err: repository.ErrVersion}
+}
+
+func TestApplyPartial(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.NewTaskRepository()
+	flows := workflow.NewSet(nil)
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	var ids []string
+	for _, title := range []string{"一", "二", "三"} {
+		task := &models.Task{Title: title, CreatedBy: "u1", Status: "To Do", Priority: "Medium", CreatedAt: now, UpdatedAt: now,
+			Deadline: &deadline, Recurrence: &models.Recurrence{Rule: "FREQ=DAILY"}}
+		if err := mem.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		ids = append(ids, task.ID)
+	}
+
+	results, applied, err := Apply(ctx, partialTasks{mem}, flows, "u1", ids, Operation{Op: OpSetStatus, Status: "Done"}, now)
+	var partial *repository.PartialError
+	if !errors.As(err, &partial) || !applied {
+		t.Fatalf("Expected partial write, got %v (%v)", err, applied)
+	}
+	if results[0].Err != nil || !errors.Is(results[1].Err, repository.ErrVersion) || !errors.Is(results[2].Err, ErrAborted) {
+		t.Errorf("Unexpected results %+v", results)
+	}
+	for i := range results {
+		if results[i].Next != nil {
+			t.Errorf("Result %d: next occurrence was not created, got %+v", i, results[i].Next)
+		}
+	}
+}
+
+func TestApplyCreatesNextInSameWrite(t *testing.T) {
+	ctx := context.Background()
+	tasks := memory.NewTaskRepository()
+	flows := workflow.NewSet(nil)
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	daily := &models.Task{Title: "日报", CreatedBy: "u1", Status: "To Do", Priority: "Medium", CreatedAt: now, UpdatedAt: now,
+		Deadline: &deadline, Recurrence: &models.Recurrence{Rule: "FREQ=DAILY"}}
+	if err := tasks.Create(ctx, daily); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// 读取后被修改的任务使整批失败，下一次实例也不会创建
+	stale := racing{TaskRepository: tasks, race: func() {
+		current, _ := tasks.Get(ctx, "u1", daily.ID)
+		current.Title = "日报（改）"
+		_ = tasks.Update(ctx, current)
+	}}
+	if _, _, err := Apply(ctx, stale, flows, "u1", []string{daily.ID}, Operation{Op: OpSetStatus, Status: "Done"}, now); !errors.Is(err, repository.ErrVersion) {
+		t.Fatalf("Expected ErrVersion, got %v", err)
+	}
+	if n, _ := tasks.Count(ctx, repository.TaskFilter{UserID: "u1"}); n != 1 {
+		t.Errorf("Expected no next occurrence after failed write, got %d tasks", n)
+	}
+}
+
+// racing 在批量写入前执行 race，模拟读取之后的并发修改
+type racing struct {
+	repository.TaskRepository
+	race func()
+}
+
+func (r racing) BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error {
+	r.race()
+	return r.TaskRepository.BulkUpdate(ctx, tasks, created...)
+}
@@ -0,0 +1,99 @@
+// Package taskcomment 处理任务评论：创建带稳定 ID 的评论与回复、只允许作者编辑，
+// 删除评论时连同其下的回复一并删除。HTTP 处理器与 gRPC 服务共用这些逻辑。
+package taskcomment
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+var (
+	// ErrEmpty 评论内容为空
+	ErrEmpty = errors.New("comment text is required")
+	// ErrNotFound 任务中没有该评论
+	ErrNotFound = errors.New("comment not found")
+	// ErrParent 所回复的评论不存在
+	ErrParent = errors.New("parent comment not found")
+	// ErrForbidden 只有评论作者可以编辑或删除评论
+	ErrForbidden = errors.New("only the author can change a comment")
+)
+
+// LegacyID 返回迁移前没有 ID 的评论使用的 ID，由评论在任务中的位置决定，
+// 与 SQL 迁移回填的值一致，评论再次写入后即固定下来
+func LegacyID(position int) string {
+	return fmt.Sprintf("legacy-%d", position)
+}
+
+// Add 以 userID 的身份在任务末尾追加评论；parentID 非空时为回复，被回复的评论须属于同一任务
+func Add(task *models.Task, userID, text string, parentID *string, now time.Time) (*models.Comment, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrEmpty
+	}
+	if parentID != nil && Index(task, *parentID) < 0 {
+		return nil, ErrParent
+	}
+	task.Comments = append(task.Comments, models.Comment{
+		ID:        repository.NewID(),
+		ParentID:  parentID,
+		Text:      text,
+		CreatedBy: userID,
+		CreatedAt: now,
+	})
+	return &task.Comments[len(task.Comments)-1], nil
+}
+
+// Index 返回评论在任务中的下标，不存在时返回 -1
+func Index(task *models.Task, id string) int {
+	for i, c := range task.Comments {
+		if c.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Edit 修改评论内容并记录编辑时间，只有作者可以修改
+func Edit(task *models.Task, id, userID, text string, now time.Time) (*models.Comment, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrEmpty
+	}
+	i := Index(task, id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	c := &task.Comments[i]
+	if c.CreatedBy != userID {
+		return nil, ErrForbidden
+	}
+	c.Text = text
+	c.UpdatedAt = &now
+	return c, nil
+}
+
+// Remove 删除评论及其下的全部回复，只有作者可以删除；返回删除的评论数
+func Remove(task *models.Task, id, userID string) (int, error) {
+	i := Index(task, id)
+	if i < 0 {
+		return 0, ErrNotFound
+	}
+	if task.Comments[i].CreatedBy != userID {
+		return 0, ErrForbidden
+	}
+	removed := map[string]bool{id: true}
+	// 回复总在被回复的评论之后，按顺序扫描一遍即可找到整个子树
+	kept := make([]models.Comment, 0, len(task.Comments))
+	for _, c := range task.Comments {
+		if removed[c.ID] || (c.ParentID != nil && removed[*c.ParentID]) {
+			removed[c.ID] = true
+			continue
+		}
+		kept = append(kept, c)
+	}
+	task.Comments = kept
+	return len(removed), nil
+}
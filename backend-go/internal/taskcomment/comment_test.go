@@ -0,0 +1,67 @@
+package taskcomment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+)
+
+func TestAddAndEdit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := &models.Task{}
+	root, err := Add(task, "u1", "先列提纲", nil, now)
+	if err != nil || root.ID == "" || root.CreatedBy != "u1" || root.UpdatedAt != nil {
+		t.Fatalf("Unexpected comment %+v (%v)", root, err)
+	}
+	if _, err := Add(task, "u1", "  ", nil, now); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	missing := "missing"
+	if _, err := Add(task, "u1", "回复", &missing, now); !errors.Is(err, ErrParent) {
+		t.Errorf("Expected ErrParent, got %v", err)
+	}
+	rootID := root.ID
+	reply, err := Add(task, "u2", "好的", &rootID, now)
+	if err != nil || reply.ParentID == nil || *reply.ParentID != rootID {
+		t.Fatalf("Unexpected reply %+v (%v)", reply, err)
+	}
+
+	later := now.Add(time.Hour)
+	if _, err := Edit(task, rootID, "u2", "改写", later); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for non-author, got %v", err)
+	}
+	edited, err := Edit(task, rootID, "u1", "列好提纲", later)
+	if err != nil || edited.Text != "列好提纲" || !edited.CreatedAt.Equal(now) || edited.UpdatedAt == nil || !edited.UpdatedAt.Equal(later) {
+		t.Errorf("Unexpected edited comment %+v (%v)", edited, err)
+	}
+	if _, err := Edit(task, "missing", "u1", "x", later); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRemoveThread(t *testing.T) {
+	now := time.Now()
+	task := &models.Task{}
+	a, _ := Add(task, "u1", "a", nil, now)
+	aID := a.ID
+	b, _ := Add(task, "u1", "b", &aID, now)
+	bID := b.ID
+	_, _ = Add(task, "u1", "c", &bID, now)
+	_, _ = Add(task, "u1", "d", nil, now)
+
+	if _, err := Remove(task, aID, "u2"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	n, err := Remove(task, aID, "u1")
+	if err != nil || n != 3 {
+		t.Fatalf("Expected thread of 3 removed, got %d (%v)", n, err)
+	}
+	if len(task.Comments) != 1 || task.Comments[0].Text != "d" {
+		t.Errorf("Unexpected remaining comments %+v", task.Comments)
+	}
+	if _, err := Remove(task, aID, "u1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,462 @@
+// Package taskedit 创建与修改单个任务：校验父任务、项目、工作流、阻塞任务、重复规则与标签，
+// 完成时处理阻塞检查、级联完成与重复任务的下一次实例。/api 处理器与 gRPC 服务共用这些逻辑，
+// 只负责把请求转换为 Input 并把错误转换为各自的响应
+package taskedit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/depgraph"
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/recurrence"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/taskcomment"
+	"github.com/axfinn/todoIng/backend-go/internal/tasklabel"
+	"github.com/axfinn/todoIng/backend-go/internal/taskproject"
+	"github.com/axfinn/todoIng/backend-go/internal/tasktree"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+var (
+	// ErrTitle 标题为空
+	ErrTitle = errors.New("title is required")
+	// ErrParent 父任务不存在或不属于当前用户
+	ErrParent = errors.New("parent task not found")
+	// ErrProject 引用的项目不存在
+	ErrProject = errors.New("project not found")
+)
+
+// WorkflowError 状态或优先级不符合任务所在的工作流，Err 为 workflow 包的错误；
+// From 与 To 为流转前后的状态，新建任务时 From 为空
+type WorkflowError struct {
+	Err      error
+	From, To string
+}
+
+func (e *WorkflowError) Error() string { return e.Err.Error() }
+
+func (e *WorkflowError) Unwrap() error { return e.Err }
+
+// BlockedError 任务仍有未完成的阻塞任务，不能改为完成
+type BlockedError struct {
+	Open []*models.Task
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("task is blocked by %d unfinished task(s)", len(e.Open))
+}
+
+// RecurrenceError 重复规则不合法，或完成时无法推算下一次实例
+type RecurrenceError struct {
+	Err error
+}
+
+func (e *RecurrenceError) Error() string { return e.Err.Error() }
+
+func (e *RecurrenceError) Unwrap() error { return e.Err }
+
+// Service 任务的创建与修改；Labels 为 nil 时任务不能引用标签，Projects 为 nil 时不能归入项目
+type Service struct {
+	Tasks     repository.TaskRepository
+	Labels    repository.LabelRepository
+	Projects  repository.ProjectRepository
+	Workflows repository.WorkflowRepository
+}
+
+// Input 任务的可编辑字段。创建与整体替换时零值表示清空，Status 与 Priority 为空时取任务所在工作流的默认值
+type Input struct {
+	Title         string
+	Description   string
+	Status        string
+	Priority      string
+	Assignee      *string // 空字符串与 nil 相同
+	Deadline      *time.Time
+	ScheduledDate *time.Time
+	ParentID      *string // 空字符串与 nil 相同，表示顶层任务
+	BlockedBy     []string
+	Recurrence    *models.Recurrence // rule 为空表示不重复
+	Labels        []string           // 标签名称，须为已有标签，不区分大小写
+	ProjectID     *string            // 空字符串与 nil 相同；新建子任务时未指定则归入父任务所在的项目
+	Comments      []string           // 追加为新评论，空白内容被忽略
+}
+
+// Options 修改任务的选项
+type Options struct {
+	Cascade bool // 改为完成时一并完成全部子任务与检查项
+	Force   bool // 仍有未完成的阻塞任务时也允许改为完成
+}
+
+// Editable 返回任务当前的可编辑字段，只修改部分字段时以此为起点
+func Editable(t *models.Task) Input {
+	return Input{
+		Title:         t.Title,
+		Description:   t.Description,
+		Status:        t.Status,
+		Priority:      t.Priority,
+		Assignee:      t.Assignee,
+		Deadline:      t.Deadline,
+		ScheduledDate: t.ScheduledDate,
+		ParentID:      t.ParentID,
+		BlockedBy:     t.BlockedBy,
+		Recurrence:    t.Recurrence,
+		Labels:        t.Labels,
+		ProjectID:     t.ProjectID,
+	}
+}
+
+// Create 校验 in 并为 userID 创建任务。校验失败时返回本包的错误、tasktree、depgraph、taskproject
+// 或 tasklabel 的错误，其余为存储错误
+func (s *Service) Create(ctx context.Context, userID string, in Input, now time.Time) (*models.Task, error) {
+	if strings.TrimSpace(in.Title) == "" {
+		return nil, ErrTitle
+	}
+	task := &models.Task{
+		Title:         in.Title,
+		Description:   in.Description,
+		Status:        in.Status,
+		Priority:      in.Priority,
+		Assignee:      optional(in.Assignee),
+		Deadline:      in.Deadline,
+		ScheduledDate: in.ScheduledDate,
+		Comments:      []models.Comment{},
+		CreatedBy:     userID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	addComments(task, userID, in.Comments)
+	if parentID := optional(in.ParentID); parentID != nil {
+		parent, err := s.Tasks.Get(ctx, userID, *parentID)
+		if err != nil {
+			return nil, parentError(err)
+		}
+		task.ParentID = parentID
+		// 未指定项目的子任务归入父任务所在的项目
+		task.ProjectID = parent.ProjectID
+	}
+	if projectID := optional(in.ProjectID); projectID != nil {
+		if err := s.setProject(ctx, userID, task, *projectID); err != nil {
+			return nil, err
+		}
+	}
+	flows, err := workflow.Load(ctx, s.Workflows, userID)
+	if err != nil {
+		return nil, err
+	}
+	setDefaults(task, flows)
+	if err := s.checkWorkflow(ctx, flows, task, nil); err != nil {
+		return nil, err
+	}
+	if len(in.BlockedBy) > 0 {
+		if err := s.setBlockedBy(ctx, userID, flows, task, in.BlockedBy); err != nil {
+			return nil, err
+		}
+	}
+	if err := setRecurrence(task, in.Recurrence); err != nil {
+		return nil, err
+	}
+	if len(in.Labels) > 0 {
+		if err := s.setLabels(ctx, userID, task, in.Labels); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Tasks.Create(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Replace 以 in 整体替换 task 的可编辑字段并保存，检查项、已有评论、创建信息与版本不受影响；
+// 返回重复任务完成时创建的下一次实例。父任务、项目、阻塞任务与标签只在有变化时重新校验，
+// 引用已归档项目或回收站中阻塞任务的任务仍可修改其他字段。task 为读取到的当前任务，
+// 读取后已被其他请求修改时返回 repository.ErrVersion；错误与 Create 相同，另有 *BlockedError
+func (s *Service) Replace(ctx context.Context, userID string, task *models.Task, in Input, opts Options, now time.Time) (*models.Task, error) {
+	if strings.TrimSpace(in.Title) == "" {
+		return nil, ErrTitle
+	}
+	flows, err := workflow.Load(ctx, s.Workflows, userID)
+	if err != nil {
+		return nil, err
+	}
+	before := *task
+	task.Title = in.Title
+	task.Description = in.Description
+	task.Assignee = optional(in.Assignee)
+	task.Deadline = in.Deadline
+	task.ScheduledDate = in.ScheduledDate
+	if parentID := optional(in.ParentID); !sameRef(parentID, task.ParentID) {
+		if parentID != nil {
+			if err := tasktree.CheckParent(ctx, s.Tasks, userID, task.ID, *parentID); err != nil {
+				return nil, parentError(err)
+			}
+		}
+		task.ParentID = parentID
+	}
+	switch projectID := optional(in.ProjectID); {
+	case sameRef(projectID, task.ProjectID):
+	case projectID == nil:
+		task.ProjectID = nil
+	default:
+		if err := s.setProject(ctx, userID, task, *projectID); err != nil {
+			return nil, err
+		}
+	}
+	task.Status, task.Priority = in.Status, in.Priority
+	setDefaults(task, flows)
+	if !slices.Equal(in.BlockedBy, task.BlockedBy) {
+		if err := s.setBlockedBy(ctx, userID, flows, task, in.BlockedBy); err != nil {
+			return nil, err
+		}
+	}
+	if err := setRecurrence(task, in.Recurrence); err != nil {
+		return nil, err
+	}
+	if !slices.Equal(in.Labels, task.Labels) {
+		if err := s.setLabels(ctx, userID, task, in.Labels); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkWorkflow(ctx, flows, task, &before); err != nil {
+		return nil, err
+	}
+	task.UpdatedAt = now
+	addComments(task, userID, in.Comments)
+	done, err := Complete(ctx, s.Tasks, flows, userID, []workflow.Change{{Task: task, Before: &before}}, opts, now)
+	if err != nil {
+		return nil, err
+	}
+	if done[0].Err != nil {
+		return nil, done[0].Err
+	}
+	writes := append([]models.Task{*task}, done[0].Descendants...)
+	var created []*models.Task
+	if next := done[0].Next; next != nil {
+		created = append(created, next)
+	}
+	if err := s.Tasks.BulkUpdate(ctx, writes, created...); err != nil {
+		return nil, err
+	}
+	task.Version = writes[0].Version
+	return done[0].Next, nil
+}
+
+// Completion 一个任务在 Complete 中的处理结果
+type Completion struct {
+	Err         error         // *BlockedError 或 *RecurrenceError，此时任务不能写入
+	Next        *models.Task  // 重复任务完成时的下一次实例，尚未保存
+	Descendants []models.Task // Cascade 时一并完成的后代，只含有变化的任务，尚未保存
+}
+
+// Complete 处理 changes 中处于完成类状态的任务，返回与 changes 一一对应的结果，调用方将任务与结果中的
+// 下一次实例、后代以一次批量写入保存。新改为完成的任务仍有未完成的阻塞任务时失败，除非 Force；
+// 同一批中一起完成的任务不再互相阻塞，与任务在批中的顺序无关：先将全部待完成的任务视为已完成，
+// 再反复剔除仍被阻塞的任务，直到不再变化。重复任务推算下一次实例并将重复规则转移过去；
+// Cascade 时完成任务的检查项并收集尚未完成的后代。Change.Task 被就地修改，返回的 error 只表示存储错误
+func Complete(ctx context.Context, tasks repository.TaskRepository, flows *workflow.Set, userID string, changes []workflow.Change, opts Options, now time.Time) ([]Completion, error) {
+	results := make([]Completion, len(changes))
+	var pending []int
+	for i, c := range changes {
+		if !flows.Done(c.Task) || (c.Before != nil && flows.Done(c.Before)) {
+			continue
+		}
+		if c.Task.Recurrence != nil {
+			next, err := recurrence.Next(c.Task, now)
+			if err != nil {
+				results[i].Err = &RecurrenceError{Err: err}
+				continue
+			}
+			if next != nil {
+				next.Status = workflow.Initial(flows.For(next.ProjectID))
+			}
+			results[i].Next = next
+		}
+		pending = append(pending, i)
+	}
+	if len(pending) > 0 && !opts.Force {
+		if err := checkBlockers(ctx, tasks, flows, userID, changes, pending, results); err != nil {
+			return nil, err
+		}
+	}
+	for _, i := range pending {
+		if results[i].Err == nil {
+			changes[i].Task.Recurrence = nil // 重复规则转移到下一次实例，系列结束时不再重复
+		}
+	}
+	if opts.Cascade {
+		if err := cascade(ctx, tasks, flows, userID, changes, results, now); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// checkBlockers 将 pending 中仍有未完成阻塞任务的任务标记为 *BlockedError 并放弃其下一次实例
+func checkBlockers(ctx context.Context, tasks repository.TaskRepository, flows *workflow.Set, userID string, changes []workflow.Change, pending []int, results []Completion) error {
+	g, err := depgraph.Load(ctx, tasks, flows, userID)
+	if err != nil {
+		return err
+	}
+	setStatus := func(id, status string) {
+		if t := g.Task(id); t != nil {
+			t.Status = status
+		}
+	}
+	for _, i := range pending {
+		setStatus(changes[i].Task.ID, changes[i].Task.Status)
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, i := range pending {
+			if results[i].Err != nil {
+				continue
+			}
+			if open := g.OpenBlockers(changes[i].Task); len(open) > 0 {
+				results[i].Err, results[i].Next = &BlockedError{Open: open}, nil
+				if before := changes[i].Before; before != nil {
+					setStatus(before.ID, before.Status)
+				}
+				changed = true
+			}
+		}
+	}
+	return nil
+}
+
+// cascade 完成处于完成类状态的任务的检查项，并收集其尚未完成的后代；已在 changes 中的后代由其自身的修改写入
+func cascade(ctx context.Context, tasks repository.TaskRepository, flows *workflow.Set, userID string, changes []workflow.Change, results []Completion, now time.Time) error {
+	seen := make(map[string]bool, len(changes))
+	for _, c := range changes {
+		seen[c.Task.ID] = true
+	}
+	for i, c := range changes {
+		if results[i].Err != nil || !flows.Done(c.Task) {
+			continue
+		}
+		tasktree.Complete(c.Task, flows)
+		descendants, err := tasktree.CompleteDescendants(ctx, tasks, flows, userID, c.Task.ID, now)
+		if err != nil {
+			return err
+		}
+		for _, t := range descendants {
+			if !seen[t.ID] {
+				seen[t.ID] = true
+				results[i].Descendants = append(results[i].Descendants, t)
+			}
+		}
+	}
+	return nil
+}
+
+// optional 将空字符串视为未设置
+func optional(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
+
+// sameRef 判断两个可选 ID 是否相同
+func sameRef(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// addComments 将非空白的内容追加为新评论
+func addComments(task *models.Task, userID string, texts []string) {
+	for _, text := range texts {
+		if strings.TrimSpace(text) != "" {
+			_, _ = taskcomment.Add(task, userID, text, nil, task.UpdatedAt)
+		}
+	}
+}
+
+// setDefaults 将未设置的状态与优先级设为任务所在工作流的默认值
+func setDefaults(task *models.Task, flows *workflow.Set) {
+	flow := flows.For(task.ProjectID)
+	if task.Status == "" {
+		task.Status = workflow.Initial(flow)
+	}
+	if task.Priority == "" {
+		task.Priority = workflow.DefaultPriority(flow)
+	}
+}
+
+// parentError 将父任务不存在转换为 ErrParent，与任务本身不存在区分
+func parentError(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrParent
+	}
+	return err
+}
+
+// checkWorkflow 按任务所在的工作流校验状态、流转、WIP 上限与优先级；before 为写入前的任务，新建时为 nil
+func (s *Service) checkWorkflow(ctx context.Context, flows *workflow.Set, task, before *models.Task) error {
+	err := workflow.Check(ctx, s.Tasks, flows, task, before)
+	switch {
+	case errors.Is(err, workflow.ErrStatus), errors.Is(err, workflow.ErrPriority),
+		errors.Is(err, workflow.ErrTransition), errors.Is(err, workflow.ErrWIPLimit):
+		werr := &WorkflowError{Err: err, To: task.Status}
+		if before != nil {
+			werr.From = before.Status
+		}
+		return werr
+	}
+	return err
+}
+
+// setBlockedBy 校验并设置阻塞任务；依赖不存在返回 depgraph.ErrMissing，成环返回 *depgraph.CycleError
+func (s *Service) setBlockedBy(ctx context.Context, userID string, flows *workflow.Set, task *models.Task, ids []string) error {
+	g, err := depgraph.Load(ctx, s.Tasks, flows, userID)
+	if err != nil {
+		return err
+	}
+	return g.SetBlockedBy(task, ids)
+}
+
+// setRecurrence 设置并校验重复规则，rule 为空时取消重复
+func setRecurrence(task *models.Task, r *models.Recurrence) error {
+	task.Recurrence = nil
+	if r == nil || r.Rule == "" {
+		return nil
+	}
+	task.Recurrence = r
+	if err := recurrence.Validate(task); err != nil {
+		return &RecurrenceError{Err: err}
+	}
+	return nil
+}
+
+// setLabels 将标签名称解析为用户已有的标签，标签不存在时返回 *tasklabel.UnknownError
+func (s *Service) setLabels(ctx context.Context, userID string, task *models.Task, names []string) error {
+	var known []models.Label
+	if s.Labels != nil && len(names) > 0 {
+		var err error
+		if known, err = s.Labels.List(ctx, userID); err != nil {
+			return err
+		}
+	}
+	labels, err := tasklabel.Resolve(known, names)
+	if err != nil {
+		return err
+	}
+	task.Labels = labels
+	return nil
+}
+
+// setProject 将任务归入项目，项目不存在时返回 ErrProject，已归档时返回 taskproject.ErrArchived
+func (s *Service) setProject(ctx context.Context, userID string, task *models.Task, id string) error {
+	if _, err := taskproject.Open(ctx, s.Projects, userID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrProject
+		}
+		return err
+	}
+	task.ProjectID = &id
+	return nil
+}
@@ -0,0 +1,147 @@
+package taskedit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/recurrence"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+	"github.com/axfinn/todoIng/backend-go/internal/taskproject"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	projects := memory.NewProjectRepository()
+	s := &Service{Tasks: memory.NewTaskRepository(), Projects: projects}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	project := &models.Project{UserID: "u1", Name: "p"}
+	archived := &models.Project{UserID: "u1", Name: "old", Archived: true}
+	for _, p := range []*models.Project{project, archived} {
+		if err := projects.Create(ctx, p); err != nil {
+			t.Fatalf("Create project failed: %v", err)
+		}
+	}
+
+	if _, err := s.Create(ctx, "u1", Input{Title: " "}, now); !errors.Is(err, ErrTitle) {
+		t.Errorf("Expected ErrTitle, got %v", err)
+	}
+	parent, err := s.Create(ctx, "u1", Input{Title: "parent", ProjectID: &project.ID, Comments: []string{"hi", " "}}, now)
+	if err != nil || parent.Status != "To Do" || parent.Priority != "Medium" || len(parent.Comments) != 1 {
+		t.Fatalf("Expected defaults and one comment, got %+v (%v)", parent, err)
+	}
+	// 未指定项目的子任务归入父任务所在的项目
+	child, err := s.Create(ctx, "u1", Input{Title: "child", ParentID: &parent.ID}, now)
+	if err != nil || child.ProjectID == nil || *child.ProjectID != project.ID {
+		t.Errorf("Expected child in parent's project, got %+v (%v)", child, err)
+	}
+
+	missing := "missing"
+	tests := []struct {
+		in   Input
+		want error
+	}{
+		{Input{Title: "x", ParentID: &missing}, ErrParent},
+		{Input{Title: "x", ProjectID: &missing}, ErrProject},
+		{Input{Title: "x", ProjectID: &archived.ID}, taskproject.ErrArchived},
+		{Input{Title: "x", Status: "Nope"}, workflow.ErrStatus},
+		{Input{Title: "x", Recurrence: &models.Recurrence{Rule: "FREQ=WEEKLY"}}, recurrence.ErrNoAnchor},
+	}
+	for _, tt := range tests {
+		if _, err := s.Create(ctx, "u1", tt.in, now); !errors.Is(err, tt.want) {
+			t.Errorf("%+v: expected %v, got %v", tt.in, tt.want, err)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	ctx := context.Background()
+	tasks := memory.NewTaskRepository()
+	s := &Service{Tasks: tasks}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	blocker, _ := s.Create(ctx, "u1", Input{Title: "blocker"}, now)
+	due := now.Add(24 * time.Hour)
+	task, err := s.Create(ctx, "u1", Input{
+		Title:      "weekly",
+		Deadline:   &due,
+		BlockedBy:  []string{blocker.ID},
+		Recurrence: &models.Recurrence{Rule: "FREQ=WEEKLY"},
+	}, now)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	done := Editable(task)
+	done.Status = "Done"
+	var blocked *BlockedError
+	if _, err := s.Replace(ctx, "u1", task, done, Options{}, now); !errors.As(err, &blocked) || len(blocked.Open) != 1 {
+		t.Fatalf("Expected BlockedError, got %v", err)
+	}
+
+	// 读取后被修改的任务不被覆盖
+	current, _ := tasks.Get(ctx, "u1", task.ID)
+	stale := *current
+	edited := Editable(current)
+	edited.Description = "edited"
+	if _, err := s.Replace(ctx, "u1", current, edited, Options{}, now); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if _, err := s.Replace(ctx, "u1", &stale, done, Options{Force: true}, now); !errors.Is(err, repository.ErrVersion) {
+		t.Errorf("Expected ErrVersion for stale task, got %v", err)
+	}
+
+	// 完成重复任务时创建下一次实例，省略的字段被清空
+	current, _ = tasks.Get(ctx, "u1", task.ID)
+	next, err := s.Replace(ctx, "u1", current, Input{Title: "weekly", Status: "Done", Deadline: &due, BlockedBy: current.BlockedBy, Recurrence: current.Recurrence}, Options{Force: true}, now)
+	if err != nil || next == nil || next.Status != "To Do" || current.Recurrence != nil || current.Description != "" {
+		t.Fatalf("Expected next occurrence, got %+v / %+v (%v)", next, current, err)
+	}
+	if got, _ := tasks.Get(ctx, "u1", next.ID); got == nil || got.Deadline == nil || !got.Deadline.After(due) {
+		t.Errorf("Expected next occurrence saved after %v, got %+v", due, got)
+	}
+}
+
+// bulkOnly 只允许批量写入，确认任务、级联完成的后代与下一次实例在同一次写入中保存
+type bulkOnly struct {
+	*memory.TaskRepository
+	bulks int
+}
+
+func (r *bulkOnly) Update(context.Context, *models.Task) error {
+	return errors.New("unexpected Update")
+}
+
+func (r *bulkOnly) BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error {
+	r.bulks++
+	return r.TaskRepository.BulkUpdate(ctx, tasks, created...)
+}
+
+func TestReplaceCascade(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.NewTaskRepository()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &Service{Tasks: mem}
+	due := now.Add(24 * time.Hour)
+	parent, _ := s.Create(ctx, "u1", Input{Title: "parent", Deadline: &due, Recurrence: &models.Recurrence{Rule: "FREQ=DAILY"}}, now)
+	child, _ := s.Create(ctx, "u1", Input{Title: "child", ParentID: &parent.ID}, now)
+	grandchild, _ := s.Create(ctx, "u1", Input{Title: "grandchild", ParentID: &child.ID}, now)
+
+	repo := &bulkOnly{TaskRepository: mem}
+	s.Tasks = repo
+	done := Editable(parent)
+	done.Status = "Done"
+	version := parent.Version
+	next, err := s.Replace(ctx, "u1", parent, done, Options{Cascade: true}, now)
+	if err != nil || next == nil || repo.bulks != 1 || parent.Version != version+1 {
+		t.Fatalf("Expected one bulk write with the next occurrence, got %+v, %d writes (%v)", next, repo.bulks, err)
+	}
+	for _, id := range []string{child.ID, grandchild.ID} {
+		if got, _ := mem.Get(ctx, "u1", id); got.Status != "Done" {
+			t.Errorf("Expected descendant %s done, got %s", got.Title, got.Status)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+// Package taskhistory 记录任务的字段级变更历史：包装任务存储，在创建、更新与删除时
+// 比较前后状态并写入一条不可修改的历史记录
+package taskhistory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/observability"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// 字段名，与任务 JSON 字段一致；评论与检查项的变化以 FieldChange.ItemID 区分条目
+const (
+	FieldComment       = "comment"
+	FieldChecklist     = "checklist"
+	FieldChecklistDone = "checklist.done"
+	FieldStatus        = "status"
+)
+
+type actorKey struct{}
+
+type loadedKey struct{}
+
+// loaded 一次操作中读取或写入过的任务，按 ID 保存最近一个版本的副本
+type loaded struct {
+	mu    sync.Mutex
+	tasks map[string]models.Task
+}
+
+// WithActor 在 context 中记录执行操作的用户，并开始记住本次操作读取到的任务，
+// 写入时与调用方读取到的版本比较变化，不再重新读取
+func WithActor(ctx context.Context, userID string) context.Context {
+	ctx = context.WithValue(ctx, loadedKey{}, &loaded{tasks: map[string]models.Task{}})
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// Actor 返回 context 中执行操作的用户，未设置时返回空串
+func Actor(ctx context.Context) string {
+	uid, _ := ctx.Value(actorKey{}).(string)
+	return uid
+}
+
+// remember 记住读取或写入后的任务副本，context 未经 WithActor 时忽略
+func remember(ctx context.Context, tasks ...models.Task) {
+	l, _ := ctx.Value(loadedKey{}).(*loaded)
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range tasks {
+		l.tasks[tasks[i].ID] = clone(&tasks[i])
+	}
+}
+
+// recall 返回本次操作读取到的与 task 同一版本的任务
+func recall(ctx context.Context, task *models.Task) *models.Task {
+	l, _ := ctx.Value(loadedKey{}).(*loaded)
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t, ok := l.tasks[task.ID]; ok && t.Version == task.Version && t.CreatedBy == task.CreatedBy {
+		return &t
+	}
+	return nil
+}
+
+// clone 复制任务，调用方就地修改评论、检查项等切片时不影响副本
+func clone(t *models.Task) models.Task {
+	c := *t
+	c.Comments = append([]models.Comment(nil), t.Comments...)
+	c.Checklist = append([]models.ChecklistItem(nil), t.Checklist...)
+	c.BlockedBy = append([]string(nil), t.BlockedBy...)
+	c.Labels = append([]string(nil), t.Labels...)
+	return c
+}
+
+// TaskRepository 包装任务存储，写入时记录历史。存储支持 repository.HistoryTaskRepository 时历史随任务
+// 在同一事务中写入；否则在任务写入后记录，历史写入失败只记日志，不影响任务写入
+type TaskRepository struct {
+	repository.TaskRepository
+	history repository.HistoryRepository
+}
+
+// NewTaskRepository 创建记录历史的任务存储
+func NewTaskRepository(tasks repository.TaskRepository, history repository.HistoryRepository) *TaskRepository {
+	return &TaskRepository{TaskRepository: tasks, history: history}
+}
+
+// Get 获取任务，并记住读取到的版本供写入时比较
+func (r *TaskRepository) Get(ctx context.Context, userID, id string) (*models.Task, error) {
+	task, err := r.TaskRepository.Get(ctx, userID, id)
+	if err == nil {
+		remember(ctx, *task)
+	}
+	return task, err
+}
+
+// List 查询任务，并记住读取到的版本供写入时比较
+func (r *TaskRepository) List(ctx context.Context, filter repository.TaskFilter) ([]models.Task, error) {
+	tasks, err := r.TaskRepository.List(ctx, filter)
+	if err == nil {
+		remember(ctx, tasks...)
+	}
+	return tasks, err
+}
+
+// Create 保存任务并记录各字段的初始值
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	return single(r.BulkUpdate(ctx, nil, task))
+}
+
+// Update 覆盖任务并记录变化的字段；没有变化时不记录
+func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
+	tasks := []models.Task{*task}
+	if err := single(r.BulkUpdate(ctx, tasks)); err != nil {
+		return err
+	}
+	task.Version = tasks[0].Version
+	return nil
+}
+
+// single 将只有一条写入的批量写入错误还原为单条写入的错误
+func single(err error) error {
+	var partial *repository.PartialError
+	if errors.As(err, &partial) {
+		return partial.Err
+	}
+	return err
+}
+
+// BulkUpdate 批量覆盖与创建任务，并为每个有变化或新建的任务各记录一条历史；部分写入时只记录已生效的任务。
+// 覆盖的任务与本次操作读取到的版本比较，未读取过的任务才从存储中读取
+func (r *TaskRepository) BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error {
+	before, err := r.before(ctx, tasks)
+	if err != nil {
+		return err
+	}
+	events := func(written int) []*models.TaskEvent {
+		var out []*models.TaskEvent
+		for i := 0; i < written && i < len(tasks); i++ {
+			if changes := Diff(before[i], &tasks[i]); len(changes) > 0 {
+				out = append(out, event(ctx, &tasks[i], action(before[i], &tasks[i], changes), changes))
+			}
+		}
+		for i := len(tasks); i < written; i++ {
+			task := created[i-len(tasks)]
+			out = append(out, event(ctx, task, models.ActionCreated, Diff(&models.Task{}, task)))
+		}
+		return out
+	}
+	if store, ok := r.TaskRepository.(repository.HistoryTaskRepository); ok {
+		err = store.BulkUpdateWithHistory(ctx, tasks, created, events)
+	} else {
+		err = r.TaskRepository.BulkUpdate(ctx, tasks, created...)
+		for _, e := range events(repository.Written(err, len(tasks)+len(created))) {
+			r.save(ctx, e)
+		}
+	}
+	written := repository.Written(err, len(tasks)+len(created))
+	for i := 0; i < written; i++ {
+		if i < len(tasks) {
+			remember(ctx, tasks[i])
+		} else {
+			remember(ctx, *created[i-len(tasks)])
+		}
+	}
+	return err
+}
+
+// before 返回与 tasks 一一对应的写入前的任务：优先取本次操作读取到的同一版本，其余一次性从存储读取；
+// 任一任务不存在或不属于其 CreatedBy 时返回 repository.ErrNotFound
+func (r *TaskRepository) before(ctx context.Context, tasks []models.Task) ([]*models.Task, error) {
+	before := make([]*models.Task, len(tasks))
+	var missing []string
+	for i := range tasks {
+		if before[i] = recall(ctx, &tasks[i]); before[i] == nil {
+			missing = append(missing, tasks[i].ID)
+		}
+	}
+	if len(missing) == 0 {
+		return before, nil
+	}
+	list, err := r.TaskRepository.List(ctx, repository.TaskFilter{IDs: missing, Trash: repository.WithTrashed})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Task, len(list))
+	for i := range list {
+		byID[list[i].ID] = &list[i]
+	}
+	for i := range tasks {
+		if before[i] != nil {
+			continue
+		}
+		if b := byID[tasks[i].ID]; b != nil && b.CreatedBy == tasks[i].CreatedBy {
+			before[i] = b
+			continue
+		}
+		return nil, repository.ErrNotFound
+	}
+	return before, nil
+}
+
+// Delete 永久删除任务并记录删除前各字段的值
+func (r *TaskRepository) Delete(ctx context.Context, userID, id string) error {
+	before, err := r.find(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := r.TaskRepository.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+	r.save(ctx, event(ctx, before, models.ActionPurged, Diff(before, &models.Task{})))
+	return nil
+}
+
+// find 获取任务，回收站中的任务同样返回
+func (r *TaskRepository) find(ctx context.Context, userID, id string) (*models.Task, error) {
+	list, err := r.TaskRepository.List(ctx, repository.TaskFilter{UserID: userID, IDs: []string{id}, Trash: repository.WithTrashed})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &list[0], nil
+}
+
+// action 根据变化判断操作类型：移入或移出回收站优先，只有评论变化时为 commented
+func action(before, after *models.Task, changes []models.FieldChange) string {
+	switch {
+	case before.DeletedAt == nil && after.DeletedAt != nil:
+		return models.ActionDeleted
+	case before.DeletedAt != nil && after.DeletedAt == nil:
+		return models.ActionRestored
+	}
+	for _, c := range changes {
+		if c.Field != FieldComment {
+			return models.ActionUpdated
+		}
+	}
+	return models.ActionCommented
+}
+
+// event 生成一条历史记录，未指定操作者时视为任务所属用户
+func event(ctx context.Context, task *models.Task, action string, changes []models.FieldChange) *models.TaskEvent {
+	actor := Actor(ctx)
+	if actor == "" {
+		actor = task.CreatedBy
+	}
+	return &models.TaskEvent{
+		UserID:    task.CreatedBy,
+		TaskID:    task.ID,
+		Actor:     actor,
+		Action:    action,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}
+}
+
+// save 在任务写入之后单独保存历史记录，失败只记日志
+func (r *TaskRepository) save(ctx context.Context, e *models.TaskEvent) {
+	if err := r.history.Create(ctx, e); err != nil {
+		observability.LogError("Failed to record history for task %s: %v", e.TaskID, err)
+	}
+}
+
+// Diff 比较任务的前后状态，按固定字段顺序返回变化；评论与检查项按 ID 逐条比较
+func Diff(before, after *models.Task) []models.FieldChange {
+	changes := []models.FieldChange{}
+	add := func(field, itemID string, old, new *string) {
+		if old == nil && new == nil || old != nil && new != nil && *old == *new {
+			return
+		}
+		changes = append(changes, models.FieldChange{Field: field, ItemID: itemID, Old: old, New: new})
+	}
+	add("title", "", text(before.Title), text(after.Title))
+	add("description", "", text(before.Description), text(after.Description))
+	add(FieldStatus, "", text(before.Status), text(after.Status))
+	add("priority", "", text(before.Priority), text(after.Priority))
+	add("assignee", "", before.Assignee, after.Assignee)
+	add("deadline", "", date(before.Deadline), date(after.Deadline))
+	add("scheduledDate", "", date(before.ScheduledDate), date(after.ScheduledDate))
+	add("parentId", "", before.ParentID, after.ParentID)
+	add("projectId", "", before.ProjectID, after.ProjectID)
+	add("labels", "", list(before.Labels), list(after.Labels))
+	add("blockedBy", "", list(before.BlockedBy), list(after.BlockedBy))
+	add("recurrence", "", recurrence(before.Recurrence), recurrence(after.Recurrence))
+	add("deletedAt", "", date(before.DeletedAt), date(after.DeletedAt))
+
+	oldComments := make(map[string]string, len(before.Comments))
+	for _, c := range before.Comments {
+		oldComments[c.ID] = c.Text
+	}
+	newComments := make(map[string]bool, len(after.Comments))
+	for _, c := range after.Comments {
+		newComments[c.ID] = true
+		var old *string
+		if t, ok := oldComments[c.ID]; ok {
+			old = &t
+		}
+		add(FieldComment, c.ID, old, text(c.Text))
+	}
+	for _, c := range before.Comments {
+		if !newComments[c.ID] {
+			add(FieldComment, c.ID, text(c.Text), nil)
+		}
+	}
+
+	oldItems := make(map[string]models.ChecklistItem, len(before.Checklist))
+	for _, item := range before.Checklist {
+		oldItems[item.ID] = item
+	}
+	newItems := make(map[string]bool, len(after.Checklist))
+	for _, item := range after.Checklist {
+		newItems[item.ID] = true
+		prev, ok := oldItems[item.ID]
+		if !ok {
+			add(FieldChecklist, item.ID, nil, text(item.Text))
+			if item.Done {
+				add(FieldChecklistDone, item.ID, nil, boolean(true))
+			}
+			continue
+		}
+		add(FieldChecklist, item.ID, text(prev.Text), text(item.Text))
+		add(FieldChecklistDone, item.ID, boolean(prev.Done), boolean(item.Done))
+	}
+	for _, item := range before.Checklist {
+		if !newItems[item.ID] {
+			add(FieldChecklist, item.ID, text(item.Text), nil)
+		}
+	}
+	return changes
+}
+
+// text 空串视为未设置
+func text(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func boolean(b bool) *string {
+	s := strconv.FormatBool(b)
+	return &s
+}
+
+func date(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
+
+// list 空列表视为未设置，其余编码为 JSON 数组
+func list(values []string) *string {
+	if len(values) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(values)
+	s := string(b)
+	return &s
+}
+
+func recurrence(r *models.Recurrence) *string {
+	if r == nil {
+		return nil
+	}
+	b, _ := json.Marshal(r)
+	s := string(b)
+	return &s
+}
+
+// ForTasks 返回 tasks 在 [from, to] 内的历史记录，history 为 nil 时返回空
+func ForTasks(ctx context.Context, history repository.HistoryRepository, userID string, tasks []models.Task, from, to time.Time) ([]models.TaskEvent, error) {
+	if history == nil || len(tasks) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return history.List(ctx, repository.HistoryFilter{UserID: userID, TaskIDs: ids, From: &from, To: &to})
+}
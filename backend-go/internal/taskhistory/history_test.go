@@ -0,0 +1,147 @@
+package taskhistory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestDiff(t *testing.T) {
+	deadline := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := &models.Task{Title: "写周报", Status: "To Do", Labels: []string{"工作"},
+		Comments:  []models.Comment{{ID: "c1", Text: "先列提纲"}},
+		Checklist: []models.ChecklistItem{{ID: "i1", Text: "收集数据"}, {ID: "i2", Text: "画图"}}}
+	after := &models.Task{Title: "写周报", Status: "Done", Deadline: &deadline, Labels: []string{"工作", "紧急"},
+		Comments:  []models.Comment{{ID: "c1", Text: "先列提纲"}, {ID: "c2", Text: "已发"}},
+		Checklist: []models.ChecklistItem{{ID: "i1", Text: "收集数据", Done: true}}}
+
+	got := Diff(before, after)
+	want := []struct{ field, item, old, new string }{
+		{"status", "", "To Do", "Done"},
+		{"deadline", "", "", "2024-03-01T12:00:00Z"},
+		{"labels", "", `["工作"]`, `["工作","紧急"]`},
+		{"comment", "c2", "", "已发"},
+		{"checklist.done", "i1", "false", "true"},
+		{"checklist", "i2", "画图", ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d changes, got %+v", len(want), got)
+	}
+	str := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	for i, w := range want {
+		c := got[i]
+		if c.Field != w.field || c.ItemID != w.item || str(c.Old) != w.old || str(c.New) != w.new {
+			t.Errorf("Change %d: expected %+v, got %s %s %q %q", i, w, c.Field, c.ItemID, str(c.Old), str(c.New))
+		}
+	}
+	if len(Diff(after, after)) != 0 {
+		t.Error("Expected no changes for identical tasks")
+	}
+}
+
+func TestRecording(t *testing.T) {
+	history := memory.NewHistoryRepository()
+	tasks := NewTaskRepository(memory.NewTaskRepository(), history)
+	ctx := context.Background()
+	now := time.Now()
+
+	task := &models.Task{Title: "写周报", Status: "To Do", CreatedBy: "u1", CreatedAt: now, UpdatedAt: now}
+	if err := tasks.Create(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	task.Comments = []models.Comment{{ID: "c1", Text: "看一下", CreatedBy: "u2", CreatedAt: now}}
+	if err := tasks.Update(WithActor(ctx, "u2"), task); err != nil {
+		t.Fatal(err)
+	}
+	if err := tasks.Update(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	task.Status = "Done"
+	if err := tasks.Update(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	task.DeletedAt = &now
+	if err := tasks.Update(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	task.DeletedAt = nil
+	if err := tasks.Update(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	if err := tasks.Delete(ctx, "u1", task.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := history.List(ctx, repository.HistoryFilter{UserID: "u1", TaskIDs: []string{task.ID}})
+	if err != nil || len(events) != 6 {
+		t.Fatalf("Expected 6 events, got %+v (%v)", events, err)
+	}
+	actions := []string{models.ActionCreated, models.ActionCommented, models.ActionUpdated,
+		models.ActionDeleted, models.ActionRestored, models.ActionPurged}
+	for i, a := range actions {
+		if events[i].Action != a {
+			t.Errorf("Event %d: expected %s, got %s", i, a, events[i].Action)
+		}
+	}
+	if events[1].Actor != "u2" || events[2].Actor != "u1" {
+		t.Errorf("Unexpected actors %s %s", events[1].Actor, events[2].Actor)
+	}
+	if c := events[2].Changes; len(c) != 1 || c[0].Field != FieldStatus || *c[0].Old != "To Do" || *c[0].New != "Done" {
+		t.Errorf("Unexpected status change %+v", c)
+	}
+	if err := tasks.Delete(ctx, "u1", task.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when deleting twice, got %v", err)
+	}
+}
+
+// countingTasks 统计对存储的 List 调用
+type countingTasks struct {
+	*memory.TaskRepository
+	lists int
+}
+
+func (r *countingTasks) List(ctx context.Context, filter repository.TaskFilter) ([]models.Task, error) {
+	r.lists++
+	return r.TaskRepository.List(ctx, filter)
+}
+
+func TestRecordingLoaded(t *testing.T) {
+	history := memory.NewHistoryRepository()
+	inner := &countingTasks{TaskRepository: memory.NewTaskRepository()}
+	tasks := NewTaskRepository(inner, history)
+	ctx := WithActor(context.Background(), "u1")
+	now := time.Now()
+
+	created := &models.Task{Title: "写周报", Status: "To Do", CreatedBy: "u1", CreatedAt: now, UpdatedAt: now,
+		Checklist: []models.ChecklistItem{{ID: "i1", Text: "初稿"}}}
+	if err := tasks.Create(ctx, created); err != nil {
+		t.Fatal(err)
+	}
+	// 与读取到的版本比较，不再读取存储；就地修改检查项不影响记住的副本
+	task, _ := tasks.Get(ctx, "u1", created.ID)
+	task.Checklist[0].Done = true
+	if err := tasks.Update(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	task.Status = "Done"
+	if err := tasks.Update(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	if inner.lists != 0 {
+		t.Errorf("Expected no extra reads, got %d", inner.lists)
+	}
+	events, _ := history.List(ctx, repository.HistoryFilter{TaskIDs: []string{task.ID}})
+	if len(events) != 3 || events[1].Changes[0].Field != FieldChecklistDone || events[2].Changes[0].Field != FieldStatus {
+		t.Errorf("Unexpected events %+v", events)
+	}
+}
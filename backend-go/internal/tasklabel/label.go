@@ -0,0 +1,103 @@
+// Package tasklabel 处理标签与任务的关联：校验标签、将请求中的名称解析为已有标签，
+// 以及重命名、合并或删除标签时批量改写任务。HTTP 处理器与 gRPC 服务共用这些逻辑。
+package tasklabel
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// MaxNameLength 标签名称的最大字符数
+const MaxNameLength = 50
+
+// ErrInvalid 标签名称或颜色不合法
+var ErrInvalid = errors.New("invalid label")
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// UnknownError 请求中引用了用户没有的标签
+type UnknownError struct {
+	Name string
+}
+
+func (e *UnknownError) Error() string { return "label not found: " + e.Name }
+
+// Validate 去除名称首尾空白并校验名称与颜色；颜色为 #RRGGBB，可为空
+func Validate(label *models.Label) error {
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" || utf8.RuneCountInString(label.Name) > MaxNameLength {
+		return ErrInvalid
+	}
+	if label.Color != "" && !colorPattern.MatchString(label.Color) {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// Resolve 将名称按 known 中的标签规范化为其名称（不区分大小写），去重并保持顺序；
+// 名称不存在时返回 *UnknownError
+func Resolve(known []models.Label, names []string) ([]string, error) {
+	byKey := make(map[string]string, len(known))
+	for _, l := range known {
+		byKey[strings.ToLower(l.Name)] = l.Name
+	}
+	out := make([]string, 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		canonical, ok := byKey[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, &UnknownError{Name: name}
+		}
+		if !seen[canonical] {
+			seen[canonical] = true
+			out = append(out, canonical)
+		}
+	}
+	return out, nil
+}
+
+// Rewrite 将 userID 名下带有 from 中任一标签的任务改为带有 to，to 为空表示移除，
+// 标签位置与其余标签保持不变，回收站中的任务一并修改；返回被修改的任务数
+func Rewrite(ctx context.Context, tasks repository.TaskRepository, userID string, from []string, to string, now time.Time) (int, error) {
+	list, err := tasks.List(ctx, repository.TaskFilter{UserID: userID, Labels: from, Trash: repository.WithTrashed})
+	if err != nil {
+		return 0, err
+	}
+	old := make(map[string]bool, len(from))
+	for _, name := range from {
+		old[name] = true
+	}
+	for i := range list {
+		task := &list[i]
+		task.Labels = replace(task.Labels, old, to)
+		task.UpdatedAt = now
+		if err := tasks.Update(ctx, task); err != nil {
+			return i, err
+		}
+	}
+	return len(list), nil
+}
+
+// replace 将 labels 中属于 old 的名称替换为 to 并去重
+func replace(labels []string, old map[string]bool, to string) []string {
+	out := make([]string, 0, len(labels))
+	seen := map[string]bool{}
+	for _, name := range labels {
+		if old[name] {
+			name = to
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	return out
+}
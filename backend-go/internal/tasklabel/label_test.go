@@ -0,0 +1,77 @@
+package tasklabel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestValidate(t *testing.T) {
+	label := &models.Label{Name: "  紧急 ", Color: "#FF0000"}
+	if err := Validate(label); err != nil || label.Name != "紧急" {
+		t.Errorf("Expected trimmed name, got %q (%v)", label.Name, err)
+	}
+	for _, l := range []models.Label{
+		{Name: " "},
+		{Name: strings.Repeat("标", MaxNameLength+1)},
+		{Name: "x", Color: "red"},
+		{Name: "x", Color: "#fff"},
+	} {
+		if err := Validate(&l); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Validate(%+v): expected ErrInvalid, got %v", l, err)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	known := []models.Label{{Name: "Bug"}, {Name: "工作"}}
+	got, err := Resolve(known, []string{"bug", "工作", "BUG"})
+	if err != nil || !reflect.DeepEqual(got, []string{"Bug", "工作"}) {
+		t.Errorf("Expected [Bug 工作], got %v (%v)", got, err)
+	}
+	var unknown *UnknownError
+	if _, err := Resolve(known, []string{"Bug", "docs"}); !errors.As(err, &unknown) || unknown.Name != "docs" {
+		t.Errorf("Expected UnknownError for docs, got %v", err)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewTaskRepository()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	create := func(labels ...string) string {
+		task := &models.Task{Title: "t", CreatedBy: "u1", CreatedAt: now, Labels: labels}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task.ID
+	}
+	a := create("bug", "工作")
+	b := create("defect", "bug")
+	c := create("工作")
+	later := now.Add(time.Hour)
+
+	// 合并 defect 到 bug
+	if n, err := Rewrite(ctx, repo, "u1", []string{"defect"}, "bug", later); err != nil || n != 1 {
+		t.Fatalf("Rewrite merge: %d tasks (%v)", n, err)
+	}
+	if got, _ := repo.Get(ctx, "u1", b); !reflect.DeepEqual(got.Labels, []string{"bug"}) || !got.UpdatedAt.Equal(later) {
+		t.Errorf("Expected merged labels [bug], got %+v", got)
+	}
+	// 删除 工作
+	if n, _ := Rewrite(ctx, repo, "u1", []string{"工作"}, "", later); n != 2 {
+		t.Errorf("Expected 2 tasks to lose 工作, got %d", n)
+	}
+	if got, _ := repo.Get(ctx, "u1", a); !reflect.DeepEqual(got.Labels, []string{"bug"}) {
+		t.Errorf("Expected [bug], got %v", got.Labels)
+	}
+	if got, _ := repo.Get(ctx, "u1", c); len(got.Labels) != 0 {
+		t.Errorf("Expected no labels, got %v", got.Labels)
+	}
+}
@@ -0,0 +1,196 @@
+// Package taskproject 处理项目与任务的关联：校验项目、检查任务能否归入项目、
+// 按项目移动任务子树、统计各项目的任务以及删除项目时移出任务。
+// HTTP 处理器与 gRPC 服务共用这些逻辑。
+package taskproject
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/report"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/tasktree"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+// MaxNameLength 项目名称的最大字符数
+const MaxNameLength = 100
+
+// ErrInvalid 项目名称不合法
+var ErrInvalid = errors.New("invalid project")
+
+// ErrArchived 项目已归档，不能再加入任务
+var ErrArchived = errors.New("project is archived")
+
+// Validate 去除名称首尾空白并校验名称长度
+func Validate(project *models.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" || utf8.RuneCountInString(project.Name) > MaxNameLength {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// Get 获取属于 userID 的项目；未配置项目存储时视为项目不存在
+func Get(ctx context.Context, projects repository.ProjectRepository, userID, id string) (*models.Project, error) {
+	if projects == nil {
+		return nil, repository.ErrNotFound
+	}
+	return projects.Get(ctx, userID, id)
+}
+
+// Open 获取可以加入任务的项目；项目不存在时返回 repository.ErrNotFound，已归档时返回 ErrArchived
+func Open(ctx context.Context, projects repository.ProjectRepository, userID, id string) (*models.Project, error) {
+	p, err := Get(ctx, projects, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Archived {
+		return nil, ErrArchived
+	}
+	return p, nil
+}
+
+// NextPosition 返回排在现有项目之后的位置
+func NextPosition(list []models.Project) int {
+	next := 0
+	for _, p := range list {
+		if p.Position >= next {
+			next = p.Position + 1
+		}
+	}
+	return next
+}
+
+// Reorder 按 ids 的顺序重新编排位置，未列出的项目保持原有相对顺序排在之后；
+// list 须按当前位置排序，返回位置有变化的项目。ids 中不属于 list 的 ID 返回 repository.ErrNotFound
+func Reorder(list []models.Project, ids []string) ([]*models.Project, error) {
+	byID := make(map[string]*models.Project, len(list))
+	for i := range list {
+		byID[list[i].ID] = &list[i]
+	}
+	ordered := make([]*models.Project, 0, len(list))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		p, ok := byID[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		if !seen[id] {
+			seen[id] = true
+			ordered = append(ordered, p)
+		}
+	}
+	for i := range list {
+		if !seen[list[i].ID] {
+			ordered = append(ordered, &list[i])
+		}
+	}
+	var changed []*models.Project
+	for pos, p := range ordered {
+		if p.Position != pos {
+			p.Position = pos
+			changed = append(changed, p)
+		}
+	}
+	return changed, nil
+}
+
+// Move 将 ids 对应的任务及其全部后代移到项目 projectID，nil 表示移出项目；
+// 只写入有变化的任务，返回被修改的任务数。任务不存在时返回 repository.ErrNotFound
+func Move(ctx context.Context, tasks repository.TaskRepository, userID string, ids []string, projectID *string, now time.Time) (int, error) {
+	roots := make([]models.Task, 0, len(ids))
+	for _, id := range ids {
+		t, err := tasks.Get(ctx, userID, id)
+		if err != nil {
+			return 0, err
+		}
+		roots = append(roots, *t)
+	}
+	all, err := tasktree.WithDescendants(ctx, tasks, userID, dedupe(roots))
+	if err != nil {
+		return 0, err
+	}
+	updated := 0
+	for i := range all {
+		task := &all[i]
+		if sameProject(task.ProjectID, projectID) {
+			continue
+		}
+		task.ProjectID = projectID
+		task.UpdatedAt = now
+		if err := tasks.Update(ctx, task); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// Detach 将项目内的全部任务（含回收站中的任务）移出项目，返回被修改的任务数
+func Detach(ctx context.Context, tasks repository.TaskRepository, userID, projectID string, now time.Time) (int, error) {
+	list, err := tasks.List(ctx, repository.TaskFilter{UserID: userID, ProjectIDs: []string{projectID}, Trash: repository.WithTrashed})
+	if err != nil {
+		return 0, err
+	}
+	for i := range list {
+		list[i].ProjectID = nil
+		list[i].UpdatedAt = now
+		if err := tasks.Update(ctx, &list[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(list), nil
+}
+
+// Stats 按项目统计任务，统计口径与报表一致；没有任务的项目也有一条全零的统计
+func Stats(ctx context.Context, tasks repository.TaskRepository, flows *workflow.Set, userID string, projectIDs []string, now time.Time) (map[string]models.Statistics, error) {
+	list, err := tasks.List(ctx, repository.TaskFilter{UserID: userID, ProjectIDs: projectIDs})
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string][]models.Task, len(projectIDs))
+	for _, t := range list {
+		groups[*t.ProjectID] = append(groups[*t.ProjectID], t)
+	}
+	out := make(map[string]models.Statistics, len(projectIDs))
+	for _, id := range projectIDs {
+		out[id] = report.Stats(groups[id], now, flows)
+	}
+	return out, nil
+}
+
+// InProject 只保留属于 projectID 的任务
+func InProject(tasks []models.Task, projectID string) []models.Task {
+	out := tasks[:0]
+	for _, t := range tasks {
+		if t.ProjectID != nil && *t.ProjectID == projectID {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func sameProject(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// dedupe 去除重复的任务，保持顺序
+func dedupe(tasks []models.Task) []models.Task {
+	seen := make(map[string]bool, len(tasks))
+	out := tasks[:0]
+	for _, t := range tasks {
+		if !seen[t.ID] {
+			seen[t.ID] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package taskproject
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func TestValidate(t *testing.T) {
+	p := &models.Project{Name: "  工作 "}
+	if err := Validate(p); err != nil || p.Name != "工作" {
+		t.Errorf("Expected trimmed name, got %q (%v)", p.Name, err)
+	}
+	for _, name := range []string{" ", strings.Repeat("项", MaxNameLength+1)} {
+		if err := Validate(&models.Project{Name: name}); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Validate(%q): expected ErrInvalid, got %v", name, err)
+		}
+	}
+}
+
+func TestReorder(t *testing.T) {
+	list := []models.Project{{ID: "a", Position: 0}, {ID: "b", Position: 1}, {ID: "c", Position: 2}}
+	changed, err := Reorder(list, []string{"c", "a"})
+	if err != nil {
+		t.Fatalf("Reorder failed: %v", err)
+	}
+	want := map[string]int{"c": 0, "a": 1, "b": 2}
+	for _, p := range list {
+		if p.Position != want[p.ID] {
+			t.Errorf("Project %s: expected position %d, got %d", p.ID, want[p.ID], p.Position)
+		}
+	}
+	if len(changed) != 3 {
+		t.Errorf("Expected 3 changed projects, got %d", len(changed))
+	}
+	if _, err := Reorder(list, []string{"x"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for unknown project, got %v", err)
+	}
+	if NextPosition(list) != 3 || NextPosition(nil) != 0 {
+		t.Errorf("Unexpected next position")
+	}
+}
+
+func TestMoveAndDetach(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewTaskRepository()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	other := "other"
+	newTask := func(parent *string, project *string) *models.Task {
+		task := &models.Task{Title: "t", Status: "To Do", CreatedBy: "u1", CreatedAt: now, UpdatedAt: now, ParentID: parent, ProjectID: project}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task
+	}
+	root := newTask(nil, nil)
+	child := newTask(&root.ID, &other)
+	newTask(&child.ID, nil)
+	newTask(nil, nil)
+
+	target := "p1"
+	n, err := Move(ctx, repo, "u1", []string{root.ID, root.ID}, &target, now.Add(time.Hour))
+	if err != nil || n != 3 {
+		t.Fatalf("Expected 3 tasks moved, got %d (%v)", n, err)
+	}
+	if n, _ := Move(ctx, repo, "u1", []string{child.ID}, &target, now); n != 0 {
+		t.Errorf("Expected no changes when already in project, got %d", n)
+	}
+	if _, err := Move(ctx, repo, "u1", []string{"missing"}, &target, now); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	stats, err := Stats(ctx, repo, nil, "u1", []string{"p1", "p2"}, now)
+	if err != nil || stats["p1"].TotalTasks != 3 || stats["p2"].TotalTasks != 0 {
+		t.Errorf("Unexpected stats %v (%v)", stats, err)
+	}
+
+	if n, err := Detach(ctx, repo, "u1", "p1", now); err != nil || n != 3 {
+		t.Errorf("Expected 3 tasks detached, got %d (%v)", n, err)
+	}
+	if n, _ := repo.Count(ctx, repository.TaskFilter{UserID: "u1", ProjectIDs: []string{"p1"}}); n != 0 {
+		t.Errorf("Expected no tasks left in project, got %d", n)
+	}
+}
@@ -0,0 +1,193 @@
+// Package tasktree 处理任务的父子关系：加载子树、汇总进度、校验移动是否成环，
+// 以及级联完成与级联删除。HTTP 处理器、gRPC 服务与报表生成共用这些逻辑。
+package tasktree
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/workflow"
+)
+
+// ErrCycle 将任务移动到自身或其子任务之下
+var ErrCycle = errors.New("task cannot be moved under itself or one of its subtasks")
+
+// Node 子树节点，Progress 为 0-100 的完成度
+type Node struct {
+	Task     models.Task
+	Progress int
+	Children []*Node
+}
+
+// Load 逐层加载 task 的全部子任务并计算各节点进度，子任务按创建时间正序；
+// flows 为用户的工作流，用于判断任务是否已完成
+func Load(ctx context.Context, repo repository.TaskRepository, flows *workflow.Set, task *models.Task) (*Node, error) {
+	root := &Node{Task: *task}
+	level := []*Node{root}
+	seen := map[string]bool{task.ID: true}
+	for len(level) > 0 {
+		byID := make(map[string]*Node, len(level))
+		ids := make([]string, 0, len(level))
+		for _, n := range level {
+			byID[n.Task.ID] = n
+			ids = append(ids, n.Task.ID)
+		}
+		children, err := repo.List(ctx, repository.TaskFilter{
+			UserID:    task.CreatedBy,
+			ParentIDs: ids,
+			Sort:      repository.TaskSort{Field: repository.SortByCreatedAt, Asc: true},
+		})
+		if err != nil {
+			return nil, err
+		}
+		level = level[:0:0]
+		for _, c := range children {
+			if seen[c.ID] { // 防御历史数据中的环
+				continue
+			}
+			seen[c.ID] = true
+			n := &Node{Task: c}
+			parent := byID[*c.ParentID]
+			parent.Children = append(parent.Children, n)
+			level = append(level, n)
+		}
+	}
+	computeProgress(root, flows)
+	return root, nil
+}
+
+// computeProgress 已完成的任务为 100；否则按检查项与子任务等权平均，
+// 子任务按其自身进度计入，既无检查项也无子任务时为 0
+func computeProgress(n *Node, flows *workflow.Set) float64 {
+	var sum float64
+	for _, c := range n.Children {
+		sum += computeProgress(c, flows)
+	}
+	units := len(n.Task.Checklist) + len(n.Children)
+	var p float64
+	switch {
+	case flows.Done(&n.Task):
+		p = 1
+	case units > 0:
+		for _, item := range n.Task.Checklist {
+			if item.Done {
+				sum++
+			}
+		}
+		p = sum / float64(units)
+	}
+	n.Progress = int(math.Round(p * 100))
+	return p
+}
+
+// Walk 先序遍历子树
+func (n *Node) Walk(fn func(*Node)) {
+	fn(n)
+	for _, c := range n.Children {
+		c.Walk(fn)
+	}
+}
+
+// Descendants 返回 ids 的全部后代（不含 ids 本身），按层序排列
+func Descendants(ctx context.Context, repo repository.TaskRepository, userID string, ids []string) ([]models.Task, error) {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	var out []models.Task
+	for len(ids) > 0 {
+		children, err := repo.List(ctx, repository.TaskFilter{UserID: userID, ParentIDs: ids})
+		if err != nil {
+			return nil, err
+		}
+		ids = nil
+		for _, c := range children {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			out = append(out, c)
+			ids = append(ids, c.ID)
+		}
+	}
+	return out, nil
+}
+
+// WithDescendants 在 tasks 之后追加其中尚未包含的后代任务
+func WithDescendants(ctx context.Context, repo repository.TaskRepository, userID string, tasks []models.Task) ([]models.Task, error) {
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	more, err := Descendants(ctx, repo, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return append(tasks, more...), nil
+}
+
+// CheckParent 校验 parentID 存在且不是 taskID 自身或其后代；父任务不存在时返回 repository.ErrNotFound
+func CheckParent(ctx context.Context, repo repository.TaskRepository, userID, taskID, parentID string) error {
+	seen := map[string]bool{}
+	for id := parentID; ; {
+		if id == taskID {
+			return ErrCycle
+		}
+		parent, err := repo.Get(ctx, userID, id)
+		if err != nil {
+			return err
+		}
+		seen[id] = true
+		if parent.ParentID == nil || seen[*parent.ParentID] {
+			return nil
+		}
+		id = *parent.ParentID
+	}
+}
+
+// Complete 将任务及其全部检查项标记为完成；未处于完成类状态的任务改为其工作流的完成状态
+func Complete(task *models.Task, flows *workflow.Set) {
+	if !flows.Done(task) {
+		task.Status = workflow.DoneStatus(flows.For(task.ProjectID))
+	}
+	for i := range task.Checklist {
+		task.Checklist[i].Done = true
+	}
+}
+
+// CompleteDescendants 级联完成任务的全部后代，返回有变化的后代供调用方与任务本身一并写入
+func CompleteDescendants(ctx context.Context, repo repository.TaskRepository, flows *workflow.Set, userID, id string, now time.Time) ([]models.Task, error) {
+	tasks, err := Descendants(ctx, repo, userID, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	changed := tasks[:0]
+	for _, t := range tasks {
+		if isComplete(&t, flows) {
+			continue
+		}
+		Complete(&t, flows)
+		t.UpdatedAt = now
+		changed = append(changed, t)
+	}
+	return changed, nil
+}
+
+func isComplete(t *models.Task, flows *workflow.Set) bool {
+	if !flows.Done(t) {
+		return false
+	}
+	for _, item := range t.Checklist {
+		if !item.Done {
+			return false
+		}
+	}
+	return true
+}
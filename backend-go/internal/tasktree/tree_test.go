@@ -0,0 +1,112 @@
+package tasktree
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+// buildTree 创建 root -> (a -> a1, b)，a 带两个检查项，其中一个已完成
+func buildTree(t *testing.T, repo repository.TaskRepository) (root, a, a1, b *models.Task) {
+	t.Helper()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	n := 0
+	create := func(title string, parent *models.Task, checklist ...models.ChecklistItem) *models.Task {
+		n++
+		task := &models.Task{Title: title, Status: "To Do", CreatedBy: "u1", Checklist: checklist,
+			CreatedAt: base.Add(time.Duration(n) * time.Hour)}
+		if parent != nil {
+			task.ParentID = &parent.ID
+		}
+		if err := repo.Create(context.Background(), task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task
+	}
+	root = create("root", nil)
+	a = create("a", root, models.ChecklistItem{ID: "1", Done: true}, models.ChecklistItem{ID: "2"})
+	a1 = create("a1", a)
+	b = create("b", root)
+	return
+}
+
+func TestLoadProgress(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewTaskRepository()
+	root, a, a1, _ := buildTree(t, repo)
+
+	node, err := Load(ctx, repo, nil, root)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(node.Children) != 2 || node.Children[0].Task.Title != "a" || len(node.Children[0].Children) != 1 {
+		t.Fatalf("Unexpected tree shape %+v", node)
+	}
+	// a: 检查项 1/2 + a1 0 → 1/3；root: (1/3 + 0) / 2
+	if node.Children[0].Progress != 33 || node.Progress != 17 {
+		t.Errorf("Expected progress 33/17, got %d/%d", node.Children[0].Progress, node.Progress)
+	}
+
+	a1.Status = "Done"
+	_ = repo.Update(ctx, a1)
+	node, _ = Load(ctx, repo, nil, root)
+	if node.Children[0].Progress != 67 || node.Progress != 33 {
+		t.Errorf("Expected progress 67/33, got %d/%d", node.Children[0].Progress, node.Progress)
+	}
+
+	var titles []string
+	node.Walk(func(n *Node) { titles = append(titles, n.Task.Title) })
+	if len(titles) != 4 || titles[2] != "a1" {
+		t.Errorf("Unexpected walk order %v", titles)
+	}
+	if leaf, _ := Load(ctx, repo, nil, a); leaf.Progress != 67 {
+		t.Errorf("Expected subtree progress 67, got %d", leaf.Progress)
+	}
+}
+
+func TestCheckParent(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewTaskRepository()
+	root, a, a1, b := buildTree(t, repo)
+
+	for _, target := range []string{root.ID, a.ID, a1.ID} {
+		if err := CheckParent(ctx, repo, "u1", root.ID, target); !errors.Is(err, ErrCycle) {
+			t.Errorf("Moving root under %s: expected ErrCycle, got %v", target, err)
+		}
+	}
+	if err := CheckParent(ctx, repo, "u1", a.ID, b.ID); err != nil {
+		t.Errorf("Moving a under b: unexpected error %v", err)
+	}
+	if err := CheckParent(ctx, repo, "u2", a.ID, b.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for other user, got %v", err)
+	}
+}
+
+func TestCascade(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewTaskRepository()
+	root, _, _, _ := buildTree(t, repo)
+
+	now := time.Now()
+	tasks, err := CompleteDescendants(ctx, repo, nil, "u1", root.ID, now)
+	if err != nil {
+		t.Fatalf("CompleteDescendants failed: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("Expected 3 descendants, got %d", len(tasks))
+	}
+	for _, task := range tasks {
+		if !isComplete(&task, nil) {
+			t.Errorf("Expected %s to be complete", task.Title)
+		}
+	}
+	if stored, _ := Descendants(ctx, repo, "u1", []string{root.ID}); isComplete(&stored[0], nil) {
+		t.Error("CompleteDescendants must leave writing to the caller")
+	}
+
+}
@@ -0,0 +1,171 @@
+// Package trash 实现任务与报表的回收站：删除只记录 DeletedAt，之后可以恢复或永久删除，
+// 超过保留期限的记录由 Purge 清理。HTTP 处理器与 gRPC 服务共用这些规则
+package trash
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/depgraph"
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/tasktree"
+)
+
+// TrashTask 将调用方读取的任务及其全部后代在一次批量写入中移入回收站，同一次删除的任务使用相同的 DeletedAt；
+// 返回移入的任务数。任一任务在读取后被修改时返回 repository.ErrVersion，批量写入的原子性见 TaskRepository.BulkUpdate
+func TrashTask(ctx context.Context, tasks repository.TaskRepository, task *models.Task, now time.Time) (int, error) {
+	descendants, err := tasktree.Descendants(ctx, tasks, task.CreatedBy, []string{task.ID})
+	if err != nil {
+		return 0, err
+	}
+	all := append([]models.Task{*task}, descendants...)
+	for i := range all {
+		at := now
+		all[i].DeletedAt = &at
+	}
+	if err := tasks.BulkUpdate(ctx, all); err != nil {
+		return 0, err
+	}
+	return len(all), nil
+}
+
+// RestoreTask 在一次批量写入中恢复回收站中的任务及与其一同删除的后代；父任务已不存在时移为顶层任务。返回恢复的任务数
+func RestoreTask(ctx context.Context, tasks repository.TaskRepository, userID, id string) (int, error) {
+	task, err := trashedTask(ctx, tasks, userID, id)
+	if err != nil {
+		return 0, err
+	}
+	at := *task.DeletedAt
+	restore := []models.Task{*task}
+	for ids := []string{id}; len(ids) > 0; {
+		children, err := tasks.List(ctx, repository.TaskFilter{UserID: userID, ParentIDs: ids, Trash: repository.OnlyTrashed})
+		if err != nil {
+			return 0, err
+		}
+		ids = nil
+		for _, c := range children {
+			if c.DeletedAt.Equal(at) {
+				restore = append(restore, c)
+				ids = append(ids, c.ID)
+			}
+		}
+	}
+	if task.ParentID != nil {
+		if _, err := tasks.Get(ctx, userID, *task.ParentID); errors.Is(err, repository.ErrNotFound) {
+			restore[0].ParentID = nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+	for i := range restore {
+		restore[i].DeletedAt = nil
+	}
+	if err := tasks.BulkUpdate(ctx, restore); err != nil {
+		return 0, err
+	}
+	return len(restore), nil
+}
+
+// PurgeTask 永久删除回收站中的任务及其全部后代，并从其他任务的阻塞列表中移除；返回删除的任务数
+func PurgeTask(ctx context.Context, tasks repository.TaskRepository, userID, id string, now time.Time) (int, error) {
+	if _, err := trashedTask(ctx, tasks, userID, id); err != nil {
+		return 0, err
+	}
+	deleted := []string{id}
+	for ids := []string{id}; len(ids) > 0; {
+		children, err := tasks.List(ctx, repository.TaskFilter{UserID: userID, ParentIDs: ids, Trash: repository.WithTrashed})
+		if err != nil {
+			return 0, err
+		}
+		ids = nil
+		for _, c := range children {
+			deleted = append(deleted, c.ID)
+			ids = append(ids, c.ID)
+		}
+	}
+	// 先删除最深层的子任务
+	for i := len(deleted) - 1; i >= 0; i-- {
+		if err := tasks.Delete(ctx, userID, deleted[i]); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return len(deleted) - 1 - i, err
+		}
+	}
+	return len(deleted), depgraph.Unlink(ctx, tasks, userID, deleted, now)
+}
+
+// trashedTask 获取回收站中的任务，不在回收站中时返回 ErrNotFound
+func trashedTask(ctx context.Context, tasks repository.TaskRepository, userID, id string) (*models.Task, error) {
+	list, err := tasks.List(ctx, repository.TaskFilter{UserID: userID, IDs: []string{id}, Trash: repository.OnlyTrashed})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &list[0], nil
+}
+
+// TrashReport 将报表移入回收站
+func TrashReport(ctx context.Context, reports repository.ReportRepository, report *models.Report, now time.Time) error {
+	rep := *report
+	rep.DeletedAt = &now
+	return reports.Update(ctx, &rep)
+}
+
+// RestoreReport 恢复回收站中的报表
+func RestoreReport(ctx context.Context, reports repository.ReportRepository, userID, id string) error {
+	rep, err := trashedReport(ctx, reports, userID, id)
+	if err != nil {
+		return err
+	}
+	rep.DeletedAt = nil
+	return reports.Update(ctx, rep)
+}
+
+// PurgeReport 永久删除回收站中的报表
+func PurgeReport(ctx context.Context, reports repository.ReportRepository, userID, id string) error {
+	if _, err := trashedReport(ctx, reports, userID, id); err != nil {
+		return err
+	}
+	return reports.Delete(ctx, userID, id)
+}
+
+// trashedReport 获取回收站中的报表，不在回收站中时返回 ErrNotFound
+func trashedReport(ctx context.Context, reports repository.ReportRepository, userID, id string) (*models.Report, error) {
+	list, err := reports.List(ctx, repository.ReportFilter{UserID: userID, IDs: []string{id}, Trash: repository.OnlyTrashed})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &list[0], nil
+}
+
+// Purge 永久删除所有用户在 before 之前移入回收站的任务与报表，返回删除的任务数与报表数
+func Purge(ctx context.Context, tasks repository.TaskRepository, reports repository.ReportRepository, before, now time.Time) (int, int, error) {
+	expired, err := tasks.List(ctx, repository.TaskFilter{Trash: repository.OnlyTrashed, DeletedTo: &before})
+	if err != nil {
+		return 0, 0, err
+	}
+	purgedTasks := 0
+	for _, t := range expired {
+		// 随父任务一起删除的子任务不再单独处理
+		n, err := PurgeTask(ctx, tasks, t.CreatedBy, t.ID, now)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return purgedTasks, 0, err
+		}
+		purgedTasks += n
+	}
+	expiredReports, err := reports.List(ctx, repository.ReportFilter{Trash: repository.OnlyTrashed, DeletedTo: &before})
+	if err != nil {
+		return purgedTasks, 0, err
+	}
+	for i, rep := range expiredReports {
+		if err := reports.Delete(ctx, rep.UserID, rep.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return purgedTasks, i, err
+		}
+	}
+	return purgedTasks, len(expiredReports), nil
+}
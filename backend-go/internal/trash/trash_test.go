@@ -0,0 +1,173 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+// racingTasks 在批量写入前执行 race，模拟读取之后的并发修改
+type racingTasks struct {
+	repository.TaskRepository
+	race func()
+}
+
+func (r *racingTasks) BulkUpdate(ctx context.Context, tasks []models.Task, created ...*models.Task) error {
+	r.race()
+	return r.TaskRepository.BulkUpdate(ctx, tasks, created...)
+}
+
+func TestTasks(t *testing.T) {
+	ctx := context.Background()
+	tasks := memory.NewTaskRepository()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	create := func(title string, parent *models.Task, blockedBy ...string) *models.Task {
+		task := &models.Task{Title: title, CreatedBy: "u1", BlockedBy: blockedBy, CreatedAt: base, UpdatedAt: base}
+		if parent != nil {
+			task.ParentID = &parent.ID
+		}
+		if err := tasks.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return task
+	}
+	trashTask := func(id string, at time.Time) (int, error) {
+		task, err := tasks.Get(ctx, "u1", id)
+		if err != nil {
+			return 0, err
+		}
+		return TrashTask(ctx, tasks, task, at)
+	}
+	root := create("root", nil)
+	child := create("child", root)
+	leaf := create("leaf", child)
+	other := create("other", nil, root.ID)
+
+	// 读取后被修改的任务不移入回收站，后代也保持不变
+	stale := *root
+	root.Title = "root renamed"
+	if err := tasks.Update(ctx, root); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := TrashTask(ctx, tasks, &stale, base); !errors.Is(err, repository.ErrVersion) {
+		t.Errorf("Stale task: expected ErrVersion, got %v", err)
+	}
+	if n, _ := tasks.Count(ctx, repository.TaskFilter{UserID: "u1", Trash: repository.OnlyTrashed}); n != 0 {
+		t.Errorf("Expected nothing trashed for stale task, got %d", n)
+	}
+
+	// 读取后代之后、写入之前后代被修改时整棵子树保持不变
+	current, _ := tasks.Get(ctx, "u1", root.ID)
+	racing := &racingTasks{TaskRepository: tasks, race: func() {
+		c, _ := tasks.Get(ctx, "u1", child.ID)
+		c.Description = "edited concurrently"
+		_ = tasks.Update(ctx, c)
+	}}
+	if _, err := TrashTask(ctx, racing, current, base); !errors.Is(err, repository.ErrVersion) {
+		t.Errorf("Stale descendant: expected ErrVersion, got %v", err)
+	}
+	if n, _ := tasks.Count(ctx, repository.TaskFilter{UserID: "u1", Trash: repository.OnlyTrashed}); n != 0 {
+		t.Errorf("Expected nothing trashed for stale descendant, got %d", n)
+	}
+
+	if n, err := trashTask(leaf.ID, base.Add(time.Hour)); err != nil || n != 1 {
+		t.Fatalf("TrashTask leaf: %d (%v)", n, err)
+	}
+	if n, err := trashTask(root.ID, base.Add(2*time.Hour)); err != nil || n != 2 {
+		t.Fatalf("TrashTask root: %d (%v)", n, err)
+	}
+	if _, err := tasks.Get(ctx, "u1", child.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected trashed child to be hidden, got %v", err)
+	}
+
+	// 只恢复与根任务一同删除的后代
+	if n, err := RestoreTask(ctx, tasks, "u1", root.ID); err != nil || n != 2 {
+		t.Fatalf("RestoreTask root: %d (%v)", n, err)
+	}
+	if n, _ := tasks.Count(ctx, repository.TaskFilter{UserID: "u1", Trash: repository.OnlyTrashed}); n != 1 {
+		t.Errorf("Expected leaf to stay in trash, got %d trashed", n)
+	}
+	if _, err := RestoreTask(ctx, tasks, "u1", root.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Restore live task: expected ErrNotFound, got %v", err)
+	}
+
+	// 父任务仍在回收站时恢复为顶层任务
+	if _, err := trashTask(root.ID, base.Add(3*time.Hour)); err != nil {
+		t.Fatalf("TrashTask root: %v", err)
+	}
+	if n, err := RestoreTask(ctx, tasks, "u1", leaf.ID); err != nil || n != 1 {
+		t.Fatalf("RestoreTask leaf: %d (%v)", n, err)
+	}
+	if got, err := tasks.Get(ctx, "u1", leaf.ID); err != nil || got.ParentID != nil || got.DeletedAt != nil {
+		t.Errorf("Expected leaf restored as top-level task, got %+v (%v)", got, err)
+	}
+
+	if _, err := PurgeTask(ctx, tasks, "u1", other.ID, base); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Purge live task: expected ErrNotFound, got %v", err)
+	}
+	purged, _, err := Purge(ctx, tasks, memory.NewReportRepository(), base.Add(3*time.Hour), base.Add(4*time.Hour))
+	if err != nil || purged != 2 {
+		t.Fatalf("Purge: %d (%v)", purged, err)
+	}
+	if n, _ := tasks.Count(ctx, repository.TaskFilter{UserID: "u1", Trash: repository.WithTrashed}); n != 2 {
+		t.Errorf("Expected other and leaf to remain, got %d", n)
+	}
+	if got, _ := tasks.Get(ctx, "u1", other.ID); len(got.BlockedBy) != 0 {
+		t.Errorf("Expected purged blocker to be unlinked, got %v", got.BlockedBy)
+	}
+}
+
+func TestReports(t *testing.T) {
+	ctx := context.Background()
+	reports := memory.NewReportRepository()
+	tasks := memory.NewTaskRepository()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rep := &models.Report{UserID: "u1", Type: "weekly", Title: "周报", CreatedAt: base, UpdatedAt: base}
+	if err := reports.Create(ctx, rep); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if err := RestoreReport(ctx, reports, "u1", rep.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Restore live report: expected ErrNotFound, got %v", err)
+	}
+	if err := TrashReport(ctx, reports, rep, base); err != nil {
+		t.Fatalf("TrashReport failed: %v", err)
+	}
+	if err := TrashReport(ctx, reports, rep, base); !errors.Is(err, repository.ErrVersion) {
+		t.Errorf("Stale report: expected ErrVersion, got %v", err)
+	}
+	if _, err := reports.Get(ctx, "u1", rep.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected trashed report to be hidden, got %v", err)
+	}
+	if err := RestoreReport(ctx, reports, "u1", rep.ID); err != nil {
+		t.Fatalf("RestoreReport failed: %v", err)
+	}
+	if _, err := reports.Get(ctx, "u1", rep.ID); err != nil {
+		t.Errorf("Expected restored report, got %v", err)
+	}
+	if err := PurgeReport(ctx, reports, "u1", rep.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Purge live report: expected ErrNotFound, got %v", err)
+	}
+
+	rep, err := reports.Get(ctx, "u1", rep.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := TrashReport(ctx, reports, rep, base); err != nil {
+		t.Fatalf("TrashReport failed: %v", err)
+	}
+	if _, n, err := Purge(ctx, tasks, reports, base.Add(-time.Hour), base); err != nil || n != 0 {
+		t.Errorf("Purge within retention: %d (%v)", n, err)
+	}
+	if _, n, err := Purge(ctx, tasks, reports, base, base); err != nil || n != 1 {
+		t.Errorf("Purge expired: %d (%v)", n, err)
+	}
+	if n, _ := reports.Count(ctx, repository.ReportFilter{UserID: "u1", Trash: repository.WithTrashed}); n != 0 {
+		t.Errorf("Expected report to be purged, got %d", n)
+	}
+}
@@ -0,0 +1,376 @@
+// Package workflow 自定义工作流：校验工作流定义，按任务所属项目解析生效的工作流，
+// 判断状态类别、状态流转与在制品（WIP）上限。项目工作流优先于用户默认工作流，
+// 两者都未配置时使用与原有状态一致的内置工作流。HTTP 处理器、gRPC 服务与统计共用这些逻辑。
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository"
+)
+
+// MaxNameLength 工作流、状态与优先级名称的最大字符数
+const MaxNameLength = 50
+
+var (
+	// ErrInvalid 工作流定义不合法
+	ErrInvalid = errors.New("invalid workflow")
+	// ErrStatus 状态不属于任务所在的工作流
+	ErrStatus = errors.New("unknown status")
+	// ErrPriority 优先级不属于任务所在的工作流
+	ErrPriority = errors.New("unknown priority")
+	// ErrTransition 工作流不允许该状态流转
+	ErrTransition = errors.New("transition not allowed")
+	// ErrWIPLimit 目标状态的任务数已达到 WIP 上限
+	ErrWIPLimit = errors.New("wip limit reached")
+)
+
+// Default 返回内置工作流：To Do、In Progress、Done 三个状态可任意流转，优先级为 Low、Medium、High
+func Default() *models.Workflow {
+	return &models.Workflow{
+		Name: "Default",
+		Statuses: []models.WorkflowStatus{
+			{Name: "To Do", Category: models.CategoryOpen},
+			{Name: "In Progress", Category: models.CategoryActive},
+			{Name: "Done", Category: models.CategoryDone},
+		},
+		Transitions: []models.WorkflowTransition{},
+		Priorities:  []string{"Low", "Medium", "High"},
+	}
+}
+
+var builtin = Default()
+
+// Validate 去除名称首尾空白并校验工作流：状态名称不区分大小写唯一且至少有一个完成类状态，
+// 流转只能引用已有状态，重复的流转被合并；未设置优先级时使用内置的优先级
+func Validate(w *models.Workflow) error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" || utf8.RuneCountInString(w.Name) > MaxNameLength {
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalid, MaxNameLength)
+	}
+	if len(w.Statuses) == 0 {
+		return fmt.Errorf("%w: at least one status is required", ErrInvalid)
+	}
+	seen := map[string]bool{}
+	done := false
+	for i := range w.Statuses {
+		s := &w.Statuses[i]
+		s.Name = strings.TrimSpace(s.Name)
+		key := strings.ToLower(s.Name)
+		switch {
+		case s.Name == "" || utf8.RuneCountInString(s.Name) > MaxNameLength:
+			return fmt.Errorf("%w: status name must be 1-%d characters", ErrInvalid, MaxNameLength)
+		case seen[key]:
+			return fmt.Errorf("%w: duplicate status %q", ErrInvalid, s.Name)
+		case s.Category != models.CategoryOpen && s.Category != models.CategoryActive && s.Category != models.CategoryDone:
+			return fmt.Errorf("%w: status %q has invalid category %q", ErrInvalid, s.Name, s.Category)
+		case s.WIPLimit < 0:
+			return fmt.Errorf("%w: status %q has negative wipLimit", ErrInvalid, s.Name)
+		}
+		seen[key] = true
+		done = done || s.Category == models.CategoryDone
+	}
+	if !done {
+		return fmt.Errorf("%w: at least one status must be in the done category", ErrInvalid)
+	}
+
+	transitions := make([]models.WorkflowTransition, 0, len(w.Transitions))
+	pairs := map[models.WorkflowTransition]bool{}
+	for _, t := range w.Transitions {
+		t.From, t.To = strings.TrimSpace(t.From), strings.TrimSpace(t.To)
+		if Find(w, t.From) == nil || Find(w, t.To) == nil {
+			return fmt.Errorf("%w: transition %q -> %q references an unknown status", ErrInvalid, t.From, t.To)
+		}
+		if t.From == t.To || pairs[t] {
+			continue
+		}
+		pairs[t] = true
+		transitions = append(transitions, t)
+	}
+	w.Transitions = transitions
+
+	if len(w.Priorities) == 0 {
+		w.Priorities = append([]string{}, builtin.Priorities...)
+	}
+	seen = map[string]bool{}
+	for i, p := range w.Priorities {
+		p = strings.TrimSpace(p)
+		key := strings.ToLower(p)
+		if p == "" || utf8.RuneCountInString(p) > MaxNameLength {
+			return fmt.Errorf("%w: priority name must be 1-%d characters", ErrInvalid, MaxNameLength)
+		}
+		if seen[key] {
+			return fmt.Errorf("%w: duplicate priority %q", ErrInvalid, p)
+		}
+		seen[key] = true
+		w.Priorities[i] = p
+	}
+	return nil
+}
+
+// Find 返回工作流中名为 status 的状态，不存在时返回 nil
+func Find(w *models.Workflow, status string) *models.WorkflowStatus {
+	for i := range w.Statuses {
+		if w.Statuses[i].Name == status {
+			return &w.Statuses[i]
+		}
+	}
+	return nil
+}
+
+// Category 返回状态所属的类别；工作流中没有该状态时（如工作流修改前写入的任务）
+// 按内置工作流判断，仍未知时视为未开始
+func Category(w *models.Workflow, status string) string {
+	if s := Find(w, status); s != nil {
+		return s.Category
+	}
+	if s := Find(builtin, status); s != nil {
+		return s.Category
+	}
+	return models.CategoryOpen
+}
+
+// Initial 返回新任务的初始状态
+func Initial(w *models.Workflow) string {
+	return w.Statuses[0].Name
+}
+
+// DoneStatus 返回完成任务时使用的状态，即第一个完成类状态
+func DoneStatus(w *models.Workflow) string {
+	for _, s := range w.Statuses {
+		if s.Category == models.CategoryDone {
+			return s.Name
+		}
+	}
+	return builtin.Statuses[2].Name
+}
+
+// HasPriority 判断优先级是否属于工作流
+func HasPriority(w *models.Workflow, priority string) bool {
+	for _, p := range w.Priorities {
+		if p == priority {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultPriority 返回新任务的默认优先级，即居中的优先级
+func DefaultPriority(w *models.Workflow) string {
+	return w.Priorities[(len(w.Priorities)-1)/2]
+}
+
+// CanTransition 判断能否从 from 流转到 to；未限制流转、状态未变或 from 不属于工作流时均允许
+func CanTransition(w *models.Workflow, from, to string) bool {
+	if len(w.Transitions) == 0 || from == to || Find(w, from) == nil {
+		return true
+	}
+	for _, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Set 某个用户生效的全部工作流；nil 表示只使用内置工作流
+type Set struct {
+	user     *models.Workflow
+	projects map[string]*models.Workflow
+}
+
+// Load 加载用户的工作流；未配置工作流存储时只使用内置工作流
+func Load(ctx context.Context, repo repository.WorkflowRepository, userID string) (*Set, error) {
+	if repo == nil {
+		return nil, nil
+	}
+	list, err := repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return NewSet(list), nil
+}
+
+// NewSet 由工作流列表构建 Set
+func NewSet(list []models.Workflow) *Set {
+	s := &Set{user: builtin, projects: map[string]*models.Workflow{}}
+	for i := range list {
+		if w := &list[i]; w.ProjectID == nil {
+			s.user = w
+		} else {
+			s.projects[*w.ProjectID] = w
+		}
+	}
+	return s
+}
+
+// For 返回项目内任务使用的工作流，projectID 为 nil 或项目未配置工作流时返回用户默认工作流；
+// 返回值由 Set 共享，调用方不能修改
+func (s *Set) For(projectID *string) *models.Workflow {
+	if s == nil {
+		return builtin
+	}
+	if projectID != nil {
+		if w, ok := s.projects[*projectID]; ok {
+			return w
+		}
+	}
+	return s.user
+}
+
+// Category 返回任务状态所属的类别
+func (s *Set) Category(t *models.Task) string {
+	return Category(s.For(t.ProjectID), t.Status)
+}
+
+// Done 判断任务是否处于完成类状态
+func (s *Set) Done(t *models.Task) bool {
+	return s.Category(t) == models.CategoryDone
+}
+
+// all 返回全部生效的工作流，并包含内置工作流以便查询工作流修改前写入的任务
+func (s *Set) all() []*models.Workflow {
+	out := []*models.Workflow{builtin}
+	if s == nil {
+		return out
+	}
+	out = append(out, s.user)
+	for _, w := range s.projects {
+		out = append(out, w)
+	}
+	return out
+}
+
+// HasStatus 判断状态是否属于用户的任一工作流，用于校验列表过滤条件
+func (s *Set) HasStatus(status string) bool {
+	for _, w := range s.all() {
+		if Find(w, status) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPriority 判断优先级是否属于用户的任一工作流
+func (s *Set) HasPriority(priority string) bool {
+	for _, w := range s.all() {
+		if HasPriority(w, priority) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check 校验写入前后的任务：before 为写入前的任务，新建任务为 nil。
+// 状态或所属工作流改变时校验状态属于新的工作流、流转被允许且未超过目标状态的 WIP 上限；
+// 优先级改变时校验优先级属于工作流。工作流修改前写入的旧状态与旧优先级保持不变时不受影响
+func Check(ctx context.Context, tasks repository.TaskRepository, s *Set, task, before *models.Task) error {
+	errs, err := CheckAll(ctx, tasks, s, []Change{{Task: task, Before: before}})
+	if err != nil {
+		return err
+	}
+	return errs[0]
+}
+
+// Change 同一次写入中一个任务写入前后的状态，新建任务的 Before 为 nil
+type Change struct {
+	Task, Before *models.Task
+}
+
+// CheckAll 按 Check 的规则校验同一次写入中的多个任务，返回与 changes 一一对应的校验错误；
+// 进入同一状态的任务合并计入 WIP 上限，未通过校验的任务不占用名额。读取存储失败时返回第二个错误
+func CheckAll(ctx context.Context, tasks repository.TaskRepository, s *Set, changes []Change) ([]error, error) {
+	type slot struct {
+		w              *models.Workflow
+		userID, status string
+	}
+	occupied := map[slot]int64{}
+	errs := make([]error, len(changes))
+	for i, c := range changes {
+		task, before := c.Task, c.Before
+		w := s.For(task.ProjectID)
+		moved := before != nil && s.For(before.ProjectID) != w
+		entering := before == nil || before.Status != task.Status || moved
+		var target *models.WorkflowStatus
+		if entering {
+			if target = Find(w, task.Status); target == nil {
+				errs[i] = ErrStatus
+				continue
+			}
+			if before != nil && !moved && !CanTransition(w, before.Status, task.Status) {
+				errs[i] = ErrTransition
+				continue
+			}
+		}
+		if (before == nil || before.Priority != task.Priority || moved) && !HasPriority(w, task.Priority) {
+			errs[i] = ErrPriority
+			continue
+		}
+		if target == nil || target.WIPLimit <= 0 {
+			continue
+		}
+		key := slot{w, task.CreatedBy, task.Status}
+		n, ok := occupied[key]
+		if !ok {
+			var err error
+			if n, err = s.count(ctx, tasks, w, task.CreatedBy, task.Status); err != nil {
+				return nil, err
+			}
+		}
+		if n >= int64(target.WIPLimit) {
+			errs[i] = ErrWIPLimit
+			occupied[key] = n
+			continue
+		}
+		occupied[key] = n + 1
+	}
+	return errs, nil
+}
+
+// Fallback 返回删除工作流 w 后其任务改用的工作流：项目工作流回退到用户默认工作流，默认工作流回退到内置工作流
+func (s *Set) Fallback(w *models.Workflow) *models.Workflow {
+	if w.ProjectID != nil {
+		return s.For(nil)
+	}
+	return builtin
+}
+
+// InUse 返回 old 中仍有任务使用、但 next 中已不存在的状态，修改或删除工作流前用于防止任务处于无效状态
+func (s *Set) InUse(ctx context.Context, tasks repository.TaskRepository, old, next *models.Workflow) ([]string, error) {
+	var out []string
+	for _, st := range old.Statuses {
+		if Find(next, st.Name) != nil {
+			continue
+		}
+		n, err := s.count(ctx, tasks, old, old.UserID, st.Name)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			out = append(out, st.Name)
+		}
+	}
+	return out, nil
+}
+
+// count 统计使用工作流 w 且处于 status 的任务数；用户默认工作流不计入配置了自身工作流的项目
+func (s *Set) count(ctx context.Context, tasks repository.TaskRepository, w *models.Workflow, userID, status string) (int64, error) {
+	if w.ProjectID != nil {
+		return tasks.Count(ctx, repository.TaskFilter{UserID: userID, Status: status, ProjectIDs: []string{*w.ProjectID}})
+	}
+	n, err := tasks.Count(ctx, repository.TaskFilter{UserID: userID, Status: status})
+	if err != nil || s == nil || len(s.projects) == 0 {
+		return n, err
+	}
+	ids := make([]string, 0, len(s.projects))
+	for id := range s.projects {
+		ids = append(ids, id)
+	}
+	other, err := tasks.Count(ctx, repository.TaskFilter{UserID: userID, Status: status, ProjectIDs: ids})
+	return n - other, err
+}
@@ -0,0 +1,162 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/axfinn/todoIng/backend-go/internal/models"
+	"github.com/axfinn/todoIng/backend-go/internal/repository/memory"
+)
+
+func kanban() *models.Workflow {
+	return &models.Workflow{
+		Name: " 看板 ",
+		Statuses: []models.WorkflowStatus{
+			{Name: "Backlog", Category: models.CategoryOpen},
+			{Name: " Review ", Category: models.CategoryActive, WIPLimit: 1},
+			{Name: "Shipped", Category: models.CategoryDone},
+		},
+		Transitions: []models.WorkflowTransition{
+			{From: "Backlog", To: "Review"},
+			{From: "Backlog", To: "Review"},
+			{From: "Review", To: "Shipped"},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	w := kanban()
+	if err := Validate(w); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if w.Name != "看板" || w.Statuses[1].Name != "Review" || len(w.Transitions) != 2 || len(w.Priorities) != 3 {
+		t.Errorf("Unexpected normalized workflow %+v", w)
+	}
+	if Initial(w) != "Backlog" || DoneStatus(w) != "Shipped" || DefaultPriority(w) != "Medium" {
+		t.Errorf("Unexpected defaults %q %q %q", Initial(w), DoneStatus(w), DefaultPriority(w))
+	}
+
+	bad := []func(w *models.Workflow){
+		func(w *models.Workflow) { w.Name = " " },
+		func(w *models.Workflow) { w.Statuses[2].Category = models.CategoryActive },
+		func(w *models.Workflow) { w.Statuses[1].Name = "backlog" },
+		func(w *models.Workflow) { w.Statuses[0].Category = "blocked" },
+		func(w *models.Workflow) { w.Transitions[0].To = "Missing" },
+		func(w *models.Workflow) { w.Priorities = []string{"P1", "p1"} },
+	}
+	for i, mutate := range bad {
+		w := kanban()
+		mutate(w)
+		if err := Validate(w); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Case %d: expected ErrInvalid, got %v", i, err)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	project := "p1"
+	flow := kanban()
+	_ = Validate(flow)
+	flow.ProjectID = &project
+	s := NewSet([]models.Workflow{*flow})
+
+	if got := s.For(nil); got != builtin {
+		t.Errorf("Expected built-in workflow outside projects, got %v", got.Name)
+	}
+	if got := s.For(&project); got.Name != "看板" {
+		t.Errorf("Expected project workflow, got %v", got.Name)
+	}
+	if !s.Done(&models.Task{Status: "Shipped", ProjectID: &project}) || s.Done(&models.Task{Status: "Shipped"}) {
+		t.Error("Done should follow the task's workflow")
+	}
+	if !s.Done(&models.Task{Status: "Done", ProjectID: &project}) {
+		t.Error("Legacy status should fall back to the built-in category")
+	}
+	if !s.HasStatus("Review") || !s.HasStatus("To Do") || s.HasStatus("Blocked") {
+		t.Error("HasStatus should cover every workflow")
+	}
+	var none *Set
+	if none.For(&project) != builtin || none.Done(&models.Task{Status: "In Progress"}) {
+		t.Error("nil Set should use the built-in workflow")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	project := "p1"
+	flow := kanban()
+	_ = Validate(flow)
+	flow.ProjectID = &project
+	s := NewSet([]models.Workflow{*flow})
+	tasks := memory.NewTaskRepository()
+
+	task := &models.Task{CreatedBy: "u1", ProjectID: &project, Status: "Backlog", Priority: "Medium"}
+	if err := Check(ctx, tasks, s, task, nil); err != nil {
+		t.Fatalf("Check new task: %v", err)
+	}
+	if err := tasks.Create(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	before := *task
+	task.Status = "Shipped"
+	if err := Check(ctx, tasks, s, task, &before); !errors.Is(err, ErrTransition) {
+		t.Errorf("Expected ErrTransition, got %v", err)
+	}
+	task.Status = "Review"
+	if err := Check(ctx, tasks, s, task, &before); err != nil {
+		t.Errorf("Allowed transition: %v", err)
+	}
+	if err := tasks.Update(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+
+	other := &models.Task{CreatedBy: "u1", ProjectID: &project, Status: "Review", Priority: "Medium"}
+	if err := Check(ctx, tasks, s, other, nil); !errors.Is(err, ErrWIPLimit) {
+		t.Errorf("Expected ErrWIPLimit, got %v", err)
+	}
+	other.Status, other.Priority = "To Do", "Medium"
+	if err := Check(ctx, tasks, s, other, nil); !errors.Is(err, ErrStatus) {
+		t.Errorf("Expected ErrStatus, got %v", err)
+	}
+	other.Status, other.Priority = "Backlog", "Urgent"
+	if err := Check(ctx, tasks, s, other, nil); !errors.Is(err, ErrPriority) {
+		t.Errorf("Expected ErrPriority, got %v", err)
+	}
+
+	// 移出项目时改用默认工作流，状态须属于新工作流但不校验流转
+	moved := *task
+	moved.ProjectID, moved.Status = nil, "Done"
+	if err := Check(ctx, tasks, s, &moved, task); err != nil {
+		t.Errorf("Move out of project: %v", err)
+	}
+
+	used, err := s.InUse(ctx, tasks, flow, Default())
+	if err != nil || len(used) != 1 || used[0] != "Review" {
+		t.Errorf("Expected Review in use, got %v (%v)", used, err)
+	}
+}
+
+func TestCheckAll(t *testing.T) {
+	ctx := context.Background()
+	flow := kanban()
+	_ = Validate(flow)
+	s := NewSet([]models.Workflow{*flow})
+	tasks := memory.NewTaskRepository()
+
+	// 同一批中进入 Review 的任务合并计入 WIP 上限，未通过校验的任务不占用名额
+	var changes []Change
+	for _, p := range []string{"Urgent", "Medium", "Medium"} {
+		before := &models.Task{CreatedBy: "u1", Status: "Backlog", Priority: "Medium"}
+		after := *before
+		after.Status, after.Priority = "Review", p
+		changes = append(changes, Change{Task: &after, Before: before})
+	}
+	errs, err := CheckAll(ctx, tasks, s, changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(errs[0], ErrPriority) || errs[1] != nil || !errors.Is(errs[2], ErrWIPLimit) {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+}
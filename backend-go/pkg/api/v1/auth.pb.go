@@ -7,6 +7,7 @@
 package v1
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -107,6 +108,8 @@ type RegisterRequest struct {
 	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
 	CaptchaId     string                 `protobuf:"bytes,4,opt,name=captcha_id,json=captchaId,proto3" json:"captcha_id,omitempty"`
 	CaptchaCode   string                 `protobuf:"bytes,5,opt,name=captcha_code,json=captchaCode,proto3" json:"captcha_code,omitempty"`
+	EmailCode     string                 `protobuf:"bytes,6,opt,name=email_code,json=emailCode,proto3" json:"email_code,omitempty"`         // 启用邮箱验证时必填
+	EmailCodeId   string                 `protobuf:"bytes,7,opt,name=email_code_id,json=emailCodeId,proto3" json:"email_code_id,omitempty"` // 启用邮箱验证时必填
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -176,11 +179,26 @@ func (x *RegisterRequest) GetCaptchaCode() string {
 	return ""
 }
 
+func (x *RegisterRequest) GetEmailCode() string {
+	if x != nil {
+		return x.EmailCode
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetEmailCodeId() string {
+	if x != nil {
+		return x.EmailCodeId
+	}
+	return ""
+}
+
 // 注册响应
 type RegisterResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Response      *Response              `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
 	User          *User                  `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Token         string                 `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -229,6 +247,13 @@ func (x *RegisterResponse) GetUser() *User {
 	return nil
 }
 
+func (x *RegisterResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
 // 登录请求
 type LoginRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -287,6 +312,7 @@ type EmailCodeLoginRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	CodeId        string                 `protobuf:"bytes,3,opt,name=code_id,json=codeId,proto3" json:"code_id,omitempty"` // SendLoginEmailCode 返回的验证码 ID
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -335,6 +361,13 @@ func (x *EmailCodeLoginRequest) GetCode() string {
 	return ""
 }
 
+func (x *EmailCodeLoginRequest) GetCodeId() string {
+	if x != nil {
+		return x.CodeId
+	}
+	return ""
+}
+
 // 发送登录邮箱验证码请求
 type SendLoginEmailCodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -380,6 +413,59 @@ func (x *SendLoginEmailCodeRequest) GetEmail() string {
 	return ""
 }
 
+// 发送登录邮箱验证码响应
+type SendLoginEmailCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Response      *Response              `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	CodeId        string                 `protobuf:"bytes,2,opt,name=code_id,json=codeId,proto3" json:"code_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendLoginEmailCodeResponse) Reset() {
+	*x = SendLoginEmailCodeResponse{}
+	mi := &file_auth_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendLoginEmailCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendLoginEmailCodeResponse) ProtoMessage() {}
+
+func (x *SendLoginEmailCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendLoginEmailCodeResponse.ProtoReflect.Descriptor instead.
+func (*SendLoginEmailCodeResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SendLoginEmailCodeResponse) GetResponse() *Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *SendLoginEmailCodeResponse) GetCodeId() string {
+	if x != nil {
+		return x.CodeId
+	}
+	return ""
+}
+
 // 登录响应
 type LoginResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -392,7 +478,7 @@ type LoginResponse struct {
 
 func (x *LoginResponse) Reset() {
 	*x = LoginResponse{}
-	mi := &file_auth_proto_msgTypes[6]
+	mi := &file_auth_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -404,7 +490,7 @@ func (x *LoginResponse) String() string {
 func (*LoginResponse) ProtoMessage() {}
 
 func (x *LoginResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_proto_msgTypes[6]
+	mi := &file_auth_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -417,7 +503,7 @@ func (x *LoginResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
 func (*LoginResponse) Descriptor() ([]byte, []int) {
-	return file_auth_proto_rawDescGZIP(), []int{6}
+	return file_auth_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *LoginResponse) GetResponse() *Response {
@@ -451,7 +537,7 @@ type VerifyTokenRequest struct {
 
 func (x *VerifyTokenRequest) Reset() {
 	*x = VerifyTokenRequest{}
-	mi := &file_auth_proto_msgTypes[7]
+	mi := &file_auth_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -463,7 +549,7 @@ func (x *VerifyTokenRequest) String() string {
 func (*VerifyTokenRequest) ProtoMessage() {}
 
 func (x *VerifyTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_proto_msgTypes[7]
+	mi := &file_auth_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -476,7 +562,7 @@ func (x *VerifyTokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyTokenRequest.ProtoReflect.Descriptor instead.
 func (*VerifyTokenRequest) Descriptor() ([]byte, []int) {
-	return file_auth_proto_rawDescGZIP(), []int{7}
+	return file_auth_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *VerifyTokenRequest) GetToken() string {
@@ -497,7 +583,7 @@ type VerifyTokenResponse struct {
 
 func (x *VerifyTokenResponse) Reset() {
 	*x = VerifyTokenResponse{}
-	mi := &file_auth_proto_msgTypes[8]
+	mi := &file_auth_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -509,7 +595,7 @@ func (x *VerifyTokenResponse) String() string {
 func (*VerifyTokenResponse) ProtoMessage() {}
 
 func (x *VerifyTokenResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_auth_proto_msgTypes[8]
+	mi := &file_auth_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -522,7 +608,7 @@ func (x *VerifyTokenResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use VerifyTokenResponse.ProtoReflect.Descriptor instead.
 func (*VerifyTokenResponse) Descriptor() ([]byte, []int) {
-	return file_auth_proto_rawDescGZIP(), []int{8}
+	return file_auth_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *VerifyTokenResponse) GetResponse() *Response {
@@ -544,7 +630,7 @@ var File_auth_proto protoreflect.FileDescriptor
 const file_auth_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"auth.proto\x12\x0etodoing.api.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\fcommon.proto\"\xbe\x01\n" +
+	"auth.proto\x12\x0etodoing.api.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\fcommon.proto\x1a\x1cgoogle/api/annotations.proto\"\xbe\x01\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
@@ -552,25 +638,33 @@ const file_auth_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xa1\x01\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xe4\x01\n" +
 	"\x0fRegisterRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
 	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x1d\n" +
 	"\n" +
 	"captcha_id\x18\x04 \x01(\tR\tcaptchaId\x12!\n" +
-	"\fcaptcha_code\x18\x05 \x01(\tR\vcaptchaCode\"r\n" +
+	"\fcaptcha_code\x18\x05 \x01(\tR\vcaptchaCode\x12\x1d\n" +
+	"\n" +
+	"email_code\x18\x06 \x01(\tR\temailCode\x12\"\n" +
+	"\remail_code_id\x18\a \x01(\tR\vemailCodeId\"\x88\x01\n" +
 	"\x10RegisterResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x12(\n" +
-	"\x04user\x18\x02 \x01(\v2\x14.todoing.api.v1.UserR\x04user\"@\n" +
+	"\x04user\x18\x02 \x01(\v2\x14.todoing.api.v1.UserR\x04user\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"@\n" +
 	"\fLoginRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"A\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"Z\n" +
 	"\x15EmailCodeLoginRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x12\n" +
-	"\x04code\x18\x02 \x01(\tR\x04code\"1\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x17\n" +
+	"\acode_id\x18\x03 \x01(\tR\x06codeId\"1\n" +
 	"\x19SendLoginEmailCodeRequest\x12\x14\n" +
-	"\x05email\x18\x01 \x01(\tR\x05email\"\x85\x01\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"k\n" +
+	"\x1aSendLoginEmailCodeResponse\x124\n" +
+	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x12\x17\n" +
+	"\acode_id\x18\x02 \x01(\tR\x06codeId\"\x85\x01\n" +
 	"\rLoginResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x12\x14\n" +
 	"\x05token\x18\x02 \x01(\tR\x05token\x12(\n" +
@@ -579,13 +673,13 @@ const file_auth_proto_rawDesc = "" +
 	"\x05token\x18\x01 \x01(\tR\x05token\"u\n" +
 	"\x13VerifyTokenResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x12(\n" +
-	"\x04user\x18\x02 \x01(\v2\x14.todoing.api.v1.UserR\x04user2\xad\x03\n" +
-	"\vAuthService\x12M\n" +
-	"\bRegister\x12\x1f.todoing.api.v1.RegisterRequest\x1a .todoing.api.v1.RegisterResponse\x12D\n" +
-	"\x05Login\x12\x1c.todoing.api.v1.LoginRequest\x1a\x1d.todoing.api.v1.LoginResponse\x12V\n" +
-	"\x0eEmailCodeLogin\x12%.todoing.api.v1.EmailCodeLoginRequest\x1a\x1d.todoing.api.v1.LoginResponse\x12Y\n" +
-	"\x12SendLoginEmailCode\x12).todoing.api.v1.SendLoginEmailCodeRequest\x1a\x18.todoing.api.v1.Response\x12V\n" +
-	"\vVerifyToken\x12\".todoing.api.v1.VerifyTokenRequest\x1a#.todoing.api.v1.VerifyTokenResponseB1Z/github.com/axfinn/todoIng/backend-go/pkg/api/v1b\x06proto3"
+	"\x04user\x18\x02 \x01(\v2\x14.todoing.api.v1.UserR\x04user2\x81\x05\n" +
+	"\vAuthService\x12o\n" +
+	"\bRegister\x12\x1f.todoing.api.v1.RegisterRequest\x1a .todoing.api.v1.RegisterResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/api/v2/auth/register\x12c\n" +
+	"\x05Login\x12\x1c.todoing.api.v1.LoginRequest\x1a\x1d.todoing.api.v1.LoginResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/api/v2/auth/login\x12\x80\x01\n" +
+	"\x0eEmailCodeLogin\x12%.todoing.api.v1.EmailCodeLoginRequest\x1a\x1d.todoing.api.v1.LoginResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/v2/auth/login/email-code\x12\x9a\x01\n" +
+	"\x12SendLoginEmailCode\x12).todoing.api.v1.SendLoginEmailCodeRequest\x1a*.todoing.api.v1.SendLoginEmailCodeResponse\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/api/v2/auth/send-login-email-code\x12|\n" +
+	"\vVerifyToken\x12\".todoing.api.v1.VerifyTokenRequest\x1a#.todoing.api.v1.VerifyTokenResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/api/v2/auth/verify-tokenB1Z/github.com/axfinn/todoIng/backend-go/pkg/api/v1b\x06proto3"
 
 var (
 	file_auth_proto_rawDescOnce sync.Once
@@ -599,44 +693,46 @@ func file_auth_proto_rawDescGZIP() []byte {
 	return file_auth_proto_rawDescData
 }
 
-var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_auth_proto_goTypes = []any{
-	(*User)(nil),                      // 0: todoing.api.v1.User
-	(*RegisterRequest)(nil),           // 1: todoing.api.v1.RegisterRequest
-	(*RegisterResponse)(nil),          // 2: todoing.api.v1.RegisterResponse
-	(*LoginRequest)(nil),              // 3: todoing.api.v1.LoginRequest
-	(*EmailCodeLoginRequest)(nil),     // 4: todoing.api.v1.EmailCodeLoginRequest
-	(*SendLoginEmailCodeRequest)(nil), // 5: todoing.api.v1.SendLoginEmailCodeRequest
-	(*LoginResponse)(nil),             // 6: todoing.api.v1.LoginResponse
-	(*VerifyTokenRequest)(nil),        // 7: todoing.api.v1.VerifyTokenRequest
-	(*VerifyTokenResponse)(nil),       // 8: todoing.api.v1.VerifyTokenResponse
-	(*timestamppb.Timestamp)(nil),     // 9: google.protobuf.Timestamp
-	(*Response)(nil),                  // 10: todoing.api.v1.Response
+	(*User)(nil),                       // 0: todoing.api.v1.User
+	(*RegisterRequest)(nil),            // 1: todoing.api.v1.RegisterRequest
+	(*RegisterResponse)(nil),           // 2: todoing.api.v1.RegisterResponse
+	(*LoginRequest)(nil),               // 3: todoing.api.v1.LoginRequest
+	(*EmailCodeLoginRequest)(nil),      // 4: todoing.api.v1.EmailCodeLoginRequest
+	(*SendLoginEmailCodeRequest)(nil),  // 5: todoing.api.v1.SendLoginEmailCodeRequest
+	(*SendLoginEmailCodeResponse)(nil), // 6: todoing.api.v1.SendLoginEmailCodeResponse
+	(*LoginResponse)(nil),              // 7: todoing.api.v1.LoginResponse
+	(*VerifyTokenRequest)(nil),         // 8: todoing.api.v1.VerifyTokenRequest
+	(*VerifyTokenResponse)(nil),        // 9: todoing.api.v1.VerifyTokenResponse
+	(*timestamppb.Timestamp)(nil),      // 10: google.protobuf.Timestamp
+	(*Response)(nil),                   // 11: todoing.api.v1.Response
 }
 var file_auth_proto_depIdxs = []int32{
-	9,  // 0: todoing.api.v1.User.created_at:type_name -> google.protobuf.Timestamp
-	9,  // 1: todoing.api.v1.User.updated_at:type_name -> google.protobuf.Timestamp
-	10, // 2: todoing.api.v1.RegisterResponse.response:type_name -> todoing.api.v1.Response
+	10, // 0: todoing.api.v1.User.created_at:type_name -> google.protobuf.Timestamp
+	10, // 1: todoing.api.v1.User.updated_at:type_name -> google.protobuf.Timestamp
+	11, // 2: todoing.api.v1.RegisterResponse.response:type_name -> todoing.api.v1.Response
 	0,  // 3: todoing.api.v1.RegisterResponse.user:type_name -> todoing.api.v1.User
-	10, // 4: todoing.api.v1.LoginResponse.response:type_name -> todoing.api.v1.Response
-	0,  // 5: todoing.api.v1.LoginResponse.user:type_name -> todoing.api.v1.User
-	10, // 6: todoing.api.v1.VerifyTokenResponse.response:type_name -> todoing.api.v1.Response
-	0,  // 7: todoing.api.v1.VerifyTokenResponse.user:type_name -> todoing.api.v1.User
-	1,  // 8: todoing.api.v1.AuthService.Register:input_type -> todoing.api.v1.RegisterRequest
-	3,  // 9: todoing.api.v1.AuthService.Login:input_type -> todoing.api.v1.LoginRequest
-	4,  // 10: todoing.api.v1.AuthService.EmailCodeLogin:input_type -> todoing.api.v1.EmailCodeLoginRequest
-	5,  // 11: todoing.api.v1.AuthService.SendLoginEmailCode:input_type -> todoing.api.v1.SendLoginEmailCodeRequest
-	7,  // 12: todoing.api.v1.AuthService.VerifyToken:input_type -> todoing.api.v1.VerifyTokenRequest
-	2,  // 13: todoing.api.v1.AuthService.Register:output_type -> todoing.api.v1.RegisterResponse
-	6,  // 14: todoing.api.v1.AuthService.Login:output_type -> todoing.api.v1.LoginResponse
-	6,  // 15: todoing.api.v1.AuthService.EmailCodeLogin:output_type -> todoing.api.v1.LoginResponse
-	10, // 16: todoing.api.v1.AuthService.SendLoginEmailCode:output_type -> todoing.api.v1.Response
-	8,  // 17: todoing.api.v1.AuthService.VerifyToken:output_type -> todoing.api.v1.VerifyTokenResponse
-	13, // [13:18] is the sub-list for method output_type
-	8,  // [8:13] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	11, // 4: todoing.api.v1.SendLoginEmailCodeResponse.response:type_name -> todoing.api.v1.Response
+	11, // 5: todoing.api.v1.LoginResponse.response:type_name -> todoing.api.v1.Response
+	0,  // 6: todoing.api.v1.LoginResponse.user:type_name -> todoing.api.v1.User
+	11, // 7: todoing.api.v1.VerifyTokenResponse.response:type_name -> todoing.api.v1.Response
+	0,  // 8: todoing.api.v1.VerifyTokenResponse.user:type_name -> todoing.api.v1.User
+	1,  // 9: todoing.api.v1.AuthService.Register:input_type -> todoing.api.v1.RegisterRequest
+	3,  // 10: todoing.api.v1.AuthService.Login:input_type -> todoing.api.v1.LoginRequest
+	4,  // 11: todoing.api.v1.AuthService.EmailCodeLogin:input_type -> todoing.api.v1.EmailCodeLoginRequest
+	5,  // 12: todoing.api.v1.AuthService.SendLoginEmailCode:input_type -> todoing.api.v1.SendLoginEmailCodeRequest
+	8,  // 13: todoing.api.v1.AuthService.VerifyToken:input_type -> todoing.api.v1.VerifyTokenRequest
+	2,  // 14: todoing.api.v1.AuthService.Register:output_type -> todoing.api.v1.RegisterResponse
+	7,  // 15: todoing.api.v1.AuthService.Login:output_type -> todoing.api.v1.LoginResponse
+	7,  // 16: todoing.api.v1.AuthService.EmailCodeLogin:output_type -> todoing.api.v1.LoginResponse
+	6,  // 17: todoing.api.v1.AuthService.SendLoginEmailCode:output_type -> todoing.api.v1.SendLoginEmailCodeResponse
+	9,  // 18: todoing.api.v1.AuthService.VerifyToken:output_type -> todoing.api.v1.VerifyTokenResponse
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_auth_proto_init() }
@@ -651,7 +747,7 @@ func file_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_proto_rawDesc), len(file_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
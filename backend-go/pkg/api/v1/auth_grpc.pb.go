@@ -39,7 +39,7 @@ type AuthServiceClient interface {
 	// 邮箱验证码登录
 	EmailCodeLogin(ctx context.Context, in *EmailCodeLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
 	// 发送登录邮箱验证码
-	SendLoginEmailCode(ctx context.Context, in *SendLoginEmailCodeRequest, opts ...grpc.CallOption) (*Response, error)
+	SendLoginEmailCode(ctx context.Context, in *SendLoginEmailCodeRequest, opts ...grpc.CallOption) (*SendLoginEmailCodeResponse, error)
 	// 验证令牌
 	VerifyToken(ctx context.Context, in *VerifyTokenRequest, opts ...grpc.CallOption) (*VerifyTokenResponse, error)
 }
@@ -82,9 +82,9 @@ func (c *authServiceClient) EmailCodeLogin(ctx context.Context, in *EmailCodeLog
 	return out, nil
 }
 
-func (c *authServiceClient) SendLoginEmailCode(ctx context.Context, in *SendLoginEmailCodeRequest, opts ...grpc.CallOption) (*Response, error) {
+func (c *authServiceClient) SendLoginEmailCode(ctx context.Context, in *SendLoginEmailCodeRequest, opts ...grpc.CallOption) (*SendLoginEmailCodeResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Response)
+	out := new(SendLoginEmailCodeResponse)
 	err := c.cc.Invoke(ctx, AuthService_SendLoginEmailCode_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -115,7 +115,7 @@ type AuthServiceServer interface {
 	// 邮箱验证码登录
 	EmailCodeLogin(context.Context, *EmailCodeLoginRequest) (*LoginResponse, error)
 	// 发送登录邮箱验证码
-	SendLoginEmailCode(context.Context, *SendLoginEmailCodeRequest) (*Response, error)
+	SendLoginEmailCode(context.Context, *SendLoginEmailCodeRequest) (*SendLoginEmailCodeResponse, error)
 	// 验证令牌
 	VerifyToken(context.Context, *VerifyTokenRequest) (*VerifyTokenResponse, error)
 	mustEmbedUnimplementedAuthServiceServer()
@@ -137,7 +137,7 @@ func (UnimplementedAuthServiceServer) Login(context.Context, *LoginRequest) (*Lo
 func (UnimplementedAuthServiceServer) EmailCodeLogin(context.Context, *EmailCodeLoginRequest) (*LoginResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EmailCodeLogin not implemented")
 }
-func (UnimplementedAuthServiceServer) SendLoginEmailCode(context.Context, *SendLoginEmailCodeRequest) (*Response, error) {
+func (UnimplementedAuthServiceServer) SendLoginEmailCode(context.Context, *SendLoginEmailCodeRequest) (*SendLoginEmailCodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendLoginEmailCode not implemented")
 }
 func (UnimplementedAuthServiceServer) VerifyToken(context.Context, *VerifyTokenRequest) (*VerifyTokenResponse, error) {
@@ -7,6 +7,7 @@
 package v1
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -164,11 +165,64 @@ func (x *GetCaptchaResponse) GetCaptcha() *Captcha {
 	return nil
 }
 
+// 校验验证码请求
+type VerifyCaptchaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CaptchaId     string                 `protobuf:"bytes,1,opt,name=captcha_id,json=captchaId,proto3" json:"captcha_id,omitempty"`
+	Captcha       string                 `protobuf:"bytes,2,opt,name=captcha,proto3" json:"captcha,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyCaptchaRequest) Reset() {
+	*x = VerifyCaptchaRequest{}
+	mi := &file_captcha_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyCaptchaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyCaptchaRequest) ProtoMessage() {}
+
+func (x *VerifyCaptchaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_captcha_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyCaptchaRequest.ProtoReflect.Descriptor instead.
+func (*VerifyCaptchaRequest) Descriptor() ([]byte, []int) {
+	return file_captcha_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VerifyCaptchaRequest) GetCaptchaId() string {
+	if x != nil {
+		return x.CaptchaId
+	}
+	return ""
+}
+
+func (x *VerifyCaptchaRequest) GetCaptcha() string {
+	if x != nil {
+		return x.Captcha
+	}
+	return ""
+}
+
 var File_captcha_proto protoreflect.FileDescriptor
 
 const file_captcha_proto_rawDesc = "" +
 	"\n" +
-	"\rcaptcha.proto\x12\x0etodoing.api.v1\x1a\fcommon.proto\"8\n" +
+	"\rcaptcha.proto\x12\x0etodoing.api.v1\x1a\fcommon.proto\x1a\x1cgoogle/api/annotations.proto\"8\n" +
 	"\aCaptcha\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -176,10 +230,15 @@ const file_captcha_proto_rawDesc = "" +
 	"\x11GetCaptchaRequest\"}\n" +
 	"\x12GetCaptchaResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x121\n" +
-	"\acaptcha\x18\x02 \x01(\v2\x17.todoing.api.v1.CaptchaR\acaptcha2e\n" +
-	"\x0eCaptchaService\x12S\n" +
+	"\acaptcha\x18\x02 \x01(\v2\x17.todoing.api.v1.CaptchaR\acaptcha\"O\n" +
+	"\x14VerifyCaptchaRequest\x12\x1d\n" +
+	"\n" +
+	"captcha_id\x18\x01 \x01(\tR\tcaptchaId\x12\x18\n" +
+	"\acaptcha\x18\x02 \x01(\tR\acaptcha2\xfc\x01\n" +
+	"\x0eCaptchaService\x12q\n" +
 	"\n" +
-	"GetCaptcha\x12!.todoing.api.v1.GetCaptchaRequest\x1a\".todoing.api.v1.GetCaptchaResponseB1Z/github.com/axfinn/todoIng/backend-go/pkg/api/v1b\x06proto3"
+	"GetCaptcha\x12!.todoing.api.v1.GetCaptchaRequest\x1a\".todoing.api.v1.GetCaptchaResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/api/v2/auth/captcha\x12w\n" +
+	"\rVerifyCaptcha\x12$.todoing.api.v1.VerifyCaptchaRequest\x1a\x18.todoing.api.v1.Response\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/api/v2/auth/verify-captchaB1Z/github.com/axfinn/todoIng/backend-go/pkg/api/v1b\x06proto3"
 
 var (
 	file_captcha_proto_rawDescOnce sync.Once
@@ -193,20 +252,23 @@ func file_captcha_proto_rawDescGZIP() []byte {
 	return file_captcha_proto_rawDescData
 }
 
-var file_captcha_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_captcha_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_captcha_proto_goTypes = []any{
-	(*Captcha)(nil),            // 0: todoing.api.v1.Captcha
-	(*GetCaptchaRequest)(nil),  // 1: todoing.api.v1.GetCaptchaRequest
-	(*GetCaptchaResponse)(nil), // 2: todoing.api.v1.GetCaptchaResponse
-	(*Response)(nil),           // 3: todoing.api.v1.Response
+	(*Captcha)(nil),              // 0: todoing.api.v1.Captcha
+	(*GetCaptchaRequest)(nil),    // 1: todoing.api.v1.GetCaptchaRequest
+	(*GetCaptchaResponse)(nil),   // 2: todoing.api.v1.GetCaptchaResponse
+	(*VerifyCaptchaRequest)(nil), // 3: todoing.api.v1.VerifyCaptchaRequest
+	(*Response)(nil),             // 4: todoing.api.v1.Response
 }
 var file_captcha_proto_depIdxs = []int32{
-	3, // 0: todoing.api.v1.GetCaptchaResponse.response:type_name -> todoing.api.v1.Response
+	4, // 0: todoing.api.v1.GetCaptchaResponse.response:type_name -> todoing.api.v1.Response
 	0, // 1: todoing.api.v1.GetCaptchaResponse.captcha:type_name -> todoing.api.v1.Captcha
 	1, // 2: todoing.api.v1.CaptchaService.GetCaptcha:input_type -> todoing.api.v1.GetCaptchaRequest
-	2, // 3: todoing.api.v1.CaptchaService.GetCaptcha:output_type -> todoing.api.v1.GetCaptchaResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
+	3, // 3: todoing.api.v1.CaptchaService.VerifyCaptcha:input_type -> todoing.api.v1.VerifyCaptchaRequest
+	2, // 4: todoing.api.v1.CaptchaService.GetCaptcha:output_type -> todoing.api.v1.GetCaptchaResponse
+	4, // 5: todoing.api.v1.CaptchaService.VerifyCaptcha:output_type -> todoing.api.v1.Response
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
 	2, // [2:2] is the sub-list for extension type_name
 	2, // [2:2] is the sub-list for extension extendee
 	0, // [0:2] is the sub-list for field type_name
@@ -224,7 +286,7 @@ func file_captcha_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_captcha_proto_rawDesc), len(file_captcha_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
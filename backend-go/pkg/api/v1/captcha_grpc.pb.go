@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CaptchaService_GetCaptcha_FullMethodName = "/todoing.api.v1.CaptchaService/GetCaptcha"
+	CaptchaService_GetCaptcha_FullMethodName    = "/todoing.api.v1.CaptchaService/GetCaptcha"
+	CaptchaService_VerifyCaptcha_FullMethodName = "/todoing.api.v1.CaptchaService/VerifyCaptcha"
 )
 
 // CaptchaServiceClient is the client API for CaptchaService service.
@@ -30,6 +31,8 @@ const (
 type CaptchaServiceClient interface {
 	// 获取验证码
 	GetCaptcha(ctx context.Context, in *GetCaptchaRequest, opts ...grpc.CallOption) (*GetCaptchaResponse, error)
+	// 校验验证码
+	VerifyCaptcha(ctx context.Context, in *VerifyCaptchaRequest, opts ...grpc.CallOption) (*Response, error)
 }
 
 type captchaServiceClient struct {
@@ -50,6 +53,16 @@ func (c *captchaServiceClient) GetCaptcha(ctx context.Context, in *GetCaptchaReq
 	return out, nil
 }
 
+func (c *captchaServiceClient) VerifyCaptcha(ctx context.Context, in *VerifyCaptchaRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, CaptchaService_VerifyCaptcha_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CaptchaServiceServer is the server API for CaptchaService service.
 // All implementations must embed UnimplementedCaptchaServiceServer
 // for forward compatibility.
@@ -58,6 +71,8 @@ func (c *captchaServiceClient) GetCaptcha(ctx context.Context, in *GetCaptchaReq
 type CaptchaServiceServer interface {
 	// 获取验证码
 	GetCaptcha(context.Context, *GetCaptchaRequest) (*GetCaptchaResponse, error)
+	// 校验验证码
+	VerifyCaptcha(context.Context, *VerifyCaptchaRequest) (*Response, error)
 	mustEmbedUnimplementedCaptchaServiceServer()
 }
 
@@ -71,6 +86,9 @@ type UnimplementedCaptchaServiceServer struct{}
 func (UnimplementedCaptchaServiceServer) GetCaptcha(context.Context, *GetCaptchaRequest) (*GetCaptchaResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCaptcha not implemented")
 }
+func (UnimplementedCaptchaServiceServer) VerifyCaptcha(context.Context, *VerifyCaptchaRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyCaptcha not implemented")
+}
 func (UnimplementedCaptchaServiceServer) mustEmbedUnimplementedCaptchaServiceServer() {}
 func (UnimplementedCaptchaServiceServer) testEmbeddedByValue()                        {}
 
@@ -110,6 +128,24 @@ func _CaptchaService_GetCaptcha_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CaptchaService_VerifyCaptcha_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyCaptchaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaptchaServiceServer).VerifyCaptcha(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CaptchaService_VerifyCaptcha_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaptchaServiceServer).VerifyCaptcha(ctx, req.(*VerifyCaptchaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CaptchaService_ServiceDesc is the grpc.ServiceDesc for CaptchaService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -121,6 +157,10 @@ var CaptchaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCaptcha",
 			Handler:    _CaptchaService_GetCaptcha_Handler,
 		},
+		{
+			MethodName: "VerifyCaptcha",
+			Handler:    _CaptchaService_VerifyCaptcha_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "captcha.proto",
@@ -7,6 +7,7 @@
 package v1
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -77,18 +78,26 @@ func (ReportType) EnumDescriptor() ([]byte, []int) {
 
 // 报表模型
 type Report struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Type          ReportType             `protobuf:"varint,3,opt,name=type,proto3,enum=todoing.api.v1.ReportType" json:"type,omitempty"`
-	StartDate     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
-	EndDate       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
-	UserId        string                 `protobuf:"bytes,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	Tasks         []*Task                `protobuf:"bytes,8,rep,name=tasks,proto3" json:"tasks,omitempty"`
-	Stats         *ReportStats           `protobuf:"bytes,9,opt,name=stats,proto3" json:"stats,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title           string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Type            ReportType             `protobuf:"varint,3,opt,name=type,proto3,enum=todoing.api.v1.ReportType" json:"type,omitempty"`
+	StartDate       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	UserId          string                 `protobuf:"bytes,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Tasks           []*Task                `protobuf:"bytes,8,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	Stats           *ReportStats           `protobuf:"bytes,9,opt,name=stats,proto3" json:"stats,omitempty"`
+	Period          string                 `protobuf:"bytes,10,opt,name=period,proto3" json:"period,omitempty"`
+	Content         string                 `protobuf:"bytes,11,opt,name=content,proto3" json:"content,omitempty"`
+	PolishedContent string                 `protobuf:"bytes,12,opt,name=polished_content,json=polishedContent,proto3" json:"polished_content,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	LabelStats      []*LabelStats          `protobuf:"bytes,14,rep,name=label_stats,json=labelStats,proto3" json:"label_stats,omitempty"` // 按标签分组的统计，一个任务可计入多个标签
+	ProjectId       string                 `protobuf:"bytes,15,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`    // 报表限定的项目，为空表示不限项目
+	DeletedAt       *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`    // 移入回收站的时间，未设置表示未删除
+	Version         int64                  `protobuf:"varint,17,opt,name=version,proto3" json:"version,omitempty"`                        // 每次写入递增，用于乐观并发控制
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Report) Reset() {
@@ -184,6 +193,62 @@ func (x *Report) GetStats() *ReportStats {
 	return nil
 }
 
+func (x *Report) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *Report) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Report) GetPolishedContent() string {
+	if x != nil {
+		return x.PolishedContent
+	}
+	return ""
+}
+
+func (x *Report) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Report) GetLabelStats() []*LabelStats {
+	if x != nil {
+		return x.LabelStats
+	}
+	return nil
+}
+
+func (x *Report) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *Report) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Report) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 // 报表统计信息
 type ReportStats struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -192,6 +257,7 @@ type ReportStats struct {
 	PendingTasks    int32                  `protobuf:"varint,3,opt,name=pending_tasks,json=pendingTasks,proto3" json:"pending_tasks,omitempty"`
 	InProgressTasks int32                  `protobuf:"varint,4,opt,name=in_progress_tasks,json=inProgressTasks,proto3" json:"in_progress_tasks,omitempty"`
 	CompletionRate  float64                `protobuf:"fixed64,5,opt,name=completion_rate,json=completionRate,proto3" json:"completion_rate,omitempty"`
+	OverdueTasks    int32                  `protobuf:"varint,6,opt,name=overdue_tasks,json=overdueTasks,proto3" json:"overdue_tasks,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -261,6 +327,66 @@ func (x *ReportStats) GetCompletionRate() float64 {
 	return 0
 }
 
+func (x *ReportStats) GetOverdueTasks() int32 {
+	if x != nil {
+		return x.OverdueTasks
+	}
+	return 0
+}
+
+// 带有某个标签的任务统计，label 为空表示未打标签的任务
+type LabelStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Stats         *ReportStats           `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LabelStats) Reset() {
+	*x = LabelStats{}
+	mi := &file_report_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LabelStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelStats) ProtoMessage() {}
+
+func (x *LabelStats) ProtoReflect() protoreflect.Message {
+	mi := &file_report_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelStats.ProtoReflect.Descriptor instead.
+func (*LabelStats) Descriptor() ([]byte, []int) {
+	return file_report_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LabelStats) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *LabelStats) GetStats() *ReportStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
 // 生成报表请求
 type GenerateReportRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -268,13 +394,16 @@ type GenerateReportRequest struct {
 	Type          ReportType             `protobuf:"varint,2,opt,name=type,proto3,enum=todoing.api.v1.ReportType" json:"type,omitempty"`
 	StartDate     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
 	EndDate       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Period        string                 `protobuf:"bytes,5,opt,name=period,proto3" json:"period,omitempty"`                        // 为空时根据起止日期生成
+	Query         string                 `protobuf:"bytes,6,opt,name=query,proto3" json:"query,omitempty"`                          // 可选，查询语言表达式，仅统计匹配的任务
+	ProjectId     string                 `protobuf:"bytes,7,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"` // 可选，仅统计该项目内的任务
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GenerateReportRequest) Reset() {
 	*x = GenerateReportRequest{}
-	mi := &file_report_proto_msgTypes[2]
+	mi := &file_report_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -286,7 +415,7 @@ func (x *GenerateReportRequest) String() string {
 func (*GenerateReportRequest) ProtoMessage() {}
 
 func (x *GenerateReportRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[2]
+	mi := &file_report_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -299,7 +428,7 @@ func (x *GenerateReportRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GenerateReportRequest.ProtoReflect.Descriptor instead.
 func (*GenerateReportRequest) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{2}
+	return file_report_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GenerateReportRequest) GetTitle() string {
@@ -330,6 +459,27 @@ func (x *GenerateReportRequest) GetEndDate() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *GenerateReportRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *GenerateReportRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *GenerateReportRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
 // 生成报表响应
 type GenerateReportResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -341,7 +491,7 @@ type GenerateReportResponse struct {
 
 func (x *GenerateReportResponse) Reset() {
 	*x = GenerateReportResponse{}
-	mi := &file_report_proto_msgTypes[3]
+	mi := &file_report_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -353,7 +503,7 @@ func (x *GenerateReportResponse) String() string {
 func (*GenerateReportResponse) ProtoMessage() {}
 
 func (x *GenerateReportResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[3]
+	mi := &file_report_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,7 +516,7 @@ func (x *GenerateReportResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GenerateReportResponse.ProtoReflect.Descriptor instead.
 func (*GenerateReportResponse) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{3}
+	return file_report_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GenerateReportResponse) GetResponse() *Response {
@@ -388,13 +538,14 @@ type GetReportsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Pagination    *PaginationRequest     `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
 	Type          ReportType             `protobuf:"varint,2,opt,name=type,proto3,enum=todoing.api.v1.ReportType" json:"type,omitempty"`
+	ProjectId     string                 `protobuf:"bytes,3,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"` // 只返回限定在该项目的报表
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetReportsRequest) Reset() {
 	*x = GetReportsRequest{}
-	mi := &file_report_proto_msgTypes[4]
+	mi := &file_report_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -406,7 +557,7 @@ func (x *GetReportsRequest) String() string {
 func (*GetReportsRequest) ProtoMessage() {}
 
 func (x *GetReportsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[4]
+	mi := &file_report_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -419,7 +570,7 @@ func (x *GetReportsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReportsRequest.ProtoReflect.Descriptor instead.
 func (*GetReportsRequest) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{4}
+	return file_report_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetReportsRequest) GetPagination() *PaginationRequest {
@@ -436,6 +587,13 @@ func (x *GetReportsRequest) GetType() ReportType {
 	return ReportType_REPORT_TYPE_UNSPECIFIED
 }
 
+func (x *GetReportsRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
 // 获取报表列表响应
 type GetReportsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -448,7 +606,7 @@ type GetReportsResponse struct {
 
 func (x *GetReportsResponse) Reset() {
 	*x = GetReportsResponse{}
-	mi := &file_report_proto_msgTypes[5]
+	mi := &file_report_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -460,7 +618,7 @@ func (x *GetReportsResponse) String() string {
 func (*GetReportsResponse) ProtoMessage() {}
 
 func (x *GetReportsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[5]
+	mi := &file_report_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -473,7 +631,7 @@ func (x *GetReportsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReportsResponse.ProtoReflect.Descriptor instead.
 func (*GetReportsResponse) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{5}
+	return file_report_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetReportsResponse) GetResponse() *Response {
@@ -507,7 +665,7 @@ type GetReportRequest struct {
 
 func (x *GetReportRequest) Reset() {
 	*x = GetReportRequest{}
-	mi := &file_report_proto_msgTypes[6]
+	mi := &file_report_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -519,7 +677,7 @@ func (x *GetReportRequest) String() string {
 func (*GetReportRequest) ProtoMessage() {}
 
 func (x *GetReportRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[6]
+	mi := &file_report_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -532,7 +690,7 @@ func (x *GetReportRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReportRequest.ProtoReflect.Descriptor instead.
 func (*GetReportRequest) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{6}
+	return file_report_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetReportRequest) GetId() string {
@@ -553,7 +711,7 @@ type GetReportResponse struct {
 
 func (x *GetReportResponse) Reset() {
 	*x = GetReportResponse{}
-	mi := &file_report_proto_msgTypes[7]
+	mi := &file_report_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -565,7 +723,7 @@ func (x *GetReportResponse) String() string {
 func (*GetReportResponse) ProtoMessage() {}
 
 func (x *GetReportResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[7]
+	mi := &file_report_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -578,7 +736,7 @@ func (x *GetReportResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetReportResponse.ProtoReflect.Descriptor instead.
 func (*GetReportResponse) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{7}
+	return file_report_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetReportResponse) GetResponse() *Response {
@@ -605,7 +763,7 @@ type DeleteReportRequest struct {
 
 func (x *DeleteReportRequest) Reset() {
 	*x = DeleteReportRequest{}
-	mi := &file_report_proto_msgTypes[8]
+	mi := &file_report_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -617,7 +775,7 @@ func (x *DeleteReportRequest) String() string {
 func (*DeleteReportRequest) ProtoMessage() {}
 
 func (x *DeleteReportRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[8]
+	mi := &file_report_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -630,7 +788,7 @@ func (x *DeleteReportRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteReportRequest.ProtoReflect.Descriptor instead.
 func (*DeleteReportRequest) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{8}
+	return file_report_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *DeleteReportRequest) GetId() string {
@@ -644,14 +802,14 @@ func (x *DeleteReportRequest) GetId() string {
 type ExportReportRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"` // "csv", "excel", "pdf"
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"` // "md"/"markdown" 导出 Markdown，其余导出纯文本
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExportReportRequest) Reset() {
 	*x = ExportReportRequest{}
-	mi := &file_report_proto_msgTypes[9]
+	mi := &file_report_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -663,7 +821,7 @@ func (x *ExportReportRequest) String() string {
 func (*ExportReportRequest) ProtoMessage() {}
 
 func (x *ExportReportRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[9]
+	mi := &file_report_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -676,7 +834,7 @@ func (x *ExportReportRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExportReportRequest.ProtoReflect.Descriptor instead.
 func (*ExportReportRequest) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{9}
+	return file_report_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ExportReportRequest) GetId() string {
@@ -706,7 +864,7 @@ type ExportReportResponse struct {
 
 func (x *ExportReportResponse) Reset() {
 	*x = ExportReportResponse{}
-	mi := &file_report_proto_msgTypes[10]
+	mi := &file_report_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -718,7 +876,7 @@ func (x *ExportReportResponse) String() string {
 func (*ExportReportResponse) ProtoMessage() {}
 
 func (x *ExportReportResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_report_proto_msgTypes[10]
+	mi := &file_report_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -731,7 +889,7 @@ func (x *ExportReportResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExportReportResponse.ProtoReflect.Descriptor instead.
 func (*ExportReportResponse) Descriptor() ([]byte, []int) {
-	return file_report_proto_rawDescGZIP(), []int{10}
+	return file_report_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ExportReportResponse) GetResponse() *Response {
@@ -766,8 +924,8 @@ var File_report_proto protoreflect.FileDescriptor
 
 const file_report_proto_rawDesc = "" +
 	"\n" +
-	"\freport.proto\x12\x0etodoing.api.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\fcommon.proto\x1a\n" +
-	"task.proto\"\x83\x03\n" +
+	"\freport.proto\x12\x0etodoing.api.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\fcommon.proto\x1a\x1cgoogle/api/annotations.proto\x1a\n" +
+	"task.proto\"\xcc\x05\n" +
 	"\x06Report\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12.\n" +
@@ -779,28 +937,52 @@ const file_report_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12*\n" +
 	"\x05tasks\x18\b \x03(\v2\x14.todoing.api.v1.TaskR\x05tasks\x121\n" +
-	"\x05stats\x18\t \x01(\v2\x1b.todoing.api.v1.ReportStatsR\x05stats\"\xd1\x01\n" +
+	"\x05stats\x18\t \x01(\v2\x1b.todoing.api.v1.ReportStatsR\x05stats\x12\x16\n" +
+	"\x06period\x18\n" +
+	" \x01(\tR\x06period\x12\x18\n" +
+	"\acontent\x18\v \x01(\tR\acontent\x12)\n" +
+	"\x10polished_content\x18\f \x01(\tR\x0fpolishedContent\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12;\n" +
+	"\vlabel_stats\x18\x0e \x03(\v2\x1a.todoing.api.v1.LabelStatsR\n" +
+	"labelStats\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x0f \x01(\tR\tprojectId\x129\n" +
+	"\n" +
+	"deleted_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x18\n" +
+	"\aversion\x18\x11 \x01(\x03R\aversion\"\xf6\x01\n" +
 	"\vReportStats\x12\x1f\n" +
 	"\vtotal_tasks\x18\x01 \x01(\x05R\n" +
 	"totalTasks\x12'\n" +
 	"\x0fcompleted_tasks\x18\x02 \x01(\x05R\x0ecompletedTasks\x12#\n" +
 	"\rpending_tasks\x18\x03 \x01(\x05R\fpendingTasks\x12*\n" +
 	"\x11in_progress_tasks\x18\x04 \x01(\x05R\x0finProgressTasks\x12'\n" +
-	"\x0fcompletion_rate\x18\x05 \x01(\x01R\x0ecompletionRate\"\xcf\x01\n" +
+	"\x0fcompletion_rate\x18\x05 \x01(\x01R\x0ecompletionRate\x12#\n" +
+	"\roverdue_tasks\x18\x06 \x01(\x05R\foverdueTasks\"U\n" +
+	"\n" +
+	"LabelStats\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x121\n" +
+	"\x05stats\x18\x02 \x01(\v2\x1b.todoing.api.v1.ReportStatsR\x05stats\"\x9c\x02\n" +
 	"\x15GenerateReportRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12.\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x1a.todoing.api.v1.ReportTypeR\x04type\x129\n" +
 	"\n" +
 	"start_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
-	"\bend_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\"~\n" +
+	"\bend_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x16\n" +
+	"\x06period\x18\x05 \x01(\tR\x06period\x12\x14\n" +
+	"\x05query\x18\x06 \x01(\tR\x05query\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\a \x01(\tR\tprojectId\"~\n" +
 	"\x16GenerateReportResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x12.\n" +
-	"\x06report\x18\x02 \x01(\v2\x16.todoing.api.v1.ReportR\x06report\"\x86\x01\n" +
+	"\x06report\x18\x02 \x01(\v2\x16.todoing.api.v1.ReportR\x06report\"\xa5\x01\n" +
 	"\x11GetReportsRequest\x12A\n" +
 	"\n" +
 	"pagination\x18\x01 \x01(\v2!.todoing.api.v1.PaginationRequestR\n" +
 	"pagination\x12.\n" +
-	"\x04type\x18\x02 \x01(\x0e2\x1a.todoing.api.v1.ReportTypeR\x04type\"\xc0\x01\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1a.todoing.api.v1.ReportTypeR\x04type\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x03 \x01(\tR\tprojectId\"\xc0\x01\n" +
 	"\x12GetReportsResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x120\n" +
 	"\areports\x18\x02 \x03(\v2\x16.todoing.api.v1.ReportR\areports\x12B\n" +
@@ -827,14 +1009,14 @@ const file_report_proto_rawDesc = "" +
 	"\x17REPORT_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11REPORT_TYPE_DAILY\x10\x01\x12\x16\n" +
 	"\x12REPORT_TYPE_WEEKLY\x10\x02\x12\x17\n" +
-	"\x13REPORT_TYPE_MONTHLY\x10\x032\xc1\x03\n" +
-	"\rReportService\x12_\n" +
-	"\x0eGenerateReport\x12%.todoing.api.v1.GenerateReportRequest\x1a&.todoing.api.v1.GenerateReportResponse\x12S\n" +
+	"\x13REPORT_TYPE_MONTHLY\x10\x032\xeb\x04\n" +
+	"\rReportService\x12\x84\x01\n" +
+	"\x0eGenerateReport\x12%.todoing.api.v1.GenerateReportRequest\x1a&.todoing.api.v1.GenerateReportResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/api/v2/reports/generate\x12l\n" +
 	"\n" +
-	"GetReports\x12!.todoing.api.v1.GetReportsRequest\x1a\".todoing.api.v1.GetReportsResponse\x12P\n" +
-	"\tGetReport\x12 .todoing.api.v1.GetReportRequest\x1a!.todoing.api.v1.GetReportResponse\x12M\n" +
-	"\fDeleteReport\x12#.todoing.api.v1.DeleteReportRequest\x1a\x18.todoing.api.v1.Response\x12Y\n" +
-	"\fExportReport\x12#.todoing.api.v1.ExportReportRequest\x1a$.todoing.api.v1.ExportReportResponseB1Z/github.com/axfinn/todoIng/backend-go/pkg/api/v1b\x06proto3"
+	"GetReports\x12!.todoing.api.v1.GetReportsRequest\x1a\".todoing.api.v1.GetReportsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/api/v2/reports\x12n\n" +
+	"\tGetReport\x12 .todoing.api.v1.GetReportRequest\x1a!.todoing.api.v1.GetReportResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/api/v2/reports/{id}\x12k\n" +
+	"\fDeleteReport\x12#.todoing.api.v1.DeleteReportRequest\x1a\x18.todoing.api.v1.Response\"\x1c\x82\xd3\xe4\x93\x02\x16*\x14/api/v2/reports/{id}\x12\x87\x01\n" +
+	"\fExportReport\x12#.todoing.api.v1.ExportReportRequest\x1a$.todoing.api.v1.ExportReportResponse\",\x82\xd3\xe4\x93\x02&\x12$/api/v2/reports/{id}/export/{format}B1Z/github.com/axfinn/todoIng/backend-go/pkg/api/v1b\x06proto3"
 
 var (
 	file_report_proto_rawDescOnce sync.Once
@@ -849,61 +1031,66 @@ func file_report_proto_rawDescGZIP() []byte {
 }
 
 var file_report_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_report_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_report_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_report_proto_goTypes = []any{
 	(ReportType)(0),                // 0: todoing.api.v1.ReportType
 	(*Report)(nil),                 // 1: todoing.api.v1.Report
 	(*ReportStats)(nil),            // 2: todoing.api.v1.ReportStats
-	(*GenerateReportRequest)(nil),  // 3: todoing.api.v1.GenerateReportRequest
-	(*GenerateReportResponse)(nil), // 4: todoing.api.v1.GenerateReportResponse
-	(*GetReportsRequest)(nil),      // 5: todoing.api.v1.GetReportsRequest
-	(*GetReportsResponse)(nil),     // 6: todoing.api.v1.GetReportsResponse
-	(*GetReportRequest)(nil),       // 7: todoing.api.v1.GetReportRequest
-	(*GetReportResponse)(nil),      // 8: todoing.api.v1.GetReportResponse
-	(*DeleteReportRequest)(nil),    // 9: todoing.api.v1.DeleteReportRequest
-	(*ExportReportRequest)(nil),    // 10: todoing.api.v1.ExportReportRequest
-	(*ExportReportResponse)(nil),   // 11: todoing.api.v1.ExportReportResponse
-	(*timestamppb.Timestamp)(nil),  // 12: google.protobuf.Timestamp
-	(*Task)(nil),                   // 13: todoing.api.v1.Task
-	(*Response)(nil),               // 14: todoing.api.v1.Response
-	(*PaginationRequest)(nil),      // 15: todoing.api.v1.PaginationRequest
-	(*PaginationResponse)(nil),     // 16: todoing.api.v1.PaginationResponse
+	(*LabelStats)(nil),             // 3: todoing.api.v1.LabelStats
+	(*GenerateReportRequest)(nil),  // 4: todoing.api.v1.GenerateReportRequest
+	(*GenerateReportResponse)(nil), // 5: todoing.api.v1.GenerateReportResponse
+	(*GetReportsRequest)(nil),      // 6: todoing.api.v1.GetReportsRequest
+	(*GetReportsResponse)(nil),     // 7: todoing.api.v1.GetReportsResponse
+	(*GetReportRequest)(nil),       // 8: todoing.api.v1.GetReportRequest
+	(*GetReportResponse)(nil),      // 9: todoing.api.v1.GetReportResponse
+	(*DeleteReportRequest)(nil),    // 10: todoing.api.v1.DeleteReportRequest
+	(*ExportReportRequest)(nil),    // 11: todoing.api.v1.ExportReportRequest
+	(*ExportReportResponse)(nil),   // 12: todoing.api.v1.ExportReportResponse
+	(*timestamppb.Timestamp)(nil),  // 13: google.protobuf.Timestamp
+	(*Task)(nil),                   // 14: todoing.api.v1.Task
+	(*Response)(nil),               // 15: todoing.api.v1.Response
+	(*PaginationRequest)(nil),      // 16: todoing.api.v1.PaginationRequest
+	(*PaginationResponse)(nil),     // 17: todoing.api.v1.PaginationResponse
 }
 var file_report_proto_depIdxs = []int32{
 	0,  // 0: todoing.api.v1.Report.type:type_name -> todoing.api.v1.ReportType
-	12, // 1: todoing.api.v1.Report.start_date:type_name -> google.protobuf.Timestamp
-	12, // 2: todoing.api.v1.Report.end_date:type_name -> google.protobuf.Timestamp
-	12, // 3: todoing.api.v1.Report.created_at:type_name -> google.protobuf.Timestamp
-	13, // 4: todoing.api.v1.Report.tasks:type_name -> todoing.api.v1.Task
+	13, // 1: todoing.api.v1.Report.start_date:type_name -> google.protobuf.Timestamp
+	13, // 2: todoing.api.v1.Report.end_date:type_name -> google.protobuf.Timestamp
+	13, // 3: todoing.api.v1.Report.created_at:type_name -> google.protobuf.Timestamp
+	14, // 4: todoing.api.v1.Report.tasks:type_name -> todoing.api.v1.Task
 	2,  // 5: todoing.api.v1.Report.stats:type_name -> todoing.api.v1.ReportStats
-	0,  // 6: todoing.api.v1.GenerateReportRequest.type:type_name -> todoing.api.v1.ReportType
-	12, // 7: todoing.api.v1.GenerateReportRequest.start_date:type_name -> google.protobuf.Timestamp
-	12, // 8: todoing.api.v1.GenerateReportRequest.end_date:type_name -> google.protobuf.Timestamp
-	14, // 9: todoing.api.v1.GenerateReportResponse.response:type_name -> todoing.api.v1.Response
-	1,  // 10: todoing.api.v1.GenerateReportResponse.report:type_name -> todoing.api.v1.Report
-	15, // 11: todoing.api.v1.GetReportsRequest.pagination:type_name -> todoing.api.v1.PaginationRequest
-	0,  // 12: todoing.api.v1.GetReportsRequest.type:type_name -> todoing.api.v1.ReportType
-	14, // 13: todoing.api.v1.GetReportsResponse.response:type_name -> todoing.api.v1.Response
-	1,  // 14: todoing.api.v1.GetReportsResponse.reports:type_name -> todoing.api.v1.Report
-	16, // 15: todoing.api.v1.GetReportsResponse.pagination:type_name -> todoing.api.v1.PaginationResponse
-	14, // 16: todoing.api.v1.GetReportResponse.response:type_name -> todoing.api.v1.Response
-	1,  // 17: todoing.api.v1.GetReportResponse.report:type_name -> todoing.api.v1.Report
-	14, // 18: todoing.api.v1.ExportReportResponse.response:type_name -> todoing.api.v1.Response
-	3,  // 19: todoing.api.v1.ReportService.GenerateReport:input_type -> todoing.api.v1.GenerateReportRequest
-	5,  // 20: todoing.api.v1.ReportService.GetReports:input_type -> todoing.api.v1.GetReportsRequest
-	7,  // 21: todoing.api.v1.ReportService.GetReport:input_type -> todoing.api.v1.GetReportRequest
-	9,  // 22: todoing.api.v1.ReportService.DeleteReport:input_type -> todoing.api.v1.DeleteReportRequest
-	10, // 23: todoing.api.v1.ReportService.ExportReport:input_type -> todoing.api.v1.ExportReportRequest
-	4,  // 24: todoing.api.v1.ReportService.GenerateReport:output_type -> todoing.api.v1.GenerateReportResponse
-	6,  // 25: todoing.api.v1.ReportService.GetReports:output_type -> todoing.api.v1.GetReportsResponse
-	8,  // 26: todoing.api.v1.ReportService.GetReport:output_type -> todoing.api.v1.GetReportResponse
-	14, // 27: todoing.api.v1.ReportService.DeleteReport:output_type -> todoing.api.v1.Response
-	11, // 28: todoing.api.v1.ReportService.ExportReport:output_type -> todoing.api.v1.ExportReportResponse
-	24, // [24:29] is the sub-list for method output_type
-	19, // [19:24] is the sub-list for method input_type
-	19, // [19:19] is the sub-list for extension type_name
-	19, // [19:19] is the sub-list for extension extendee
-	0,  // [0:19] is the sub-list for field type_name
+	13, // 6: todoing.api.v1.Report.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 7: todoing.api.v1.Report.label_stats:type_name -> todoing.api.v1.LabelStats
+	13, // 8: todoing.api.v1.Report.deleted_at:type_name -> google.protobuf.Timestamp
+	2,  // 9: todoing.api.v1.LabelStats.stats:type_name -> todoing.api.v1.ReportStats
+	0,  // 10: todoing.api.v1.GenerateReportRequest.type:type_name -> todoing.api.v1.ReportType
+	13, // 11: todoing.api.v1.GenerateReportRequest.start_date:type_name -> google.protobuf.Timestamp
+	13, // 12: todoing.api.v1.GenerateReportRequest.end_date:type_name -> google.protobuf.Timestamp
+	15, // 13: todoing.api.v1.GenerateReportResponse.response:type_name -> todoing.api.v1.Response
+	1,  // 14: todoing.api.v1.GenerateReportResponse.report:type_name -> todoing.api.v1.Report
+	16, // 15: todoing.api.v1.GetReportsRequest.pagination:type_name -> todoing.api.v1.PaginationRequest
+	0,  // 16: todoing.api.v1.GetReportsRequest.type:type_name -> todoing.api.v1.ReportType
+	15, // 17: todoing.api.v1.GetReportsResponse.response:type_name -> todoing.api.v1.Response
+	1,  // 18: todoing.api.v1.GetReportsResponse.reports:type_name -> todoing.api.v1.Report
+	17, // 19: todoing.api.v1.GetReportsResponse.pagination:type_name -> todoing.api.v1.PaginationResponse
+	15, // 20: todoing.api.v1.GetReportResponse.response:type_name -> todoing.api.v1.Response
+	1,  // 21: todoing.api.v1.GetReportResponse.report:type_name -> todoing.api.v1.Report
+	15, // 22: todoing.api.v1.ExportReportResponse.response:type_name -> todoing.api.v1.Response
+	4,  // 23: todoing.api.v1.ReportService.GenerateReport:input_type -> todoing.api.v1.GenerateReportRequest
+	6,  // 24: todoing.api.v1.ReportService.GetReports:input_type -> todoing.api.v1.GetReportsRequest
+	8,  // 25: todoing.api.v1.ReportService.GetReport:input_type -> todoing.api.v1.GetReportRequest
+	10, // 26: todoing.api.v1.ReportService.DeleteReport:input_type -> todoing.api.v1.DeleteReportRequest
+	11, // 27: todoing.api.v1.ReportService.ExportReport:input_type -> todoing.api.v1.ExportReportRequest
+	5,  // 28: todoing.api.v1.ReportService.GenerateReport:output_type -> todoing.api.v1.GenerateReportResponse
+	7,  // 29: todoing.api.v1.ReportService.GetReports:output_type -> todoing.api.v1.GetReportsResponse
+	9,  // 30: todoing.api.v1.ReportService.GetReport:output_type -> todoing.api.v1.GetReportResponse
+	15, // 31: todoing.api.v1.ReportService.DeleteReport:output_type -> todoing.api.v1.Response
+	12, // 32: todoing.api.v1.ReportService.ExportReport:output_type -> todoing.api.v1.ExportReportResponse
+	28, // [28:33] is the sub-list for method output_type
+	23, // [23:28] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_report_proto_init() }
@@ -919,7 +1106,7 @@ func file_report_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_report_proto_rawDesc), len(file_report_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   11,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -7,8 +7,10 @@
 package v1
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -128,6 +130,213 @@ func (TaskPriority) EnumDescriptor() ([]byte, []int) {
 	return file_task_proto_rawDescGZIP(), []int{1}
 }
 
+// 任务评论
+type Comment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,2,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Id            string                 `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`                                // 评论 ID，在任务内唯一
+	ParentId      string                 `protobuf:"bytes,5,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`    // 所回复的评论 ID，为空表示顶层评论
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // 最后一次编辑的时间，未编辑时不设置
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_task_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Comment) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Comment) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Comment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Comment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// 任务检查项
+type ChecklistItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Done          bool                   `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChecklistItem) Reset() {
+	*x = ChecklistItem{}
+	mi := &file_task_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChecklistItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecklistItem) ProtoMessage() {}
+
+func (x *ChecklistItem) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecklistItem.ProtoReflect.Descriptor instead.
+func (*ChecklistItem) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ChecklistItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChecklistItem) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ChecklistItem) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *ChecklistItem) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// 重复规则，rule 为 RFC 5545 RRULE 子集，tzid 为空表示 UTC
+type Recurrence struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Tzid          string                 `protobuf:"bytes,2,opt,name=tzid,proto3" json:"tzid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Recurrence) Reset() {
+	*x = Recurrence{}
+	mi := &file_task_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Recurrence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recurrence) ProtoMessage() {}
+
+func (x *Recurrence) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recurrence.ProtoReflect.Descriptor instead.
+func (*Recurrence) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Recurrence) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *Recurrence) GetTzid() string {
+	if x != nil {
+		return x.Tzid
+	}
+	return ""
+}
+
 // 任务模型
 type Task struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -140,13 +349,28 @@ type Task struct {
 	UserId        string                 `protobuf:"bytes,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Assignee      string                 `protobuf:"bytes,10,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	ScheduledDate *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=scheduled_date,json=scheduledDate,proto3" json:"scheduled_date,omitempty"`
+	Comments      []*Comment             `protobuf:"bytes,12,rep,name=comments,proto3" json:"comments,omitempty"`
+	ParentId      string                 `protobuf:"bytes,13,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // 为空表示顶层任务
+	Checklist     []*ChecklistItem       `protobuf:"bytes,14,rep,name=checklist,proto3" json:"checklist,omitempty"`
+	Progress      int32                  `protobuf:"varint,15,opt,name=progress,proto3" json:"progress,omitempty"`                            // 汇总子任务与检查项后的完成度（0-100），仅 GetTask 返回
+	Subtasks      []*Task                `protobuf:"bytes,16,rep,name=subtasks,proto3" json:"subtasks,omitempty"`                             // 嵌套的子任务，仅 GetTask 返回
+	BlockedBy     []string               `protobuf:"bytes,17,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`          // 阻塞该任务的任务 ID
+	Recurrence    *Recurrence            `protobuf:"bytes,18,opt,name=recurrence,proto3" json:"recurrence,omitempty"`                         // 未设置表示不重复
+	Labels        []string               `protobuf:"bytes,19,rep,name=labels,proto3" json:"labels,omitempty"`                                 // 标签名称
+	ProjectId     string                 `protobuf:"bytes,20,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`          // 所属项目 ID，为空表示未归入项目
+	StatusName    string                 `protobuf:"bytes,21,opt,name=status_name,json=statusName,proto3" json:"status_name,omitempty"`       // 状态名称，自定义工作流的状态只能通过该字段表示
+	PriorityName  string                 `protobuf:"bytes,22,opt,name=priority_name,json=priorityName,proto3" json:"priority_name,omitempty"` // 优先级名称
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,23,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`          // 移入回收站的时间，未设置表示未删除
+	Version       int64                  `protobuf:"varint,24,opt,name=version,proto3" json:"version,omitempty"`                              // 每次写入递增，用于乐观并发控制
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Task) Reset() {
 	*x = Task{}
-	mi := &file_task_proto_msgTypes[0]
+	mi := &file_task_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -158,7 +382,7 @@ func (x *Task) String() string {
 func (*Task) ProtoMessage() {}
 
 func (x *Task) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[0]
+	mi := &file_task_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -171,7 +395,7 @@ func (x *Task) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Task.ProtoReflect.Descriptor instead.
 func (*Task) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{0}
+	return file_task_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Task) GetId() string {
@@ -237,6 +461,111 @@ func (x *Task) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Task) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *Task) GetScheduledDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledDate
+	}
+	return nil
+}
+
+func (x *Task) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *Task) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Task) GetChecklist() []*ChecklistItem {
+	if x != nil {
+		return x.Checklist
+	}
+	return nil
+}
+
+func (x *Task) GetProgress() int32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *Task) GetSubtasks() []*Task {
+	if x != nil {
+		return x.Subtasks
+	}
+	return nil
+}
+
+func (x *Task) GetBlockedBy() []string {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *Task) GetRecurrence() *Recurrence {
+	if x != nil {
+		return x.Recurrence
+	}
+	return nil
+}
+
+func (x *Task) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Task) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *Task) GetStatusName() string {
+	if x != nil {
+		return x.StatusName
+	}
+	return ""
+}
+
+func (x *Task) GetPriorityName() string {
+	if x != nil {
+		return x.PriorityName
+	}
+	return ""
+}
+
+func (x *Task) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Task) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 // 创建任务请求
 type CreateTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -245,13 +574,23 @@ type CreateTaskRequest struct {
 	Status        TaskStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=todoing.api.v1.TaskStatus" json:"status,omitempty"`
 	Priority      TaskPriority           `protobuf:"varint,4,opt,name=priority,proto3,enum=todoing.api.v1.TaskPriority" json:"priority,omitempty"`
 	DueDate       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	Assignee      string                 `protobuf:"bytes,6,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	ScheduledDate *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=scheduled_date,json=scheduledDate,proto3" json:"scheduled_date,omitempty"`
+	Comments      []string               `protobuf:"bytes,8,rep,name=comments,proto3" json:"comments,omitempty"`                              // 评论内容
+	ParentId      string                 `protobuf:"bytes,9,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`              // 父任务 ID，为空时创建顶层任务
+	BlockedBy     []string               `protobuf:"bytes,10,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`          // 阻塞该任务的任务 ID，不能成环
+	Recurrence    *Recurrence            `protobuf:"bytes,11,opt,name=recurrence,proto3" json:"recurrence,omitempty"`                         // 重复规则，需同时设置 due_date 或 scheduled_date
+	Labels        []string               `protobuf:"bytes,12,rep,name=labels,proto3" json:"labels,omitempty"`                                 // 标签名称，须为已有标签，不区分大小写
+	ProjectId     string                 `protobuf:"bytes,13,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`          // 所属项目，不能是已归档的项目；为空时子任务归入父任务所在的项目
+	StatusName    string                 `protobuf:"bytes,14,opt,name=status_name,json=statusName,proto3" json:"status_name,omitempty"`       // 非空时覆盖 status，须属于任务所在的工作流
+	PriorityName  string                 `protobuf:"bytes,15,opt,name=priority_name,json=priorityName,proto3" json:"priority_name,omitempty"` // 非空时覆盖 priority，须属于任务所在的工作流
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateTaskRequest) Reset() {
 	*x = CreateTaskRequest{}
-	mi := &file_task_proto_msgTypes[1]
+	mi := &file_task_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -263,7 +602,7 @@ func (x *CreateTaskRequest) String() string {
 func (*CreateTaskRequest) ProtoMessage() {}
 
 func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[1]
+	mi := &file_task_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -276,7 +615,7 @@ func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
 func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{1}
+	return file_task_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateTaskRequest) GetTitle() string {
@@ -314,6 +653,76 @@ func (x *CreateTaskRequest) GetDueDate() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *CreateTaskRequest) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetScheduledDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledDate
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetComments() []string {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetBlockedBy() []string {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetRecurrence() *Recurrence {
+	if x != nil {
+		return x.Recurrence
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetStatusName() string {
+	if x != nil {
+		return x.StatusName
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetPriorityName() string {
+	if x != nil {
+		return x.PriorityName
+	}
+	return ""
+}
+
 // 创建任务响应
 type CreateTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -325,7 +734,7 @@ type CreateTaskResponse struct {
 
 func (x *CreateTaskResponse) Reset() {
 	*x = CreateTaskResponse{}
-	mi := &file_task_proto_msgTypes[2]
+	mi := &file_task_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -337,7 +746,7 @@ func (x *CreateTaskResponse) String() string {
 func (*CreateTaskResponse) ProtoMessage() {}
 
 func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[2]
+	mi := &file_task_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -350,7 +759,7 @@ func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTaskResponse.ProtoReflect.Descriptor instead.
 func (*CreateTaskResponse) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{2}
+	return file_task_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateTaskResponse) GetResponse() *Response {
@@ -368,18 +777,33 @@ func (x *CreateTaskResponse) GetTask() *Task {
 }
 
 // 获取任务列表请求
+// 时间范围均含边界；sort 形如 "deadline"、"-createdAt"，"-" 表示降序
 type GetTasksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Pagination    *PaginationRequest     `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
 	Status        TaskStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=todoing.api.v1.TaskStatus" json:"status,omitempty"`
 	Priority      TaskPriority           `protobuf:"varint,3,opt,name=priority,proto3,enum=todoing.api.v1.TaskPriority" json:"priority,omitempty"`
+	Assignee      string                 `protobuf:"bytes,4,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	DueDateFrom   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=due_date_from,json=dueDateFrom,proto3" json:"due_date_from,omitempty"`
+	DueDateTo     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=due_date_to,json=dueDateTo,proto3" json:"due_date_to,omitempty"`
+	ScheduledFrom *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=scheduled_from,json=scheduledFrom,proto3" json:"scheduled_from,omitempty"`
+	ScheduledTo   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=scheduled_to,json=scheduledTo,proto3" json:"scheduled_to,omitempty"`
+	CreatedFrom   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	UpdatedFrom   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_from,json=updatedFrom,proto3" json:"updated_from,omitempty"`
+	UpdatedTo     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_to,json=updatedTo,proto3" json:"updated_to,omitempty"`
+	Sort          string                 `protobuf:"bytes,13,opt,name=sort,proto3" json:"sort,omitempty"`
+	Query         string                 `protobuf:"bytes,14,opt,name=query,proto3" json:"query,omitempty"`                          // 查询语言表达式，如 status:todo due<7d
+	Labels        []string               `protobuf:"bytes,15,rep,name=labels,proto3" json:"labels,omitempty"`                        // 带有其中任一标签，按名称精确匹配
+	ProjectId     string                 `protobuf:"bytes,16,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"` // 只返回该项目的任务，none 表示未归入项目的任务
+	ParentId      string                 `protobuf:"bytes,17,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`    // 只返回该任务的直接子任务
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetTasksRequest) Reset() {
 	*x = GetTasksRequest{}
-	mi := &file_task_proto_msgTypes[3]
+	mi := &file_task_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -391,7 +815,7 @@ func (x *GetTasksRequest) String() string {
 func (*GetTasksRequest) ProtoMessage() {}
 
 func (x *GetTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[3]
+	mi := &file_task_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -404,7 +828,7 @@ func (x *GetTasksRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTasksRequest.ProtoReflect.Descriptor instead.
 func (*GetTasksRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{3}
+	return file_task_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetTasksRequest) GetPagination() *PaginationRequest {
@@ -428,6 +852,104 @@ func (x *GetTasksRequest) GetPriority() TaskPriority {
 	return TaskPriority_TASK_PRIORITY_UNSPECIFIED
 }
 
+func (x *GetTasksRequest) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *GetTasksRequest) GetDueDateFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDateFrom
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetDueDateTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDateTo
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetScheduledFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledFrom
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetScheduledTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledTo
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetUpdatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedFrom
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetUpdatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedTo
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *GetTasksRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *GetTasksRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *GetTasksRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *GetTasksRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
 // 获取任务列表响应
 type GetTasksResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -440,7 +962,7 @@ type GetTasksResponse struct {
 
 func (x *GetTasksResponse) Reset() {
 	*x = GetTasksResponse{}
-	mi := &file_task_proto_msgTypes[4]
+	mi := &file_task_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -452,7 +974,7 @@ func (x *GetTasksResponse) String() string {
 func (*GetTasksResponse) ProtoMessage() {}
 
 func (x *GetTasksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[4]
+	mi := &file_task_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -465,7 +987,7 @@ func (x *GetTasksResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTasksResponse.ProtoReflect.Descriptor instead.
 func (*GetTasksResponse) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{4}
+	return file_task_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetTasksResponse) GetResponse() *Response {
@@ -499,7 +1021,7 @@ type GetTaskRequest struct {
 
 func (x *GetTaskRequest) Reset() {
 	*x = GetTaskRequest{}
-	mi := &file_task_proto_msgTypes[5]
+	mi := &file_task_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -511,7 +1033,7 @@ func (x *GetTaskRequest) String() string {
 func (*GetTaskRequest) ProtoMessage() {}
 
 func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[5]
+	mi := &file_task_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -524,7 +1046,7 @@ func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
 func (*GetTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{5}
+	return file_task_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetTaskRequest) GetId() string {
@@ -545,7 +1067,7 @@ type GetTaskResponse struct {
 
 func (x *GetTaskResponse) Reset() {
 	*x = GetTaskResponse{}
-	mi := &file_task_proto_msgTypes[6]
+	mi := &file_task_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -557,7 +1079,7 @@ func (x *GetTaskResponse) String() string {
 func (*GetTaskResponse) ProtoMessage() {}
 
 func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[6]
+	mi := &file_task_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -570,7 +1092,7 @@ func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskResponse.ProtoReflect.Descriptor instead.
 func (*GetTaskResponse) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{6}
+	return file_task_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetTaskResponse) GetResponse() *Response {
@@ -596,13 +1118,30 @@ type UpdateTaskRequest struct {
 	Status        TaskStatus             `protobuf:"varint,4,opt,name=status,proto3,enum=todoing.api.v1.TaskStatus" json:"status,omitempty"`
 	Priority      TaskPriority           `protobuf:"varint,5,opt,name=priority,proto3,enum=todoing.api.v1.TaskPriority" json:"priority,omitempty"`
 	DueDate       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	Assignee      string                 `protobuf:"bytes,7,opt,name=assignee,proto3" json:"assignee,omitempty"`
+	ScheduledDate *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=scheduled_date,json=scheduledDate,proto3" json:"scheduled_date,omitempty"`
+	Comments      []string               `protobuf:"bytes,9,rep,name=comments,proto3" json:"comments,omitempty"`                               // 追加为新评论，已有评论保持不变
+	Cascade       bool                   `protobuf:"varint,10,opt,name=cascade,proto3" json:"cascade,omitempty"`                               // 状态改为完成时级联完成全部子任务与检查项
+	BlockedBy     []string               `protobuf:"bytes,11,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`           // 非空时替换全部阻塞任务，不能成环
+	Force         bool                   `protobuf:"varint,12,opt,name=force,proto3" json:"force,omitempty"`                                   // 仍有未完成的阻塞任务时也允许改为完成
+	Recurrence    *Recurrence            `protobuf:"bytes,13,opt,name=recurrence,proto3" json:"recurrence,omitempty"`                          // 设置时替换重复规则，rule 为空表示取消重复
+	Labels        []string               `protobuf:"bytes,14,rep,name=labels,proto3" json:"labels,omitempty"`                                  // 非空时替换全部标签
+	ClearLabels   bool                   `protobuf:"varint,15,opt,name=clear_labels,json=clearLabels,proto3" json:"clear_labels,omitempty"`    // 移除全部标签
+	ProjectId     string                 `protobuf:"bytes,16,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`           // 非空时移到该项目，只移动任务本身
+	ClearProject  bool                   `protobuf:"varint,17,opt,name=clear_project,json=clearProject,proto3" json:"clear_project,omitempty"` // 移出项目
+	StatusName    string                 `protobuf:"bytes,18,opt,name=status_name,json=statusName,proto3" json:"status_name,omitempty"`        // 非空时覆盖 status，须属于任务所在的工作流且允许流转
+	PriorityName  string                 `protobuf:"bytes,19,opt,name=priority_name,json=priorityName,proto3" json:"priority_name,omitempty"`  // 非空时覆盖 priority
+	// 要修改的字段，取值为本消息的字段名，如 "assignee"、"due_date"；列出的字段为空时被清空，
+	// status 与 priority 为空时恢复为工作流的默认值。未设置时只修改非空的字段
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,20,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	Version       int64                  `protobuf:"varint,21,opt,name=version,proto3" json:"version,omitempty"` // 非 0 时只在与任务的当前版本一致时修改，否则返回 ABORTED
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateTaskRequest) Reset() {
 	*x = UpdateTaskRequest{}
-	mi := &file_task_proto_msgTypes[7]
+	mi := &file_task_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -614,7 +1153,7 @@ func (x *UpdateTaskRequest) String() string {
 func (*UpdateTaskRequest) ProtoMessage() {}
 
 func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[7]
+	mi := &file_task_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -627,7 +1166,7 @@ func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
 func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{7}
+	return file_task_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *UpdateTaskRequest) GetId() string {
@@ -672,18 +1211,124 @@ func (x *UpdateTaskRequest) GetDueDate() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *UpdateTaskRequest) GetAssignee() string {
+	if x != nil {
+		return x.Assignee
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetScheduledDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledDate
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetComments() []string {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+func (x *UpdateTaskRequest) GetBlockedBy() []string {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *UpdateTaskRequest) GetRecurrence() *Recurrence {
+	if x != nil {
+		return x.Recurrence
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetClearLabels() bool {
+	if x != nil {
+		return x.ClearLabels
+	}
+	return false
+}
+
+func (x *UpdateTaskRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetClearProject() bool {
+	if x != nil {
+		return x.ClearProject
+	}
+	return false
+}
+
+func (x *UpdateTaskRequest) GetStatusName() string {
+	if x != nil {
+		return x.StatusName
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetPriorityName() string {
+	if x != nil {
+		return x.PriorityName
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 // 更新任务响应
 type UpdateTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Response      *Response              `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
 	Task          *Task                  `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	Next          *Task                  `protobuf:"bytes,3,opt,name=next,proto3" json:"next,omitempty"` // 重复任务完成时创建的下一次实例
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateTaskResponse) Reset() {
 	*x = UpdateTaskResponse{}
-	mi := &file_task_proto_msgTypes[8]
+	mi := &file_task_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -695,7 +1340,7 @@ func (x *UpdateTaskResponse) String() string {
 func (*UpdateTaskResponse) ProtoMessage() {}
 
 func (x *UpdateTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[8]
+	mi := &file_task_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -708,7 +1353,7 @@ func (x *UpdateTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTaskResponse.ProtoReflect.Descriptor instead.
 func (*UpdateTaskResponse) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{8}
+	return file_task_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *UpdateTaskResponse) GetResponse() *Response {
@@ -725,17 +1370,25 @@ func (x *UpdateTaskResponse) GetTask() *Task {
 	return nil
 }
 
+func (x *UpdateTaskResponse) GetNext() *Task {
+	if x != nil {
+		return x.Next
+	}
+	return nil
+}
+
 // 删除任务请求
 type DeleteTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // 非 0 时只在与任务的当前版本一致时删除，否则返回 ABORTED
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteTaskRequest) Reset() {
 	*x = DeleteTaskRequest{}
-	mi := &file_task_proto_msgTypes[9]
+	mi := &file_task_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -747,7 +1400,7 @@ func (x *DeleteTaskRequest) String() string {
 func (*DeleteTaskRequest) ProtoMessage() {}
 
 func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[9]
+	mi := &file_task_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -760,7 +1413,7 @@ func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
 func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{9}
+	return file_task_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *DeleteTaskRequest) GetId() string {
@@ -770,12 +1423,39 @@ func (x *DeleteTaskRequest) GetId() string {
 	return ""
 }
 
+func (x *DeleteTaskRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 var File_task_proto protoreflect.FileDescriptor
 
 const file_task_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"task.proto\x12\x0etodoing.api.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\fcommon.proto\"\x82\x03\n" +
+	"task.proto\x12\x0etodoing.api.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/field_mask.proto\x1a\fcommon.proto\x1a\x1cgoogle/api/annotations.proto\"\xdf\x01\n" +
+	"\aComment\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x02 \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\x12\x1b\n" +
+	"\tparent_id\x18\x05 \x01(\tR\bparentId\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x82\x01\n" +
+	"\rChecklistItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x12\n" +
+	"\x04done\x18\x03 \x01(\bR\x04done\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"4\n" +
+	"\n" +
+	"Recurrence\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x12\n" +
+	"\x04tzid\x18\x02 \x01(\tR\x04tzid\"\xeb\a\n" +
 	"\x04Task\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
@@ -787,22 +1467,78 @@ const file_task_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xf0\x01\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\bassignee\x18\n" +
+	" \x01(\tR\bassignee\x12A\n" +
+	"\x0escheduled_date\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledDate\x123\n" +
+	"\bcomments\x18\f \x03(\v2\x17.todoing.api.v1.CommentR\bcomments\x12\x1b\n" +
+	"\tparent_id\x18\r \x01(\tR\bparentId\x12;\n" +
+	"\tchecklist\x18\x0e \x03(\v2\x1d.todoing.api.v1.ChecklistItemR\tchecklist\x12\x1a\n" +
+	"\bprogress\x18\x0f \x01(\x05R\bprogress\x120\n" +
+	"\bsubtasks\x18\x10 \x03(\v2\x14.todoing.api.v1.TaskR\bsubtasks\x12\x1d\n" +
+	"\n" +
+	"blocked_by\x18\x11 \x03(\tR\tblockedBy\x12:\n" +
+	"\n" +
+	"recurrence\x18\x12 \x01(\v2\x1a.todoing.api.v1.RecurrenceR\n" +
+	"recurrence\x12\x16\n" +
+	"\x06labels\x18\x13 \x03(\tR\x06labels\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x14 \x01(\tR\tprojectId\x12\x1f\n" +
+	"\vstatus_name\x18\x15 \x01(\tR\n" +
+	"statusName\x12#\n" +
+	"\rpriority_name\x18\x16 \x01(\tR\fpriorityName\x129\n" +
+	"\n" +
+	"deleted_at\x18\x17 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x18\n" +
+	"\aversion\x18\x18 \x01(\x03R\aversion\"\xe0\x04\n" +
 	"\x11CreateTaskRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x122\n" +
 	"\x06status\x18\x03 \x01(\x0e2\x1a.todoing.api.v1.TaskStatusR\x06status\x128\n" +
 	"\bpriority\x18\x04 \x01(\x0e2\x1c.todoing.api.v1.TaskPriorityR\bpriority\x125\n" +
-	"\bdue_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\"t\n" +
+	"\bdue_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x12\x1a\n" +
+	"\bassignee\x18\x06 \x01(\tR\bassignee\x12A\n" +
+	"\x0escheduled_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledDate\x12\x1a\n" +
+	"\bcomments\x18\b \x03(\tR\bcomments\x12\x1b\n" +
+	"\tparent_id\x18\t \x01(\tR\bparentId\x12\x1d\n" +
+	"\n" +
+	"blocked_by\x18\n" +
+	" \x03(\tR\tblockedBy\x12:\n" +
+	"\n" +
+	"recurrence\x18\v \x01(\v2\x1a.todoing.api.v1.RecurrenceR\n" +
+	"recurrence\x12\x16\n" +
+	"\x06labels\x18\f \x03(\tR\x06labels\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\r \x01(\tR\tprojectId\x12\x1f\n" +
+	"\vstatus_name\x18\x0e \x01(\tR\n" +
+	"statusName\x12#\n" +
+	"\rpriority_name\x18\x0f \x01(\tR\fpriorityName\"t\n" +
 	"\x12CreateTaskResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x12(\n" +
-	"\x04task\x18\x02 \x01(\v2\x14.todoing.api.v1.TaskR\x04task\"\xc2\x01\n" +
+	"\x04task\x18\x02 \x01(\v2\x14.todoing.api.v1.TaskR\x04task\"\xce\x06\n" +
 	"\x0fGetTasksRequest\x12A\n" +
 	"\n" +
 	"pagination\x18\x01 \x01(\v2!.todoing.api.v1.PaginationRequestR\n" +
 	"pagination\x122\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x1a.todoing.api.v1.TaskStatusR\x06status\x128\n" +
-	"\bpriority\x18\x03 \x01(\x0e2\x1c.todoing.api.v1.TaskPriorityR\bpriority\"\xb8\x01\n" +
+	"\bpriority\x18\x03 \x01(\x0e2\x1c.todoing.api.v1.TaskPriorityR\bpriority\x12\x1a\n" +
+	"\bassignee\x18\x04 \x01(\tR\bassignee\x12>\n" +
+	"\rdue_date_from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vdueDateFrom\x12:\n" +
+	"\vdue_date_to\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tdueDateTo\x12A\n" +
+	"\x0escheduled_from\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledFrom\x12=\n" +
+	"\fscheduled_to\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledTo\x12=\n" +
+	"\fcreated_from\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x12=\n" +
+	"\fupdated_from\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vupdatedFrom\x129\n" +
+	"\n" +
+	"updated_to\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedTo\x12\x12\n" +
+	"\x04sort\x18\r \x01(\tR\x04sort\x12\x14\n" +
+	"\x05query\x18\x0e \x01(\tR\x05query\x12\x16\n" +
+	"\x06labels\x18\x0f \x03(\tR\x06labels\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x10 \x01(\tR\tprojectId\x12\x1b\n" +
+	"\tparent_id\x18\x11 \x01(\tR\bparentId\"\xb8\x01\n" +
 	"\x10GetTasksResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x12*\n" +
 	"\x05tasks\x18\x02 \x03(\v2\x14.todoing.api.v1.TaskR\x05tasks\x12B\n" +
@@ -813,19 +1549,43 @@ const file_task_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"q\n" +
 	"\x0fGetTaskResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x12(\n" +
-	"\x04task\x18\x02 \x01(\v2\x14.todoing.api.v1.TaskR\x04task\"\x80\x02\n" +
+	"\x04task\x18\x02 \x01(\v2\x14.todoing.api.v1.TaskR\x04task\"\xa2\x06\n" +
 	"\x11UpdateTaskRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x122\n" +
 	"\x06status\x18\x04 \x01(\x0e2\x1a.todoing.api.v1.TaskStatusR\x06status\x128\n" +
 	"\bpriority\x18\x05 \x01(\x0e2\x1c.todoing.api.v1.TaskPriorityR\bpriority\x125\n" +
-	"\bdue_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\"t\n" +
+	"\bdue_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x12\x1a\n" +
+	"\bassignee\x18\a \x01(\tR\bassignee\x12A\n" +
+	"\x0escheduled_date\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledDate\x12\x1a\n" +
+	"\bcomments\x18\t \x03(\tR\bcomments\x12\x18\n" +
+	"\acascade\x18\n" +
+	" \x01(\bR\acascade\x12\x1d\n" +
+	"\n" +
+	"blocked_by\x18\v \x03(\tR\tblockedBy\x12\x14\n" +
+	"\x05force\x18\f \x01(\bR\x05force\x12:\n" +
+	"\n" +
+	"recurrence\x18\r \x01(\v2\x1a.todoing.api.v1.RecurrenceR\n" +
+	"recurrence\x12\x16\n" +
+	"\x06labels\x18\x0e \x03(\tR\x06labels\x12!\n" +
+	"\fclear_labels\x18\x0f \x01(\bR\vclearLabels\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x10 \x01(\tR\tprojectId\x12#\n" +
+	"\rclear_project\x18\x11 \x01(\bR\fclearProject\x12\x1f\n" +
+	"\vstatus_name\x18\x12 \x01(\tR\n" +
+	"statusName\x12#\n" +
+	"\rpriority_name\x18\x13 \x01(\tR\fpriorityName\x12;\n" +
+	"\vupdate_mask\x18\x14 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12\x18\n" +
+	"\aversion\x18\x15 \x01(\x03R\aversion\"\x9e\x01\n" +
 	"\x12UpdateTaskResponse\x124\n" +
 	"\bresponse\x18\x01 \x01(\v2\x18.todoing.api.v1.ResponseR\bresponse\x12(\n" +
-	"\x04task\x18\x02 \x01(\v2\x14.todoing.api.v1.TaskR\x04task\"#\n" +
+	"\x04task\x18\x02 \x01(\v2\x14.todoing.api.v1.TaskR\x04task\x12(\n" +
+	"\x04next\x18\x03 \x01(\v2\x14.todoing.api.v1.TaskR\x04next\"=\n" +
 	"\x11DeleteTaskRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id*r\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion*r\n" +
 	"\n" +
 	"TaskStatus\x12\x1b\n" +
 	"\x17TASK_STATUS_UNSPECIFIED\x10\x00\x12\x14\n" +
@@ -836,16 +1596,16 @@ const file_task_proto_rawDesc = "" +
 	"\x19TASK_PRIORITY_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11TASK_PRIORITY_LOW\x10\x01\x12\x18\n" +
 	"\x14TASK_PRIORITY_MEDIUM\x10\x02\x12\x16\n" +
-	"\x12TASK_PRIORITY_HIGH\x10\x032\x9d\x03\n" +
-	"\vTaskService\x12S\n" +
+	"\x12TASK_PRIORITY_HIGH\x10\x032\xa5\x04\n" +
+	"\vTaskService\x12m\n" +
 	"\n" +
-	"CreateTask\x12!.todoing.api.v1.CreateTaskRequest\x1a\".todoing.api.v1.CreateTaskResponse\x12M\n" +
-	"\bGetTasks\x12\x1f.todoing.api.v1.GetTasksRequest\x1a .todoing.api.v1.GetTasksResponse\x12J\n" +
-	"\aGetTask\x12\x1e.todoing.api.v1.GetTaskRequest\x1a\x1f.todoing.api.v1.GetTaskResponse\x12S\n" +
+	"CreateTask\x12!.todoing.api.v1.CreateTaskRequest\x1a\".todoing.api.v1.CreateTaskResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/api/v2/tasks\x12d\n" +
+	"\bGetTasks\x12\x1f.todoing.api.v1.GetTasksRequest\x1a .todoing.api.v1.GetTasksResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/api/v2/tasks\x12f\n" +
+	"\aGetTask\x12\x1e.todoing.api.v1.GetTaskRequest\x1a\x1f.todoing.api.v1.GetTaskResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/api/v2/tasks/{id}\x12r\n" +
 	"\n" +
-	"UpdateTask\x12!.todoing.api.v1.UpdateTaskRequest\x1a\".todoing.api.v1.UpdateTaskResponse\x12I\n" +
+	"UpdateTask\x12!.todoing.api.v1.UpdateTaskRequest\x1a\".todoing.api.v1.UpdateTaskResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\x1a\x12/api/v2/tasks/{id}\x12e\n" +
 	"\n" +
-	"DeleteTask\x12!.todoing.api.v1.DeleteTaskRequest\x1a\x18.todoing.api.v1.ResponseB1Z/github.com/axfinn/todoIng/backend-go/pkg/api/v1b\x06proto3"
+	"DeleteTask\x12!.todoing.api.v1.DeleteTaskRequest\x1a\x18.todoing.api.v1.Response\"\x1a\x82\xd3\xe4\x93\x02\x14*\x12/api/v2/tasks/{id}B1Z/github.com/axfinn/todoIng/backend-go/pkg/api/v1b\x06proto3"
 
 var (
 	file_task_proto_rawDescOnce sync.Once
@@ -860,64 +1620,91 @@ func file_task_proto_rawDescGZIP() []byte {
 }
 
 var file_task_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_task_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_task_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_task_proto_goTypes = []any{
 	(TaskStatus)(0),               // 0: todoing.api.v1.TaskStatus
 	(TaskPriority)(0),             // 1: todoing.api.v1.TaskPriority
-	(*Task)(nil),                  // 2: todoing.api.v1.Task
-	(*CreateTaskRequest)(nil),     // 3: todoing.api.v1.CreateTaskRequest
-	(*CreateTaskResponse)(nil),    // 4: todoing.api.v1.CreateTaskResponse
-	(*GetTasksRequest)(nil),       // 5: todoing.api.v1.GetTasksRequest
-	(*GetTasksResponse)(nil),      // 6: todoing.api.v1.GetTasksResponse
-	(*GetTaskRequest)(nil),        // 7: todoing.api.v1.GetTaskRequest
-	(*GetTaskResponse)(nil),       // 8: todoing.api.v1.GetTaskResponse
-	(*UpdateTaskRequest)(nil),     // 9: todoing.api.v1.UpdateTaskRequest
-	(*UpdateTaskResponse)(nil),    // 10: todoing.api.v1.UpdateTaskResponse
-	(*DeleteTaskRequest)(nil),     // 11: todoing.api.v1.DeleteTaskRequest
-	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
-	(*Response)(nil),              // 13: todoing.api.v1.Response
-	(*PaginationRequest)(nil),     // 14: todoing.api.v1.PaginationRequest
-	(*PaginationResponse)(nil),    // 15: todoing.api.v1.PaginationResponse
+	(*Comment)(nil),               // 2: todoing.api.v1.Comment
+	(*ChecklistItem)(nil),         // 3: todoing.api.v1.ChecklistItem
+	(*Recurrence)(nil),            // 4: todoing.api.v1.Recurrence
+	(*Task)(nil),                  // 5: todoing.api.v1.Task
+	(*CreateTaskRequest)(nil),     // 6: todoing.api.v1.CreateTaskRequest
+	(*CreateTaskResponse)(nil),    // 7: todoing.api.v1.CreateTaskResponse
+	(*GetTasksRequest)(nil),       // 8: todoing.api.v1.GetTasksRequest
+	(*GetTasksResponse)(nil),      // 9: todoing.api.v1.GetTasksResponse
+	(*GetTaskRequest)(nil),        // 10: todoing.api.v1.GetTaskRequest
+	(*GetTaskResponse)(nil),       // 11: todoing.api.v1.GetTaskResponse
+	(*UpdateTaskRequest)(nil),     // 12: todoing.api.v1.UpdateTaskRequest
+	(*UpdateTaskResponse)(nil),    // 13: todoing.api.v1.UpdateTaskResponse
+	(*DeleteTaskRequest)(nil),     // 14: todoing.api.v1.DeleteTaskRequest
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+	(*Response)(nil),              // 16: todoing.api.v1.Response
+	(*PaginationRequest)(nil),     // 17: todoing.api.v1.PaginationRequest
+	(*PaginationResponse)(nil),    // 18: todoing.api.v1.PaginationResponse
+	(*fieldmaskpb.FieldMask)(nil), // 19: google.protobuf.FieldMask
 }
 var file_task_proto_depIdxs = []int32{
-	0,  // 0: todoing.api.v1.Task.status:type_name -> todoing.api.v1.TaskStatus
-	1,  // 1: todoing.api.v1.Task.priority:type_name -> todoing.api.v1.TaskPriority
-	12, // 2: todoing.api.v1.Task.due_date:type_name -> google.protobuf.Timestamp
-	12, // 3: todoing.api.v1.Task.created_at:type_name -> google.protobuf.Timestamp
-	12, // 4: todoing.api.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 5: todoing.api.v1.CreateTaskRequest.status:type_name -> todoing.api.v1.TaskStatus
-	1,  // 6: todoing.api.v1.CreateTaskRequest.priority:type_name -> todoing.api.v1.TaskPriority
-	12, // 7: todoing.api.v1.CreateTaskRequest.due_date:type_name -> google.protobuf.Timestamp
-	13, // 8: todoing.api.v1.CreateTaskResponse.response:type_name -> todoing.api.v1.Response
-	2,  // 9: todoing.api.v1.CreateTaskResponse.task:type_name -> todoing.api.v1.Task
-	14, // 10: todoing.api.v1.GetTasksRequest.pagination:type_name -> todoing.api.v1.PaginationRequest
-	0,  // 11: todoing.api.v1.GetTasksRequest.status:type_name -> todoing.api.v1.TaskStatus
-	1,  // 12: todoing.api.v1.GetTasksRequest.priority:type_name -> todoing.api.v1.TaskPriority
-	13, // 13: todoing.api.v1.GetTasksResponse.response:type_name -> todoing.api.v1.Response
-	2,  // 14: todoing.api.v1.GetTasksResponse.tasks:type_name -> todoing.api.v1.Task
-	15, // 15: todoing.api.v1.GetTasksResponse.pagination:type_name -> todoing.api.v1.PaginationResponse
-	13, // 16: todoing.api.v1.GetTaskResponse.response:type_name -> todoing.api.v1.Response
-	2,  // 17: todoing.api.v1.GetTaskResponse.task:type_name -> todoing.api.v1.Task
-	0,  // 18: todoing.api.v1.UpdateTaskRequest.status:type_name -> todoing.api.v1.TaskStatus
-	1,  // 19: todoing.api.v1.UpdateTaskRequest.priority:type_name -> todoing.api.v1.TaskPriority
-	12, // 20: todoing.api.v1.UpdateTaskRequest.due_date:type_name -> google.protobuf.Timestamp
-	13, // 21: todoing.api.v1.UpdateTaskResponse.response:type_name -> todoing.api.v1.Response
-	2,  // 22: todoing.api.v1.UpdateTaskResponse.task:type_name -> todoing.api.v1.Task
-	3,  // 23: todoing.api.v1.TaskService.CreateTask:input_type -> todoing.api.v1.CreateTaskRequest
-	5,  // 24: todoing.api.v1.TaskService.GetTasks:input_type -> todoing.api.v1.GetTasksRequest
-	7,  // 25: todoing.api.v1.TaskService.GetTask:input_type -> todoing.api.v1.GetTaskRequest
-	9,  // 26: todoing.api.v1.TaskService.UpdateTask:input_type -> todoing.api.v1.UpdateTaskRequest
-	11, // 27: todoing.api.v1.TaskService.DeleteTask:input_type -> todoing.api.v1.DeleteTaskRequest
-	4,  // 28: todoing.api.v1.TaskService.CreateTask:output_type -> todoing.api.v1.CreateTaskResponse
-	6,  // 29: todoing.api.v1.TaskService.GetTasks:output_type -> todoing.api.v1.GetTasksResponse
-	8,  // 30: todoing.api.v1.TaskService.GetTask:output_type -> todoing.api.v1.GetTaskResponse
-	10, // 31: todoing.api.v1.TaskService.UpdateTask:output_type -> todoing.api.v1.UpdateTaskResponse
-	13, // 32: todoing.api.v1.TaskService.DeleteTask:output_type -> todoing.api.v1.Response
-	28, // [28:33] is the sub-list for method output_type
-	23, // [23:28] is the sub-list for method input_type
-	23, // [23:23] is the sub-list for extension type_name
-	23, // [23:23] is the sub-list for extension extendee
-	0,  // [0:23] is the sub-list for field type_name
+	15, // 0: todoing.api.v1.Comment.created_at:type_name -> google.protobuf.Timestamp
+	15, // 1: todoing.api.v1.Comment.updated_at:type_name -> google.protobuf.Timestamp
+	15, // 2: todoing.api.v1.ChecklistItem.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: todoing.api.v1.Task.status:type_name -> todoing.api.v1.TaskStatus
+	1,  // 4: todoing.api.v1.Task.priority:type_name -> todoing.api.v1.TaskPriority
+	15, // 5: todoing.api.v1.Task.due_date:type_name -> google.protobuf.Timestamp
+	15, // 6: todoing.api.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	15, // 7: todoing.api.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
+	15, // 8: todoing.api.v1.Task.scheduled_date:type_name -> google.protobuf.Timestamp
+	2,  // 9: todoing.api.v1.Task.comments:type_name -> todoing.api.v1.Comment
+	3,  // 10: todoing.api.v1.Task.checklist:type_name -> todoing.api.v1.ChecklistItem
+	5,  // 11: todoing.api.v1.Task.subtasks:type_name -> todoing.api.v1.Task
+	4,  // 12: todoing.api.v1.Task.recurrence:type_name -> todoing.api.v1.Recurrence
+	15, // 13: todoing.api.v1.Task.deleted_at:type_name -> google.protobuf.Timestamp
+	0,  // 14: todoing.api.v1.CreateTaskRequest.status:type_name -> todoing.api.v1.TaskStatus
+	1,  // 15: todoing.api.v1.CreateTaskRequest.priority:type_name -> todoing.api.v1.TaskPriority
+	15, // 16: todoing.api.v1.CreateTaskRequest.due_date:type_name -> google.protobuf.Timestamp
+	15, // 17: todoing.api.v1.CreateTaskRequest.scheduled_date:type_name -> google.protobuf.Timestamp
+	4,  // 18: todoing.api.v1.CreateTaskRequest.recurrence:type_name -> todoing.api.v1.Recurrence
+	16, // 19: todoing.api.v1.CreateTaskResponse.response:type_name -> todoing.api.v1.Response
+	5,  // 20: todoing.api.v1.CreateTaskResponse.task:type_name -> todoing.api.v1.Task
+	17, // 21: todoing.api.v1.GetTasksRequest.pagination:type_name -> todoing.api.v1.PaginationRequest
+	0,  // 22: todoing.api.v1.GetTasksRequest.status:type_name -> todoing.api.v1.TaskStatus
+	1,  // 23: todoing.api.v1.GetTasksRequest.priority:type_name -> todoing.api.v1.TaskPriority
+	15, // 24: todoing.api.v1.GetTasksRequest.due_date_from:type_name -> google.protobuf.Timestamp
+	15, // 25: todoing.api.v1.GetTasksRequest.due_date_to:type_name -> google.protobuf.Timestamp
+	15, // 26: todoing.api.v1.GetTasksRequest.scheduled_from:type_name -> google.protobuf.Timestamp
+	15, // 27: todoing.api.v1.GetTasksRequest.scheduled_to:type_name -> google.protobuf.Timestamp
+	15, // 28: todoing.api.v1.GetTasksRequest.created_from:type_name -> google.protobuf.Timestamp
+	15, // 29: todoing.api.v1.GetTasksRequest.created_to:type_name -> google.protobuf.Timestamp
+	15, // 30: todoing.api.v1.GetTasksRequest.updated_from:type_name -> google.protobuf.Timestamp
+	15, // 31: todoing.api.v1.GetTasksRequest.updated_to:type_name -> google.protobuf.Timestamp
+	16, // 32: todoing.api.v1.GetTasksResponse.response:type_name -> todoing.api.v1.Response
+	5,  // 33: todoing.api.v1.GetTasksResponse.tasks:type_name -> todoing.api.v1.Task
+	18, // 34: todoing.api.v1.GetTasksResponse.pagination:type_name -> todoing.api.v1.PaginationResponse
+	16, // 35: todoing.api.v1.GetTaskResponse.response:type_name -> todoing.api.v1.Response
+	5,  // 36: todoing.api.v1.GetTaskResponse.task:type_name -> todoing.api.v1.Task
+	0,  // 37: todoing.api.v1.UpdateTaskRequest.status:type_name -> todoing.api.v1.TaskStatus
+	1,  // 38: todoing.api.v1.UpdateTaskRequest.priority:type_name -> todoing.api.v1.TaskPriority
+	15, // 39: todoing.api.v1.UpdateTaskRequest.due_date:type_name -> google.protobuf.Timestamp
+	15, // 40: todoing.api.v1.UpdateTaskRequest.scheduled_date:type_name -> google.protobuf.Timestamp
+	4,  // 41: todoing.api.v1.UpdateTaskRequest.recurrence:type_name -> todoing.api.v1.Recurrence
+	19, // 42: todoing.api.v1.UpdateTaskRequest.update_mask:type_name -> google.protobuf.FieldMask
+	16, // 43: todoing.api.v1.UpdateTaskResponse.response:type_name -> todoing.api.v1.Response
+	5,  // 44: todoing.api.v1.UpdateTaskResponse.task:type_name -> todoing.api.v1.Task
+	5,  // 45: todoing.api.v1.UpdateTaskResponse.next:type_name -> todoing.api.v1.Task
+	6,  // 46: todoing.api.v1.TaskService.CreateTask:input_type -> todoing.api.v1.CreateTaskRequest
+	8,  // 47: todoing.api.v1.TaskService.GetTasks:input_type -> todoing.api.v1.GetTasksRequest
+	10, // 48: todoing.api.v1.TaskService.GetTask:input_type -> todoing.api.v1.GetTaskRequest
+	12, // 49: todoing.api.v1.TaskService.UpdateTask:input_type -> todoing.api.v1.UpdateTaskRequest
+	14, // 50: todoing.api.v1.TaskService.DeleteTask:input_type -> todoing.api.v1.DeleteTaskRequest
+	7,  // 51: todoing.api.v1.TaskService.CreateTask:output_type -> todoing.api.v1.CreateTaskResponse
+	9,  // 52: todoing.api.v1.TaskService.GetTasks:output_type -> todoing.api.v1.GetTasksResponse
+	11, // 53: todoing.api.v1.TaskService.GetTask:output_type -> todoing.api.v1.GetTaskResponse
+	13, // 54: todoing.api.v1.TaskService.UpdateTask:output_type -> todoing.api.v1.UpdateTaskResponse
+	16, // 55: todoing.api.v1.TaskService.DeleteTask:output_type -> todoing.api.v1.Response
+	51, // [51:56] is the sub-list for method output_type
+	46, // [46:51] is the sub-list for method input_type
+	46, // [46:46] is the sub-list for extension type_name
+	46, // [46:46] is the sub-list for extension extendee
+	0,  // [0:46] is the sub-list for field type_name
 }
 
 func init() { file_task_proto_init() }
@@ -932,7 +1719,7 @@ func file_task_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_task_proto_rawDesc), len(file_task_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   10,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -73,75 +73,77 @@ router.get('/:id', auth, async (req, res) => {
 });
 
 // Update Task
-router.put(
-  '/:id',
+// PUT 与 PATCH 共用：未提供的字段保持不变，description/assignee/deadline/scheduledDate 传 null 表示清空
+const updateValidators = [
+  auth,
   [
-    auth,
-    [
-      check('title', 'Title is required').optional().not().isEmpty(),
-      check('status', 'Invalid status')
-        .optional()
-        .isIn(['To Do', 'In Progress', 'Done']),
-      check('priority', 'Invalid priority')
-        .optional()
-        .isIn(['Low', 'Medium', 'High']),
-    ],
+    check('title', 'Title is required').optional().not().isEmpty(),
+    check('status', 'Invalid status')
+      .optional()
+      .isIn(['To Do', 'In Progress', 'Done']),
+    check('priority', 'Invalid priority')
+      .optional()
+      .isIn(['Low', 'Medium', 'High']),
   ],
-  async (req, res) => {
-    const errors = validationResult(req);
-    if (!errors.isEmpty()) {
-      return res.status(400).json({ errors: errors.array() });
-    }
+];
 
-    const { title, description, status, priority, assignee, comments, deadline, scheduledDate } = req.body;
+const updateTask = async (req, res) => {
+  const errors = validationResult(req);
+  if (!errors.isEmpty()) {
+    return res.status(400).json({ errors: errors.array() });
+  }
 
-    // Build task object
-    const taskFields = {};
-    if (title) taskFields.title = title;
-    if (description) taskFields.description = description;
-    if (status) taskFields.status = status;
-    if (priority) taskFields.priority = priority;
-    if (assignee !== undefined) taskFields.assignee = assignee;
-    if (deadline !== undefined) taskFields.deadline = deadline;
-    if (scheduledDate !== undefined) taskFields.scheduledDate = scheduledDate;
-
-    // 处理评论，确保添加创建者信息
-    if (comments) {
-      taskFields.comments = comments.map(comment => {
-        // 如果评论没有创建者信息，则添加当前用户作为创建者
-        if (!comment.createdBy) {
-          return {
-            ...comment,
-            createdBy: req.user.id
-          };
-        }
-        return comment;
-      });
-    }
+  const { title, description, status, priority, assignee, comments, deadline, scheduledDate } = req.body;
+
+  // Build task object
+  const taskFields = {};
+  if (title) taskFields.title = title;
+  if (description !== undefined) taskFields.description = description || '';
+  if (status) taskFields.status = status;
+  if (priority) taskFields.priority = priority;
+  if (assignee !== undefined) taskFields.assignee = assignee;
+  if (deadline !== undefined) taskFields.deadline = deadline;
+  if (scheduledDate !== undefined) taskFields.scheduledDate = scheduledDate;
+
+  // 处理评论，确保添加创建者信息
+  if (comments) {
+    taskFields.comments = comments.map(comment => {
+      // 如果评论没有创建者信息，则添加当前用户作为创建者
+      if (!comment.createdBy) {
+        return {
+          ...comment,
+          createdBy: req.user.id
+        };
+      }
+      return comment;
+    });
+  }
 
-    try {
-      let task = await Task.findById(req.params.id);
+  try {
+    let task = await Task.findById(req.params.id);
 
-      if (!task) return res.status(404).json({ msg: 'Task not found' });
+    if (!task) return res.status(404).json({ msg: 'Task not found' });
 
-      // Make sure user owns task
-      if (task.createdBy.toString() !== req.user.id) {
-        return res.status(401).json({ msg: 'User not authorized' });
-      }
+    // Make sure user owns task
+    if (task.createdBy.toString() !== req.user.id) {
+      return res.status(401).json({ msg: 'User not authorized' });
+    }
 
-      task = await Task.findByIdAndUpdate(
-        req.params.id,
-        { $set: taskFields },
-        { new: true }
-      );
+    task = await Task.findByIdAndUpdate(
+      req.params.id,
+      { $set: taskFields },
+      { new: true }
+    );
 
-      res.json(task);
-    } catch (err) {
-      console.error(err.message);
-      res.status(500).send('Server Error');
-    }
+    res.json(task);
+  } catch (err) {
+    console.error(err.message);
+    res.status(500).send('Server Error');
   }
-);
+};
+
+router.put('/:id', updateValidators, updateTask);
+router.patch('/:id', updateValidators, updateTask);
 
 // Delete Task
 router.delete('/:id', auth, async (req, res) => {
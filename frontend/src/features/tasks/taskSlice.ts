@@ -79,7 +79,7 @@ export const updateTask = createAsyncThunk<Task, { _id: string } & UpdateTaskFie
   async (taskData: { _id: string } & UpdateTaskFields, { rejectWithValue }) => {
     try {
       const { _id, ...taskUpdate } = taskData;
-      const res = await api.put(`/tasks/${_id}`, taskUpdate);
+      const res = await api.patch(`/tasks/${_id}`, taskUpdate);
       return res.data as Task;
     } catch (err: any) {
       if (err.response && err.response.data) {